@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+type gcOptions struct {
+	inactiveFor  time.Duration
+	deleteBranch bool
+	yes          bool
+	dryRun       bool
+	scanDepth    int
+}
+
+// runGCCommand implements `rv gc`, which deletes managed worktrees that are
+// merged, missing or unused, together with their sessions.
+func runGCCommand(args []string, fs ports.Filesystem, sessions ports.SessionManager, hooks lifecycleHooks, inactiveFor time.Duration, scanDepth int, in io.Reader, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("gc", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	inactiveDays := flags.Int("inactive-days", int(inactiveFor.Hours()/24), "Offer worktrees unused for longer than this many days (0 turns the check off)")
+	deleteBranch := flags.Bool("delete-branch", false, "Also delete the branches of deleted worktrees when fully merged")
+	yes := flags.Bool("yes", false, "Delete every stale worktree without asking")
+	dryRun := flags.Bool("dry-run", false, "List stale worktrees without deleting them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *inactiveDays < 0 {
+		_, _ = fmt.Fprintln(errOut, "Error: --inactive-days cannot be negative")
+		return 2
+	}
+	if *yes && *dryRun {
+		_, _ = fmt.Fprintln(errOut, "Error: --yes and --dry-run are mutually exclusive")
+		return 2
+	}
+
+	roots := rootsOrDefault(flags.Args())
+
+	opts := gcOptions{
+		inactiveFor:  time.Duration(*inactiveDays) * 24 * time.Hour,
+		deleteBranch: *deleteBranch,
+		yes:          *yes,
+		dryRun:       *dryRun,
+		scanDepth:    scanDepth,
+	}
+	if err := collectWorktrees(fs, sessions, hooks, roots, opts, in, out); err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// collectWorktrees lists the stale worktrees under roots and deletes the
+// ones the user confirms, or all of them with --yes.
+func collectWorktrees(fs ports.Filesystem, sessions ports.SessionManager, hooks lifecycleHooks, roots []string, opts gcOptions, in io.Reader, out io.Writer) error {
+	collector, ok := fs.(ports.WorktreeCollector)
+	if !ok {
+		return errors.New("cleaning up worktrees is not supported")
+	}
+	dirs, err := fs.ScanDirs(roots, opts.scanDepth)
+	if err != nil {
+		return err
+	}
+	var activity []core.WorktreeActivity
+	for _, dir := range dirs {
+		found, err := collector.WorktreeActivity(dir.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dir.Name, err)
+		}
+		activity = append(activity, found...)
+	}
+	var running []core.SessionInfo
+	var launches []core.Launch
+	if sessions != nil {
+		// Without tmux, inactivity falls back to the last commit.
+		running, _ = sessions.ListSessions()
+		if history, ok := sessions.(ports.LaunchHistory); ok {
+			launches, _ = history.RecentLaunches()
+		}
+	}
+
+	stale := core.FindStaleWorktrees(activity, running, launches, opts.inactiveFor, time.Now())
+	if len(stale) == 0 {
+		_, _ = fmt.Fprintln(out, "No stale worktrees found.")
+		return nil
+	}
+	printStaleWorktrees(out, stale)
+	if opts.dryRun {
+		_, _ = fmt.Fprintf(out, "Found %d stale worktrees.\n", len(stale))
+		return nil
+	}
+
+	reader := bufio.NewReader(in)
+	deleted := 0
+	var failures []error
+	for _, wt := range stale {
+		if !opts.yes {
+			confirmed, err := promptYesNo(reader, out, fmt.Sprintf("Delete %s (%s)?", wt.Worktree.Name, wt.Reason))
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				continue
+			}
+		}
+		if err := deleteWorktreeWithSessions(fs, sessions, hooks, wt.ProjectPath, wt.Worktree.Path, wt.Worktree.Branch); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", wt.Worktree.Name, err))
+			_, _ = fmt.Fprintf(out, "failed %s: %v\n", wt.Worktree.Name, err)
+			continue
+		}
+		deleted++
+		_, _ = fmt.Fprintf(out, "deleted %s\n", wt.Worktree.Path)
+		if opts.deleteBranch {
+			if err := deleteMergedBranch(fs, wt.ProjectPath, wt.Worktree.Branch); err != nil {
+				failures = append(failures, fmt.Errorf("%s: %w", wt.Worktree.Name, err))
+				_, _ = fmt.Fprintf(out, "kept branch %s: %v\n", wt.Worktree.Branch, err)
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(out, "Deleted %d of %d stale worktrees.\n", deleted, len(stale))
+	return errors.Join(failures...)
+}
+
+func printStaleWorktrees(out io.Writer, stale []core.StaleWorktree) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PROJECT\tWORKTREE\tREASON\tLAST ACTIVE")
+	for _, wt := range stale {
+		lastActive := "-"
+		if !wt.LastActive.IsZero() {
+			lastActive = wt.LastActive.Local().Format("2006-01-02 15:04")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", filepath.Base(wt.ProjectPath), wt.Worktree.Name, wt.Reason, lastActive)
+	}
+	_ = w.Flush()
+}
+
+func promptYesNo(reader *bufio.Reader, out io.Writer, question string) (bool, error) {
+	_, _ = fmt.Fprintf(out, "%s [y/N] ", question)
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// deleteWorktreeWithSessions deletes a worktree the way the UI does: pre
+// hooks, then its sessions, then the worktree itself, then post hooks.
+func deleteWorktreeWithSessions(fs ports.Filesystem, sessions ports.SessionManager, hooks lifecycleHooks, projectPath, worktreePath, branch string) error {
+	payload := core.HookPayload{ProjectPath: projectPath, WorktreePath: worktreePath, Branch: branch}
+	payload.Event = core.HookPreWorktreeDelete
+	hooks.run(payload)
+	if sessions != nil {
+		running, err := sessions.ListSessions()
+		if err != nil {
+			return err
+		}
+		for _, session := range core.SessionsInDir(running, worktreePath) {
+			if err := sessions.KillSessionByName(session.Name); err != nil {
+				return err
+			}
+		}
+	}
+	if err := fs.DeleteWorktree(projectPath, worktreePath); err != nil {
+		return err
+	}
+	payload.Event = core.HookPostWorktreeDelete
+	hooks.run(payload)
+	return nil
+}
+
+// deleteMergedBranch deletes branch if it is fully merged.
+func deleteMergedBranch(fs ports.Filesystem, projectPath, branch string) error {
+	deleter, ok := fs.(ports.BranchDeleter)
+	if !ok {
+		return errors.New("deleting branches is not supported")
+	}
+	if branch == "" || branch == "(detached)" {
+		return nil
+	}
+	return deleter.DeleteBranch(projectPath, branch, false)
+}
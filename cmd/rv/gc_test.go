@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/adapters"
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+type collectingStubFilesystem struct {
+	landingStubFilesystem
+	activity        []core.WorktreeActivity
+	deletedBranches []string
+}
+
+func (c *collectingStubFilesystem) ScanDirs([]string, int) ([]core.DirEntry, error) {
+	return []core.DirEntry{{Path: "/p/api", Name: "api"}}, nil
+}
+
+func (c *collectingStubFilesystem) WorktreeActivity(string) ([]core.WorktreeActivity, error) {
+	return c.activity, nil
+}
+
+func (c *collectingStubFilesystem) BranchMerged(string, string) (bool, error) {
+	return true, nil
+}
+
+func (c *collectingStubFilesystem) DeleteBranch(_, branch string, force bool) error {
+	if !force {
+		c.deletedBranches = append(c.deletedBranches, branch)
+	}
+	return nil
+}
+
+func newCollectingStub() *collectingStubFilesystem {
+	return &collectingStubFilesystem{activity: []core.WorktreeActivity{
+		{ProjectPath: "/p/api", Worktree: core.Worktree{Path: "/wt/api--done", Name: "api--done", Branch: "done"}, Merged: true, LastCommit: time.Now()},
+		{ProjectPath: "/p/api", Worktree: core.Worktree{Path: "/wt/api--gone", Name: "api--gone", Branch: "gone"}, Missing: true},
+		{ProjectPath: "/p/api", Worktree: core.Worktree{Path: "/wt/api--wip", Name: "api--wip", Branch: "wip"}, LastCommit: time.Now()},
+	}}
+}
+
+func TestRunGCCommandDeletesConfirmedWorktrees(t *testing.T) {
+	fs := newCollectingStub()
+	var out, errOut bytes.Buffer
+	in := strings.NewReader("y\nn\n")
+	code := runGCCommand([]string{"--delete-branch", "/p"}, fs, &stubSessionManager{}, lifecycleHooks{}, 30*24*time.Hour, 2, in, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if len(fs.deleted) != 1 || fs.deleted[0] != "/wt/api--done" {
+		t.Fatalf("expected only the confirmed worktree to be deleted, got %v", fs.deleted)
+	}
+	if len(fs.deletedBranches) != 1 || fs.deletedBranches[0] != "done" {
+		t.Fatalf("expected a safe delete of its branch, got %v", fs.deletedBranches)
+	}
+	for _, want := range []string{"api--done", "merged", "api--gone", "missing", "Deleted 1 of 2 stale worktrees."} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output %q", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "api--wip") {
+		t.Fatalf("expected the active worktree to be left alone, got %q", out.String())
+	}
+}
+
+type killingSessionManager struct {
+	stubSessionManager
+	running []core.SessionInfo
+	killed  []string
+}
+
+func (s *killingSessionManager) ListSessions() ([]core.SessionInfo, error) { return s.running, nil }
+
+func (s *killingSessionManager) KillSessionByName(name string) error {
+	s.killed = append(s.killed, name)
+	return nil
+}
+
+func TestRunGCCommandKillsEverySessionOfDeletedWorktrees(t *testing.T) {
+	fs := newCollectingStub()
+	sessions := &killingSessionManager{running: []core.SessionInfo{
+		{Name: "scratch", DirPath: "/wt/api--done/"},
+		{Name: "-wt-api--done", DirPath: "/wt/api--done"},
+		{Name: "-wt-api--wip", DirPath: "/wt/api--wip"},
+	}}
+	var out, errOut bytes.Buffer
+	if code := runGCCommand([]string{"--yes", "/p"}, fs, sessions, lifecycleHooks{}, 0, 2, nil, &out, &errOut); code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if want := []string{"scratch", "-wt-api--done"}; !slices.Equal(sessions.killed, want) {
+		t.Fatalf("expected the sessions of the deleted worktree killed by name %v, got %v", want, sessions.killed)
+	}
+}
+
+func TestRunGCCommandDryRunAndYes(t *testing.T) {
+	fs := newCollectingStub()
+	var out, errOut bytes.Buffer
+	if code := runGCCommand([]string{"--dry-run", "/p"}, fs, nil, lifecycleHooks{}, 0, 2, strings.NewReader(""), &out, &errOut); code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if len(fs.deleted) != 0 || !strings.Contains(out.String(), "Found 2 stale worktrees.") {
+		t.Fatalf("expected a listing only, got %v %q", fs.deleted, out.String())
+	}
+
+	out.Reset()
+	if code := runGCCommand([]string{"--yes", "/p"}, fs, nil, lifecycleHooks{}, 0, 2, strings.NewReader(""), &out, &errOut); code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if len(fs.deleted) != 2 || len(fs.deletedBranches) != 0 {
+		t.Fatalf("expected both worktrees deleted and branches kept, got %v %v", fs.deleted, fs.deletedBranches)
+	}
+
+	if code := runGCCommand([]string{"--yes", "--dry-run"}, fs, nil, lifecycleHooks{}, 0, 2, nil, &out, &errOut); code != 2 {
+		t.Fatalf("expected a usage error, got %d", code)
+	}
+}
+
+func TestRunGCCommandKeepsNewWorktree(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	project := filepath.Join(root, "api")
+	for _, args := range [][]string{
+		{"init", "--quiet", "-b", "main", project},
+		{"-C", project, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "--quiet", "--allow-empty", "-m", "base"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	fs := adapters.NewOSFilesystem(core.ScanRules{})
+	worktreePath, err := fs.CreateWorktree(project, "fresh")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	if code := runGCCommand([]string{"--yes", root}, fs, nil, lifecycleHooks{}, 30*24*time.Hour, 2, nil, &out, &errOut); code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if _, err := os.Stat(worktreePath); err != nil {
+		t.Fatalf("expected the new worktree to be kept: %v (output %q)", err, out.String())
+	}
+	if !strings.Contains(out.String(), "No stale worktrees found.") {
+		t.Fatalf("expected nothing stale, got %q", out.String())
+	}
+}
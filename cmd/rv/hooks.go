@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ariguillegp/rivet/internal/adapters"
+	"github.com/ariguillegp/rivet/internal/config"
+	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+// lifecycleHooks runs the configured hooks outside the UI. As in the UI, a
+// failed hook is reported but does not stop the launch.
+type lifecycleHooks struct {
+	hooks  core.Hooks
+	runner ports.HookRunner
+	out    io.Writer
+}
+
+// run runs the hooks for payload's event one after another and reports
+// whether all of them succeeded.
+func (h lifecycleHooks) run(payload core.HookPayload) bool {
+	if h.runner == nil {
+		return true
+	}
+	ok := true
+	for _, eff := range h.hooks.Effects(payload) {
+		hook, isHook := eff.(core.EffRunHook)
+		if !isHook {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), hook.Timeout)
+		err := h.runner.RunHook(ctx, hook.Command, hook.Payload)
+		cancel()
+		if err != nil {
+			ok = false
+			_, _ = fmt.Fprintf(h.out, "Warning: %v\n", core.HookError(payload.Event, hook.Command, err))
+		}
+	}
+	return ok
+}
+
+// runHookCommand implements `rv hook`, which runs the hooks for one event.
+// Tool windows use it to report agent_exit.
+func runHookCommand(args []string, runner ports.HookRunner, errOut io.Writer) int {
+	flags := flag.NewFlagSet("hook", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	configPath := flags.String("config", config.DefaultPath, "Path to the config file")
+	event := flags.String("event", "", "Event to run the hooks of")
+	worktree := flags.String("worktree", "", "Worktree the event happened in")
+	project := flags.String("project", "", "Project the event happened in (default: the worktree's project)")
+	branch := flags.String("branch", "", "Branch the event is about (default: the worktree's branch)")
+	tool := flags.String("tool", "", "Tool the event is about")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if !slices.Contains(core.HookEvents(), core.HookEvent(*event)) {
+		_, _ = fmt.Fprintf(errOut, "Error: unknown hook event %q\n", *event)
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+
+	payload := core.HookPayload{Event: core.HookEvent(*event), Tool: *tool}
+	if *worktree != "" {
+		payload = adapters.WorktreeHookPayload(payload.Event, *worktree, *tool)
+	}
+	if *project != "" {
+		payload.ProjectPath = expandPath(*project)
+	}
+	if *branch != "" {
+		payload.Branch = *branch
+	}
+
+	hooks := lifecycleHooks{hooks: cfg.HookRegistry(), runner: runner, out: errOut}
+	if !hooks.run(payload) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+type recordingHookRunner struct {
+	commands []string
+	payloads []core.HookPayload
+	err      error
+}
+
+func (r *recordingHookRunner) RunHook(_ context.Context, command string, payload core.HookPayload) error {
+	r.commands = append(r.commands, command)
+	r.payloads = append(r.payloads, payload)
+	return r.err
+}
+
+func TestRunHookCommandRunsConfiguredHooks(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte("[hooks]\nagent_exit = [\"./notify.sh\", \"./sync.sh\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &recordingHookRunner{}
+	var errOut bytes.Buffer
+	code := runHookCommand([]string{"--config", configPath, "--event", "agent_exit", "--project", "/projects/api", "--branch", "main", "--tool", "claude"}, runner, &errOut)
+	if code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if strings.Join(runner.commands, ",") != "./notify.sh,./sync.sh" {
+		t.Fatalf("unexpected hook commands: %v", runner.commands)
+	}
+	want := core.HookPayload{Event: core.HookAgentExit, ProjectPath: "/projects/api", Branch: "main", Tool: "claude"}
+	if runner.payloads[0] != want {
+		t.Fatalf("expected payload %+v, got %+v", want, runner.payloads[0])
+	}
+}
+
+func TestRunHookCommandRejectsUnknownEvent(t *testing.T) {
+	var errOut bytes.Buffer
+	if code := runHookCommand([]string{"--event", "agent_start"}, &recordingHookRunner{}, &errOut); code != 2 {
+		t.Fatalf("expected usage error, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), `unknown hook event "agent_start"`) {
+		t.Fatalf("unexpected output %q", errOut.String())
+	}
+}
+
+func TestResolveWorktreePathRunsCreateHooks(t *testing.T) {
+	runner := &recordingHookRunner{err: errors.New("exit status 1")}
+	var out bytes.Buffer
+	hooks := lifecycleHooks{
+		hooks: core.Hooks{Commands: map[core.HookEvent][]string{
+			core.HookPreWorktreeCreate:  {"./check.sh"},
+			core.HookPostWorktreeCreate: {"./announce.sh"},
+		}},
+		runner: runner,
+		out:    &out,
+	}
+	fs := &stubFilesystem{createWorktreePath: "/projects/demo/.rivet/demo--feature"}
+
+	path, err := resolveWorktreePath(fs, hooks, "/projects/demo", "feature")
+	if err != nil {
+		t.Fatalf("hook failures must not stop the launch: %v", err)
+	}
+	if path != "/projects/demo/.rivet/demo--feature" {
+		t.Fatalf("unexpected path %q", path)
+	}
+	if len(runner.payloads) != 2 || runner.payloads[0].Event != core.HookPreWorktreeCreate || runner.payloads[1].WorktreePath != path {
+		t.Fatalf("unexpected hook runs: %+v", runner.payloads)
+	}
+	if !strings.Contains(out.String(), `Warning: pre_worktree_create hook "./check.sh" failed: exit status 1`) {
+		t.Fatalf("expected a warning, got %q", out.String())
+	}
+}
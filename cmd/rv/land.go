@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+// runLandCommand implements `rv land`, which integrates a worktree's branch
+// into the branch checked out in its project root.
+func runLandCommand(args []string, fs ports.Filesystem, sessions ports.SessionManager, hooks lifecycleHooks, rules core.ScanRules, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("land", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	project := flags.String("project", "", "Project container name or path")
+	worktree := flags.String("worktree", "", "Worktree name or path to land")
+	strategyFlag := flags.String("strategy", string(core.LandMerge), "How to integrate the branch (merge, squash or rebase)")
+	deleteAfter := flags.Bool("delete", false, "Delete the worktree and kill its sessions after landing")
+	deleteBranch := flags.Bool("delete-branch", false, "With --delete, also delete the branch if it is fully merged, or squashed")
+	forceDeleteBranch := flags.Bool("force-delete-branch", false, "With --delete, also delete the branch even if it is not fully merged")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *project == "" || *worktree == "" {
+		_, _ = fmt.Fprintln(errOut, "Usage: rv land --project NAME --worktree NAME [--strategy merge|squash|rebase] [--delete [--delete-branch | --force-delete-branch]] [directories...]")
+		return 2
+	}
+	branchDeletion := core.BranchKeep
+	switch {
+	case *deleteBranch && *forceDeleteBranch:
+		_, _ = fmt.Fprintln(errOut, "Error: --delete-branch and --force-delete-branch are mutually exclusive")
+		return 2
+	case (*deleteBranch || *forceDeleteBranch) && !*deleteAfter:
+		_, _ = fmt.Fprintln(errOut, "Error: deleting the branch requires --delete")
+		return 2
+	case *deleteBranch:
+		branchDeletion = core.BranchDelete
+	case *forceDeleteBranch:
+		branchDeletion = core.BranchForceDelete
+	}
+	strategy, err := core.ParseLandStrategy(*strategyFlag)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 2
+	}
+
+	roots := rootsOrDefault(flags.Args())
+
+	if err := landWorktree(fs, sessions, hooks, roots, rules, *project, *worktree, strategy, *deleteAfter, branchDeletion, out); err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func landWorktree(fs ports.Filesystem, sessions ports.SessionManager, hooks lifecycleHooks, roots []string, rules core.ScanRules, project, worktree string, strategy core.LandStrategy, deleteAfter bool, branchDeletion core.BranchDeletion, out io.Writer) error {
+	lander, ok := fs.(ports.WorktreeLander)
+	if !ok {
+		return errors.New("landing worktrees is not supported")
+	}
+	projectPath, err := resolveProjectPath(fs, hooks, roots, rules, project, false)
+	if err != nil {
+		return err
+	}
+	worktreePath, found, err := findWorktreePath(fs, projectPath, worktree)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("worktree not found: %s", worktree)
+	}
+
+	result, err := lander.LandWorktree(projectPath, worktreePath, strategy)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Landed %s into %s (%s).\n", result.Branch, result.Base, strategy)
+	if !deleteAfter {
+		return nil
+	}
+
+	// A squash never merges the branch by ancestry, though its changes are
+	// now in the base, so a safe delete would always be refused.
+	if strategy == core.LandSquash && branchDeletion == core.BranchDelete {
+		branchDeletion = core.BranchForceDelete
+	}
+	// Check a safe branch delete up front so an unmerged branch keeps the
+	// worktree too.
+	if branchDeletion == core.BranchDelete {
+		if err := checkBranchMerged(fs, projectPath, result.Branch); err != nil {
+			return fmt.Errorf("landed, but kept the worktree: %w (use --force-delete-branch to delete it anyway)", err)
+		}
+	}
+	if err := deleteWorktreeWithSessions(fs, sessions, hooks, projectPath, worktreePath, result.Branch); err != nil {
+		return fmt.Errorf("landed, but deleting the worktree failed: %w", err)
+	}
+	_, _ = fmt.Fprintf(out, "Deleted worktree %s.\n", worktreePath)
+	if branchDeletion == core.BranchKeep {
+		return nil
+	}
+
+	deleter, ok := fs.(ports.BranchDeleter)
+	if !ok {
+		return errors.New("deleting branches is not supported")
+	}
+	if err := deleter.DeleteBranch(projectPath, result.Branch, branchDeletion == core.BranchForceDelete); err != nil {
+		return fmt.Errorf("deleted the worktree, but kept branch %s: %w", result.Branch, err)
+	}
+	_, _ = fmt.Fprintf(out, "Deleted branch %s.\n", result.Branch)
+	return nil
+}
+
+// checkBranchMerged returns a BranchNotMergedError when a safe delete of
+// branch would be refused.
+func checkBranchMerged(fs ports.Filesystem, projectPath, branch string) error {
+	deleter, ok := fs.(ports.BranchDeleter)
+	if !ok {
+		return errors.New("deleting branches is not supported")
+	}
+	merged, err := deleter.BranchMerged(projectPath, branch)
+	if err != nil {
+		return err
+	}
+	if !merged {
+		return core.BranchNotMergedError{Branch: branch}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+type landingStubFilesystem struct {
+	stubFilesystem
+	landed  []core.LandStrategy
+	landErr error
+	deleted []string
+	// unmerged makes BranchMerged report the branch as not fully merged.
+	unmerged        bool
+	deletedBranches []string
+}
+
+func (l *landingStubFilesystem) LandWorktree(_, _ string, strategy core.LandStrategy) (core.LandResult, error) {
+	l.landed = append(l.landed, strategy)
+	if l.landErr != nil {
+		return core.LandResult{}, l.landErr
+	}
+	return core.LandResult{Branch: "feature", Base: "main"}, nil
+}
+
+func (l *landingStubFilesystem) DeleteWorktree(_, worktreePath string) error {
+	l.deleted = append(l.deleted, worktreePath)
+	return nil
+}
+
+func (l *landingStubFilesystem) BranchMerged(_, _ string) (bool, error) {
+	return !l.unmerged, nil
+}
+
+func (l *landingStubFilesystem) DeleteBranch(_, branch string, force bool) error {
+	if force {
+		branch += " (forced)"
+	}
+	l.deletedBranches = append(l.deletedBranches, branch)
+	return nil
+}
+
+func landProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestRunLandCommandLandsAndDeletes(t *testing.T) {
+	root := landProject(t)
+	fs := &landingStubFilesystem{stubFilesystem: stubFilesystem{listing: core.WorktreeListing{Worktrees: []core.Worktree{
+		{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"},
+	}}}}
+
+	var out, errOut bytes.Buffer
+	args := []string{"--project", "api", "--worktree", "feature", "--strategy", "squash", "--delete", root}
+	code := runLandCommand(args, fs, &stubSessionManager{}, lifecycleHooks{}, core.ScanRules{}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if len(fs.landed) != 1 || fs.landed[0] != core.LandSquash {
+		t.Fatalf("expected one squash, got %v", fs.landed)
+	}
+	if len(fs.deleted) != 1 || fs.deleted[0] != "/wt/api--feature" {
+		t.Fatalf("expected the worktree to be deleted, got %v", fs.deleted)
+	}
+	if !strings.Contains(out.String(), "Landed feature into main (squash).") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestRunLandCommandKeepsWorktreeOnConflict(t *testing.T) {
+	root := landProject(t)
+	fs := &landingStubFilesystem{
+		stubFilesystem: stubFilesystem{listing: core.WorktreeListing{Worktrees: []core.Worktree{
+			{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"},
+		}}},
+		landErr: core.LandConflictError{Strategy: core.LandMerge, Files: []string{"app.go"}},
+	}
+
+	var out, errOut bytes.Buffer
+	args := []string{"--project", "api", "--worktree", "feature", "--delete", root}
+	code := runLandCommand(args, fs, &stubSessionManager{}, lifecycleHooks{}, core.ScanRules{}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if len(fs.deleted) != 0 {
+		t.Fatalf("expected the worktree to be kept, got %v", fs.deleted)
+	}
+	if !strings.Contains(errOut.String(), "merge conflict in app.go") {
+		t.Fatalf("unexpected error output %q", errOut.String())
+	}
+}
+
+func TestRunLandCommandValidatesFlags(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := runLandCommand([]string{"--project", "api"}, &landingStubFilesystem{}, nil, lifecycleHooks{}, core.ScanRules{}, &out, &errOut); code != 2 {
+		t.Fatalf("expected usage error without --worktree, got %d", code)
+	}
+	errOut.Reset()
+	code := runLandCommand([]string{"--project", "api", "--worktree", "feature", "--strategy", "octopus"}, &landingStubFilesystem{}, nil, lifecycleHooks{}, core.ScanRules{}, &out, &errOut)
+	if code != 2 || !strings.Contains(errOut.String(), "unknown land strategy") {
+		t.Fatalf("expected an unknown strategy error, got %d: %s", code, errOut.String())
+	}
+	errOut.Reset()
+	code = runLandCommand([]string{"--project", "api", "--worktree", "feature", "--delete-branch"}, &landingStubFilesystem{}, nil, lifecycleHooks{}, core.ScanRules{}, &out, &errOut)
+	if code != 2 || !strings.Contains(errOut.String(), "requires --delete") {
+		t.Fatalf("expected --delete-branch to require --delete, got %d: %s", code, errOut.String())
+	}
+}
+
+func TestRunLandCommandDeletesBranch(t *testing.T) {
+	root := landProject(t)
+	fs := &landingStubFilesystem{stubFilesystem: stubFilesystem{listing: core.WorktreeListing{Worktrees: []core.Worktree{
+		{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"},
+	}}}}
+
+	var out, errOut bytes.Buffer
+	args := []string{"--project", "api", "--worktree", "feature", "--delete", "--delete-branch", root}
+	code := runLandCommand(args, fs, &stubSessionManager{}, lifecycleHooks{}, core.ScanRules{}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if len(fs.deletedBranches) != 1 || fs.deletedBranches[0] != "feature" {
+		t.Fatalf("expected a safe branch delete, got %v", fs.deletedBranches)
+	}
+	if !strings.Contains(out.String(), "Deleted branch feature.") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestRunLandCommandKeepsUnmergedBranchAndWorktree(t *testing.T) {
+	root := landProject(t)
+	fs := &landingStubFilesystem{
+		stubFilesystem: stubFilesystem{listing: core.WorktreeListing{Worktrees: []core.Worktree{
+			{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"},
+		}}},
+		unmerged: true,
+	}
+
+	var out, errOut bytes.Buffer
+	args := []string{"--project", "api", "--worktree", "feature", "--strategy", "rebase", "--delete", "--delete-branch", root}
+	code := runLandCommand(args, fs, &stubSessionManager{}, lifecycleHooks{}, core.ScanRules{}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if len(fs.deleted) != 0 || len(fs.deletedBranches) != 0 {
+		t.Fatalf("expected nothing to be deleted, got %v and %v", fs.deleted, fs.deletedBranches)
+	}
+	if !strings.Contains(errOut.String(), "--force-delete-branch") {
+		t.Fatalf("expected a hint to force the delete, got %q", errOut.String())
+	}
+
+	out.Reset()
+	errOut.Reset()
+	args = []string{"--project", "api", "--worktree", "feature", "--strategy", "squash", "--delete", "--force-delete-branch", root}
+	if code := runLandCommand(args, fs, &stubSessionManager{}, lifecycleHooks{}, core.ScanRules{}, &out, &errOut); code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if len(fs.deletedBranches) != 1 || fs.deletedBranches[0] != "feature (forced)" {
+		t.Fatalf("expected a forced branch delete, got %v", fs.deletedBranches)
+	}
+}
+
+func TestRunLandCommandDeletesSquashedBranch(t *testing.T) {
+	root := landProject(t)
+	fs := &landingStubFilesystem{
+		stubFilesystem: stubFilesystem{listing: core.WorktreeListing{Worktrees: []core.Worktree{
+			{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"},
+		}}},
+		unmerged: true,
+	}
+
+	var out, errOut bytes.Buffer
+	args := []string{"--project", "api", "--worktree", "feature", "--strategy", "squash", "--delete", "--delete-branch", root}
+	code := runLandCommand(args, fs, &stubSessionManager{}, lifecycleHooks{}, core.ScanRules{}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if len(fs.deleted) != 1 || len(fs.deletedBranches) != 1 || fs.deletedBranches[0] != "feature (forced)" {
+		t.Fatalf("expected the squashed branch to be force deleted, got %v and %v", fs.deleted, fs.deletedBranches)
+	}
+}
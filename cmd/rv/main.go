@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,39 +14,84 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/ariguillegp/rivet/internal/adapters"
+	"github.com/ariguillegp/rivet/internal/config"
 	"github.com/ariguillegp/rivet/internal/core"
 	"github.com/ariguillegp/rivet/internal/ports"
 	"github.com/ariguillegp/rivet/internal/ui"
 )
 
+// defaultRoots are scanned when no directories are given.
+var defaultRoots = []string{"~/Projects"}
+
+// commands are the rv subcommands, named by the first argument after the
+// global flags. A root directory with one of these names is given as a path,
+// such as ./land.
+var commands = map[string]func(env commandEnv, args []string) int{
+	"hook": func(env commandEnv, args []string) int {
+		return runHookCommand(append([]string{"--config", env.configPath}, args...), adapters.NewShellHookRunner(), os.Stderr)
+	},
+	"land": func(env commandEnv, args []string) int {
+		return runLandCommand(args, env.filesystem(), env.sessionManager(), env.hooks(), env.rules, os.Stdout, os.Stderr)
+	},
+	"gc": func(env commandEnv, args []string) int {
+		return runGCCommand(args, env.filesystem(), env.sessionManager(), env.hooks(), env.cfg.InactiveFor(), env.rules.Depth(), os.Stdin, os.Stdout, os.Stderr)
+	},
+	"sessions": func(env commandEnv, args []string) int {
+		return runSessionsCommand(args, env.filesystem(), env.sessionManager(), env.rules.Depth(), os.Stdin, os.Stdout, os.Stderr)
+	},
+	"projects": func(env commandEnv, args []string) int {
+		return runProjectsCommand(args, env.filesystem(), env.rules.Depth(), env.cfg.TagRules(), os.Stdout, os.Stderr)
+	},
+	"recent": func(env commandEnv, args []string) int {
+		return runRecentCommand(args, env.sessionManager(), env.hooks(), os.Stdout, os.Stderr)
+	},
+}
+
 func main() {
+	var global globalOptions
 	var projectFlag string
 	var worktreeFlag string
 	var toolFlag string
 	var createProjectFlag bool
 	var detachFlag bool
-	flag.StringVar(&projectFlag, "project", "", "Project container name or path")
+	global.register(flag.CommandLine)
+	flag.StringVar(&projectFlag, "project", "", "Project container name, path, or repository URL to clone")
 	flag.StringVar(&worktreeFlag, "worktree", "", "Worktree name or path")
 	flag.StringVar(&toolFlag, "tool", "", "Tool to run (opencode, amp, claude, codex, or none)")
 	flag.BoolVar(&createProjectFlag, "create-project", false, "Create the project container if missing")
 	flag.BoolVar(&detachFlag, "detach", false, "Create the tmux session without attaching")
 	flag.Parse()
 
-	roots := flag.Args()
-	if len(roots) == 0 {
-		roots = []string{"~/Projects"}
+	env, err := global.load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if run, ok := commands[flag.Arg(0)]; ok {
+		os.Exit(run(env, flag.Args()[1:]))
 	}
-	roots = expandRoots(roots)
 
-	fs := adapters.NewOSFilesystem()
-	sessions := adapters.NewTmuxSession()
+	roots := rootsOrDefault(flag.Args())
+	cfg, rules := env.cfg, env.rules
+	fs := env.filesystem()
+	sessions := env.sessionManager()
+	hooks := env.hooks()
 
-	if projectFlag != "" || worktreeFlag != "" || toolFlag != "" || createProjectFlag || detachFlag {
-		spec, err := resolveSessionSpec(fs, roots, projectFlag, worktreeFlag, toolFlag, createProjectFlag, detachFlag)
+	var start core.StartState
+	if projectFlag != "" && toolFlag == "" && !detachFlag {
+		start, err = resolveStart(fs, hooks, roots, rules, projectFlag, worktreeFlag, createProjectFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if projectFlag != "" || worktreeFlag != "" || toolFlag != "" || createProjectFlag || detachFlag {
+		spec, err := resolveSessionSpec(fs, hooks, roots, rules, projectFlag, worktreeFlag, toolFlag, createProjectFlag, detachFlag)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		hooks.run(adapters.WorktreeHookPayload(core.HookSessionOpen, spec.DirPath, spec.Tool))
+		recordLaunch(sessions, spec)
 		if err := sessions.OpenSession(spec); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -52,10 +99,15 @@ func main() {
 		return
 	}
 
-	m := ui.New(roots, fs, sessions)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	m := ui.New(roots, fs, sessions, ui.WithMaxDepth(rules.Depth()), ui.WithHooks(hooks.hooks, hooks.runner), ui.WithInactiveFor(cfg.InactiveFor()), ui.WithKeyBindings(cfg.KeyBindings()), ui.WithStart(start), ui.WithTagRules(cfg.TagRules()))
+	programOpts := []tea.ProgramOption{tea.WithAltScreen()}
+	if cfg.MouseEnabled() {
+		programOpts = append(programOpts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, programOpts...)
 
 	result, err := p.Run()
+	_ = m.Close()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -67,6 +119,7 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		recordLaunch(sessions, *final.SelectedSpec)
 		if err := sessions.OpenSession(*final.SelectedSpec); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -106,7 +159,94 @@ func returnToPreviousSession() error {
 	return cmd.Run()
 }
 
-func resolveSessionSpec(fs ports.Filesystem, roots []string, project, worktree, tool string, createProject, detach bool) (core.SessionSpec, error) {
+// stringList collects the values of a repeatable flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// scanRules layers command-line options over the config file: --depth
+// replaces the configured depth, --ignore and --marker add to the lists.
+func scanRules(cfg config.Config, depth int, ignore, markers []string) core.ScanRules {
+	rules := cfg.ScanRules()
+	if depth > 0 {
+		rules.MaxDepth = depth
+	}
+	rules.Ignore = append(rules.Ignore, ignore...)
+	rules.Markers = append(rules.Markers, markers...)
+	return rules
+}
+
+// globalOptions are the flags shared by every command. They go before the
+// command name: rv --config PATH gc.
+type globalOptions struct {
+	configPath string
+	depth      int
+	ignore     stringList
+	markers    stringList
+}
+
+func (o *globalOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.configPath, "config", config.DefaultPath, "Path to the config file")
+	flags.IntVar(&o.depth, "depth", 0, "How many directory levels to scan below each root (default 2)")
+	flags.Var(&o.ignore, "ignore", "Gitignore-style pattern of directories to skip while scanning (repeatable)")
+	flags.Var(&o.markers, "marker", "File or directory that marks a project, in addition to .git (repeatable)")
+}
+
+// load reads the config file and applies the scan flags over it.
+func (o globalOptions) load() (commandEnv, error) {
+	cfg, err := config.Load(o.configPath)
+	if err != nil {
+		return commandEnv{}, err
+	}
+	return commandEnv{configPath: o.configPath, cfg: cfg, rules: scanRules(cfg, o.depth, o.ignore, o.markers)}, nil
+}
+
+// commandEnv is the configuration a command runs with.
+type commandEnv struct {
+	configPath string
+	cfg        config.Config
+	rules      core.ScanRules
+}
+
+func (env commandEnv) filesystem() *adapters.OSFilesystem {
+	fs := adapters.NewOSFilesystem(env.rules)
+	fs.SetPostCreateCommands(env.cfg.PostCreateCommands())
+	return fs
+}
+
+// sessionManager returns the tmux session manager every command opens
+// sessions with, reporting agent exits to `rv hook` when hooks need them.
+func (env commandEnv) sessionManager() *adapters.TmuxSession {
+	sessions := adapters.NewTmuxSession()
+	if env.cfg.HookRegistry().Has(core.HookAgentExit) {
+		if exe, err := os.Executable(); err == nil {
+			sessions.SetAgentExitCommand([]string{exe, "hook", "--config", env.configPath, "--event", string(core.HookAgentExit)})
+		}
+	}
+	return sessions
+}
+
+func (env commandEnv) hooks() lifecycleHooks {
+	return lifecycleHooks{hooks: env.cfg.HookRegistry(), runner: adapters.NewShellHookRunner(), out: os.Stderr}
+}
+
+// rootsOrDefault expands the directories given on the command line, or the
+// default roots when there are none.
+func rootsOrDefault(dirs []string) []string {
+	if len(dirs) == 0 {
+		dirs = defaultRoots
+	}
+	return expandRoots(dirs)
+}
+
+func resolveSessionSpec(fs ports.Filesystem, hooks lifecycleHooks, roots []string, rules core.ScanRules, project, worktree, tool string, createProject, detach bool) (core.SessionSpec, error) {
 	if project == "" {
 		return core.SessionSpec{}, errors.New("--project is required")
 	}
@@ -120,12 +260,12 @@ func resolveSessionSpec(fs ports.Filesystem, roots []string, project, worktree,
 		return core.SessionSpec{}, fmt.Errorf("unsupported tool: %s", tool)
 	}
 
-	projectPath, err := resolveProjectPath(fs, roots, project, createProject)
+	projectPath, err := resolveProjectPath(fs, hooks, roots, rules, project, createProject)
 	if err != nil {
 		return core.SessionSpec{}, err
 	}
 
-	worktreePath, err := resolveWorktreePath(fs, projectPath, worktree)
+	worktreePath, err := resolveWorktreePath(fs, hooks, projectPath, worktree)
 	if err != nil {
 		return core.SessionSpec{}, err
 	}
@@ -133,7 +273,34 @@ func resolveSessionSpec(fs ports.Filesystem, roots []string, project, worktree,
 	return core.SessionSpec{DirPath: worktreePath, Tool: tool, Detach: detach}, nil
 }
 
-func resolveProjectPath(fs ports.Filesystem, roots []string, project string, createProject bool) (string, error) {
+// resolveStart picks the step the TUI opens at when --project is given
+// without --tool: Step 3 for a worktree that exists, otherwise Step 2 with
+// the worktree name as its filter.
+func resolveStart(fs ports.Filesystem, hooks lifecycleHooks, roots []string, rules core.ScanRules, project, worktree string, createProject bool) (core.StartState, error) {
+	projectPath, err := resolveProjectPath(fs, hooks, roots, rules, project, createProject)
+	if err != nil {
+		return core.StartState{}, err
+	}
+	start := core.StartState{ProjectPath: projectPath}
+	if worktree == "" {
+		return start, nil
+	}
+	path, found, err := findWorktreePath(fs, projectPath, worktree)
+	if err != nil {
+		return core.StartState{}, err
+	}
+	if found {
+		start.WorktreePath = path
+	} else {
+		start.WorktreeQuery = worktree
+	}
+	return start, nil
+}
+
+func resolveProjectPath(fs ports.Filesystem, hooks lifecycleHooks, roots []string, rules core.ScanRules, project string, createProject bool) (string, error) {
+	if core.IsCloneSource(project) || (looksLikePath(project) && isBareRepository(fs, project)) {
+		return resolveClonedProject(fs, hooks, roots, project, createProject)
+	}
 	if looksLikePath(project) {
 		path := expandPath(project)
 		if path == "" {
@@ -152,6 +319,7 @@ func resolveProjectPath(fs ports.Filesystem, roots []string, project string, cre
 				if err != nil {
 					return "", err
 				}
+				hooks.run(core.HookPayload{Event: core.HookProjectCreate, ProjectPath: createdPath})
 				return createdPath, nil
 			}
 			return "", fmt.Errorf("project not found: %s", project)
@@ -159,10 +327,24 @@ func resolveProjectPath(fs ports.Filesystem, roots []string, project string, cre
 		return path, nil
 	}
 
-	for _, root := range roots {
-		candidate := filepath.Join(expandPath(root), project)
-		if exists(candidate) {
-			return candidate, nil
+	if !rules.Ignored(project) {
+		for _, root := range roots {
+			candidate := filepath.Join(expandPath(root), project)
+			if exists(candidate) {
+				return candidate, nil
+			}
+		}
+	}
+
+	// Projects nested deeper than a root's direct children are found by name
+	// with the same rules as the project list.
+	dirs, err := fs.ScanDirs(roots, rules.Depth())
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		if dir.Name == project {
+			return dir.Path, nil
 		}
 	}
 
@@ -175,43 +357,140 @@ func resolveProjectPath(fs ports.Filesystem, roots []string, project string, cre
 		if err != nil {
 			return "", err
 		}
+		hooks.run(core.HookPayload{Event: core.HookProjectCreate, ProjectPath: createdPath})
 		return createdPath, nil
 	}
 
 	return "", fmt.Errorf("project not found: %s", project)
 }
 
-func resolveWorktreePath(fs ports.Filesystem, projectPath, worktree string) (string, error) {
+// resolveClonedProject returns the clone of source in the first root,
+// cloning it first when createProject is set. A directory of the same name
+// cloned from elsewhere is an error rather than a match.
+func resolveClonedProject(fs ports.Filesystem, hooks lifecycleHooks, roots []string, source string, createProject bool) (string, error) {
+	name := core.CloneProjectName(source)
+	if name == "" {
+		return "", fmt.Errorf("cannot derive a project name from %s", source)
+	}
+	if len(roots) == 0 {
+		return "", fmt.Errorf("no roots available to clone project")
+	}
+	cloner, ok := fs.(ports.ProjectCloner)
+	if !ok {
+		return "", fmt.Errorf("cloning repositories is not supported")
+	}
+	target := filepath.Join(expandPath(roots[0]), name)
+	if exists(target) {
+		if !cloner.IsCloneOf(target, source) {
+			return "", fmt.Errorf("%s already exists and is not a clone of %s", target, source)
+		}
+		return target, nil
+	}
+	if !createProject {
+		return "", fmt.Errorf("project not found: %s", source)
+	}
+	printer := &cloneProgressPrinter{w: os.Stderr}
+	clonedPath, err := cloner.CloneProject(context.Background(), source, target, printer.report)
+	printer.finish()
+	if err != nil {
+		return "", err
+	}
+	hooks.run(core.HookPayload{Event: core.HookProjectCreate, ProjectPath: clonedPath})
+	return clonedPath, nil
+}
+
+// cloneProgressPrinter redraws git's progress on one line per phase.
+type cloneProgressPrinter struct {
+	w     io.Writer
+	phase string
+}
+
+func (p *cloneProgressPrinter) report(progress core.CloneProgress) {
+	if p.phase != "" && progress.Phase != p.phase {
+		_, _ = fmt.Fprintln(p.w)
+	}
+	p.phase = progress.Phase
+	if progress.Percent > 0 {
+		_, _ = fmt.Fprintf(p.w, "\r%s: %d%%", progress.Phase, progress.Percent)
+		return
+	}
+	_, _ = fmt.Fprintf(p.w, "\r%s", progress.Phase)
+}
+
+func (p *cloneProgressPrinter) finish() {
+	if p.phase != "" {
+		_, _ = fmt.Fprintln(p.w)
+	}
+}
+
+func resolveWorktreePath(fs ports.Filesystem, hooks lifecycleHooks, projectPath, worktree string) (string, error) {
+	path, found, err := findWorktreePath(fs, projectPath, worktree)
+	if err != nil || found {
+		return path, err
+	}
+
+	hooks.run(core.HookPayload{Event: core.HookPreWorktreeCreate, ProjectPath: projectPath, Branch: worktree})
+	worktreePath, err := fs.CreateWorktree(projectPath, worktree)
+	if err != nil {
+		return "", err
+	}
+	setupWorktree(fs, projectPath, worktreePath, os.Stderr)
+	hooks.run(core.HookPayload{
+		Event:        core.HookPostWorktreeCreate,
+		ProjectPath:  projectPath,
+		WorktreePath: worktreePath,
+		Branch:       worktree,
+	})
+	return worktreePath, nil
+}
+
+// findWorktreePath looks up an existing worktree by path, branch or name.
+// A path that does not exist is an error; an unknown name is not found.
+func findWorktreePath(fs ports.Filesystem, projectPath, worktree string) (string, bool, error) {
 	if looksLikePath(worktree) {
 		path := expandPath(worktree)
 		if !filepath.IsAbs(path) {
 			absPath, err := filepath.Abs(path)
 			if err != nil {
-				return "", fmt.Errorf("cannot resolve path: %w", err)
+				return "", false, fmt.Errorf("cannot resolve path: %w", err)
 			}
 			path = absPath
 		}
 		if exists(path) {
-			return path, nil
+			return path, true, nil
 		}
-		return "", fmt.Errorf("worktree not found: %s", worktree)
+		return "", false, fmt.Errorf("worktree not found: %s", worktree)
 	}
 
 	listing, err := fs.ListWorktrees(projectPath)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	if listing.Warning != "" {
-		return "", fmt.Errorf("%s", listing.Warning)
+		return "", false, fmt.Errorf("%s", listing.Warning)
 	}
 
 	for _, wt := range listing.Worktrees {
 		if wt.Branch == worktree || wt.Name == worktree {
-			return wt.Path, nil
+			return wt.Path, true, nil
 		}
 	}
+	return "", false, nil
+}
 
-	return fs.CreateWorktree(projectPath, worktree)
+// setupWorktree runs the project's setup for a new worktree, echoing its
+// output. Setup failures are warnings: the worktree is usable either way.
+func setupWorktree(fs ports.Filesystem, projectPath, worktreePath string, out io.Writer) {
+	preparer, ok := fs.(ports.WorktreePreparer)
+	if !ok {
+		return
+	}
+	err := preparer.SetupWorktree(context.Background(), projectPath, worktreePath, func(line string) {
+		_, _ = fmt.Fprintln(out, line)
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Warning: workspace setup: %v\n", err)
+	}
 }
 
 func expandRoots(roots []string) []string {
@@ -248,6 +527,13 @@ func looksLikePath(s string) bool {
 		strings.Contains(s, string(filepath.Separator))
 }
 
+// isBareRepository reports whether path is a local bare repository, which
+// is cloned like a URL rather than opened as a project.
+func isBareRepository(fs ports.Filesystem, path string) bool {
+	cloner, ok := fs.(ports.ProjectCloner)
+	return ok && cloner.IsBareRepository(path)
+}
+
 func exists(path string) bool {
 	info, err := os.Stat(path)
 	if err != nil {
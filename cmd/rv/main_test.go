@@ -1,11 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/ariguillegp/rivet/internal/adapters"
+	"github.com/ariguillegp/rivet/internal/config"
 	"github.com/ariguillegp/rivet/internal/core"
 )
 
@@ -89,17 +97,17 @@ func (s *stubFilesystem) PruneWorktrees(string) error {
 func TestResolveSessionSpecRequiresFlags(t *testing.T) {
 	fs := &stubFilesystem{}
 
-	_, err := resolveSessionSpec(fs, nil, "", "main", "amp", false, false)
+	_, err := resolveSessionSpec(fs, lifecycleHooks{}, nil, core.ScanRules{}, "", "main", "amp", false, false)
 	if err == nil || err.Error() != "--project is required" {
 		t.Fatalf("expected missing project error, got %v", err)
 	}
 
-	_, err = resolveSessionSpec(fs, nil, "demo", "", "amp", false, false)
+	_, err = resolveSessionSpec(fs, lifecycleHooks{}, nil, core.ScanRules{}, "demo", "", "amp", false, false)
 	if err == nil || err.Error() != "--worktree is required" {
 		t.Fatalf("expected missing worktree error, got %v", err)
 	}
 
-	_, err = resolveSessionSpec(fs, nil, "demo", "main", "", false, false)
+	_, err = resolveSessionSpec(fs, lifecycleHooks{}, nil, core.ScanRules{}, "demo", "main", "", false, false)
 	if err == nil || err.Error() != "--tool is required" {
 		t.Fatalf("expected missing tool error, got %v", err)
 	}
@@ -108,7 +116,7 @@ func TestResolveSessionSpecRequiresFlags(t *testing.T) {
 func TestResolveSessionSpecRejectsUnsupportedTool(t *testing.T) {
 	fs := &stubFilesystem{}
 
-	_, err := resolveSessionSpec(fs, nil, "demo", "main", "invalid", false, false)
+	_, err := resolveSessionSpec(fs, lifecycleHooks{}, nil, core.ScanRules{}, "demo", "main", "invalid", false, false)
 	if err == nil || !strings.Contains(err.Error(), "unsupported tool") {
 		t.Fatalf("expected unsupported tool error, got %v", err)
 	}
@@ -130,7 +138,7 @@ func TestResolveSessionSpecResolvesNamedProjectAndWorktree(t *testing.T) {
 		},
 	}
 
-	spec, err := resolveSessionSpec(fs, []string{root}, "demo", "feature", "amp", false, true)
+	spec, err := resolveSessionSpec(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, "demo", "feature", "amp", false, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -157,7 +165,7 @@ func TestResolveSessionSpecCreatesProjectAndWorktreeWhenMissing(t *testing.T) {
 		createWorktreePath: worktreePath,
 	}
 
-	spec, err := resolveSessionSpec(fs, []string{root}, "demo", "feature-a", "codex", true, false)
+	spec, err := resolveSessionSpec(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, "demo", "feature-a", "codex", true, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -175,12 +183,57 @@ func TestResolveSessionSpecCreatesProjectAndWorktreeWhenMissing(t *testing.T) {
 	}
 }
 
+func TestResolveStartOpensWorktreeOrToolStep(t *testing.T) {
+	root := t.TempDir()
+	projectPath := filepath.Join(root, "demo")
+	if err := os.MkdirAll(projectPath, 0o755); err != nil {
+		t.Fatalf("failed to create project path: %v", err)
+	}
+	worktreePath := filepath.Join(root, "worktrees", "feature")
+	fs := &stubFilesystem{
+		listing: core.WorktreeListing{
+			Worktrees: []core.Worktree{{Path: worktreePath, Name: "feature", Branch: "feature"}},
+		},
+	}
+
+	start, err := resolveStart(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, "demo", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start != (core.StartState{ProjectPath: projectPath}) {
+		t.Fatalf("expected Step 2 for the project, got %+v", start)
+	}
+
+	start, err = resolveStart(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, "demo", "feature", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start.WorktreePath != worktreePath {
+		t.Fatalf("expected Step 3 for the worktree, got %+v", start)
+	}
+
+	start, err = resolveStart(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, "demo", "bugfix", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start.WorktreePath != "" || start.WorktreeQuery != "bugfix" {
+		t.Fatalf("expected Step 2 filtered by the unknown worktree, got %+v", start)
+	}
+	if len(fs.createWorktreeCalls) != 0 {
+		t.Fatalf("did not expect a worktree to be created, got %d calls", len(fs.createWorktreeCalls))
+	}
+
+	if _, err := resolveStart(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, "missing", "", false); err == nil {
+		t.Fatal("expected an unknown project to be an error")
+	}
+}
+
 func TestResolveProjectPathCreatesMissingPathLikeProject(t *testing.T) {
 	root := t.TempDir()
 	projectPath := filepath.Join(root, "new-project")
 	fs := &stubFilesystem{createProjectPath: projectPath}
 
-	resolved, err := resolveProjectPath(fs, nil, projectPath, true)
+	resolved, err := resolveProjectPath(fs, lifecycleHooks{}, nil, core.ScanRules{}, projectPath, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -197,12 +250,33 @@ func TestResolveWorktreePathReturnsListingWarning(t *testing.T) {
 		listing: core.WorktreeListing{Warning: "Project has no repository. Create a project first."},
 	}
 
-	_, err := resolveWorktreePath(fs, "/projects/demo", "feature")
+	_, err := resolveWorktreePath(fs, lifecycleHooks{}, "/projects/demo", "feature")
 	if err == nil || !strings.Contains(err.Error(), "Project has no repository") {
 		t.Fatalf("expected warning error, got %v", err)
 	}
 }
 
+type preparingStubFilesystem struct {
+	*stubFilesystem
+	err error
+}
+
+func (p preparingStubFilesystem) SetupWorktree(_ context.Context, _, _ string, output func(string)) error {
+	output("copied .env")
+	return p.err
+}
+
+func TestSetupWorktreeReportsFailureAsWarning(t *testing.T) {
+	var out bytes.Buffer
+	fs := preparingStubFilesystem{stubFilesystem: &stubFilesystem{}, err: errors.New(`post-create hook "make" failed: exit status 2`)}
+
+	setupWorktree(fs, "/projects/demo", "/projects/demo/.rivet/demo--feature", &out)
+	want := "copied .env\nWarning: workspace setup: post-create hook \"make\" failed: exit status 2\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
+
 func TestExpandRootsExpandsHomePrefix(t *testing.T) {
 	home, err := os.UserHomeDir()
 	if err != nil || strings.TrimSpace(home) == "" {
@@ -307,3 +381,161 @@ func writeExecutable(t *testing.T, path, content string) {
 		t.Fatalf("failed to write executable %s: %v", path, err)
 	}
 }
+
+func TestResolveProjectPathFindsNestedProjectByName(t *testing.T) {
+	root := t.TempDir()
+	fs := &scanningFilesystem{dirs: []core.DirEntry{{Path: filepath.Join(root, "mono", "services", "api"), Name: "api"}}}
+
+	resolved, err := resolveProjectPath(fs, lifecycleHooks{}, []string{root}, core.ScanRules{MaxDepth: 3}, "api", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved != filepath.Join(root, "mono", "services", "api") {
+		t.Fatalf("expected nested project path, got %q", resolved)
+	}
+}
+
+func TestResolveProjectPathSkipsIgnoredDirectories(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "bazel-out"), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	fs := &stubFilesystem{}
+
+	_, err := resolveProjectPath(fs, lifecycleHooks{}, []string{root}, core.ScanRules{Ignore: []string{"bazel-*"}}, "bazel-out", false)
+	if err == nil || !strings.Contains(err.Error(), "project not found") {
+		t.Fatalf("expected ignored directory to be rejected, got %v", err)
+	}
+}
+
+func TestScanRulesLayersFlagsOverConfig(t *testing.T) {
+	cfg := config.Config{Scan: config.ScanConfig{Depth: 3, Ignore: []string{"bazel-*"}, Markers: []string{".jj"}}}
+
+	rules := scanRules(cfg, 0, []string{"dist"}, []string{"go.work"})
+	if rules.Depth() != 3 {
+		t.Fatalf("expected configured depth, got %d", rules.Depth())
+	}
+	if strings.Join(rules.Ignore, ",") != "bazel-*,dist" || strings.Join(rules.Markers, ",") != ".jj,go.work" {
+		t.Fatalf("expected flags to extend config lists, got %+v", rules)
+	}
+
+	rules = scanRules(cfg, 4, nil, nil)
+	if rules.Depth() != 4 {
+		t.Fatalf("expected --depth to override config, got %d", rules.Depth())
+	}
+}
+
+func TestGlobalOptionsApplyBeforeCommandName(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte("[scan]\ndepth = 3\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	var global globalOptions
+	flags := flag.NewFlagSet("rv", flag.ContinueOnError)
+	global.register(flags)
+	if err := flags.Parse([]string{"--config", configPath, "--ignore", "dist", "gc", "--yes"}); err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if _, ok := commands[flags.Arg(0)]; !ok || strings.Join(flags.Args()[1:], " ") != "--yes" {
+		t.Fatalf("expected the gc command with its own flags, got %v", flags.Args())
+	}
+	env, err := global.load()
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if env.rules.Depth() != 3 || !slices.Contains(env.rules.Ignore, "dist") {
+		t.Fatalf("expected the config file and scan flags to reach the command, got %+v", env.rules)
+	}
+
+	if roots := rootsOrDefault(nil); len(roots) != 1 || roots[0] != expandPath(defaultRoots[0]) {
+		t.Fatalf("expected the default roots, got %v", roots)
+	}
+}
+
+func initBareRepo(t *testing.T, path string) {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "src")
+	for _, args := range [][]string{
+		{"init", "-b", "main", src},
+		{"-C", src, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "--allow-empty", "-m", "initial commit"},
+		{"clone", "--bare", src, path},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+}
+
+func TestResolveProjectPathClonesRepositoryIntoFirstRoot(t *testing.T) {
+	root := t.TempDir()
+	bare := filepath.Join(t.TempDir(), "service.git")
+	initBareRepo(t, bare)
+	fs := adapters.NewOSFilesystem(core.ScanRules{})
+
+	if _, err := resolveProjectPath(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, bare, false); err == nil {
+		t.Fatal("expected an error without --create-project")
+	}
+
+	resolved, err := resolveProjectPath(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, bare, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := filepath.Join(root, "service")
+	if resolved != want {
+		t.Fatalf("expected %q, got %q", want, resolved)
+	}
+	if _, err := os.Stat(filepath.Join(want, ".git")); err != nil {
+		t.Fatalf("expected a clone at %q: %v", want, err)
+	}
+
+	// A second run reuses the existing clone.
+	resolved, err = resolveProjectPath(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, bare, false)
+	if err != nil || resolved != want {
+		t.Fatalf("expected existing clone %q, got %q, %v", want, resolved, err)
+	}
+}
+
+func TestResolveProjectPathClonesBareRepositoryWithoutSuffix(t *testing.T) {
+	root := t.TempDir()
+	bare := filepath.Join(t.TempDir(), "mirror")
+	initBareRepo(t, bare)
+	fs := adapters.NewOSFilesystem(core.ScanRules{})
+
+	resolved, err := resolveProjectPath(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, bare, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved != filepath.Join(root, "mirror") {
+		t.Fatalf("expected clone in root, got %q", resolved)
+	}
+}
+
+func TestResolveProjectPathRejectsProjectClonedFromElsewhere(t *testing.T) {
+	root := t.TempDir()
+	bare := filepath.Join(t.TempDir(), "service.git")
+	initBareRepo(t, bare)
+	other := filepath.Join(t.TempDir(), "other", "service.git")
+	initBareRepo(t, other)
+	if output, err := exec.Command("git", "clone", other, filepath.Join(root, "service")).CombinedOutput(); err != nil {
+		t.Fatalf("git clone failed: %v: %s", err, output)
+	}
+	fs := adapters.NewOSFilesystem(core.ScanRules{})
+
+	_, err := resolveProjectPath(fs, lifecycleHooks{}, []string{root}, core.ScanRules{}, bare, true)
+	if err == nil || !strings.Contains(err.Error(), "is not a clone of") {
+		t.Fatalf("expected a clone of another origin to be rejected, got %v", err)
+	}
+}
+
+func TestResolveProjectPathRejectsCloneWithoutCloner(t *testing.T) {
+	fs := &stubFilesystem{}
+
+	_, err := resolveProjectPath(fs, lifecycleHooks{}, []string{t.TempDir()}, core.ScanRules{}, "https://example.com/org/service.git", true)
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("expected unsupported clone error, got %v", err)
+	}
+	if len(fs.createProjectCalls) != 0 {
+		t.Fatalf("expected no git init for a clone source, got %v", fs.createProjectCalls)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+// runProjectsCommand implements `rv projects`, which lists the projects under
+// the given roots, optionally only those carrying every --tag.
+func runProjectsCommand(args []string, fs ports.Filesystem, scanDepth int, tagRules core.TagRules, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("projects", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	var tags stringList
+	flags.Var(&tags, "tag", "Only list projects with this tag (repeatable)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	roots := rootsOrDefault(flags.Args())
+
+	dirs, err := listProjects(fs, roots, scanDepth, tagRules, core.ParseTags(strings.Join(tags, ",")))
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, dir := range dirs {
+		var labels []string
+		for _, tag := range dir.Tags {
+			labels = append(labels, "#"+tag)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", dir.Name, dir.Path, strings.Join(labels, " "))
+	}
+	_ = w.Flush()
+	return 0
+}
+
+// listProjects scans roots and returns the projects carrying every tag in
+// tags, with their tags from the config rules and the saved tags.
+func listProjects(fs ports.Filesystem, roots []string, scanDepth int, tagRules core.TagRules, tags []string) ([]core.DirEntry, error) {
+	dirs, err := fs.ScanDirs(roots, scanDepth)
+	if err != nil {
+		return nil, err
+	}
+	var saved map[string][]string
+	if store, ok := fs.(ports.TagStore); ok {
+		// Saved tags are optional; the config rules still apply without them.
+		saved, _ = store.ProjectTags()
+	}
+	dirs = core.TagDirs(dirs, tagRules, saved)
+	if len(tags) == 0 {
+		return dirs, nil
+	}
+	query := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		query = append(query, core.TagPrefix+tag)
+	}
+	return core.FilterDirs(dirs, strings.Join(query, " ")), nil
+}
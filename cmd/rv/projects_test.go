@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+type taggedFilesystem struct {
+	scanningFilesystem
+	tags map[string][]string
+}
+
+func (t *taggedFilesystem) ProjectTags() (map[string][]string, error) { return t.tags, nil }
+
+func (t *taggedFilesystem) SetProjectTags(string, []string) error { return nil }
+
+func TestProjectsCommandFiltersByTag(t *testing.T) {
+	fs := &taggedFilesystem{
+		scanningFilesystem: scanningFilesystem{dirs: []core.DirEntry{
+			{Path: "/projects/api", Name: "api"},
+			{Path: "/projects/web", Name: "web"},
+			{Path: "/projects/billing", Name: "billing"},
+		}},
+		tags: map[string][]string{"/projects/billing": {"backend", "payments"}},
+	}
+	rules := core.TagRules{"backend": {"api"}}
+
+	var out, errOut bytes.Buffer
+	code := runProjectsCommand([]string{"--tag", "backend", "/projects"}, fs, 2, rules, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two backend projects, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[0], "api") || !strings.Contains(lines[0], "#backend") {
+		t.Fatalf("expected api tagged by the config rule, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "billing") || !strings.Contains(lines[1], "#payments") {
+		t.Fatalf("expected billing with its saved tags, got %q", lines[1])
+	}
+}
+
+func TestProjectsCommandListsAllWithoutTag(t *testing.T) {
+	fs := &scanningFilesystem{dirs: []core.DirEntry{{Path: "/projects/api", Name: "api"}, {Path: "/projects/web", Name: "web"}}}
+
+	var out, errOut bytes.Buffer
+	if code := runProjectsCommand([]string{"/projects"}, fs, 2, nil, &out, &errOut); code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, errOut.String())
+	}
+	if got := strings.Count(out.String(), "\n"); got != 2 {
+		t.Fatalf("expected every project listed, got %q", out.String())
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+var errNoLaunchHistory = errors.New("recent workspaces are not supported")
+
+func runRecentCommand(args []string, sessions ports.SessionManager, hooks lifecycleHooks, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("recent", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	open := flags.Int("open", 0, "Reopen the Nth recent workspace")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		_, _ = fmt.Fprintln(errOut, "Usage: rv recent [--open N]")
+		return 2
+	}
+
+	history, ok := sessions.(ports.LaunchHistory)
+	if !ok {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", errNoLaunchHistory)
+		return 1
+	}
+	launches, err := history.RecentLaunches()
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+
+	if *open == 0 {
+		listRecentLaunches(sessions, launches, out)
+		return 0
+	}
+	if *open < 1 || *open > len(launches) {
+		_, _ = fmt.Fprintf(errOut, "Error: no recent workspace %d\n", *open)
+		return 1
+	}
+	launch := launches[*open-1]
+	spec := launch.Spec()
+	hooks.run(core.HookPayload{
+		Event:        core.HookSessionOpen,
+		ProjectPath:  launch.ProjectPath,
+		WorktreePath: launch.WorktreePath,
+		Branch:       launch.Branch,
+		Tool:         launch.Tool,
+	})
+	recordLaunch(sessions, spec)
+	if err := sessions.OpenSession(spec); err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func listRecentLaunches(sessions ports.SessionManager, launches []core.Launch, out io.Writer) {
+	if len(launches) == 0 {
+		_, _ = fmt.Fprintln(out, "No recent workspaces.")
+		return
+	}
+	// Without sessions, every launch shows as stopped.
+	running, _ := sessions.ListSessions()
+	for i, recent := range core.MarkAlive(launches, running) {
+		state := "stopped"
+		if recent.Alive {
+			state = "running"
+		}
+		_, _ = fmt.Fprintf(out, "%d. %s  %s  %s  %s\n", i+1, recent.Label(), recent.Tool, recent.OpenedAt.Local().Format("2006-01-02 15:04"), state)
+	}
+}
+
+// recordLaunch adds spec to the launch history when the session manager
+// keeps one. The history is a convenience, so failures are ignored.
+func recordLaunch(sessions ports.SessionManager, spec core.SessionSpec) {
+	if history, ok := sessions.(ports.LaunchHistory); ok {
+		_ = history.RecordLaunch(spec)
+	}
+}
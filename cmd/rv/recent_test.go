@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+type historySessionManager struct {
+	stubSessionManager
+	launches []core.Launch
+	running  []core.SessionInfo
+	recorded []core.SessionSpec
+	opened   []core.SessionSpec
+}
+
+func (s *historySessionManager) ListSessions() ([]core.SessionInfo, error) { return s.running, nil }
+
+func (s *historySessionManager) OpenSession(spec core.SessionSpec) error {
+	s.opened = append(s.opened, spec)
+	return nil
+}
+
+func (s *historySessionManager) RecentLaunches() ([]core.Launch, error) { return s.launches, nil }
+
+func (s *historySessionManager) RecordLaunch(spec core.SessionSpec) error {
+	s.recorded = append(s.recorded, spec)
+	return nil
+}
+
+func recentSessions() *historySessionManager {
+	openedAt := time.Date(2026, 10, 18, 9, 30, 0, 0, time.Local)
+	return &historySessionManager{
+		launches: []core.Launch{
+			{ProjectPath: "/projects/api", WorktreePath: "/projects/api/.worktrees/fix", Branch: "fix", Tool: "amp", OpenedAt: openedAt},
+			{ProjectPath: "/projects/web", WorktreePath: "/projects/web", Branch: "main", Tool: "claude", OpenedAt: openedAt},
+		},
+		running: []core.SessionInfo{{Name: "web", DirPath: "/projects/web"}},
+	}
+}
+
+func TestRecentCommandListsLaunches(t *testing.T) {
+	sessions := recentSessions()
+	var out, errOut bytes.Buffer
+
+	if code := runRecentCommand(nil, sessions, lifecycleHooks{}, &out, &errOut); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (%s)", code, errOut.String())
+	}
+	want := "1. api/fix  amp  2026-10-18 09:30  stopped\n2. web/main  claude  2026-10-18 09:30  running\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestRecentCommandOpensNthLaunch(t *testing.T) {
+	sessions := recentSessions()
+	var out, errOut bytes.Buffer
+
+	if code := runRecentCommand([]string{"--open", "2"}, sessions, lifecycleHooks{}, &out, &errOut); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (%s)", code, errOut.String())
+	}
+	want := core.SessionSpec{DirPath: "/projects/web", Tool: "claude"}
+	if len(sessions.opened) != 1 || sessions.opened[0] != want {
+		t.Fatalf("expected %+v to be opened, got %+v", want, sessions.opened)
+	}
+	if len(sessions.recorded) != 1 || sessions.recorded[0] != want {
+		t.Fatalf("expected %+v to be recorded, got %+v", want, sessions.recorded)
+	}
+}
+
+func TestRecentCommandRejectsMissingLaunch(t *testing.T) {
+	sessions := recentSessions()
+	var out, errOut bytes.Buffer
+
+	if code := runRecentCommand([]string{"--open", "3"}, sessions, lifecycleHooks{}, &out, &errOut); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), "no recent workspace 3") {
+		t.Fatalf("unexpected error output: %q", errOut.String())
+	}
+	if len(sessions.opened) != 0 {
+		t.Fatalf("expected no session to be opened, got %+v", sessions.opened)
+	}
+}
+
+func TestRecentSessionManagerReportsAgentExit(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte("[hooks]\nagent_exit = [\"./notify.sh\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(tmpDir, "tmux.log")
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), "#!/bin/sh\necho \"$@\" >> \""+logPath+"\"\nexit 0\n")
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", tmpDir)
+
+	env, err := globalOptions{configPath: configPath}.load()
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if err := env.sessionManager().OpenSession(core.SessionSpec{DirPath: tmpDir, Tool: "claude", Detach: true}); err != nil {
+		t.Fatalf("unexpected open error: %v", err)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read tmux log: %v", err)
+	}
+	if !strings.Contains(string(content), "'hook' '--config' '"+configPath+"' '--event' 'agent_exit'") {
+		t.Fatalf("expected tool windows to report agent exits, got log:\n%s", content)
+	}
+}
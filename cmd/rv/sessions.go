@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+type migrateOptions struct {
+	action    core.SessionMigrationAction
+	dryRun    bool
+	scanDepth int
+}
+
+func runSessionsCommand(args []string, fs ports.Filesystem, sessions ports.SessionManager, scanDepth int, in io.Reader, out, errOut io.Writer) int {
+	if len(args) == 0 || args[0] != "migrate" {
+		_, _ = fmt.Fprintln(errOut, "Usage: rv sessions migrate [--rename | --adopt] [--dry-run] [directories...]")
+		return 2
+	}
+
+	flags := flag.NewFlagSet("sessions migrate", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	rename := flags.Bool("rename", false, "Rename every legacy session into the current naming scheme")
+	adopt := flags.Bool("adopt", false, "Keep every legacy session name and adopt it for its worktree")
+	dryRun := flags.Bool("dry-run", false, "Report legacy sessions without changing them")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *rename && *adopt {
+		_, _ = fmt.Fprintln(errOut, "Error: --rename and --adopt are mutually exclusive")
+		return 2
+	}
+
+	opts := migrateOptions{dryRun: *dryRun, scanDepth: scanDepth}
+	switch {
+	case *rename:
+		opts.action = core.SessionMigrationRename
+	case *adopt:
+		opts.action = core.SessionMigrationAdopt
+	}
+
+	roots := rootsOrDefault(flags.Args())
+
+	if err := migrateSessions(fs, sessions, roots, opts, in, out); err != nil {
+		_, _ = fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// migrateSessions finds sessions for known worktrees that use a legacy name
+// and renames or adopts them, prompting when no action was chosen up front.
+func migrateSessions(fs ports.Filesystem, sessions ports.SessionManager, roots []string, opts migrateOptions, in io.Reader, out io.Writer) error {
+	worktreePaths, err := knownWorktreePaths(fs, roots, opts.scanDepth)
+	if err != nil {
+		return err
+	}
+	legacy, err := sessions.FindLegacySessions(worktreePaths)
+	if err != nil {
+		return err
+	}
+	if len(legacy) == 0 {
+		_, _ = fmt.Fprintln(out, "No legacy sessions found.")
+		return nil
+	}
+
+	reader := bufio.NewReader(in)
+	migrated := 0
+	var failures []error
+	for _, migration := range legacy {
+		if opts.dryRun {
+			_, _ = fmt.Fprintf(out, "legacy %s (%s) -> %s\n", migration.Name, migration.DirPath, migration.CurrentName)
+			continue
+		}
+
+		action := opts.action
+		if action == "" {
+			action, err = promptMigrationAction(reader, out, migration)
+			if err != nil {
+				return err
+			}
+		}
+		if action == "" {
+			_, _ = fmt.Fprintf(out, "skipped %s\n", migration.Name)
+			continue
+		}
+
+		migration.Action = action
+		if err := sessions.MigrateSession(migration); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", migration.Name, err))
+			_, _ = fmt.Fprintf(out, "failed %s: %v\n", migration.Name, err)
+			continue
+		}
+		migrated++
+		switch action {
+		case core.SessionMigrationRename:
+			_, _ = fmt.Fprintf(out, "renamed %s -> %s\n", migration.Name, migration.CurrentName)
+		case core.SessionMigrationAdopt:
+			_, _ = fmt.Fprintf(out, "adopted %s for %s\n", migration.Name, migration.DirPath)
+		}
+	}
+
+	if opts.dryRun {
+		_, _ = fmt.Fprintf(out, "Found %d legacy sessions.\n", len(legacy))
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "Migrated %d of %d legacy sessions.\n", migrated, len(legacy))
+	return errors.Join(failures...)
+}
+
+func promptMigrationAction(reader *bufio.Reader, out io.Writer, migration core.SessionMigration) (core.SessionMigrationAction, error) {
+	for {
+		_, _ = fmt.Fprintf(out, "%s (%s) -> %s: [r]ename, [a]dopt, [s]kip? ", migration.Name, migration.DirPath, migration.CurrentName)
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "r", "rename":
+			return core.SessionMigrationRename, nil
+		case "a", "adopt":
+			return core.SessionMigrationAdopt, nil
+		case "s", "skip", "":
+			return "", nil
+		}
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+	}
+}
+
+// knownWorktreePaths lists every worktree of every project found under roots.
+func knownWorktreePaths(fs ports.Filesystem, roots []string, depth int) ([]string, error) {
+	dirs, err := fs.ScanDirs(roots, core.ScanRules{MaxDepth: depth}.Depth())
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, dir := range dirs {
+		worktrees, err := fs.ListWorktreePaths(dir.Path)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, worktrees...)
+	}
+	return paths, nil
+}
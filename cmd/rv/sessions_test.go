@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+type stubSessionManager struct {
+	legacy       []core.SessionMigration
+	legacyPaths  []string
+	migrateCalls []core.SessionMigration
+}
+
+func (s *stubSessionManager) OpenSession(core.SessionSpec) error { return nil }
+
+func (s *stubSessionManager) PrewarmSession(core.SessionSpec) (bool, error) { return false, nil }
+
+func (s *stubSessionManager) PrewarmTools(string, []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+func (s *stubSessionManager) KillSession(core.SessionSpec) error { return nil }
+
+func (s *stubSessionManager) KillSessionByName(string) error { return nil }
+
+func (s *stubSessionManager) ListSessions() ([]core.SessionInfo, error) { return nil, nil }
+
+func (s *stubSessionManager) AttachSession(string) error { return nil }
+
+func (s *stubSessionManager) FindLegacySessions(worktreePaths []string) ([]core.SessionMigration, error) {
+	s.legacyPaths = append([]string(nil), worktreePaths...)
+	return append([]core.SessionMigration(nil), s.legacy...), nil
+}
+
+func (s *stubSessionManager) MigrateSession(migration core.SessionMigration) error {
+	s.migrateCalls = append(s.migrateCalls, migration)
+	return nil
+}
+
+type scanningFilesystem struct {
+	stubFilesystem
+	dirs []core.DirEntry
+}
+
+func (s *scanningFilesystem) ScanDirs(_ []string, _ int) ([]core.DirEntry, error) {
+	return s.dirs, nil
+}
+
+func TestMigrateSessionsPromptsForEachSession(t *testing.T) {
+	fs := &scanningFilesystem{
+		stubFilesystem: stubFilesystem{listWorktreePaths: []string{"/projects/demo", "/projects/api"}},
+		dirs:           []core.DirEntry{{Path: "/projects/demo", Name: "demo"}},
+	}
+	sessions := &stubSessionManager{
+		legacy: []core.SessionMigration{
+			{Name: "demo__amp", DirPath: "/projects/demo", CurrentName: "-projects-demo"},
+			{Name: "api__claude", DirPath: "/projects/api", CurrentName: "-projects-api"},
+		},
+	}
+
+	var out bytes.Buffer
+	err := migrateSessions(fs, sessions, []string{"/projects"}, migrateOptions{}, strings.NewReader("r\na\n"), &out)
+	if err != nil {
+		t.Fatalf("unexpected migrate error: %v", err)
+	}
+	if len(sessions.legacyPaths) != 2 {
+		t.Fatalf("expected known worktree paths to be forwarded, got %v", sessions.legacyPaths)
+	}
+	if len(sessions.migrateCalls) != 2 {
+		t.Fatalf("expected two migrations, got %d", len(sessions.migrateCalls))
+	}
+	if sessions.migrateCalls[0].Action != core.SessionMigrationRename {
+		t.Fatalf("expected first session to be renamed, got %q", sessions.migrateCalls[0].Action)
+	}
+	if sessions.migrateCalls[1].Action != core.SessionMigrationAdopt {
+		t.Fatalf("expected second session to be adopted, got %q", sessions.migrateCalls[1].Action)
+	}
+	report := out.String()
+	if !strings.Contains(report, "renamed demo__amp -> -projects-demo") {
+		t.Fatalf("expected rename report, got:\n%s", report)
+	}
+	if !strings.Contains(report, "adopted api__claude for /projects/api") {
+		t.Fatalf("expected adopt report, got:\n%s", report)
+	}
+	if !strings.Contains(report, "Migrated 2 of 2 legacy sessions.") {
+		t.Fatalf("expected summary, got:\n%s", report)
+	}
+}
+
+func TestMigrateSessionsDryRunDoesNotMigrate(t *testing.T) {
+	fs := &scanningFilesystem{
+		stubFilesystem: stubFilesystem{listWorktreePaths: []string{"/projects/demo"}},
+		dirs:           []core.DirEntry{{Path: "/projects/demo", Name: "demo"}},
+	}
+	sessions := &stubSessionManager{
+		legacy: []core.SessionMigration{{Name: "demo__amp", DirPath: "/projects/demo", CurrentName: "-projects-demo"}},
+	}
+
+	var out bytes.Buffer
+	opts := migrateOptions{action: core.SessionMigrationRename, dryRun: true}
+	if err := migrateSessions(fs, sessions, []string{"/projects"}, opts, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("unexpected migrate error: %v", err)
+	}
+	if len(sessions.migrateCalls) != 0 {
+		t.Fatalf("did not expect migrations in dry-run mode, got %d", len(sessions.migrateCalls))
+	}
+	if !strings.Contains(out.String(), "Found 1 legacy sessions.") {
+		t.Fatalf("expected dry-run summary, got:\n%s", out.String())
+	}
+}
+
+func TestRunSessionsCommandRejectsConflictingActions(t *testing.T) {
+	var errOut bytes.Buffer
+	code := runSessionsCommand([]string{"migrate", "--rename", "--adopt"}, &stubFilesystem{}, &stubSessionManager{}, 2, strings.NewReader(""), &bytes.Buffer{}, &errOut)
+	if code != 2 {
+		t.Fatalf("expected usage exit code, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), "mutually exclusive") {
+		t.Fatalf("expected conflict error, got %q", errOut.String())
+	}
+}
@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
@@ -9,79 +10,157 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/ariguillegp/rivet/internal/core"
 )
 
-type OSFilesystem struct{}
+type OSFilesystem struct {
+	indexPath string
+	pinsPath  string
+	tagsPath  string
+	rules     core.ScanRules
+	// postCreate holds the commands run in new worktrees, by project path.
+	postCreate map[string][]string
+}
+
+// NewOSFilesystem returns a filesystem whose project scans follow rules.
+// rules.MaxDepth is left to callers, which pass a depth to ScanDirs.
+func NewOSFilesystem(rules core.ScanRules) *OSFilesystem {
+	return &OSFilesystem{indexPath: expandPath(rivetProjectIndexFile), pinsPath: expandPath(rivetPinsFile), tagsPath: expandPath(rivetTagsFile), rules: rules}
+}
 
-func NewOSFilesystem() *OSFilesystem {
-	return &OSFilesystem{}
+// SetPostCreateCommands sets the commands SetupWorktree runs in every new
+// worktree of a project, keyed by cleaned project path.
+func (f *OSFilesystem) SetPostCreateCommands(commands map[string][]string) {
+	f.postCreate = commands
 }
 
 const rivetWorktreesDir = "~/.rivet/worktrees"
 
-var ignoreDirs = map[string]bool{
-	".git":         true,
-	"node_modules": true,
-	"vendor":       true,
-	".cache":       true,
-	"__pycache__":  true,
-	".venv":        true,
-	"target":       true,
+func (f *OSFilesystem) ScanDirs(roots []string, maxDepth int) ([]core.DirEntry, error) {
+	return f.ScanDirsStream(context.Background(), roots, maxDepth, nil)
 }
 
-func (f *OSFilesystem) ScanDirs(roots []string, maxDepth int) ([]core.DirEntry, error) {
+// ScanDirsStream walks every root concurrently and passes each batch of
+// discovered projects to emit as soon as a directory has been read. The
+// returned list holds every project in root order. emit may be called from
+// several goroutines and may be nil.
+func (f *OSFilesystem) ScanDirsStream(ctx context.Context, roots []string, maxDepth int, emit func([]core.DirEntry)) ([]core.DirEntry, error) {
+	seen := &scanSeen{paths: make(map[string]bool)}
+	results := make([][]core.DirEntry, len(roots))
+
+	var wg sync.WaitGroup
+	for i, root := range roots {
+		wg.Go(func() {
+			w := f.newDirWalker(ctx, expandPath(root), maxDepth, seen)
+			w.emit = emit
+			_ = w.scanDir(w.root, 0)
+			results[i] = w.dirs
+		})
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var dirs []core.DirEntry
-	seen := make(map[string]bool)
+	for _, rootDirs := range results {
+		dirs = append(dirs, rootDirs...)
+	}
+	return dirs, nil
+}
 
-	for _, root := range roots {
-		root = expandPath(root)
-		err := scanDir(root, 0, maxDepth, seen, &dirs)
-		if err != nil {
-			continue
-		}
+type scanSeen struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+// claim reports whether path had not been visited yet and marks it visited.
+func (s *scanSeen) claim(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paths[path] {
+		return false
 	}
+	s.paths[path] = true
+	return true
+}
 
-	return dirs, nil
+type dirWalker struct {
+	ctx        context.Context
+	root       string
+	maxDepth   int
+	rules      core.ScanRules
+	markers    []string
+	seen       *scanSeen
+	emit       func([]core.DirEntry)
+	dirs       []core.DirEntry
+	containers []string
 }
 
-func scanDir(path string, depth, maxDepth int, seen map[string]bool, dirs *[]core.DirEntry) error {
-	if depth > maxDepth {
+func (f *OSFilesystem) newDirWalker(ctx context.Context, root string, maxDepth int, seen *scanSeen) *dirWalker {
+	return &dirWalker{
+		ctx:      ctx,
+		root:     filepath.Clean(root),
+		maxDepth: maxDepth,
+		rules:    f.rules,
+		markers:  f.rules.ProjectMarkers(),
+		seen:     seen,
+	}
+}
+
+func (w *dirWalker) scanDir(path string, depth int) error {
+	if depth > w.maxDepth {
 		return nil
 	}
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
 
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return err
 	}
+	w.containers = append(w.containers, path)
 
+	var found []core.DirEntry
+	var nested []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
 		name := entry.Name()
-		if strings.HasPrefix(name, ".") || ignoreDirs[name] {
+		fullPath := filepath.Join(path, name)
+		if w.ignored(fullPath) {
 			continue
 		}
-
-		fullPath := filepath.Join(path, name)
-		if seen[fullPath] {
+		if !w.seen.claim(fullPath) {
 			continue
 		}
-		seen[fullPath] = true
 
-		if hasGitMarker(fullPath) {
-			*dirs = append(*dirs, core.DirEntry{
+		if hasProjectMarker(fullPath, w.markers) {
+			found = append(found, core.DirEntry{
 				Path:   fullPath,
 				Name:   name,
 				Exists: true,
 			})
 			continue
 		}
+		nested = append(nested, fullPath)
+	}
+
+	if len(found) > 0 {
+		w.dirs = append(w.dirs, found...)
+		if w.emit != nil {
+			w.emit(found)
+		}
+	}
 
-		if err := scanDir(fullPath, depth+1, maxDepth, seen, dirs); err != nil {
+	for _, nestedPath := range nested {
+		if err := w.scanDir(nestedPath, depth+1); err != nil {
 			return err
 		}
 	}
@@ -89,6 +168,23 @@ func scanDir(path string, depth, maxDepth int, seen map[string]bool, dirs *[]cor
 	return nil
 }
 
+func (w *dirWalker) ignored(path string) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return false
+	}
+	return w.rules.Ignored(filepath.ToSlash(rel))
+}
+
+func hasProjectMarker(path string, markers []string) bool {
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(path, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func hasGitMarker(path string) bool {
 	_, err := os.Stat(filepath.Join(path, ".git"))
 	return err == nil
@@ -178,6 +274,16 @@ func (f *OSFilesystem) ListWorktreePaths(projectPath string) ([]string, error) {
 }
 
 func (f *OSFilesystem) ListWorktrees(projectPath string) (core.WorktreeListing, error) {
+	return f.listWorktrees(projectPath, false)
+}
+
+// ListAllWorktrees lists every worktree of the project, marking the ones
+// outside the managed directory as unmanaged.
+func (f *OSFilesystem) ListAllWorktrees(projectPath string) (core.WorktreeListing, error) {
+	return f.listWorktrees(projectPath, true)
+}
+
+func (f *OSFilesystem) listWorktrees(projectPath string, all bool) (core.WorktreeListing, error) {
 	projectPath = expandPath(projectPath)
 	if !hasGitMarker(projectPath) {
 		return core.WorktreeListing{Warning: "Project has no repository. Create a project first."}, nil
@@ -192,12 +298,6 @@ func (f *OSFilesystem) ListWorktrees(projectPath string) (core.WorktreeListing,
 		return core.WorktreeListing{}, err
 	}
 
-	projectName := filepath.Base(projectPath)
-	projectID := projectWorktreePrefix(projectPath)
-	rivetDir := expandPath(rivetWorktreesDir)
-	prefix := projectID + "--"
-	legacyPrefix := projectName + "--"
-
 	var worktrees []core.Worktree
 	var current core.Worktree
 
@@ -224,13 +324,12 @@ func (f *OSFilesystem) ListWorktrees(projectPath string) (core.WorktreeListing,
 
 	var filtered []core.Worktree
 	for _, wt := range worktrees {
-		wtClean := filepath.Clean(wt.Path)
-		isRoot := wtClean == filepath.Clean(projectPath)
-		isUnderRivet := strings.HasPrefix(wtClean, rivetDir+string(filepath.Separator)) &&
-			(strings.HasPrefix(filepath.Base(wtClean), prefix) || strings.HasPrefix(filepath.Base(wtClean), legacyPrefix))
-
-		if !isRoot && !isUnderRivet {
-			continue
+		isRoot := filepath.Clean(wt.Path) == filepath.Clean(projectPath)
+		if !isRoot && !isManagedWorktree(projectPath, wt.Path) {
+			if !all {
+				continue
+			}
+			wt.Unmanaged = true
 		}
 
 		wt.Name = filepath.Base(wt.Path)
@@ -240,6 +339,18 @@ func (f *OSFilesystem) ListWorktrees(projectPath string) (core.WorktreeListing,
 	return core.WorktreeListing{Worktrees: filtered}, nil
 }
 
+// isManagedWorktree reports whether worktreePath is one of the project's
+// worktrees in the managed directory, under the current or legacy naming.
+func isManagedWorktree(projectPath, worktreePath string) bool {
+	wtClean := filepath.Clean(worktreePath)
+	if !strings.HasPrefix(wtClean, expandPath(rivetWorktreesDir)+string(filepath.Separator)) {
+		return false
+	}
+	name := filepath.Base(wtClean)
+	return strings.HasPrefix(name, projectWorktreePrefix(projectPath)+"--") ||
+		strings.HasPrefix(name, filepath.Base(projectPath)+"--")
+}
+
 func (f *OSFilesystem) CreateWorktree(projectPath, branchName string) (string, error) {
 	projectPath = expandPath(projectPath)
 	if !hasGitMarker(projectPath) {
@@ -307,6 +418,59 @@ func (f *OSFilesystem) CreateWorktree(projectPath, branchName string) (string, e
 	return worktreePath, nil
 }
 
+// AdoptWorktree moves an unmanaged worktree into the managed directory with
+// git worktree move, naming it like worktrees rivet creates, and returns its
+// new path.
+func (f *OSFilesystem) AdoptWorktree(projectPath, worktreePath string) (string, error) {
+	projectPath = expandPath(projectPath)
+	if !hasGitMarker(projectPath) {
+		return "", fmt.Errorf("project has no repository; create a project first")
+	}
+
+	cleanPath := filepath.Clean(expandPath(worktreePath))
+	if cleanPath == filepath.Clean(projectPath) {
+		return "", core.ErrWorktreeAdoptRoot
+	}
+	rivetDir := expandPath(rivetWorktreesDir)
+	if strings.HasPrefix(cleanPath, rivetDir+string(filepath.Separator)) {
+		return "", core.ErrWorktreeAlreadyManaged
+	}
+	if !isRegisteredWorktree(projectPath, cleanPath) {
+		return "", core.ErrWorktreeUnregistered
+	}
+
+	name := core.SanitizeWorktreeName(worktreeBranch(cleanPath))
+	if name == "" {
+		name = core.SanitizeWorktreeName(filepath.Base(cleanPath))
+	}
+	if name == "" {
+		return "", fmt.Errorf("cannot derive a workspace name for %s", cleanPath)
+	}
+	if err := os.MkdirAll(rivetDir, 0o755); err != nil {
+		return "", err
+	}
+	target := filepath.Join(rivetDir, fmt.Sprintf("%s--%s", projectWorktreePrefix(projectPath), name))
+	if _, err := os.Stat(target); err == nil {
+		return "", fmt.Errorf("managed worktree already exists: %s", target)
+	}
+
+	cmd := gitCommand(projectPath, "worktree", "move", cleanPath, target)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git worktree move failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return target, nil
+}
+
+// worktreeBranch returns the branch checked out in a worktree, or "" when
+// HEAD is detached.
+func worktreeBranch(worktreePath string) string {
+	output, err := gitCommand(worktreePath, "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 func (f *OSFilesystem) PruneWorktrees(projectPath string) error {
 	projectPath = expandPath(projectPath)
 	if !hasGitMarker(projectPath) {
@@ -1,11 +1,14 @@
 package adapters
 
 import (
+	"context"
 	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ariguillegp/rivet/internal/core"
@@ -396,3 +399,206 @@ func initRepo(t *testing.T, dir string) {
 		t.Fatalf("git commit failed: %v: %s", err, string(output))
 	}
 }
+
+func TestScanDirsStreamEmitsBatchesForEveryRoot(t *testing.T) {
+	rootA := t.TempDir()
+	rootB := t.TempDir()
+	for _, path := range []string{
+		filepath.Join(rootA, "one", ".git"),
+		filepath.Join(rootA, "group", "two", ".git"),
+		filepath.Join(rootB, "three", ".git"),
+	} {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("failed to create git marker: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var emitted []string
+	fs := &OSFilesystem{}
+	entries, err := fs.ScanDirsStream(context.Background(), []string{rootA, rootB}, 2, func(batch []core.DirEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, entry := range batch {
+			emitted = append(emitted, entry.Name)
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected scan error: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	if strings.Join(names, ",") != "one,two,three" {
+		t.Fatalf("expected results in root order, got %v", names)
+	}
+	slices.Sort(emitted)
+	if strings.Join(emitted, ",") != "one,three,two" {
+		t.Fatalf("expected every project to be emitted once, got %v", emitted)
+	}
+}
+
+func TestScanDirsStreamStopsWhenCancelled(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "repo", ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create git marker: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fs := &OSFilesystem{}
+	entries, err := fs.ScanDirsStream(ctx, []string{root}, 2, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation error, got %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no entries from cancelled scan, got %+v", entries)
+	}
+}
+
+func TestScanDirsAppliesIgnorePatternsAndMarkers(t *testing.T) {
+	root := t.TempDir()
+	for _, path := range []string{
+		filepath.Join(root, "mono", "services", "api", ".git"),
+		filepath.Join(root, "bazel-out", "repo", ".git"),
+		filepath.Join(root, "archive", "old", ".git"),
+		filepath.Join(root, "nested", "archive", ".git"),
+		filepath.Join(root, "jj-repo", ".jj"),
+	} {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "plain"), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "plain", ".rivet-project"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create marker file: %v", err)
+	}
+
+	fs := &OSFilesystem{rules: core.ScanRules{
+		Ignore:  []string{"bazel-*", "/archive"},
+		Markers: []string{".jj", ".rivet-project"},
+	}}
+	entries, err := fs.ScanDirs([]string{root}, 3)
+	if err != nil {
+		t.Fatalf("unexpected scan error: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	slices.Sort(names)
+	if strings.Join(names, ",") != "api,archive,jj-repo,plain" {
+		t.Fatalf("unexpected scan results: %v", names)
+	}
+
+	shallow, err := fs.ScanDirs([]string{root}, 1)
+	if err != nil {
+		t.Fatalf("unexpected scan error: %v", err)
+	}
+	for _, entry := range shallow {
+		if entry.Name == "api" {
+			t.Fatalf("expected depth to limit the scan, got %+v", shallow)
+		}
+	}
+}
+
+func TestListAllWorktreesMarksUnmanagedWorktrees(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	initRepo(t, projectPath)
+
+	outsidePath := filepath.Join(t.TempDir(), "outside-worktree")
+	cmd := exec.Command("git", "-C", projectPath, "worktree", "add", outsidePath, "-b", "feature/outside")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add failed: %v: %s", err, string(output))
+	}
+
+	fs := &OSFilesystem{}
+	listing, err := fs.ListAllWorktrees(projectPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(listing.Worktrees) != 2 {
+		t.Fatalf("expected root and outside worktree, got %+v", listing.Worktrees)
+	}
+	for _, wt := range listing.Worktrees {
+		isOutside := filepath.Clean(wt.Path) == filepath.Clean(outsidePath)
+		if wt.Unmanaged != isOutside {
+			t.Fatalf("unexpected unmanaged flag for %q: %v", wt.Path, wt.Unmanaged)
+		}
+	}
+}
+
+func TestAdoptWorktreeMovesItIntoManagedDirectory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	projectPath := t.TempDir()
+	initRepo(t, projectPath)
+
+	outsidePath := filepath.Join(t.TempDir(), "outside-worktree")
+	cmd := exec.Command("git", "-C", projectPath, "worktree", "add", outsidePath, "-b", "feature/outside")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add failed: %v: %s", err, string(output))
+	}
+
+	fs := &OSFilesystem{}
+	newPath, err := fs.AdoptWorktree(projectPath, outsidePath)
+	if err != nil {
+		t.Fatalf("AdoptWorktree() error: %v", err)
+	}
+	want := filepath.Join(home, ".rivet", "worktrees", projectWorktreePrefix(projectPath)+"--feature-outside")
+	if newPath != want {
+		t.Fatalf("expected %q, got %q", want, newPath)
+	}
+	if _, err := os.Stat(outsidePath); !os.IsNotExist(err) {
+		t.Fatalf("expected the old path to be gone, got %v", err)
+	}
+
+	listing, err := fs.ListWorktrees(projectPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, wt := range listing.Worktrees {
+		if wt.Path == newPath && wt.Branch == "feature/outside" && !wt.Unmanaged {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected adopted worktree in the managed listing, got %+v", listing.Worktrees)
+	}
+
+	// The delete guardrails now apply to the adopted worktree.
+	if err := fs.DeleteWorktree(projectPath, newPath); err != nil {
+		t.Fatalf("expected adopted worktree to be deletable, got %v", err)
+	}
+}
+
+func TestAdoptWorktreeRejectsRootAndManagedWorktrees(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	initRepo(t, projectPath)
+
+	fs := &OSFilesystem{}
+	if _, err := fs.AdoptWorktree(projectPath, projectPath); !errors.Is(err, core.ErrWorktreeAdoptRoot) {
+		t.Fatalf("expected ErrWorktreeAdoptRoot, got %v", err)
+	}
+
+	managedPath, err := fs.CreateWorktree(projectPath, "feature/managed")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	if _, err := fs.AdoptWorktree(projectPath, managedPath); !errors.Is(err, core.ErrWorktreeAlreadyManaged) {
+		t.Fatalf("expected ErrWorktreeAlreadyManaged, got %v", err)
+	}
+
+	if _, err := fs.AdoptWorktree(projectPath, filepath.Join(t.TempDir(), "stray")); !errors.Is(err, core.ErrWorktreeUnregistered) {
+		t.Fatalf("expected ErrWorktreeUnregistered, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+// projectWatchDebounce groups the burst of events produced by a clone or a
+// worktree checkout into a single rescan.
+const projectWatchDebounce = 200 * time.Millisecond
+
+type projectWatchSubscription struct {
+	fs           *OSFilesystem
+	watcher      *fsnotify.Watcher
+	roots        []string
+	maxDepth     int
+	worktreesDir string
+	known        map[string]core.DirEntry
+	watched      map[string]bool
+	events       chan ports.ProjectEvent
+	stop         chan struct{}
+	done         chan struct{}
+	closeOnce    sync.Once
+	closeErr     error
+}
+
+// WatchProjects watches every directory the scan would descend into plus the
+// managed worktree directory, and reports projects that appear or disappear.
+func (f *OSFilesystem) WatchProjects(roots []string, maxDepth int) (ports.ProjectSubscription, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start filesystem watcher: %w", err)
+	}
+
+	sub := &projectWatchSubscription{
+		fs:           f,
+		watcher:      watcher,
+		roots:        normalizeRoots(roots),
+		maxDepth:     maxDepth,
+		worktreesDir: filepath.Clean(expandPath(rivetWorktreesDir)),
+		known:        make(map[string]core.DirEntry),
+		watched:      make(map[string]bool),
+		events:       make(chan ports.ProjectEvent, 1),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	for _, root := range sub.roots {
+		sub.rescan(root)
+	}
+	if exists(sub.worktreesDir) {
+		_ = watcher.Add(sub.worktreesDir)
+	}
+
+	go sub.run()
+	return sub, nil
+}
+
+func (s *projectWatchSubscription) Events() <-chan ports.ProjectEvent {
+	return s.events
+}
+
+func (s *projectWatchSubscription) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		s.closeErr = s.watcher.Close()
+		<-s.done
+	})
+	return s.closeErr
+}
+
+func (s *projectWatchSubscription) run() {
+	defer close(s.done)
+	defer close(s.events)
+
+	pendingRoots := make(map[string]bool)
+	pendingWorktrees := false
+	timer := time.NewTimer(projectWatchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if s.isWorktreeEvent(event.Name) {
+				pendingWorktrees = true
+			} else if root := s.rootFor(event.Name); root != "" {
+				pendingRoots[root] = true
+			} else {
+				continue
+			}
+			timer.Reset(projectWatchDebounce)
+		case _, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-timer.C:
+			change := ports.ProjectEvent{Worktrees: pendingWorktrees}
+			for root := range pendingRoots {
+				added, removed := s.rescan(root)
+				change.Added = append(change.Added, added...)
+				change.Removed = append(change.Removed, removed...)
+			}
+			clear(pendingRoots)
+			pendingWorktrees = false
+			if len(change.Added) == 0 && len(change.Removed) == 0 && !change.Worktrees {
+				continue
+			}
+			select {
+			case s.events <- change:
+			case <-s.stop:
+				return
+			}
+		}
+	}
+}
+
+// rescan walks root again, updates the set of known projects and watched
+// directories, and returns what changed.
+func (s *projectWatchSubscription) rescan(root string) (added []core.DirEntry, removed []string) {
+	w := s.fs.newDirWalker(context.Background(), root, s.maxDepth, &scanSeen{paths: make(map[string]bool)})
+	walkErr := w.scanDir(root, 0)
+
+	current := make(map[string]bool, len(w.dirs))
+	for _, dir := range w.dirs {
+		current[dir.Path] = true
+		if _, ok := s.known[dir.Path]; !ok {
+			s.known[dir.Path] = dir
+			added = append(added, dir)
+		}
+	}
+
+	containers := make(map[string]bool, len(w.containers))
+	for _, dir := range w.containers {
+		containers[dir] = true
+		if !s.watched[dir] && s.watcher.Add(dir) == nil {
+			s.watched[dir] = true
+		}
+	}
+
+	// A partial walk cannot tell removed projects from unreadable ones.
+	if walkErr != nil && len(w.containers) > 0 {
+		return added, nil
+	}
+
+	for path := range s.known {
+		if isUnder(path, root) && !current[path] {
+			delete(s.known, path)
+			removed = append(removed, path)
+		}
+	}
+	for dir := range s.watched {
+		if isUnder(dir, root) && !containers[dir] {
+			_ = s.watcher.Remove(dir)
+			delete(s.watched, dir)
+		}
+	}
+	sort.Strings(removed)
+	return added, removed
+}
+
+func (s *projectWatchSubscription) isWorktreeEvent(path string) bool {
+	path = filepath.Clean(path)
+	return path == s.worktreesDir || filepath.Dir(path) == s.worktreesDir
+}
+
+// rootFor returns the most specific root containing path.
+func (s *projectWatchSubscription) rootFor(path string) string {
+	path = filepath.Clean(path)
+	var match string
+	for _, root := range s.roots {
+		if isUnder(path, root) && len(root) > len(match) {
+			match = root
+		}
+	}
+	return match
+}
+
+func isUnder(path, root string) bool {
+	return path == root || strings.HasPrefix(path, root+string(filepath.Separator))
+}
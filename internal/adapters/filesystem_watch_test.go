@@ -0,0 +1,106 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+func startProjectWatch(t *testing.T, root string) ports.ProjectSubscription {
+	t.Helper()
+	fs := &OSFilesystem{}
+	sub, err := fs.WatchProjects([]string{root}, 2)
+	if err != nil {
+		t.Fatalf("failed to start project watch: %v", err)
+	}
+	t.Cleanup(func() { _ = sub.Close() })
+	return sub
+}
+
+func nextProjectEvent(t *testing.T, sub ports.ProjectSubscription) ports.ProjectEvent {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		if !ok {
+			t.Fatalf("project events closed unexpectedly")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for project event")
+	}
+	return ports.ProjectEvent{}
+}
+
+func TestWatchProjectsReportsAddedAndRemovedProjects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	existing := filepath.Join(root, "existing")
+	if err := os.MkdirAll(filepath.Join(existing, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	sub := startProjectWatch(t, root)
+
+	cloned := filepath.Join(root, "group", "cloned")
+	if err := os.MkdirAll(filepath.Join(cloned, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	event := nextProjectEvent(t, sub)
+	for len(event.Added) == 0 {
+		event = nextProjectEvent(t, sub)
+	}
+	if len(event.Added) != 1 || event.Added[0].Path != cloned || !event.Added[0].Exists {
+		t.Fatalf("expected cloned project to be added, got %+v", event)
+	}
+
+	if err := os.RemoveAll(existing); err != nil {
+		t.Fatalf("failed to remove project: %v", err)
+	}
+	event = nextProjectEvent(t, sub)
+	if len(event.Removed) != 1 || event.Removed[0] != existing {
+		t.Fatalf("expected existing project to be removed, got %+v", event)
+	}
+}
+
+func TestWatchProjectsReportsManagedWorktreeChanges(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	worktreesDir := filepath.Join(home, ".rivet", "worktrees")
+	if err := os.MkdirAll(worktreesDir, 0o755); err != nil {
+		t.Fatalf("failed to create worktrees dir: %v", err)
+	}
+
+	sub := startProjectWatch(t, t.TempDir())
+
+	if err := os.MkdirAll(filepath.Join(worktreesDir, "demo--feature"), 0o755); err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+	event := nextProjectEvent(t, sub)
+	if !event.Worktrees {
+		t.Fatalf("expected worktree change to be reported, got %+v", event)
+	}
+	if len(event.Added) != 0 || len(event.Removed) != 0 {
+		t.Fatalf("expected no project changes, got %+v", event)
+	}
+}
+
+func TestWatchProjectsCloseEndsEvents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fs := &OSFilesystem{}
+	sub, err := fs.WatchProjects([]string{t.TempDir()}, 2)
+	if err != nil {
+		t.Fatalf("failed to start project watch: %v", err)
+	}
+	if err := sub.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if err := sub.Close(); err != nil {
+		t.Fatalf("expected repeated close to be a no-op, got %v", err)
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Fatalf("expected events channel to be closed")
+	}
+}
@@ -0,0 +1,128 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// hookOutputLines is how much of a failed hook's output ends up in its error.
+const hookOutputLines = 5
+
+// hookInput is the JSON document hooks read from stdin.
+type hookInput struct {
+	Event        string `json:"event"`
+	ProjectPath  string `json:"project_path"`
+	WorktreePath string `json:"worktree_path,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	Tool         string `json:"tool,omitempty"`
+}
+
+// ShellHookRunner runs hook commands with sh.
+type ShellHookRunner struct{}
+
+func NewShellHookRunner() *ShellHookRunner {
+	return &ShellHookRunner{}
+}
+
+// RunHook runs command in the worktree, or the project when the event has no
+// worktree, with the payload as JSON on stdin and in RIVET_* variables.
+// Output is kept for the error message only, since hooks may run while the
+// UI owns the terminal.
+func (r *ShellHookRunner) RunHook(ctx context.Context, command string, payload core.HookPayload) error {
+	input, err := json.Marshal(hookInput{
+		Event:        string(payload.Event),
+		ProjectPath:  payload.ProjectPath,
+		WorktreePath: payload.WorktreePath,
+		Branch:       payload.Branch,
+		Tool:         payload.Tool,
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = hookDir(payload)
+	cmd.Env = append(os.Environ(),
+		"RIVET_HOOK_EVENT="+string(payload.Event),
+		"RIVET_PROJECT_PATH="+payload.ProjectPath,
+		"RIVET_WORKTREE_PATH="+payload.WorktreePath,
+		"RIVET_BRANCH="+payload.Branch,
+		"RIVET_TOOL="+payload.Tool,
+	)
+	cmd.Stdin = bytes.NewReader(input)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = hookWaitDelay
+
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.New("timed out")
+	}
+	if err != nil {
+		if tail := lastLines(output.String(), hookOutputLines); tail != "" {
+			return fmt.Errorf("%w: %s", err, tail)
+		}
+		return err
+	}
+	return nil
+}
+
+// hookDir picks the directory a hook runs in. Directories that no longer
+// exist, such as a deleted worktree, fall back to the project and then to
+// rivet's own working directory.
+func hookDir(payload core.HookPayload) string {
+	for _, dir := range []string{payload.WorktreePath, payload.ProjectPath} {
+		if dir == "" {
+			continue
+		}
+		if info, err := os.Stat(expandPath(dir)); err == nil && info.IsDir() {
+			return expandPath(dir)
+		}
+	}
+	return ""
+}
+
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, " | "))
+}
+
+// WorktreeHookPayload describes an event in the worktree at worktreePath,
+// looking up its project and branch with git.
+func WorktreeHookPayload(event core.HookEvent, worktreePath, tool string) core.HookPayload {
+	worktreePath = expandPath(worktreePath)
+	return core.HookPayload{
+		Event:        event,
+		ProjectPath:  worktreeProjectPath(worktreePath),
+		WorktreePath: worktreePath,
+		Branch:       worktreeBranch(worktreePath),
+		Tool:         tool,
+	}
+}
+
+// worktreeProjectPath returns the main checkout of the repository the
+// worktree belongs to.
+func worktreeProjectPath(worktreePath string) string {
+	output, err := gitCommand(worktreePath, "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	commonDir := filepath.Clean(strings.TrimSpace(string(output)))
+	if filepath.Base(commonDir) != ".git" {
+		return ""
+	}
+	return filepath.Dir(commonDir)
+}
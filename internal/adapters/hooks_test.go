@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func TestRunHookPassesPayloadOnStdin(t *testing.T) {
+	worktree := t.TempDir()
+	payload := core.HookPayload{
+		Event:        core.HookSessionOpen,
+		ProjectPath:  "/projects/api",
+		WorktreePath: worktree,
+		Branch:       "feature",
+		Tool:         "claude",
+	}
+
+	runner := NewShellHookRunner()
+	err := runner.RunHook(context.Background(), `cat > payload.json; pwd > dir; echo "$RIVET_HOOK_EVENT $RIVET_TOOL" > env`, payload)
+	if err != nil {
+		t.Fatalf("RunHook() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(worktree, "payload.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid payload %q: %v", data, err)
+	}
+	want := map[string]string{
+		"event":         "session_open",
+		"project_path":  "/projects/api",
+		"worktree_path": worktree,
+		"branch":        "feature",
+		"tool":          "claude",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("expected %s=%q, got %q", key, value, got[key])
+		}
+	}
+	if dir, _ := os.ReadFile(filepath.Join(worktree, "dir")); strings.TrimSpace(string(dir)) != worktree {
+		t.Fatalf("expected the hook to run in the worktree, got %q", dir)
+	}
+	if env, _ := os.ReadFile(filepath.Join(worktree, "env")); strings.TrimSpace(string(env)) != "session_open claude" {
+		t.Fatalf("unexpected environment %q", env)
+	}
+}
+
+func TestRunHookReportsFailureOutput(t *testing.T) {
+	runner := NewShellHookRunner()
+	err := runner.RunHook(context.Background(), "echo first; echo 'branch name taken' >&2; exit 4", core.HookPayload{Event: core.HookPreWorktreeCreate})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "exit status 4") || !strings.Contains(err.Error(), "first | branch name taken") {
+		t.Fatalf("expected exit status and output in error, got %v", err)
+	}
+}
+
+func TestRunHookTimesOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	runner := NewShellHookRunner()
+	start := time.Now()
+	err := runner.RunHook(ctx, "exec sleep 30", core.HookPayload{Event: core.HookAgentExit})
+	if err == nil || err.Error() != "timed out" {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("timeout took too long: %v", time.Since(start))
+	}
+}
+
+func TestWorktreeHookPayloadLooksUpProjectAndBranch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	initRepo(t, projectPath)
+
+	fs := &OSFilesystem{}
+	worktreePath, err := fs.CreateWorktree(projectPath, "feature/hooks")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	payload := WorktreeHookPayload(core.HookAgentExit, worktreePath, "codex")
+	resolvedProject, _ := filepath.EvalSymlinks(projectPath)
+	if payload.ProjectPath != projectPath && payload.ProjectPath != resolvedProject {
+		t.Fatalf("expected project %q, got %q", projectPath, payload.ProjectPath)
+	}
+	if payload.Branch != "feature/hooks" || payload.Tool != "codex" || payload.WorktreePath != worktreePath {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
@@ -0,0 +1,82 @@
+package adapters
+
+import (
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+const rivetLaunchHistoryFile = "~/.rivet/recent.json"
+
+// launchHistory is the on-disk launch history, newest first.
+type launchHistory struct {
+	Launches []launchEntry `json:"launches"`
+}
+
+type launchEntry struct {
+	ProjectPath  string    `json:"project_path"`
+	WorktreePath string    `json:"worktree_path"`
+	Branch       string    `json:"branch,omitempty"`
+	Tool         string    `json:"tool"`
+	OpenedAt     time.Time `json:"opened_at"`
+}
+
+// RecentLaunches returns the sessions opened most recently, newest first.
+func (t *TmuxSession) RecentLaunches() ([]core.Launch, error) {
+	history, err := t.loadLaunchHistory()
+	if err != nil {
+		return nil, err
+	}
+	launches := make([]core.Launch, 0, len(history.Launches))
+	for _, entry := range history.Launches {
+		launches = append(launches, core.Launch{
+			ProjectPath:  entry.ProjectPath,
+			WorktreePath: entry.WorktreePath,
+			Branch:       entry.Branch,
+			Tool:         entry.Tool,
+			OpenedAt:     entry.OpenedAt,
+		})
+	}
+	return launches, nil
+}
+
+// RecordLaunch adds spec to the launch history, looking up its project and
+// branch with git.
+func (t *TmuxSession) RecordLaunch(spec core.SessionSpec) error {
+	if t.historyPath == "" {
+		return nil
+	}
+	launches, err := t.RecentLaunches()
+	if err != nil {
+		// A corrupt history starts over with this launch.
+		launches = nil
+	}
+	worktreePath := expandPath(spec.DirPath)
+	launches = core.RecordLaunch(launches, core.Launch{
+		ProjectPath:  worktreeProjectPath(worktreePath),
+		WorktreePath: worktreePath,
+		Branch:       worktreeBranch(worktreePath),
+		Tool:         spec.Tool,
+		OpenedAt:     time.Now(),
+	})
+
+	history := launchHistory{Launches: make([]launchEntry, 0, len(launches))}
+	for _, launch := range launches {
+		history.Launches = append(history.Launches, launchEntry{
+			ProjectPath:  launch.ProjectPath,
+			WorktreePath: launch.WorktreePath,
+			Branch:       launch.Branch,
+			Tool:         launch.Tool,
+			OpenedAt:     launch.OpenedAt,
+		})
+	}
+	return writeStateFile(t.historyPath, history)
+}
+
+func (t *TmuxSession) loadLaunchHistory() (launchHistory, error) {
+	var history launchHistory
+	if err := readStateFile(t.historyPath, "launch history", &history); err != nil {
+		return launchHistory{}, err
+	}
+	return history, nil
+}
@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func TestRecordLaunchPersistsNewestFirst(t *testing.T) {
+	tmpDir := t.TempDir()
+	session := &TmuxSession{historyPath: filepath.Join(tmpDir, "state", "recent.json")}
+
+	launches, err := session.RecentLaunches()
+	if err != nil || len(launches) != 0 {
+		t.Fatalf("expected an empty history, got %+v (%v)", launches, err)
+	}
+
+	for _, spec := range []core.SessionSpec{
+		{DirPath: filepath.Join(tmpDir, "api--main"), Tool: "amp"},
+		{DirPath: filepath.Join(tmpDir, "web--fix"), Tool: "claude"},
+		{DirPath: filepath.Join(tmpDir, "api--main"), Tool: "amp"},
+	} {
+		if err := session.RecordLaunch(spec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	launches, err = session.RecentLaunches()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(launches) != 2 {
+		t.Fatalf("expected the repeated launch to be recorded once, got %+v", launches)
+	}
+	if launches[0].Spec() != (core.SessionSpec{DirPath: filepath.Join(tmpDir, "api--main"), Tool: "amp"}) || launches[0].OpenedAt.IsZero() {
+		t.Fatalf("expected the latest launch first, got %+v", launches[0])
+	}
+}
+
+func TestRecordLaunchRebuildsCorruptHistory(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "recent.json")
+	if err := os.WriteFile(historyPath, []byte("{"), 0o644); err != nil {
+		t.Fatalf("failed to write history: %v", err)
+	}
+	session := &TmuxSession{historyPath: historyPath}
+	if _, err := session.RecentLaunches(); err == nil {
+		t.Fatal("expected a corrupt history to be reported")
+	}
+
+	if err := session.RecordLaunch(core.SessionSpec{DirPath: "/wt/api--main", Tool: "none"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	launches, err := session.RecentLaunches()
+	if err != nil || len(launches) != 1 {
+		t.Fatalf("expected the history to start over, got %+v (%v)", launches, err)
+	}
+}
@@ -0,0 +1,44 @@
+package adapters
+
+import "slices"
+
+const rivetPinsFile = "~/.rivet/pins.json"
+
+// pinList is the on-disk list of pinned project and worktree paths.
+type pinList struct {
+	Paths []string `json:"paths"`
+}
+
+// Pins returns the paths of the pinned projects and worktrees.
+func (f *OSFilesystem) Pins() ([]string, error) {
+	pins, err := f.loadPins()
+	if err != nil {
+		return nil, err
+	}
+	return pins.Paths, nil
+}
+
+// SetPinned pins or unpins a project or worktree path.
+func (f *OSFilesystem) SetPinned(path string, pinned bool) error {
+	if f.pinsPath == "" {
+		return nil
+	}
+	pins, err := f.loadPins()
+	if err != nil {
+		return err
+	}
+	pins.Paths = slices.DeleteFunc(pins.Paths, func(existing string) bool { return existing == path })
+	if pinned {
+		pins.Paths = append(pins.Paths, path)
+	}
+
+	return writeStateFile(f.pinsPath, pins)
+}
+
+func (f *OSFilesystem) loadPins() (pinList, error) {
+	pins := pinList{Paths: []string{}}
+	if err := readStateFile(f.pinsPath, "pins", &pins); err != nil {
+		return pinList{}, err
+	}
+	return pins, nil
+}
@@ -0,0 +1,33 @@
+package adapters
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSetPinnedPersistsPins(t *testing.T) {
+	fs := &OSFilesystem{pinsPath: filepath.Join(t.TempDir(), "state", "pins.json")}
+
+	pins, err := fs.Pins()
+	if err != nil || len(pins) != 0 {
+		t.Fatalf("expected no pins, got %v (%v)", pins, err)
+	}
+
+	for _, path := range []string{"/projects/api", "/projects/web", "/projects/api"} {
+		if err := fs.SetPinned(path, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := fs.SetPinned("/projects/web", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pins, err = fs.Pins()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(pins, []string{"/projects/api"}) {
+		t.Fatalf("expected only api to stay pinned, got %v", pins)
+	}
+}
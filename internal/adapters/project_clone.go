@@ -0,0 +1,134 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// clonePercentPattern matches git's progress lines, such as
+// "Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s".
+var clonePercentPattern = regexp.MustCompile(`^([^:]+):\s+(\d+)%`)
+
+// CloneProject clones source into path with git clone, calling progress for
+// every progress line git prints. A failed or cancelled clone leaves nothing
+// behind at path.
+func (f *OSFilesystem) CloneProject(ctx context.Context, source, path string, progress func(core.CloneProgress)) (string, error) {
+	projectPath := expandPath(path)
+	if _, err := os.Stat(projectPath); err == nil {
+		return "", fmt.Errorf("project already exists: %s", projectPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(projectPath), 0o755); err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--progress", expandPath(source), projectPath)
+	// Nobody can answer a credential prompt from inside the TUI.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start git clone: %w", err)
+	}
+
+	var messages []string
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "remote:"))
+		if line == "" {
+			continue
+		}
+		update, isPercent := parseCloneProgress(line)
+		if !isPercent && !strings.HasPrefix(line, "Cloning into") {
+			messages = append(messages, line)
+		}
+		if progress != nil {
+			progress(update)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		_ = os.RemoveAll(projectPath)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if len(messages) > 0 {
+			return "", fmt.Errorf("git clone failed: %s", strings.Join(messages, "; "))
+		}
+		return "", fmt.Errorf("git clone failed: %w", err)
+	}
+	return projectPath, nil
+}
+
+// IsBareRepository reports whether path is a local bare repository, which
+// is cloned like a URL rather than opened as a project.
+func (f *OSFilesystem) IsBareRepository(path string) bool {
+	path = expandPath(strings.TrimSpace(path))
+	if path == "" || hasGitMarker(path) {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+		return false
+	}
+	return isDir(filepath.Join(path, "objects")) && isDir(filepath.Join(path, "refs"))
+}
+
+// IsCloneOf reports whether the project at path has source as its origin.
+func (f *OSFilesystem) IsCloneOf(path, source string) bool {
+	out, err := exec.Command("git", "-C", expandPath(path), "remote", "get-url", "origin").Output()
+	if err != nil {
+		return false
+	}
+	return normalizeCloneSource(string(out)) == normalizeCloneSource(source)
+}
+
+// normalizeCloneSource drops the differences git ignores between two ways of
+// writing the same remote: a trailing slash, the .git suffix and, for local
+// paths, a relative or ~ prefix.
+func normalizeCloneSource(source string) string {
+	source = expandPath(strings.TrimSpace(source))
+	if filepath.IsAbs(source) || strings.HasPrefix(source, ".") {
+		if abs, err := filepath.Abs(source); err == nil {
+			source = abs
+		}
+	}
+	return strings.TrimSuffix(strings.TrimRight(source, "/"), ".git")
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func parseCloneProgress(line string) (core.CloneProgress, bool) {
+	match := clonePercentPattern.FindStringSubmatch(line)
+	if match == nil {
+		return core.CloneProgress{Phase: line}, false
+	}
+	percent, _ := strconv.Atoi(match[2])
+	return core.CloneProgress{Phase: strings.TrimSpace(match[1]), Percent: min(percent, 100)}, true
+}
+
+// scanProgressLines splits on both \r and \n, since git redraws progress
+// lines in place with carriage returns.
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,126 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func initBareRepo(t *testing.T, dir string) string {
+	t.Helper()
+	src := filepath.Join(dir, "src")
+	initRepo(t, src)
+	bare := filepath.Join(dir, "remote.git")
+	if output, err := exec.Command("git", "clone", "--bare", src, bare).CombinedOutput(); err != nil {
+		t.Fatalf("git clone --bare failed: %v: %s", err, string(output))
+	}
+	return bare
+}
+
+func TestCloneProjectClonesBareRepository(t *testing.T) {
+	tmp := t.TempDir()
+	bare := initBareRepo(t, tmp)
+	target := filepath.Join(tmp, "root", "remote")
+
+	var updates []core.CloneProgress
+	fs := NewOSFilesystem(core.ScanRules{})
+	path, err := fs.CloneProject(context.Background(), bare, target, func(progress core.CloneProgress) {
+		updates = append(updates, progress)
+	})
+	if err != nil {
+		t.Fatalf("CloneProject() error: %v", err)
+	}
+	if path != target {
+		t.Fatalf("expected %q, got %q", target, path)
+	}
+	if !hasGitMarker(path) {
+		t.Fatalf("expected a git repository at %q", path)
+	}
+	output, err := exec.Command("git", "-C", path, "log", "--format=%s").Output()
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "initial commit" {
+		t.Fatalf("expected the cloned history, got %q", output)
+	}
+	if len(updates) == 0 {
+		t.Fatal("expected at least one progress update")
+	}
+}
+
+func TestIsBareRepositoryAndIsCloneOf(t *testing.T) {
+	tmp := t.TempDir()
+	bare := initBareRepo(t, tmp)
+	fs := NewOSFilesystem(core.ScanRules{})
+	if !fs.IsBareRepository(bare) || fs.IsBareRepository(filepath.Join(tmp, "src")) {
+		t.Fatal("expected only the bare repository to be reported as bare")
+	}
+
+	target := filepath.Join(tmp, "root", "remote")
+	if _, err := fs.CloneProject(context.Background(), bare, target, nil); err != nil {
+		t.Fatalf("CloneProject() error: %v", err)
+	}
+	if !fs.IsCloneOf(target, bare+"/") || !fs.IsCloneOf(target, strings.TrimSuffix(bare, ".git")) {
+		t.Fatal("expected the clone to match its source")
+	}
+	if fs.IsCloneOf(target, filepath.Join(tmp, "src")) || fs.IsCloneOf(filepath.Join(tmp, "src"), bare) {
+		t.Fatal("expected other repositories not to match")
+	}
+}
+
+func TestCloneProjectRemovesTargetOnFailure(t *testing.T) {
+	tmp := t.TempDir()
+	target := filepath.Join(tmp, "missing")
+
+	fs := NewOSFilesystem(core.ScanRules{})
+	_, err := fs.CloneProject(context.Background(), filepath.Join(tmp, "missing.git"), target, nil)
+	if err == nil {
+		t.Fatal("expected an error for a missing repository")
+	}
+	if !strings.Contains(err.Error(), "git clone failed") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, statErr := os.Stat(target); !os.IsNotExist(statErr) {
+		t.Fatalf("expected %q to be removed, got %v", target, statErr)
+	}
+}
+
+func TestCloneProjectRejectsExistingTarget(t *testing.T) {
+	tmp := t.TempDir()
+	bare := initBareRepo(t, tmp)
+	target := filepath.Join(tmp, "taken")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewOSFilesystem(core.ScanRules{})
+	if _, err := fs.CloneProject(context.Background(), bare, target, nil); err == nil {
+		t.Fatal("expected an error for an existing target")
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Fatalf("existing target must be left alone: %v", err)
+	}
+}
+
+func TestParseCloneProgress(t *testing.T) {
+	tests := []struct {
+		line      string
+		want      core.CloneProgress
+		isPercent bool
+	}{
+		{"Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s", core.CloneProgress{Phase: "Receiving objects", Percent: 45}, true},
+		{"Resolving deltas: 100% (12/12), done.", core.CloneProgress{Phase: "Resolving deltas", Percent: 100}, true},
+		{"Cloning into 'repo'...", core.CloneProgress{Phase: "Cloning into 'repo'..."}, false},
+	}
+	for _, tt := range tests {
+		got, isPercent := parseCloneProgress(tt.line)
+		if got != tt.want || isPercent != tt.isPercent {
+			t.Errorf("parseCloneProgress(%q) = %+v, %v; want %+v, %v", tt.line, got, isPercent, tt.want, tt.isPercent)
+		}
+	}
+}
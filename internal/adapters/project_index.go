@@ -0,0 +1,136 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+const rivetProjectIndexFile = "~/.cache/rivet/projects.json"
+
+// projectIndex is the on-disk project cache. Each entry records the result
+// of one scan for a specific set of roots, max depth and scan rules.
+type projectIndex struct {
+	Entries []projectIndexEntry `json:"entries"`
+}
+
+type projectIndexEntry struct {
+	Roots     []string          `json:"roots"`
+	MaxDepth  int               `json:"max_depth"`
+	Ignore    []string          `json:"ignore"`
+	Markers   []string          `json:"markers"`
+	ScannedAt time.Time         `json:"scanned_at"`
+	Projects  []projectIndexDir `json:"projects"`
+}
+
+type projectIndexDir struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+}
+
+// CachedDirs returns the projects recorded by the last scan of roots at
+// maxDepth with the filesystem's scan rules. Entries whose directory no longer exists are returned with
+// Exists set to false.
+func (f *OSFilesystem) CachedDirs(roots []string, maxDepth int) ([]core.DirEntry, bool) {
+	index, err := f.loadProjectIndex()
+	if err != nil {
+		return nil, false
+	}
+	key := f.projectIndexKey(roots, maxDepth)
+	for _, entry := range index.Entries {
+		if !entry.sameScan(key) {
+			continue
+		}
+		dirs := make([]core.DirEntry, 0, len(entry.Projects))
+		for _, project := range entry.Projects {
+			dirs = append(dirs, core.DirEntry{
+				Path:   project.Path,
+				Name:   project.Name,
+				Exists: exists(project.Path),
+			})
+		}
+		return dirs, true
+	}
+	return nil, false
+}
+
+// StoreDirs records a completed scan of roots at maxDepth, replacing any
+// earlier result for the same key.
+func (f *OSFilesystem) StoreDirs(roots []string, maxDepth int, dirs []core.DirEntry) error {
+	if f.indexPath == "" {
+		return nil
+	}
+	index, err := f.loadProjectIndex()
+	if err != nil {
+		// A corrupt cache is rebuilt from the fresh scan.
+		index = projectIndex{}
+	}
+
+	entry := f.projectIndexKey(roots, maxDepth)
+	entry.ScannedAt = time.Now()
+	entry.Projects = make([]projectIndexDir, 0, len(dirs))
+	for _, dir := range dirs {
+		entry.Projects = append(entry.Projects, projectIndexDir{Path: dir.Path, Name: dir.Name})
+	}
+
+	index.Entries = slices.DeleteFunc(index.Entries, func(existing projectIndexEntry) bool {
+		return existing.sameScan(entry)
+	})
+	index.Entries = append(index.Entries, entry)
+
+	return writeStateFile(f.indexPath, index)
+}
+
+func (f *OSFilesystem) loadProjectIndex() (projectIndex, error) {
+	var index projectIndex
+	if err := readStateFile(f.indexPath, "project index", &index); err != nil {
+		return projectIndex{}, err
+	}
+	return index, nil
+}
+
+// projectIndexKey returns an entry holding only what identifies a scan: the
+// roots, the depth and the ignore patterns and markers in effect, defaults
+// included, so a change to any of them misses the cache.
+func (f *OSFilesystem) projectIndexKey(roots []string, maxDepth int) projectIndexEntry {
+	ignore := append(append([]string(nil), core.DefaultIgnorePatterns...), f.rules.Ignore...)
+	markers := f.rules.ProjectMarkers()
+	slices.Sort(markers)
+	return projectIndexEntry{
+		Roots:    normalizeRoots(roots),
+		MaxDepth: maxDepth,
+		Ignore:   ignore,
+		Markers:  markers,
+	}
+}
+
+func (e projectIndexEntry) sameScan(other projectIndexEntry) bool {
+	return e.MaxDepth == other.MaxDepth &&
+		slices.Equal(e.Roots, other.Roots) &&
+		slices.Equal(e.Ignore, other.Ignore) &&
+		slices.Equal(e.Markers, other.Markers)
+}
+
+// normalizeRoots cleans and sorts roots so they can be compared regardless
+// of order, duplicates and "~/" spelling.
+func normalizeRoots(roots []string) []string {
+	key := make([]string, 0, len(roots))
+	for _, root := range roots {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+		key = append(key, filepath.Clean(expandPath(root)))
+	}
+	slices.Sort(key)
+	return slices.Compact(key)
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,96 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func TestProjectIndexRoundTripsByRootsDepthAndRules(t *testing.T) {
+	root := t.TempDir()
+	projectPath := filepath.Join(root, "demo")
+	if err := os.MkdirAll(projectPath, 0o755); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	otherRoot := t.TempDir()
+
+	fs := &OSFilesystem{indexPath: filepath.Join(t.TempDir(), "cache", "projects.json")}
+	if _, ok := fs.CachedDirs([]string{root}, 2); ok {
+		t.Fatalf("expected no cached dirs before the first scan")
+	}
+
+	dirs := []core.DirEntry{{Path: projectPath, Name: "demo", Exists: true}}
+	if err := fs.StoreDirs([]string{root, otherRoot}, 2, dirs); err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+
+	cached, ok := fs.CachedDirs([]string{otherRoot, root, root}, 2)
+	if !ok {
+		t.Fatalf("expected cache hit regardless of root order")
+	}
+	if len(cached) != 1 || cached[0].Path != projectPath || !cached[0].Exists {
+		t.Fatalf("unexpected cached dirs: %+v", cached)
+	}
+	if _, ok := fs.CachedDirs([]string{root, otherRoot}, 3); ok {
+		t.Fatalf("expected a different depth to miss the cache")
+	}
+	if _, ok := fs.CachedDirs([]string{root}, 2); ok {
+		t.Fatalf("expected a different root set to miss the cache")
+	}
+
+	fs.rules = core.ScanRules{Ignore: []string{"archive"}}
+	if _, ok := fs.CachedDirs([]string{root, otherRoot}, 2); ok {
+		t.Fatalf("expected different ignore patterns to miss the cache")
+	}
+	fs.rules = core.ScanRules{Markers: []string{"go.mod"}}
+	if _, ok := fs.CachedDirs([]string{root, otherRoot}, 2); ok {
+		t.Fatalf("expected different project markers to miss the cache")
+	}
+}
+
+func TestProjectIndexFlagsMissingDirectories(t *testing.T) {
+	root := t.TempDir()
+	projectPath := filepath.Join(root, "gone")
+
+	fs := &OSFilesystem{indexPath: filepath.Join(t.TempDir(), "projects.json")}
+	if err := fs.StoreDirs([]string{root}, 2, []core.DirEntry{{Path: projectPath, Name: "gone", Exists: true}}); err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+
+	cached, ok := fs.CachedDirs([]string{root}, 2)
+	if !ok || len(cached) != 1 {
+		t.Fatalf("expected one cached dir, got %+v", cached)
+	}
+	if cached[0].Exists {
+		t.Fatalf("expected missing directory to be flagged")
+	}
+}
+
+func TestProjectIndexReplacesEntryAndIgnoresCorruptFile(t *testing.T) {
+	root := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "projects.json")
+	if err := os.WriteFile(indexPath, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("failed to write corrupt index: %v", err)
+	}
+
+	fs := &OSFilesystem{indexPath: indexPath}
+	if _, ok := fs.CachedDirs([]string{root}, 2); ok {
+		t.Fatalf("expected corrupt index to miss")
+	}
+
+	first := []core.DirEntry{{Path: filepath.Join(root, "a"), Name: "a"}}
+	second := []core.DirEntry{{Path: filepath.Join(root, "b"), Name: "b"}}
+	if err := fs.StoreDirs([]string{root}, 2, first); err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	if err := fs.StoreDirs([]string{root}, 2, second); err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+
+	cached, ok := fs.CachedDirs([]string{root}, 2)
+	if !ok || len(cached) != 1 || cached[0].Name != "b" {
+		t.Fatalf("expected latest scan to replace the entry, got %+v", cached)
+	}
+}
@@ -1,35 +1,52 @@
 package adapters
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ariguillegp/rivet/internal/core"
 )
 
-type TmuxSession struct{}
+type TmuxSession struct {
+	// aliasesPath points at the registry of adopted legacy session names.
+	// An empty path disables alias lookups.
+	aliasesPath string
+	// historyPath points at the launch history. An empty path disables it.
+	historyPath string
+	// agentExit is run in a tool window after the agent exits, with
+	// --worktree and --tool arguments appended. Empty disables it.
+	agentExit []string
+	// metadata keeps project and branch names across ListSessions calls.
+	metadata sessionMetadataCache
+}
 
 func NewTmuxSession() *TmuxSession {
-	return &TmuxSession{}
+	return &TmuxSession{aliasesPath: expandPath(rivetSessionAliasesFile), historyPath: expandPath(rivetLaunchHistoryFile)}
+}
+
+// SetAgentExitCommand sets the command tool windows created from now on run
+// when their agent exits.
+func (t *TmuxSession) SetAgentExitCommand(command []string) {
+	t.agentExit = append([]string(nil), command...)
 }
 
 func (t *TmuxSession) OpenSession(spec core.SessionSpec) error {
-	sessionName, err := sessionNameFor(spec)
+	sessionName, err := t.sessionName(spec)
 	if err != nil {
 		return err
 	}
 
-	if err := ensureWorkspaceSession(sessionName, spec.DirPath, spec.Tool); err != nil {
-		return err
-	}
-
-	if err := selectWindow(sessionName, spec.Tool); err != nil {
+	if err := ensureWorkspaceSession(sessionName, spec.DirPath, spec.Tool, t.agentExit); err != nil {
 		return err
 	}
 
@@ -49,18 +66,41 @@ func (t *TmuxSession) OpenSession(spec core.SessionSpec) error {
 }
 
 func (t *TmuxSession) PrewarmSession(spec core.SessionSpec) (bool, error) {
-	sessionName, err := sessionNameFor(spec)
+	created, err := t.PrewarmTools(spec.DirPath, []string{spec.Tool})
 	if err != nil {
 		return false, err
 	}
-	return ensureToolWindow(sessionName, spec.DirPath, spec.Tool)
+	return created[spec.Tool], nil
+}
+
+// PrewarmTools ensures a window exists for each tool in one tmux round trip
+// and reports which windows were newly created.
+func (t *TmuxSession) PrewarmTools(dirPath string, tools []string) (map[string]bool, error) {
+	sessionName, err := t.sessionName(core.SessionSpec{DirPath: dirPath})
+	if err != nil {
+		return nil, err
+	}
+	if len(tools) == 0 {
+		return map[string]bool{}, nil
+	}
+	return ensureToolWindows(sessionName, dirPath, tools, t.agentExit)
 }
 
 func (t *TmuxSession) KillSession(spec core.SessionSpec) error {
-	sessionName, err := sessionNameFor(spec)
+	sessionName, err := t.sessionName(spec)
 	if err != nil {
 		return err
 	}
+	return t.KillSessionByName(sessionName)
+}
+
+// KillSessionByName kills the named session. A session that is already gone
+// counts as killed.
+func (t *TmuxSession) KillSessionByName(sessionName string) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return fmt.Errorf("session name is required")
+	}
 
 	cmd := exec.Command("tmux", "kill-session", "-t", tmuxSessionTarget(sessionName))
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -74,6 +114,49 @@ func (t *TmuxSession) KillSession(spec core.SessionSpec) error {
 }
 
 func (t *TmuxSession) ListSessions() ([]core.SessionInfo, error) {
+	rows, err := listTmuxSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(rows))
+	for _, row := range rows {
+		paths = append(paths, row.path)
+	}
+	metadata := t.metadata.lookup(paths, time.Now())
+
+	var sessions []core.SessionInfo
+	for _, row := range rows {
+		info, ok := parseSessionName(row.name)
+		if ok {
+			info.Name = row.name
+			if row.path != "" {
+				info.DirPath = row.path
+			}
+		} else {
+			info = core.SessionInfo{Name: row.name, DirPath: row.path}
+		}
+		info.LastActive = row.lastActive
+		meta := metadata[strings.TrimSpace(row.path)]
+		if meta.project != "" {
+			info.Project = meta.project
+		}
+		if meta.branch != "" {
+			info.Branch = meta.branch
+		}
+		sessions = append(sessions, info)
+	}
+
+	return sessions, nil
+}
+
+type tmuxSessionRow struct {
+	name       string
+	path       string
+	lastActive time.Time
+}
+
+func listTmuxSessions() ([]tmuxSessionRow, error) {
 	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}\t#{session_path}\t#{session_last_attached}")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -83,51 +166,28 @@ func (t *TmuxSession) ListSessions() ([]core.SessionInfo, error) {
 		return nil, fmt.Errorf("failed to list tmux sessions: %w (output: %s)", err, strings.TrimSpace(string(output)))
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 1 && strings.TrimSpace(lines[0]) == "" {
-		return nil, nil
-	}
-
-	var sessions []core.SessionInfo
-	for _, line := range lines {
+	var rows []tmuxSessionRow
+	for line := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
 		parts := strings.SplitN(line, "\t", 3)
 		name := strings.TrimSpace(parts[0])
-		if name == "" {
+		if name == "" || isInternalSession(name) {
 			continue
 		}
-		if name == "rv-launcher" || name == "rivet-launcher" {
-			continue
-		}
-		sessionPath := ""
+		row := tmuxSessionRow{name: name, lastActive: parseTmuxUnixTime(parts, 2)}
 		if len(parts) > 1 {
-			sessionPath = strings.TrimSpace(parts[1])
-		}
-		lastActive := parseTmuxUnixTime(parts, 2)
-		info, ok := parseSessionName(name)
-		if ok {
-			info.Name = name
-			if sessionPath != "" {
-				info.DirPath = sessionPath
-			}
-		} else {
-			info = core.SessionInfo{Name: name, DirPath: sessionPath}
-		}
-		info.LastActive = lastActive
-		projectName, branch := sessionMetadata(sessionPath)
-		if strings.TrimSpace(projectName) != "" {
-			info.Project = projectName
-		}
-		if strings.TrimSpace(branch) != "" {
-			info.Branch = branch
+			row.path = strings.TrimSpace(parts[1])
 		}
-		sessions = append(sessions, info)
+		rows = append(rows, row)
 	}
+	return rows, nil
+}
 
-	return sessions, nil
+func isInternalSession(name string) bool {
+	return name == "rv-launcher" || name == "rivet-launcher" || strings.HasPrefix(name, watchSessionPrefix)
 }
 
 func parseTmuxUnixTime(parts []string, idx int) time.Time {
@@ -145,18 +205,121 @@ func parseTmuxUnixTime(parts []string, idx int) time.Time {
 	return time.Unix(unixValue, 0)
 }
 
-func sessionMetadata(dirPath string) (projectName, branch string) {
+const sessionMetadataWorkers = 8
+
+// sessionMetadataTimeout bounds the git lookups for a single worktree path.
+var sessionMetadataTimeout = 2 * time.Second
+
+// sessionMetadataTTL is how long a path's project and branch are reused
+// before git is asked again, so a checkout shows up on a later refresh.
+var sessionMetadataTTL = 30 * time.Second
+
+type sessionMeta struct {
+	project string
+	branch  string
+}
+
+// sessionMetadataCache holds looked up metadata by session path. Its zero
+// value is empty and ready to use.
+type sessionMetadataCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedSessionMeta
+}
+
+type cachedSessionMeta struct {
+	meta    sessionMeta
+	expires time.Time
+}
+
+// lookup returns metadata for paths, running git only for paths that are not
+// cached or whose entry expired. Entries for paths no longer listed are
+// dropped; a lookup that timed out is retried on the next call.
+func (c *sessionMetadataCache) lookup(paths []string, now time.Time) map[string]sessionMeta {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results := make(map[string]sessionMeta, len(paths))
+	entries := make(map[string]cachedSessionMeta, len(paths))
+	var stale []string
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if entry, ok := c.entries[path]; ok && now.Before(entry.expires) {
+			results[path] = entry.meta
+			entries[path] = entry
+			continue
+		}
+		stale = append(stale, path)
+	}
+	for path, meta := range lookupSessionMetadata(stale) {
+		results[path] = meta
+		entries[path] = cachedSessionMeta{meta: meta, expires: now.Add(sessionMetadataTTL)}
+	}
+	c.entries = entries
+	return results
+}
+
+// lookupSessionMetadata resolves project and branch names for each distinct
+// path with a bounded pool of workers. Paths whose lookup times out are left
+// out of the result.
+func lookupSessionMetadata(paths []string) map[string]sessionMeta {
+	unique := make([]string, 0, len(paths))
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		unique = append(unique, path)
+	}
+
+	results := make(map[string]sessionMeta, len(unique))
+	if len(unique) == 0 {
+		return results
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for range min(sessionMetadataWorkers, len(unique)) {
+		wg.Go(func() {
+			for path := range jobs {
+				ctx, cancel := context.WithTimeout(context.Background(), sessionMetadataTimeout)
+				projectName, branch := sessionMetadata(ctx, path)
+				timedOut := ctx.Err() != nil
+				cancel()
+				if timedOut {
+					continue
+				}
+				mu.Lock()
+				results[path] = sessionMeta{project: projectName, branch: branch}
+				mu.Unlock()
+			}
+		})
+	}
+	for _, path := range unique {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func sessionMetadata(ctx context.Context, dirPath string) (projectName, branch string) {
 	dirPath = strings.TrimSpace(dirPath)
 	if dirPath == "" {
 		return "", ""
 	}
-	projectName = sessionProjectName(dirPath)
-	branch = gitOutput("-C", dirPath, "branch", "--show-current")
+	projectName = sessionProjectName(ctx, dirPath)
+	branch = gitOutput(ctx, "-C", dirPath, "branch", "--show-current")
+	if ctx.Err() != nil {
+		return "", ""
+	}
 	return strings.TrimSpace(projectName), strings.TrimSpace(branch)
 }
 
-func sessionProjectName(dirPath string) string {
-	commonDir := gitOutput("-C", dirPath, "rev-parse", "--git-common-dir")
+func sessionProjectName(ctx context.Context, dirPath string) string {
+	commonDir := gitOutput(ctx, "-C", dirPath, "rev-parse", "--git-common-dir")
 	if commonDir != "" {
 		if !filepath.IsAbs(commonDir) {
 			commonDir = filepath.Clean(filepath.Join(dirPath, commonDir))
@@ -169,7 +332,7 @@ func sessionProjectName(dirPath string) string {
 		}
 	}
 
-	topLevel := gitOutput("-C", dirPath, "rev-parse", "--show-toplevel")
+	topLevel := gitOutput(ctx, "-C", dirPath, "rev-parse", "--show-toplevel")
 	if topLevel == "" {
 		topLevel = dirPath
 	}
@@ -180,8 +343,8 @@ func sessionProjectName(dirPath string) string {
 	return name
 }
 
-func gitOutput(args ...string) string {
-	cmd := exec.Command("git", args...)
+func gitOutput(ctx context.Context, args ...string) string {
+	cmd := exec.CommandContext(ctx, "git", args...)
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -227,115 +390,141 @@ func sessionNameFor(spec core.SessionSpec) (string, error) {
 	return sanitizeSessionPart(cleanPath, "worktree"), nil
 }
 
-func ensureWorkspaceSession(sessionName, dirPath, selectedTool string) error {
-	if _, err := ensureToolWindow(sessionName, dirPath, selectedTool); err != nil {
-		return err
-	}
-
+// ensureWorkspaceSession makes sure the workspace session has a window for
+// every supported tool and focuses the selected one, in a single tmux chain.
+func ensureWorkspaceSession(sessionName, dirPath, selectedTool string, agentExit []string) error {
+	tools := []string{selectedTool}
 	for _, tool := range core.SupportedTools() {
-		if tool == selectedTool {
-			continue
-		}
-		if _, err := ensureToolWindow(sessionName, dirPath, tool); err != nil {
-			return err
+		if tool != selectedTool {
+			tools = append(tools, tool)
 		}
 	}
-
+	selectArgs := []string{"select-window", "-t", tmuxSessionTarget(sessionName) + ":" + selectedTool}
+	if _, err := ensureToolWindows(sessionName, dirPath, tools, agentExit, selectArgs); err != nil {
+		return fmt.Errorf("failed to prepare tmux session: %w", err)
+	}
 	return nil
 }
 
-func ensureToolWindow(sessionName, dirPath, tool string) (bool, error) {
-	tool = strings.TrimSpace(tool)
-	if tool == "" {
-		return false, fmt.Errorf("session tool is required")
-	}
-
-	check := exec.Command("tmux", "has-session", "-t", tmuxSessionTarget(sessionName))
-	sessionExists := check.Run() == nil
-
-	if !sessionExists {
-		if err := createSessionWithToolWindow(sessionName, dirPath, tool); err != nil {
-			if !isTmuxDuplicateSessionError(err) {
-				return false, err
-			}
-		} else {
-			return true, nil
+// ensureToolWindows creates the session and any missing tool windows with one
+// tmux invocation, reporting which tools got a new window. The server checks
+// for each window itself, so a concurrent client cannot race the check. Extra
+// commands are appended to the same chain.
+func ensureToolWindows(sessionName, dirPath string, tools, agentExit []string, extra ...[]string) (map[string]bool, error) {
+	for _, tool := range tools {
+		if strings.TrimSpace(tool) == "" {
+			return nil, fmt.Errorf("session tool is required")
 		}
 	}
 
-	if hasToolWindow(sessionName, tool) {
-		return false, nil
+	chain := [][]string{{"start-server"}}
+	if len(tools) > 0 {
+		chain = append(chain, unlessTmuxFormat(sessionExistsFormat(sessionName),
+			printWindowName(newSessionArgs(sessionName, dirPath, tools[0], agentExit))))
+	}
+	for _, tool := range tools {
+		chain = append(chain, unlessTmuxFormat(windowExistsFormat(sessionName, tool),
+			printWindowName(newWindowArgs(sessionName, dirPath, tool, agentExit))))
 	}
+	chain = append(chain, extra...)
 
-	if err := createWindow(sessionName, dirPath, tool); err != nil {
-		if isTmuxDuplicateWindowError(err) {
-			return false, nil
+	output, err := runTmuxChain(chain)
+	if err != nil {
+		return nil, err
+	}
+	created := make(map[string]bool, len(tools))
+	for line := range strings.SplitSeq(output, "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			created[name] = true
 		}
-		return false, err
 	}
+	return created, nil
+}
+
+// sessionExistsFormat expands to 1 when the session exists.
+func sessionExistsFormat(sessionName string) string {
+	return "#{S:#{?#{==:#{session_name}," + escapeTmuxFormat(sessionName) + "},1,}}"
+}
 
-	return true, nil
+func windowExistsFormat(sessionName, tool string) string {
+	return "#{S:#{?#{==:#{session_name}," + escapeTmuxFormat(sessionName) + "},#{W:#{?#{==:#{window_name}," + escapeTmuxFormat(tool) + "},1,}},}}"
 }
 
-func isTmuxDuplicateSessionError(err error) bool {
-	return strings.Contains(strings.ToLower(err.Error()), "duplicate session")
+// tmuxFormatEscaper keeps a name literal inside a format. Adopted and aliased
+// session names may hold any character, and a bare "," or "}" would end the
+// comparison early.
+var tmuxFormatEscaper = strings.NewReplacer("#", "##", ",", "#,", "}", "#}")
+
+func escapeTmuxFormat(s string) string {
+	return tmuxFormatEscaper.Replace(s)
 }
 
-func isTmuxDuplicateWindowError(err error) bool {
-	return strings.Contains(strings.ToLower(err.Error()), "duplicate window")
+// unlessTmuxFormat runs command only when format expands to an empty string.
+func unlessTmuxFormat(format string, command []string) []string {
+	return []string{"if-shell", "-F", format, "", tmuxCommandString(command)}
 }
 
-func createSessionWithToolWindow(sessionName, dirPath, tool string) error {
-	shell, commandArgs := toolCommand(tool)
-	args := []string{"new-session", "-d", "-s", sessionName}
-	args = append(args, tmuxEnvArgs(tool)...)
-	args = append(args, "-n", tool, "-c", dirPath, shell)
-	args = append(args, commandArgs...)
-	cmd := exec.Command("tmux", args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to create tmux session: %w (output: %s)", err, string(output))
-	}
-	return nil
+// printWindowName makes a new-session or new-window command print the name of
+// the window it created.
+func printWindowName(command []string) []string {
+	return slices.Concat(command[:1], []string{"-P", "-F", "#{window_name}"}, command[1:])
 }
 
-func hasToolWindow(sessionName, tool string) bool {
-	target := tmuxSessionTarget(sessionName) + ":" + tool
-	check := exec.Command("tmux", "list-windows", "-t", tmuxSessionTarget(sessionName), "-F", "#{window_name}")
-	output, err := check.Output()
-	if err != nil {
-		return false
+// tmuxCommandString quotes a command for tmux to parse again, as if-shell does
+// with its commands.
+func tmuxCommandString(command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
 	}
-	for line := range strings.SplitSeq(string(output), "\n") {
-		if strings.TrimSpace(line) == tool {
-			return true
-		}
-	}
-	check = exec.Command("tmux", "has-session", "-t", target)
-	return check.Run() == nil
+	return strings.Join(quoted, " ")
+}
+
+func newSessionArgs(sessionName, dirPath, tool string, agentExit []string) []string {
+	shell, commandArgs := toolCommand(tool, dirPath, agentExit)
+	args := []string{"new-session", "-d", "-s", sessionName}
+	args = append(args, tmuxEnvArgs(tool)...)
+	args = append(args, "-n", tool, "-c", dirPath, shell)
+	return append(args, commandArgs...)
 }
 
-func createWindow(sessionName, dirPath, tool string) error {
-	shell, commandArgs := toolCommand(tool)
+func newWindowArgs(sessionName, dirPath, tool string, agentExit []string) []string {
+	shell, commandArgs := toolCommand(tool, dirPath, agentExit)
 	args := []string{"new-window", "-d", "-t", tmuxSessionTarget(sessionName), "-n", tool}
 	args = append(args, tmuxEnvArgs(tool)...)
 	args = append(args, "-c", dirPath, shell)
-	args = append(args, commandArgs...)
+	return append(args, commandArgs...)
+}
+
+// runTmuxChain runs several tmux commands in one process, separated by ";",
+// and returns what they print.
+func runTmuxChain(commands [][]string) (string, error) {
+	var args []string
+	for i, command := range commands {
+		if i > 0 {
+			args = append(args, ";")
+		}
+		for _, arg := range command {
+			args = append(args, tmuxChainArg(arg))
+		}
+	}
+	var stderr bytes.Buffer
 	cmd := exec.Command("tmux", args...)
-	output, err := cmd.CombinedOutput()
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("failed to create tmux window: %w (output: %s)", err, string(output))
+		return "", fmt.Errorf("tmux command failed: %w (output: %s)", err, strings.TrimSpace(stderr.String()+string(output)))
 	}
-	return nil
+	return string(output), nil
 }
 
-func selectWindow(sessionName, tool string) error {
-	cmd := exec.Command("tmux", "select-window", "-t", tmuxSessionTarget(sessionName)+":"+tool)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to select tmux window: %w (output: %s)", err, string(output))
+// tmuxChainArg escapes a trailing semicolon, which tmux would otherwise read
+// as a command separator.
+func tmuxChainArg(arg string) string {
+	if strings.HasSuffix(arg, ";") && !strings.HasSuffix(arg, `\;`) {
+		return arg[:len(arg)-1] + `\;`
 	}
-	return nil
+	return arg
 }
 
 func switchClient(sessionName string) error {
@@ -375,7 +564,9 @@ func currentClientTTY() string {
 	return strings.TrimSpace(string(output))
 }
 
-func toolCommand(tool string) (shell string, args []string) {
+// toolCommand runs the tool and then hands the window to an interactive
+// shell. With agentExit set, that command runs in between.
+func toolCommand(tool, dirPath string, agentExit []string) (shell string, args []string) {
 	shell = os.Getenv("SHELL")
 	if strings.TrimSpace(shell) == "" {
 		shell = "/bin/sh"
@@ -383,7 +574,12 @@ func toolCommand(tool string) (shell string, args []string) {
 	if !core.ToolNeedsWarmup(tool) {
 		return shell, nil
 	}
-	return shell, []string{"-c", `"$1"; exec "$0"`, shell, tool}
+	if len(agentExit) == 0 {
+		return shell, []string{"-c", `"$1"; exec "$0"`, shell, tool}
+	}
+	args = []string{"-c", `"$1"; shift; "$@"; exec "$0"`, shell, tool}
+	args = append(args, agentExit...)
+	return shell, append(args, "--worktree", dirPath, "--tool", tool)
 }
 
 func tmuxEnvArgs(tool string) []string {
@@ -0,0 +1,140 @@
+package adapters
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/ports"
+)
+
+// watchSessionPrefix names the hidden session that hosts the control-mode
+// client. It is destroyed as soon as the client goes away, even when rivet
+// does not get to close it.
+const watchSessionPrefix = "rivet-watch-"
+
+const controlCloseTimeout = 2 * time.Second
+
+// controlNotifications maps tmux control-mode notifications to event names.
+var controlNotifications = map[string]string{
+	"%sessions-changed":        "sessions-changed",
+	"%session-renamed":         "session-renamed",
+	"%window-add":              "window-add",
+	"%window-close":            "window-close",
+	"%unlinked-window-add":     "unlinked-window-add",
+	"%unlinked-window-close":   "unlinked-window-close",
+	"%unlinked-window-renamed": "unlinked-window-renamed",
+}
+
+type tmuxControlSubscription struct {
+	cmd         *exec.Cmd
+	stdin       io.WriteCloser
+	sessionName string
+	events      chan string
+	done        chan struct{}
+	closeOnce   sync.Once
+	closeErr    error
+}
+
+// WatchSessions starts a tmux control-mode client in a hidden session and
+// forwards session and window notifications until Close is called. It
+// never starts a tmux server: without one there is nothing to watch.
+func (t *TmuxSession) WatchSessions() (ports.SessionSubscription, error) {
+	sessionName := fmt.Sprintf("%s%d", watchSessionPrefix, os.Getpid())
+	cmd := exec.Command("tmux", "-N", "-C",
+		"new-session", "-s", sessionName, "cat", ";",
+		"set-option", "-t", tmuxSessionTarget(sessionName), "destroy-unattached", "on")
+	// tmux refuses to attach a nested client while $TMUX is set.
+	cmd.Env = environWithout("TMUX")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start tmux control mode: %w", err)
+	}
+
+	sub := &tmuxControlSubscription{
+		cmd:         cmd,
+		stdin:       stdin,
+		sessionName: sessionName,
+		events:      make(chan string, 1),
+		done:        make(chan struct{}),
+	}
+	go sub.read(stdout)
+	return sub, nil
+}
+
+func (s *tmuxControlSubscription) Events() <-chan string {
+	return s.events
+}
+
+func (s *tmuxControlSubscription) read(stdout io.Reader) {
+	defer close(s.done)
+	defer close(s.events)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "%") {
+			continue
+		}
+		notification, _, _ := strings.Cut(line, " ")
+		if notification == "%exit" {
+			return
+		}
+		event, ok := controlNotifications[notification]
+		if !ok {
+			continue
+		}
+		// Bursts collapse into one pending event; a refresh covers all of them.
+		select {
+		case s.events <- event:
+		default:
+		}
+	}
+}
+
+// Close kills the hidden session, which ends the control client, and waits
+// for the reader to drain.
+func (s *tmuxControlSubscription) Close() error {
+	s.closeOnce.Do(func() {
+		_, _ = fmt.Fprintf(s.stdin, "kill-session -t %s\n", tmuxSessionTarget(s.sessionName))
+		_ = s.stdin.Close()
+
+		select {
+		case <-s.done:
+		case <-time.After(controlCloseTimeout):
+			_ = s.cmd.Process.Kill()
+			<-s.done
+		}
+		var exitErr *exec.ExitError
+		if err := s.cmd.Wait(); err != nil && !errors.As(err, &exitErr) {
+			s.closeErr = err
+		}
+	})
+	return s.closeErr
+}
+
+func environWithout(key string) []string {
+	prefix := key + "="
+	env := os.Environ()
+	filtered := make([]string, 0, len(env))
+	for _, entry := range env {
+		if !strings.HasPrefix(entry, prefix) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
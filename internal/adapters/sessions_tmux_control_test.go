@@ -0,0 +1,82 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchSessionsForwardsNotificationsAndClosesCleanly(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "tmux.log")
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+echo "args $@" >> "$TMUX_LOG"
+echo "%begin 1 1 0"
+echo "%end 1 1 0"
+echo "%output %1 ignored"
+echo "%window-close @3"
+while read -r line; do
+  echo "stdin $line" >> "$TMUX_LOG"
+done
+echo "%exit"
+`)
+	t.Setenv("TMUX_LOG", logPath)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session := &TmuxSession{}
+	sub, err := session.WatchSessions()
+	if err != nil {
+		t.Fatalf("unexpected watch error: %v", err)
+	}
+
+	select {
+	case event := <-sub.Events():
+		if event != "window-close" {
+			t.Fatalf("expected window-close event, got %q", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for session event")
+	}
+
+	if err := sub.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Fatalf("expected events channel to be closed")
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read tmux log: %v", err)
+	}
+	log := string(content)
+	if !strings.Contains(log, "args -N -C new-session -s "+watchSessionPrefix) {
+		t.Fatalf("expected a control-mode session that never starts a server, got log:\n%s", log)
+	}
+	if !strings.Contains(log, "destroy-unattached on") {
+		t.Fatalf("expected the watch session to go away with its client, got log:\n%s", log)
+	}
+	if !strings.Contains(log, "stdin kill-session -t ="+watchSessionPrefix) {
+		t.Fatalf("expected watch session to be killed on close, got log:\n%s", log)
+	}
+}
+
+func TestListSessionsSkipsWatchSession(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+printf "rivet-watch-42\t/tmp\t0\n"
+printf "demo\t/tmp/demo\t0\n"
+exit 0
+`)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rows, err := listTmuxSessions()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].name != "demo" {
+		t.Fatalf("expected watch session to be hidden, got %+v", rows)
+	}
+}
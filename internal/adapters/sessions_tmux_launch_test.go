@@ -3,6 +3,7 @@ package adapters
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -15,38 +16,14 @@ func TestOpenSessionDetachCreatesSessionWithoutAttach(t *testing.T) {
 	tmuxPath := filepath.Join(tmpDir, "tmux")
 	writeExecutable(t, tmuxPath, `#!/bin/sh
 echo "$@" >> "$TMUX_LOG"
-state="$TMUX_STATE"
-if [ "$1" = "has-session" ]; then
-  if [ -f "$state" ]; then
-    exit 0
-  fi
-  exit 1
-fi
-if [ "$1" = "new-session" ]; then
-  touch "$state"
-  printf "amp\nopencode\nclaude\ncodex\nnone\n" > "$TMUX_WINDOWS"
+if [ "$1" = "start-server" ]; then
   exit 0
 fi
-if [ "$1" = "list-windows" ]; then
-  cat "$TMUX_WINDOWS"
-  exit 0
-fi
-if [ "$1" = "new-window" ]; then
-  exit 0
-fi
-if [ "$1" = "select-window" ]; then
-  exit 0
-fi
-if [ "$1" = "attach-session" ] || [ "$1" = "switch-client" ]; then
-  echo "unexpected command $1" 1>&2
-  exit 1
-fi
-exit 0
+echo "unexpected command $1" 1>&2
+exit 1
 `)
 
 	t.Setenv("TMUX_LOG", logPath)
-	t.Setenv("TMUX_STATE", filepath.Join(tmpDir, "session.state"))
-	t.Setenv("TMUX_WINDOWS", filepath.Join(tmpDir, "windows.state"))
 	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
 	t.Setenv("TMUX", "")
 
@@ -61,17 +38,21 @@ exit 0
 		t.Fatalf("failed to read tmux log: %v", err)
 	}
 	log := string(content)
-	if !strings.Contains(log, "has-session -t =-tmp-project") {
-		t.Fatalf("expected has-session check, got log:\n%s", log)
+	lines := strings.Split(strings.TrimSpace(log), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected a single command chain, got log:\n%s", log)
 	}
-	if !strings.Contains(log, "new-session -d -s -tmp-project") {
-		t.Fatalf("expected new-session call, got log:\n%s", log)
+	if !strings.HasPrefix(lines[0], "start-server ; if-shell -F "+sessionExistsFormat("-tmp-project")+"  'new-session' '-P' '-F' '#{window_name}' '-d' '-s' '-tmp-project'") {
+		t.Fatalf("expected the chain to create the session unless it exists, got log:\n%s", log)
 	}
-	if !strings.Contains(log, "list-windows -t =-tmp-project -F #{window_name}") {
-		t.Fatalf("expected window checks for workspace session, got log:\n%s", log)
+	for _, tool := range []string{"opencode", "claude", "codex", "none"} {
+		guard := "; if-shell -F " + windowExistsFormat("-tmp-project", tool) + "  'new-window' '-P' '-F' '#{window_name}' '-d' '-t' '=-tmp-project' '-n' '" + tool + "'"
+		if !strings.Contains(lines[0], guard) {
+			t.Fatalf("expected %s window to be created unless it exists, got log:\n%s", tool, log)
+		}
 	}
-	if !strings.Contains(log, "select-window -t =-tmp-project:amp") {
-		t.Fatalf("expected selected window to be focused, got log:\n%s", log)
+	if !strings.HasSuffix(lines[0], "; select-window -t =-tmp-project:amp") {
+		t.Fatalf("expected selected window to be focused at the end of the chain, got log:\n%s", log)
 	}
 	if strings.Contains(log, "attach-session") || strings.Contains(log, "switch-client") {
 		t.Fatalf("did not expect attach or switch in detach mode, got log:\n%s", log)
@@ -84,14 +65,7 @@ func TestOpenSessionInsideTmuxUsesSwitchClient(t *testing.T) {
 	tmuxPath := filepath.Join(tmpDir, "tmux")
 	writeExecutable(t, tmuxPath, `#!/bin/sh
 echo "$@" >> "$TMUX_LOG"
-if [ "$1" = "has-session" ]; then
-  exit 0
-fi
-if [ "$1" = "list-windows" ]; then
-  printf "amp\nopencode\nclaude\ncodex\nnone\n"
-  exit 0
-fi
-if [ "$1" = "select-window" ]; then
+if [ "$1" = "start-server" ]; then
   exit 0
 fi
 if [ "$1" = "switch-client" ]; then
@@ -116,8 +90,8 @@ exit 1
 		t.Fatalf("failed to read tmux log: %v", err)
 	}
 	log := string(content)
-	if !strings.Contains(log, "has-session -t =-tmp-project") {
-		t.Fatalf("expected has-session check, got log:\n%s", log)
+	if !strings.HasPrefix(log, "start-server ;") {
+		t.Fatalf("expected the windows to be ensured in one chain, got log:\n%s", log)
 	}
 	if !strings.Contains(log, "switch-client -t =-tmp-project") {
 		t.Fatalf("expected switch-client call, got log:\n%s", log)
@@ -125,6 +99,9 @@ exit 1
 	if !strings.Contains(log, "select-window -t =-tmp-project:amp") {
 		t.Fatalf("expected selected window switch, got log:\n%s", log)
 	}
+	if strings.Contains(log, "; new-window") || strings.Contains(log, "; new-session") {
+		t.Fatalf("expected every window to be created only when missing, got log:\n%s", log)
+	}
 	if strings.Contains(log, "attach-session") {
 		t.Fatalf("did not expect attach-session inside tmux, got log:\n%s", log)
 	}
@@ -136,21 +113,6 @@ func TestPrewarmSessionReturnsFalseWhenSessionExists(t *testing.T) {
 	tmuxPath := filepath.Join(tmpDir, "tmux")
 	writeExecutable(t, tmuxPath, `#!/bin/sh
 echo "$@" >> "$TMUX_LOG"
-if [ "$1" = "has-session" ]; then
-  exit 0
-fi
-if [ "$1" = "list-windows" ]; then
-  printf "amp\n"
-  exit 0
-fi
-if [ "$1" = "new-session" ]; then
-  echo "new-session should not be called" 1>&2
-  exit 1
-fi
-if [ "$1" = "new-window" ]; then
-  echo "new-window should not be called" 1>&2
-  exit 1
-fi
 exit 0
 `)
 
@@ -168,46 +130,70 @@ exit 0
 	}
 }
 
-func TestPrewarmSessionIgnoresDuplicateSessionRace(t *testing.T) {
+func TestTmuxCommandStringQuotesEveryArgument(t *testing.T) {
+	got := tmuxCommandString([]string{"new-window", "-e", `CONFIG={"a": "it's"}`, "sh", "-c", `"$1"; exec "$0"`})
+	want := `'new-window' '-e' 'CONFIG={"a": "it'\''s"}' 'sh' '-c' '"$1"; exec "$0"'`
+	if got != want {
+		t.Fatalf("expected every argument to be single quoted, got %s", got)
+	}
+}
+
+func TestPrewarmToolsUsesSingleRoundTrip(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "tmux.log")
 	tmuxPath := filepath.Join(tmpDir, "tmux")
-	windowsPath := filepath.Join(tmpDir, "windows.state")
-	if err := os.WriteFile(windowsPath, []byte("amp\n"), 0o644); err != nil {
-		t.Fatalf("failed to write windows state: %v", err)
-	}
 	writeExecutable(t, tmuxPath, `#!/bin/sh
 echo "$@" >> "$TMUX_LOG"
-if [ "$1" = "has-session" ]; then
-  exit 1
-fi
-if [ "$1" = "new-session" ]; then
-  echo "duplicate session: $4" 1>&2
-  exit 1
-fi
-if [ "$1" = "list-windows" ]; then
-  cat "$TMUX_WINDOWS"
-  exit 0
-fi
-if [ "$1" = "new-window" ]; then
-  printf "amp\ncodex\n" > "$TMUX_WINDOWS"
-  exit 0
-fi
+printf "claude\ncodex\n"
 exit 0
 `)
 
 	t.Setenv("TMUX_LOG", logPath)
-	t.Setenv("TMUX_WINDOWS", windowsPath)
 	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
 
 	session := &TmuxSession{}
-	spec := core.SessionSpec{DirPath: "/tmp/project", Tool: "codex"}
-	created, err := session.PrewarmSession(spec)
+	created, err := session.PrewarmTools("/tmp/project", []string{"amp", "claude", "codex"})
 	if err != nil {
 		t.Fatalf("unexpected prewarm error: %v", err)
 	}
-	if !created {
-		t.Fatalf("expected created=true when fallback creates tool window")
+	if created["amp"] || !created["claude"] || !created["codex"] {
+		t.Fatalf("unexpected created windows: %v", created)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read tmux log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected a single command chain, got log:\n%s", string(content))
+	}
+	for _, tool := range []string{"amp", "claude", "codex"} {
+		if !strings.Contains(lines[0], "if-shell -F "+windowExistsFormat("-tmp-project", tool)) {
+			t.Fatalf("expected the %s window check in the chain, got log:\n%s", tool, string(content))
+		}
+	}
+}
+
+func TestOpenSessionEscapesAdoptedNamesInFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "tmux.log")
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+echo "$@" >> "$TMUX_LOG"
+exit 0
+`)
+	aliasesPath := filepath.Join(tmpDir, "sessions.json")
+	if err := os.WriteFile(aliasesPath, []byte(`{"aliases":{"/tmp/project":"weird,name}#1"}}`), 0o644); err != nil {
+		t.Fatalf("failed to write aliases: %v", err)
+	}
+
+	t.Setenv("TMUX_LOG", logPath)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TMUX", "")
+
+	session := &TmuxSession{aliasesPath: aliasesPath}
+	if err := session.OpenSession(core.SessionSpec{DirPath: "/tmp/project", Tool: "amp", Detach: true}); err != nil {
+		t.Fatalf("unexpected open-session error: %v", err)
 	}
 
 	content, err := os.ReadFile(logPath)
@@ -215,11 +201,20 @@ exit 0
 		t.Fatalf("failed to read tmux log: %v", err)
 	}
 	log := string(content)
-	if !strings.Contains(log, "new-session -d -s -tmp-project") {
-		t.Fatalf("expected attempted new-session, got log:\n%s", log)
+	if !strings.Contains(log, "if-shell -F #{S:#{?#{==:#{session_name},weird#,name#}##1},1,}}  'new-session'") {
+		t.Fatalf("expected the session check to keep the adopted name literal, got log:\n%s", log)
+	}
+	if !strings.Contains(log, "#{==:#{session_name},weird#,name#}##1},#{W:") {
+		t.Fatalf("expected the window checks to keep the adopted name literal, got log:\n%s", log)
+	}
+}
+
+func TestTmuxChainArgEscapesTrailingSemicolon(t *testing.T) {
+	if got := tmuxChainArg("FOO=a;"); got != `FOO=a\;` {
+		t.Fatalf("expected trailing semicolon to be escaped, got %q", got)
 	}
-	if !strings.Contains(log, "new-window -d -t =-tmp-project -n codex") {
-		t.Fatalf("expected new-window fallback, got log:\n%s", log)
+	if got := tmuxChainArg(`"$1"; exec "$0"`); got != `"$1"; exec "$0"` {
+		t.Fatalf("expected inner semicolon to be preserved, got %q", got)
 	}
 }
 
@@ -332,7 +327,7 @@ func TestToolCommandAndTmuxEnvArgs(t *testing.T) {
 	t.Setenv("COLORTERM", "truecolor")
 	t.Setenv("COLORFGBG", "15;0")
 
-	shell, args := toolCommand(core.ToolNone)
+	shell, args := toolCommand(core.ToolNone, "/projects/demo", nil)
 	if shell != "/bin/bash" {
 		t.Fatalf("expected configured shell, got %q", shell)
 	}
@@ -340,7 +335,7 @@ func TestToolCommandAndTmuxEnvArgs(t *testing.T) {
 		t.Fatalf("expected no command args for none tool, got %v", args)
 	}
 
-	shell, args = toolCommand("amp")
+	shell, args = toolCommand("amp", "/projects/demo", nil)
 	if shell != "/bin/bash" {
 		t.Fatalf("expected configured shell, got %q", shell)
 	}
@@ -348,6 +343,12 @@ func TestToolCommandAndTmuxEnvArgs(t *testing.T) {
 		t.Fatalf("expected warmup command args for amp, got %v", args)
 	}
 
+	_, args = toolCommand("amp", "/projects/demo", []string{"/usr/bin/rv", "hook", "--event", "agent_exit"})
+	want := []string{"/usr/bin/rv", "hook", "--event", "agent_exit", "--worktree", "/projects/demo", "--tool", "amp"}
+	if len(args) < len(want) || !slices.Equal(args[len(args)-len(want):], want) {
+		t.Fatalf("expected the agent exit command after the tool, got %v", args)
+	}
+
 	envArgs := tmuxEnvArgs("opencode")
 	joined := strings.Join(envArgs, " ")
 	if !strings.Contains(joined, "OPENCODE_CONFIG_CONTENT=") {
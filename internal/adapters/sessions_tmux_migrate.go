@@ -0,0 +1,184 @@
+package adapters
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+const rivetSessionAliasesFile = "~/.rivet/sessions.json"
+
+// sessionAliases maps a cleaned worktree path to the adopted session name.
+type sessionAliases struct {
+	Aliases map[string]string `json:"aliases"`
+}
+
+// sessionName resolves the tmux session for a spec, preferring an adopted
+// legacy name over the current naming scheme.
+func (t *TmuxSession) sessionName(spec core.SessionSpec) (string, error) {
+	name, err := sessionNameFor(spec)
+	if err != nil {
+		return "", err
+	}
+	aliases, err := t.loadAliases()
+	if err != nil {
+		return "", err
+	}
+	if alias := aliases.Aliases[filepath.Clean(spec.DirPath)]; alias != "" {
+		return alias, nil
+	}
+	return name, nil
+}
+
+// FindLegacySessions lists the running sessions for worktreePaths that carry
+// a name from an earlier naming scheme. Each path gets at most one
+// migration, for its most recently attached session, since only one session
+// can take the current name. Paths whose current or adopted session is
+// already running are left alone.
+func (t *TmuxSession) FindLegacySessions(worktreePaths []string) ([]core.SessionMigration, error) {
+	if len(worktreePaths) == 0 {
+		return nil, nil
+	}
+	known := make(map[string]bool, len(worktreePaths))
+	for _, path := range worktreePaths {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		known[filepath.Clean(path)] = true
+	}
+
+	rows, err := listTmuxSessions()
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := t.loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	running := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		running[row.name] = true
+	}
+
+	byPath := make(map[string]tmuxSessionRow)
+	for _, row := range rows {
+		if row.path == "" {
+			continue
+		}
+		dirPath := filepath.Clean(row.path)
+		if !known[dirPath] {
+			continue
+		}
+		current, err := sessionNameFor(core.SessionSpec{DirPath: dirPath})
+		if err != nil {
+			continue
+		}
+		if running[current] || running[aliases.Aliases[dirPath]] || !isLegacySessionName(row.name, dirPath) {
+			continue
+		}
+		if earlier, ok := byPath[dirPath]; ok && !newerSessionRow(row, earlier) {
+			continue
+		}
+		byPath[dirPath] = row
+	}
+
+	migrations := make([]core.SessionMigration, 0, len(byPath))
+	for dirPath, row := range byPath {
+		current, _ := sessionNameFor(core.SessionSpec{DirPath: dirPath})
+		migrations = append(migrations, core.SessionMigration{
+			Name:        row.name,
+			DirPath:     dirPath,
+			CurrentName: current,
+		})
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Name < migrations[j].Name
+	})
+	return migrations, nil
+}
+
+// isLegacySessionName reports whether name is one rivet gave the session for
+// dirPath under an earlier naming scheme: one session per tool, named
+// "<project>__<tool>". Sessions the user created by hand in a worktree keep
+// their names.
+func isLegacySessionName(name, dirPath string) bool {
+	project := sanitizeSessionPart(filepath.Base(dirPath), "worktree")
+	for _, tool := range core.SupportedTools() {
+		if name == project+"__"+tool {
+			return true
+		}
+	}
+	return false
+}
+
+// newerSessionRow reports whether a was attached after b, breaking ties by
+// name so the choice does not depend on tmux's listing order.
+func newerSessionRow(a, b tmuxSessionRow) bool {
+	if !a.lastActive.Equal(b.lastActive) {
+		return a.lastActive.After(b.lastActive)
+	}
+	return a.name < b.name
+}
+
+func (t *TmuxSession) MigrateSession(migration core.SessionMigration) error {
+	if strings.TrimSpace(migration.Name) == "" {
+		return fmt.Errorf("session name is required")
+	}
+	if strings.TrimSpace(migration.DirPath) == "" {
+		return fmt.Errorf("session directory is required")
+	}
+	dirPath := filepath.Clean(migration.DirPath)
+
+	switch migration.Action {
+	case core.SessionMigrationRename:
+		target := migration.CurrentName
+		if target == "" {
+			name, err := sessionNameFor(core.SessionSpec{DirPath: dirPath})
+			if err != nil {
+				return err
+			}
+			target = name
+		}
+		cmd := exec.Command("tmux", "rename-session", "-t", tmuxSessionTarget(migration.Name), target)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to rename tmux session: %w (output: %s)", err, strings.TrimSpace(string(output)))
+		}
+		return t.updateAliases(func(aliases map[string]string) {
+			delete(aliases, dirPath)
+		})
+	case core.SessionMigrationAdopt:
+		return t.updateAliases(func(aliases map[string]string) {
+			aliases[dirPath] = migration.Name
+		})
+	default:
+		return fmt.Errorf("unsupported session migration action: %q", migration.Action)
+	}
+}
+
+func (t *TmuxSession) loadAliases() (sessionAliases, error) {
+	var aliases sessionAliases
+	if err := readStateFile(t.aliasesPath, "session aliases", &aliases); err != nil {
+		return sessionAliases{Aliases: map[string]string{}}, err
+	}
+	if aliases.Aliases == nil {
+		aliases.Aliases = map[string]string{}
+	}
+	return aliases, nil
+}
+
+func (t *TmuxSession) updateAliases(mutate func(map[string]string)) error {
+	if t.aliasesPath == "" {
+		return fmt.Errorf("session alias registry is not configured")
+	}
+	aliases, err := t.loadAliases()
+	if err != nil {
+		return err
+	}
+	mutate(aliases.Aliases)
+	return writeStateFile(t.aliasesPath, aliases)
+}
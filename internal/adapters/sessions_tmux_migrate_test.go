@@ -0,0 +1,136 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func TestFindLegacySessionsReportsKnownWorktreesWithOldNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+if [ "$1" = "list-sessions" ]; then
+  printf "demo__amp\t/projects/demo\t0\n"
+  printf -- "-projects-api\t/projects/api\t0\n"
+  printf "scratch\t/tmp/scratch\t0\n"
+  printf "adopted\t/projects/web\t0\n"
+  exit 0
+fi
+exit 1
+`)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	aliasesPath := filepath.Join(tmpDir, "sessions.json")
+	if err := os.WriteFile(aliasesPath, []byte(`{"aliases":{"/projects/web":"adopted"}}`), 0o644); err != nil {
+		t.Fatalf("failed to write aliases: %v", err)
+	}
+
+	session := &TmuxSession{aliasesPath: aliasesPath}
+	legacy, err := session.FindLegacySessions([]string{"/projects/demo", "/projects/api", "/projects/web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(legacy) != 1 {
+		t.Fatalf("expected one legacy session, got %+v", legacy)
+	}
+	if legacy[0].Name != "demo__amp" || legacy[0].CurrentName != "-projects-demo" {
+		t.Fatalf("unexpected legacy session: %+v", legacy[0])
+	}
+}
+
+func TestFindLegacySessionsOffersOneSessionPerWorktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+if [ "$1" = "list-sessions" ]; then
+  printf "demo__amp\t/projects/demo\t100\n"
+  printf "demo__claude\t/projects/demo\t200\n"
+  printf "demo__codex\t/projects/demo\t0\n"
+  printf "api__amp\t/projects/api\t0\n"
+  printf -- "-projects-api\t/projects/api\t0\n"
+  exit 0
+fi
+exit 1
+`)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session := &TmuxSession{}
+	legacy, err := session.FindLegacySessions([]string{"/projects/demo", "/projects/api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(legacy) != 1 || legacy[0].Name != "demo__claude" || legacy[0].CurrentName != "-projects-demo" {
+		t.Fatalf("expected only the most recently attached demo session, got %+v", legacy)
+	}
+}
+
+func TestFindLegacySessionsSkipsUserCreatedSessions(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+if [ "$1" = "list-sessions" ]; then
+  printf "scratch\t/projects/demo\t200\n"
+  printf "demo__amp\t/projects/demo\t100\n"
+  printf "notes\t/projects/api\t0\n"
+  printf "web__vim\t/projects/web\t0\n"
+  exit 0
+fi
+exit 1
+`)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session := &TmuxSession{}
+	legacy, err := session.FindLegacySessions([]string{"/projects/demo", "/projects/api", "/projects/web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(legacy) != 1 || legacy[0].Name != "demo__amp" {
+		t.Fatalf("expected only the session named by rivet to be offered, got %+v", legacy)
+	}
+}
+
+func TestMigrateSessionRenameAndAdopt(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "tmux.log")
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+echo "$@" >> "$TMUX_LOG"
+exit 0
+`)
+	t.Setenv("TMUX_LOG", logPath)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session := &TmuxSession{aliasesPath: filepath.Join(tmpDir, "state", "sessions.json")}
+	err := session.MigrateSession(core.SessionMigration{
+		Name:        "demo__amp",
+		DirPath:     "/projects/demo",
+		CurrentName: "-projects-demo",
+		Action:      core.SessionMigrationRename,
+	})
+	if err != nil {
+		t.Fatalf("unexpected rename error: %v", err)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read tmux log: %v", err)
+	}
+	if !strings.Contains(string(content), "rename-session -t =demo__amp -projects-demo") {
+		t.Fatalf("expected rename-session call, got log:\n%s", string(content))
+	}
+
+	err = session.MigrateSession(core.SessionMigration{
+		Name:    "api__claude",
+		DirPath: "/projects/api",
+		Action:  core.SessionMigrationAdopt,
+	})
+	if err != nil {
+		t.Fatalf("unexpected adopt error: %v", err)
+	}
+	name, err := session.sessionName(core.SessionSpec{DirPath: "/projects/api", Tool: "claude"})
+	if err != nil {
+		t.Fatalf("unexpected session name error: %v", err)
+	}
+	if name != "api__claude" {
+		t.Fatalf("expected adopted name to be used, got %q", name)
+	}
+}
@@ -3,7 +3,9 @@ package adapters
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ariguillegp/rivet/internal/core"
 )
@@ -54,6 +56,29 @@ func TestKillSessionIgnoresMissingSession(t *testing.T) {
 	}
 }
 
+func TestKillSessionByNameTargetsTheExactName(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "tmux.log")
+	writeExecutable(t, filepath.Join(tmpDir, "tmux"), `#!/bin/sh
+echo "$@" >> "$TMUX_LOG"
+exit 0
+`)
+	t.Setenv("TMUX_LOG", logPath)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session := &TmuxSession{}
+	if err := session.KillSessionByName("scratch"); err != nil {
+		t.Fatalf("unexpected kill error: %v", err)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read tmux log: %v", err)
+	}
+	if strings.TrimSpace(string(content)) != "kill-session -t =scratch" {
+		t.Fatalf("expected the named session to be killed, got log:\n%s", content)
+	}
+}
+
 func TestListSessionsIgnoresNoServerRunning(t *testing.T) {
 	tmpDir := t.TempDir()
 	tmuxPath := filepath.Join(tmpDir, "tmux")
@@ -160,3 +185,149 @@ exit 1
 		t.Fatalf("expected branch from git metadata, got %q", sessions[0].Branch)
 	}
 }
+
+func TestListSessionsLooksUpSharedPathOnce(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmuxPath := filepath.Join(tmpDir, "tmux")
+	gitPath := filepath.Join(tmpDir, "git")
+	logPath := filepath.Join(tmpDir, "git.log")
+
+	tmuxScript := `#!/bin/sh
+echo "one	/home/demo/Projects/rivet/main	1735689600"
+echo "two	/home/demo/Projects/rivet/main	1735689600"
+echo "three	/home/demo/Projects/other/main	1735689600"
+exit 0
+`
+
+	gitScript := `#!/bin/sh
+echo "$2 $3" >> "` + logPath + `"
+if [ "$3" = "branch" ]; then
+  echo "main"
+  exit 0
+fi
+exit 1
+`
+
+	if err := os.WriteFile(tmuxPath, []byte(tmuxScript), 0o755); err != nil {
+		t.Fatalf("failed to write tmux stub: %v", err)
+	}
+	if err := os.WriteFile(gitPath, []byte(gitScript), 0o755); err != nil {
+		t.Fatalf("failed to write git stub: %v", err)
+	}
+
+	pathEnv := os.Getenv("PATH")
+	pathSep := string(os.PathListSeparator)
+	t.Setenv("PATH", tmpDir+pathSep+pathEnv)
+
+	session := &TmuxSession{}
+	sessions, err := session.ListSessions()
+	if err != nil {
+		t.Fatalf("expected no error listing sessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("expected three sessions, got %d", len(sessions))
+	}
+	for _, info := range sessions {
+		if info.Branch != "main" {
+			t.Fatalf("expected branch main for %q, got %q", info.Name, info.Branch)
+		}
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read git log: %v", err)
+	}
+	branchCalls := strings.Count(string(content), " branch")
+	if branchCalls != 2 {
+		t.Fatalf("expected one branch lookup per distinct path, got %d:\n%s", branchCalls, content)
+	}
+}
+
+func TestSessionMetadataCacheReusesEntriesUntilTheyExpire(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "git.log")
+	writeExecutable(t, filepath.Join(tmpDir, "git"), `#!/bin/sh
+echo "$2 $3" >> "`+logPath+`"
+if [ "$3" = "branch" ]; then
+  echo "main"
+  exit 0
+fi
+exit 1
+`)
+	t.Setenv("PATH", tmpDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var cache sessionMetadataCache
+	now := time.Now()
+	paths := []string{"/home/demo/Projects/rivet/main"}
+	branchCalls := func() int {
+		content, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("failed to read git log: %v", err)
+		}
+		return strings.Count(string(content), " branch")
+	}
+
+	if meta := cache.lookup(paths, now); meta[paths[0]].branch != "main" {
+		t.Fatalf("expected branch main, got %+v", meta)
+	}
+	if meta := cache.lookup(paths, now.Add(sessionMetadataTTL/2)); meta[paths[0]].branch != "main" || branchCalls() != 1 {
+		t.Fatalf("expected the cached branch without another lookup, got %+v after %d lookups", meta, branchCalls())
+	}
+	cache.lookup(paths, now.Add(sessionMetadataTTL))
+	if branchCalls() != 2 {
+		t.Fatalf("expected an expired entry to be looked up again, got %d lookups", branchCalls())
+	}
+	cache.lookup(nil, now)
+	if len(cache.entries) != 0 {
+		t.Fatalf("expected entries for paths no longer listed to be dropped, got %+v", cache.entries)
+	}
+}
+
+func TestListSessionsKeepsRowWhenMetadataTimesOut(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmuxPath := filepath.Join(tmpDir, "tmux")
+	gitPath := filepath.Join(tmpDir, "git")
+
+	tmuxScript := `#!/bin/sh
+echo "slow	/home/demo/Projects/rivet/main	1735689600"
+exit 0
+`
+
+	gitScript := `#!/bin/sh
+exec sleep 5
+`
+
+	if err := os.WriteFile(tmuxPath, []byte(tmuxScript), 0o755); err != nil {
+		t.Fatalf("failed to write tmux stub: %v", err)
+	}
+	if err := os.WriteFile(gitPath, []byte(gitScript), 0o755); err != nil {
+		t.Fatalf("failed to write git stub: %v", err)
+	}
+
+	pathEnv := os.Getenv("PATH")
+	pathSep := string(os.PathListSeparator)
+	t.Setenv("PATH", tmpDir+pathSep+pathEnv)
+
+	previousTimeout := sessionMetadataTimeout
+	sessionMetadataTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { sessionMetadataTimeout = previousTimeout })
+
+	session := &TmuxSession{}
+	start := time.Now()
+	sessions, err := session.ListSessions()
+	if err != nil {
+		t.Fatalf("expected no error listing sessions: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected metadata lookup to time out quickly, took %v", elapsed)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("expected one session, got %d", len(sessions))
+	}
+	if sessions[0].Name != "slow" {
+		t.Fatalf("expected session name slow, got %q", sessions[0].Name)
+	}
+	if sessions[0].Project != "" || sessions[0].Branch != "" {
+		t.Fatalf("expected empty metadata after timeout, got project=%q branch=%q", sessions[0].Project, sessions[0].Branch)
+	}
+}
@@ -0,0 +1,52 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteStateFileConcurrentWritersLeaveOneWholeFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	path := filepath.Join(dir, "pins.json")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- writeStateFile(path, pinList{Paths: []string{filepath.Join("/projects", string(rune('a'+i)))}})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+
+	var pins pinList
+	if err := readStateFile(path, "pins", &pins); err != nil || len(pins.Paths) != 1 {
+		t.Fatalf("expected one complete write to win, got %+v, %v", pins, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected no temporary files left behind, got %v, %v", entries, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o644 {
+		t.Fatalf("expected a world-readable state file, got %v, %v", info, err)
+	}
+}
+
+func TestReadStateFileKeepsDefaultsWithoutFile(t *testing.T) {
+	pins := pinList{Paths: []string{}}
+	if err := readStateFile(filepath.Join(t.TempDir(), "missing.json"), "pins", &pins); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pins.Paths == nil {
+		t.Fatal("expected the defaults to be kept")
+	}
+}
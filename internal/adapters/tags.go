@@ -0,0 +1,46 @@
+package adapters
+
+const rivetTagsFile = "~/.rivet/tags.json"
+
+// projectTags is the on-disk list of tags set on projects from the UI.
+type projectTags struct {
+	Projects map[string][]string `json:"projects"`
+}
+
+// ProjectTags returns the tags set on each project path from the UI.
+func (f *OSFilesystem) ProjectTags() (map[string][]string, error) {
+	tags, err := f.loadProjectTags()
+	if err != nil {
+		return nil, err
+	}
+	return tags.Projects, nil
+}
+
+// SetProjectTags replaces the tags of a project; no tags forgets it.
+func (f *OSFilesystem) SetProjectTags(projectPath string, tags []string) error {
+	if f.tagsPath == "" {
+		return nil
+	}
+	stored, err := f.loadProjectTags()
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		delete(stored.Projects, projectPath)
+	} else {
+		stored.Projects[projectPath] = tags
+	}
+
+	return writeStateFile(f.tagsPath, stored)
+}
+
+func (f *OSFilesystem) loadProjectTags() (projectTags, error) {
+	var tags projectTags
+	if err := readStateFile(f.tagsPath, "project tags", &tags); err != nil {
+		return projectTags{}, err
+	}
+	if tags.Projects == nil {
+		tags.Projects = map[string][]string{}
+	}
+	return tags, nil
+}
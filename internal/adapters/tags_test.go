@@ -0,0 +1,34 @@
+package adapters
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSetProjectTagsPersistsTags(t *testing.T) {
+	fs := &OSFilesystem{tagsPath: filepath.Join(t.TempDir(), "state", "tags.json")}
+
+	tags, err := fs.ProjectTags()
+	if err != nil || len(tags) != 0 {
+		t.Fatalf("expected no tags, got %v (%v)", tags, err)
+	}
+
+	if err := fs.SetProjectTags("/projects/api", []string{"backend", "oss"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fs.SetProjectTags("/projects/web", []string{"client-x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fs.SetProjectTags("/projects/web", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags, err = fs.ProjectTags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 1 || !slices.Equal(tags["/projects/api"], []string{"backend", "oss"}) {
+		t.Fatalf("expected only api's tags, got %v", tags)
+	}
+}
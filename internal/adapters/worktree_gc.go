@@ -0,0 +1,169 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// WorktreeActivity reports, for every managed worktree of the project,
+// whether its directory is gone, whether its branch is fully merged into the
+// branch checked out in the project root, whether it has uncommitted changes
+// and when it was last committed to. A worktree git cannot read is reported
+// as unknown. Unlike ListWorktrees it does not prune,
+// so worktrees whose directory was removed by hand are reported as missing.
+func (f *OSFilesystem) WorktreeActivity(projectPath string) ([]core.WorktreeActivity, error) {
+	projectPath = expandPath(projectPath)
+	if !hasGitMarker(projectPath) {
+		return nil, nil
+	}
+
+	output, err := gitCommand(projectPath, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git worktree list failed: %w", gitStderr(err))
+	}
+	base := worktreeBranch(projectPath)
+
+	var activity []core.WorktreeActivity
+	for _, entry := range parseWorktreeEntries(output) {
+		if filepath.Clean(entry.worktree.Path) == filepath.Clean(projectPath) || !isManagedWorktree(projectPath, entry.worktree.Path) {
+			continue
+		}
+		wt := core.WorktreeActivity{ProjectPath: projectPath, Worktree: entry.worktree}
+		if _, err := os.Stat(entry.worktree.Path); entry.prunable || errors.Is(err, os.ErrNotExist) {
+			wt.Missing = true
+			activity = append(activity, wt)
+			continue
+		}
+
+		dirty, err := hasChanges(entry.worktree.Path, true)
+		if err != nil {
+			// One broken worktree should not hide the project's others.
+			wt.Unknown = true
+			activity = append(activity, wt)
+			continue
+		}
+		wt.Dirty = dirty
+		wt.LastCommit = lastCommitTime(entry.worktree.Path)
+		branch := entry.worktree.Branch
+		if branch != "" && branch != "(detached)" && base != "" && branch != base && branchMoved(projectPath, branch, base) {
+			wt.Merged = gitCommand(projectPath, "merge-base", "--is-ancestor", "refs/heads/"+branch, "refs/heads/"+base).Run() == nil
+		}
+		activity = append(activity, wt)
+	}
+	return activity, nil
+}
+
+// DeleteBranch deletes a local branch of the project. Without force git
+// refuses to delete a branch that is not fully merged.
+func (f *OSFilesystem) DeleteBranch(projectPath, branch string, force bool) error {
+	projectPath = expandPath(projectPath)
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+	args := []string{"branch", "--delete", branch}
+	if force {
+		args = []string{"branch", "--delete", "--force", branch}
+	}
+	if err := runGit(projectPath, args...); err != nil {
+		if !force && strings.Contains(err.Error(), "not fully merged") {
+			return core.BranchNotMergedError{Branch: branch}
+		}
+		return err
+	}
+	return nil
+}
+
+// BranchMerged reports whether a safe DeleteBranch would delete branch: like
+// git, it checks the branch's upstream when it has one and the project
+// root's HEAD otherwise.
+func (f *OSFilesystem) BranchMerged(projectPath, branch string) (bool, error) {
+	projectPath = expandPath(projectPath)
+	ref := "refs/heads/" + strings.TrimSpace(branch)
+	if err := gitCommand(projectPath, "rev-parse", "--verify", "--quiet", ref).Run(); err != nil {
+		return false, fmt.Errorf("branch not found: %s", branch)
+	}
+	target := "HEAD"
+	if output, err := gitCommand(projectPath, "rev-parse", "--symbolic-full-name", ref+"@{upstream}").Output(); err == nil {
+		if upstream := strings.TrimSpace(string(output)); upstream != "" {
+			target = upstream
+		}
+	}
+	return gitCommand(projectPath, "merge-base", "--is-ancestor", ref, target).Run() == nil, nil
+}
+
+// branchMoved reports whether branch has commits of its own, so that being
+// an ancestor of base means they were merged rather than that the branch
+// never left base. It compares the tip with the commit the branch was
+// created at, from the reflog, or with base when there is no reflog.
+func branchMoved(projectPath, branch, base string) bool {
+	tip := revParse(projectPath, "refs/heads/"+branch)
+	if tip == "" {
+		return false
+	}
+	output, err := gitCommand(projectPath, "reflog", "show", "--format=%H", "refs/heads/"+branch).Output()
+	if created := strings.Fields(string(output)); err == nil && len(created) > 0 {
+		return created[len(created)-1] != tip
+	}
+	return tip != revParse(projectPath, "refs/heads/"+base)
+}
+
+func revParse(projectPath, ref string) string {
+	output, err := gitCommand(projectPath, "rev-parse", "--verify", "--quiet", ref).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+type worktreeEntry struct {
+	worktree core.Worktree
+	prunable bool
+}
+
+// parseWorktreeEntries reads `git worktree list --porcelain` output.
+func parseWorktreeEntries(output []byte) []worktreeEntry {
+	var entries []worktreeEntry
+	for block := range bytes.SplitSeq(output, []byte("\n\n")) {
+		var entry worktreeEntry
+		for line := range strings.SplitSeq(string(block), "\n") {
+			switch {
+			case strings.HasPrefix(line, "worktree "):
+				entry.worktree.Path = strings.TrimPrefix(line, "worktree ")
+				entry.worktree.Name = filepath.Base(entry.worktree.Path)
+			case strings.HasPrefix(line, "branch refs/heads/"):
+				entry.worktree.Branch = strings.TrimPrefix(line, "branch refs/heads/")
+			case line == "detached":
+				entry.worktree.Branch = "(detached)"
+			case line == "prunable" || strings.HasPrefix(line, "prunable "):
+				entry.prunable = true
+			}
+		}
+		if entry.worktree.Path != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// lastCommitTime returns the commit time of HEAD, or the zero time when the
+// worktree has no commits.
+func lastCommitTime(worktreePath string) time.Time {
+	output, err := gitCommand(worktreePath, "log", "-1", "--format=%ct").Output()
+	if err != nil {
+		return time.Time{}
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
@@ -0,0 +1,136 @@
+package adapters
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func TestWorktreeActivityReportsMergedMissingAndDirty(t *testing.T) {
+	projectPath, featurePath := landWorktree(t)
+	fs := &OSFilesystem{}
+	mergedPath, err := fs.CreateWorktree(projectPath, "merged")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	writeFile(t, filepath.Join(mergedPath, "merged.txt"), "merged\n")
+	gitRun(t, mergedPath, "add", "--all")
+	gitRun(t, mergedPath, "commit", "--quiet", "-m", "merged change")
+	gitRun(t, projectPath, "merge", "--quiet", "--ff-only", "merged")
+	freshPath, err := fs.CreateWorktree(projectPath, "fresh")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	dirtyPath, err := fs.CreateWorktree(projectPath, "dirty")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	writeFile(t, filepath.Join(dirtyPath, "scratch.txt"), "scratch\n")
+	gonePath, err := fs.CreateWorktree(projectPath, "gone")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	if err := os.RemoveAll(gonePath); err != nil {
+		t.Fatal(err)
+	}
+
+	activity, err := fs.WorktreeActivity(projectPath)
+	if err != nil {
+		t.Fatalf("WorktreeActivity() error: %v", err)
+	}
+	byPath := make(map[string]core.WorktreeActivity, len(activity))
+	for _, wt := range activity {
+		byPath[wt.Worktree.Path] = wt
+	}
+	if len(byPath) != 5 {
+		t.Fatalf("expected the five managed worktrees, got %+v", activity)
+	}
+	if _, ok := byPath[projectPath]; ok {
+		t.Fatal("expected the project root to be left out")
+	}
+	if wt := byPath[featurePath]; wt.Merged || wt.Dirty || wt.Missing || wt.LastCommit.IsZero() {
+		t.Fatalf("expected an unmerged clean feature worktree, got %+v", wt)
+	}
+	if wt := byPath[mergedPath]; !wt.Merged || wt.Worktree.Branch != "merged" {
+		t.Fatalf("expected the merged worktree to be reported as merged, got %+v", wt)
+	}
+	if wt := byPath[freshPath]; wt.Merged {
+		t.Fatalf("expected a worktree without commits of its own not to be merged, got %+v", wt)
+	}
+	if wt := byPath[dirtyPath]; !wt.Dirty {
+		t.Fatalf("expected the dirty worktree to be reported as dirty, got %+v", wt)
+	}
+	if wt := byPath[gonePath]; !wt.Missing {
+		t.Fatalf("expected the removed worktree to be reported as missing, got %+v", wt)
+	}
+
+	if err := fs.DeleteWorktree(projectPath, gonePath); err != nil {
+		t.Fatalf("DeleteWorktree() of a missing worktree error: %v", err)
+	}
+}
+
+func TestWorktreeActivityMarksUnreadableWorktreesUnknown(t *testing.T) {
+	projectPath, featurePath := landWorktree(t)
+	fs := &OSFilesystem{}
+	brokenPath, err := fs.CreateWorktree(projectPath, "broken")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	writeFile(t, filepath.Join(brokenPath, ".git"), "gitdir: "+filepath.Join(t.TempDir(), "gone")+"\n")
+
+	activity, err := fs.WorktreeActivity(projectPath)
+	if err != nil {
+		t.Fatalf("expected a broken worktree not to fail the project, got %v", err)
+	}
+	byPath := make(map[string]core.WorktreeActivity, len(activity))
+	for _, wt := range activity {
+		byPath[wt.Worktree.Path] = wt
+	}
+	if wt := byPath[brokenPath]; !wt.Unknown || wt.Missing {
+		t.Fatalf("expected the broken worktree to be reported as unknown, got %+v", activity)
+	}
+	if wt, ok := byPath[featurePath]; !ok || wt.Unknown || wt.LastCommit.IsZero() {
+		t.Fatalf("expected the other worktrees to still be read, got %+v", activity)
+	}
+}
+
+func TestDeleteBranchRefusesUnmergedWithoutForce(t *testing.T) {
+	projectPath, featurePath := landWorktree(t)
+	fs := &OSFilesystem{}
+	if err := fs.DeleteWorktree(projectPath, featurePath); err != nil {
+		t.Fatalf("DeleteWorktree() error: %v", err)
+	}
+
+	if merged, err := fs.BranchMerged(projectPath, "feature"); err != nil || merged {
+		t.Fatalf("expected feature to be unmerged, got %v %v", merged, err)
+	}
+	if err := fs.DeleteBranch(projectPath, "feature", false); !errors.Is(err, core.ErrBranchNotMerged) {
+		t.Fatalf("expected ErrBranchNotMerged, got %v", err)
+	}
+	if err := fs.DeleteBranch(projectPath, "feature", true); err != nil {
+		t.Fatalf("DeleteBranch(force) error: %v", err)
+	}
+	if gitCommand(projectPath, "rev-parse", "--verify", "--quiet", "refs/heads/feature").Run() == nil {
+		t.Fatal("expected the branch to be gone")
+	}
+}
+
+func TestBranchMergedFollowsRootHead(t *testing.T) {
+	projectPath, worktreePath := landWorktree(t)
+	fs := &OSFilesystem{}
+	if _, err := fs.LandWorktree(projectPath, worktreePath, core.LandMerge); err != nil {
+		t.Fatalf("LandWorktree() error: %v", err)
+	}
+	if merged, err := fs.BranchMerged(projectPath, "feature"); err != nil || !merged {
+		t.Fatalf("expected feature to be merged after landing, got %v %v", merged, err)
+	}
+	if err := fs.DeleteWorktree(projectPath, worktreePath); err != nil {
+		t.Fatalf("DeleteWorktree() error: %v", err)
+	}
+	if err := fs.DeleteBranch(projectPath, "feature", false); err != nil {
+		t.Fatalf("DeleteBranch() error: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package adapters
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// LandWorktree integrates the worktree's branch into the branch checked out
+// in the project root. Both trees must be clean. When the integration stops
+// on conflicts it is aborted, leaving both trees as they were, and a
+// core.LandConflictError lists the conflicting files.
+func (f *OSFilesystem) LandWorktree(projectPath, worktreePath string, strategy core.LandStrategy) (core.LandResult, error) {
+	projectPath = expandPath(projectPath)
+	worktreePath = expandPath(worktreePath)
+	if filepath.Clean(projectPath) == filepath.Clean(worktreePath) {
+		return core.LandResult{}, core.ErrWorktreeLandRoot
+	}
+
+	branch, err := currentBranch(worktreePath)
+	if err != nil {
+		return core.LandResult{}, fmt.Errorf("worktree is not on a branch")
+	}
+	base, err := currentBranch(projectPath)
+	if err != nil {
+		return core.LandResult{}, fmt.Errorf("project root is not on a branch")
+	}
+	result := core.LandResult{Branch: branch, Base: base}
+	if branch == base {
+		return result, fmt.Errorf("worktree and project root are both on %s", base)
+	}
+
+	if dirty, err := hasChanges(worktreePath, true); err != nil {
+		return result, err
+	} else if dirty {
+		return result, core.ErrWorktreeDirty
+	}
+	if dirty, err := hasChanges(projectPath, false); err != nil {
+		return result, err
+	} else if dirty {
+		return result, fmt.Errorf("project root has uncommitted changes")
+	}
+
+	output, err := gitCommand(projectPath, "rev-list", "--count", base+".."+branch).Output()
+	if err != nil {
+		return result, fmt.Errorf("git rev-list failed: %w", gitStderr(err))
+	}
+	if strings.TrimSpace(string(output)) == "0" {
+		return result, core.ErrNothingToLand
+	}
+
+	switch strategy {
+	case core.LandMerge:
+		if err := runGit(projectPath, "merge", "--no-ff", "--no-edit", branch); err != nil {
+			return result, abortLand(projectPath, strategy, err, "merge", "--abort")
+		}
+	case core.LandSquash:
+		if err := runGit(projectPath, "merge", "--squash", branch); err != nil {
+			return result, abortLand(projectPath, strategy, err, "reset", "--merge")
+		}
+		if err := runGit(projectPath, "commit", "--no-edit"); err != nil {
+			_ = runGit(projectPath, "reset", "--merge")
+			return result, err
+		}
+	case core.LandRebase:
+		if err := runGit(worktreePath, "rebase", base); err != nil {
+			return result, abortLand(worktreePath, strategy, err, "rebase", "--abort")
+		}
+		if err := runGit(projectPath, "merge", "--ff-only", branch); err != nil {
+			return result, err
+		}
+	default:
+		return result, fmt.Errorf("unknown land strategy %q", strategy)
+	}
+	return result, nil
+}
+
+// abortLand rolls back a failed integration in dir. Conflicts become a
+// core.LandConflictError; other failures keep git's error.
+func abortLand(dir string, strategy core.LandStrategy, err error, abort ...string) error {
+	var files []string
+	if output, diffErr := gitCommand(dir, "diff", "--name-only", "--diff-filter=U").Output(); diffErr == nil {
+		for _, file := range strings.Split(string(output), "\n") {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+	}
+	if abortErr := runGit(dir, abort...); abortErr != nil {
+		return fmt.Errorf("%w; rolling back also failed: %w", err, abortErr)
+	}
+	if len(files) > 0 {
+		return core.LandConflictError{Strategy: strategy, Files: files}
+	}
+	return err
+}
+
+func currentBranch(dir string) (string, error) {
+	output, err := gitCommand(dir, "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// hasChanges reports whether dir has uncommitted changes, counting
+// untracked files only when untracked is set.
+func hasChanges(dir string, untracked bool) (bool, error) {
+	args := []string{"status", "--porcelain"}
+	if !untracked {
+		args = append(args, "--untracked-files=no")
+	}
+	output, err := gitCommand(dir, args...).Output()
+	if err != nil {
+		return false, fmt.Errorf("git status failed: %w", gitStderr(err))
+	}
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// landWorktree returns a project on main and a worktree whose feature
+// branch committed one change to app.go.
+func landWorktree(t *testing.T) (projectPath, worktreePath string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+	projectPath = t.TempDir()
+	initRepo(t, projectPath)
+	writeFile(t, filepath.Join(projectPath, "app.go"), "package app\n")
+	gitRun(t, projectPath, "add", "--all")
+	gitRun(t, projectPath, "commit", "--quiet", "-m", "base")
+
+	fs := &OSFilesystem{}
+	worktreePath, err := fs.CreateWorktree(projectPath, "feature")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	writeFile(t, filepath.Join(worktreePath, "app.go"), "package app\n\n// feature\n")
+	gitRun(t, worktreePath, "commit", "--quiet", "-am", "feature change")
+	return projectPath, worktreePath
+}
+
+func gitOut(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		t.Fatalf("git %s failed: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestLandWorktreeStrategies(t *testing.T) {
+	tests := []struct {
+		strategy core.LandStrategy
+		// commits main gains, including a merge commit
+		commits string
+	}{
+		{strategy: core.LandMerge, commits: "2"},
+		{strategy: core.LandSquash, commits: "1"},
+		{strategy: core.LandRebase, commits: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			projectPath, worktreePath := landWorktree(t)
+			before := gitOut(t, projectPath, "rev-parse", "HEAD")
+
+			fs := &OSFilesystem{}
+			result, err := fs.LandWorktree(projectPath, worktreePath, tt.strategy)
+			if err != nil {
+				t.Fatalf("LandWorktree() error: %v", err)
+			}
+			if result != (core.LandResult{Branch: "feature", Base: "main"}) {
+				t.Fatalf("unexpected result %+v", result)
+			}
+			if got := gitOut(t, projectPath, "rev-list", "--count", before+"..main"); got != tt.commits {
+				t.Fatalf("expected %s new commits on main, got %s", tt.commits, got)
+			}
+			data, _ := os.ReadFile(filepath.Join(projectPath, "app.go"))
+			if !strings.Contains(string(data), "// feature") {
+				t.Fatalf("expected the feature change in the project root, got %q", data)
+			}
+		})
+	}
+}
+
+func TestLandWorktreeRejectsDirtyWorktree(t *testing.T) {
+	projectPath, worktreePath := landWorktree(t)
+	writeFile(t, filepath.Join(worktreePath, "scratch.txt"), "scratch\n")
+
+	fs := &OSFilesystem{}
+	_, err := fs.LandWorktree(projectPath, worktreePath, core.LandMerge)
+	if !errors.Is(err, core.ErrWorktreeDirty) {
+		t.Fatalf("expected ErrWorktreeDirty, got %v", err)
+	}
+}
+
+func TestLandWorktreeAbortsOnConflict(t *testing.T) {
+	for _, strategy := range core.LandStrategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			projectPath, worktreePath := landWorktree(t)
+			writeFile(t, filepath.Join(projectPath, "app.go"), "package app\n\n// main\n")
+			gitRun(t, projectPath, "commit", "--quiet", "-am", "main change")
+			before := gitOut(t, projectPath, "rev-parse", "HEAD")
+			featureBefore := gitOut(t, worktreePath, "rev-parse", "HEAD")
+
+			fs := &OSFilesystem{}
+			_, err := fs.LandWorktree(projectPath, worktreePath, strategy)
+			var conflict core.LandConflictError
+			if !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != "app.go" {
+				t.Fatalf("expected a conflict in app.go, got %v", err)
+			}
+			if got := gitOut(t, projectPath, "rev-parse", "HEAD"); got != before {
+				t.Fatalf("expected main to stay at %s, got %s", before, got)
+			}
+			if got := gitOut(t, worktreePath, "rev-parse", "HEAD"); got != featureBefore {
+				t.Fatalf("expected feature to stay at %s, got %s", featureBefore, got)
+			}
+			for _, dir := range []string{projectPath, worktreePath} {
+				if status := gitOut(t, dir, "status", "--porcelain"); status != "" {
+					t.Fatalf("expected a clean tree in %s, got %q", dir, status)
+				}
+			}
+		})
+	}
+}
+
+func TestLandWorktreeRejectsProjectRoot(t *testing.T) {
+	projectPath, _ := landWorktree(t)
+
+	fs := &OSFilesystem{}
+	_, err := fs.LandWorktree(projectPath, projectPath, core.LandMerge)
+	if !errors.Is(err, core.ErrWorktreeLandRoot) {
+		t.Fatalf("expected ErrWorktreeLandRoot, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// ListChanges lists the files the worktree changed since it branched off
+// the project's base branch, including uncommitted and untracked files.
+func (f *OSFilesystem) ListChanges(worktreePath string) (core.WorktreeChanges, error) {
+	worktreePath = expandPath(worktreePath)
+	if !repoHasCommit(worktreePath) {
+		return core.WorktreeChanges{}, fmt.Errorf("worktree has no commits to compare against")
+	}
+	base := reviewBase(worktreePath)
+	mergeBase := "HEAD"
+	if base != "" {
+		if output, err := gitCommand(worktreePath, "merge-base", "HEAD", base).Output(); err == nil {
+			mergeBase = strings.TrimSpace(string(output))
+		}
+	}
+	if mergeBase == "HEAD" {
+		output, err := gitCommand(worktreePath, "rev-parse", "HEAD").Output()
+		if err != nil {
+			return core.WorktreeChanges{}, fmt.Errorf("git rev-parse failed: %w", err)
+		}
+		mergeBase = strings.TrimSpace(string(output))
+	}
+
+	output, err := gitCommand(worktreePath, "diff", "--name-status", "-z", "-M", mergeBase).Output()
+	if err != nil {
+		return core.WorktreeChanges{}, fmt.Errorf("git diff failed: %w", gitStderr(err))
+	}
+	files := parseNameStatus(output)
+
+	output, err = gitCommand(worktreePath, "ls-files", "-z", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return core.WorktreeChanges{}, fmt.Errorf("git ls-files failed: %w", gitStderr(err))
+	}
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			files = append(files, core.FileChange{Path: path, Status: core.FileUntracked})
+		}
+	}
+	return core.WorktreeChanges{Base: base, MergeBase: mergeBase, Files: files}, nil
+}
+
+// FileDiff returns the unified diff of one file against mergeBase.
+func (f *OSFilesystem) FileDiff(worktreePath, mergeBase string, file core.FileChange) (string, error) {
+	worktreePath = expandPath(worktreePath)
+	var cmd *exec.Cmd
+	switch file.Status {
+	case core.FileUntracked:
+		cmd = gitCommand(worktreePath, "diff", "--no-color", "--no-index", "--", os.DevNull, file.Path)
+	case core.FileRenamed:
+		cmd = gitCommand(worktreePath, "diff", "--no-color", "-M", mergeBase, "--", file.OldPath, file.Path)
+	default:
+		cmd = gitCommand(worktreePath, "diff", "--no-color", mergeBase, "--", file.Path)
+	}
+	output, err := cmd.Output()
+	// git diff --no-index exits with 1 when the files differ.
+	var exitErr *exec.ExitError
+	if err != nil && !(file.Status == core.FileUntracked && errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return "", fmt.Errorf("git diff failed: %w", gitStderr(err))
+	}
+	return string(output), nil
+}
+
+// DiscardFile puts the file back the way it was at mergeBase, removing it
+// when it did not exist there.
+func (f *OSFilesystem) DiscardFile(worktreePath, mergeBase string, file core.FileChange) error {
+	worktreePath = expandPath(worktreePath)
+	if !filepath.IsLocal(file.Path) || (file.OldPath != "" && !filepath.IsLocal(file.OldPath)) {
+		return fmt.Errorf("refusing to discard a path outside the worktree: %s", file.Path)
+	}
+	switch file.Status {
+	case core.FileUntracked:
+		return os.Remove(filepath.Join(worktreePath, file.Path))
+	case core.FileAdded:
+		return runGit(worktreePath, "rm", "--force", "--quiet", "--", file.Path)
+	case core.FileRenamed:
+		if err := runGit(worktreePath, "rm", "--force", "--quiet", "--", file.Path); err != nil {
+			return err
+		}
+		return runGit(worktreePath, "restore", "--source="+mergeBase, "--staged", "--worktree", "--", file.OldPath)
+	default:
+		return runGit(worktreePath, "restore", "--source="+mergeBase, "--staged", "--worktree", "--", file.Path)
+	}
+}
+
+// CommitAll stages every change in the worktree and commits it.
+func (f *OSFilesystem) CommitAll(worktreePath, message string) error {
+	worktreePath = expandPath(worktreePath)
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("commit message cannot be empty")
+	}
+	if err := runGit(worktreePath, "add", "--all"); err != nil {
+		return err
+	}
+	return runGit(worktreePath, "commit", "--quiet", "--message", message)
+}
+
+// reviewBase picks the branch a worktree is reviewed against: the remote's
+// default branch, else the branch checked out in the main worktree, else
+// main or master.
+func reviewBase(worktreePath string) string {
+	if output, err := gitCommand(worktreePath, "rev-parse", "--abbrev-ref", "origin/HEAD").Output(); err == nil {
+		if ref := strings.TrimSpace(string(output)); ref != "" && ref != "origin/HEAD" {
+			return ref
+		}
+	}
+	if projectPath := worktreeProjectPath(worktreePath); projectPath != "" {
+		if branch := worktreeBranch(projectPath); branch != "" {
+			return branch
+		}
+	}
+	for _, candidate := range []string{"main", "master"} {
+		if gitCommand(worktreePath, "rev-parse", "--verify", "--quiet", "refs/heads/"+candidate).Run() == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// parseNameStatus reads `git diff --name-status -z` output.
+func parseNameStatus(output []byte) []core.FileChange {
+	fields := strings.Split(string(output), "\x00")
+	var files []core.FileChange
+	for i := 0; i < len(fields); i++ {
+		code := fields[i]
+		if code == "" || i+1 >= len(fields) {
+			continue
+		}
+		change := core.FileChange{Path: fields[i+1], Status: core.FileModified}
+		i++
+		switch code[0] {
+		case 'A':
+			change.Status = core.FileAdded
+		case 'D':
+			change.Status = core.FileDeleted
+		case 'R', 'C':
+			if i+1 >= len(fields) {
+				continue
+			}
+			change.OldPath = change.Path
+			change.Path = fields[i+1]
+			i++
+			if code[0] == 'R' {
+				change.Status = core.FileRenamed
+			} else {
+				change.Status = core.FileAdded
+				change.OldPath = ""
+			}
+		}
+		files = append(files, change)
+	}
+	return files
+}
+
+func runGit(dir string, args ...string) error {
+	cmd := gitCommand(dir, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// gitStderr adds what git printed on stderr to an error from Output.
+func gitStderr(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+package adapters
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test",
+		"GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=Test",
+		"GIT_COMMITTER_EMAIL=test@test.com",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, output)
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// reviewWorktree returns a worktree that committed one change on its branch
+// and has uncommitted and untracked changes on top.
+func reviewWorktree(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	initRepo(t, projectPath)
+	writeFile(t, filepath.Join(projectPath, "app.go"), "package app\n")
+	writeFile(t, filepath.Join(projectPath, "old.txt"), "old\n")
+	writeFile(t, filepath.Join(projectPath, "notes.txt"), "notes\n")
+	gitRun(t, projectPath, "add", "--all")
+	gitRun(t, projectPath, "commit", "--quiet", "-m", "base")
+
+	fs := &OSFilesystem{}
+	worktreePath, err := fs.CreateWorktree(projectPath, "feature")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	writeFile(t, filepath.Join(worktreePath, "feature.go"), "package app\n\nfunc Feature() {}\n")
+	gitRun(t, worktreePath, "add", "feature.go")
+	gitRun(t, worktreePath, "commit", "--quiet", "-m", "add feature")
+
+	writeFile(t, filepath.Join(worktreePath, "app.go"), "package app\n\n// changed\n")
+	if err := os.Remove(filepath.Join(worktreePath, "notes.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(worktreePath, "scratch.txt"), "scratch\n")
+	return worktreePath
+}
+
+func TestListChangesComparesAgainstBaseBranch(t *testing.T) {
+	worktreePath := reviewWorktree(t)
+
+	fs := &OSFilesystem{}
+	changes, err := fs.ListChanges(worktreePath)
+	if err != nil {
+		t.Fatalf("ListChanges() error: %v", err)
+	}
+	if changes.Base != "main" {
+		t.Fatalf("expected main as base, got %q", changes.Base)
+	}
+	want := []core.FileChange{
+		{Path: "app.go", Status: core.FileModified},
+		{Path: "feature.go", Status: core.FileAdded},
+		{Path: "notes.txt", Status: core.FileDeleted},
+		{Path: "scratch.txt", Status: core.FileUntracked},
+	}
+	if !slices.Equal(changes.Files, want) {
+		t.Fatalf("expected %+v, got %+v", want, changes.Files)
+	}
+
+	diff, err := fs.FileDiff(worktreePath, changes.MergeBase, want[1])
+	if err != nil || !strings.Contains(diff, "+func Feature() {}") {
+		t.Fatalf("expected the committed change in the diff, got %q, %v", diff, err)
+	}
+	diff, err = fs.FileDiff(worktreePath, changes.MergeBase, want[3])
+	if err != nil || !strings.Contains(diff, "+scratch") {
+		t.Fatalf("expected the untracked file in the diff, got %q, %v", diff, err)
+	}
+}
+
+func TestDiscardFileRestoresBaseVersion(t *testing.T) {
+	worktreePath := reviewWorktree(t)
+
+	fs := &OSFilesystem{}
+	changes, err := fs.ListChanges(worktreePath)
+	if err != nil {
+		t.Fatalf("ListChanges() error: %v", err)
+	}
+	for _, file := range changes.Files {
+		if err := fs.DiscardFile(worktreePath, changes.MergeBase, file); err != nil {
+			t.Fatalf("DiscardFile(%s) error: %v", file.Path, err)
+		}
+	}
+
+	changes, err = fs.ListChanges(worktreePath)
+	if err != nil {
+		t.Fatalf("ListChanges() error: %v", err)
+	}
+	if len(changes.Files) != 0 {
+		t.Fatalf("expected no changes left, got %+v", changes.Files)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktreePath, "notes.txt")); string(data) != "notes\n" {
+		t.Fatalf("expected deleted file to come back, got %q", data)
+	}
+}
+
+func TestDiscardFileRejectsPathsOutsideWorktree(t *testing.T) {
+	fs := &OSFilesystem{}
+	err := fs.DiscardFile(t.TempDir(), "HEAD", core.FileChange{Path: "../elsewhere", Status: core.FileUntracked})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestCommitAllCommitsEveryChange(t *testing.T) {
+	worktreePath := reviewWorktree(t)
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	fs := &OSFilesystem{}
+	if err := fs.CommitAll(worktreePath, "finish feature"); err != nil {
+		t.Fatalf("CommitAll() error: %v", err)
+	}
+	output, err := exec.Command("git", "-C", worktreePath, "status", "--porcelain").Output()
+	if err != nil || len(output) != 0 {
+		t.Fatalf("expected a clean worktree, got %q, %v", output, err)
+	}
+	if err := fs.CommitAll(worktreePath, "  "); err == nil {
+		t.Fatal("expected an empty message to be rejected")
+	}
+}
+
+func TestParseNameStatusReadsRenames(t *testing.T) {
+	got := parseNameStatus([]byte("M\x00a.go\x00R087\x00old.go\x00new.go\x00D\x00gone.go\x00"))
+	want := []core.FileChange{
+		{Path: "a.go", Status: core.FileModified},
+		{Path: "new.go", OldPath: "old.go", Status: core.FileRenamed},
+		{Path: "gone.go", Status: core.FileDeleted},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
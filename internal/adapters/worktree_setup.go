@@ -0,0 +1,191 @@
+package adapters
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/config"
+)
+
+// hookWaitDelay bounds how long a cancelled hook may keep its output open,
+// for example through a background process it started.
+const hookWaitDelay = 2 * time.Second
+
+var errSetupCancelled = errors.New("setup cancelled")
+
+// SetupWorktree prepares a new worktree: it copies and symlinks the files
+// listed in the project's .rivet.toml from the project root, then runs the
+// post-create hooks the user configured for the project in the worktree,
+// passing every line they print to output. Copy errors do not stop the setup; the first failing hook does.
+func (f *OSFilesystem) SetupWorktree(ctx context.Context, projectPath, worktreePath string, output func(string)) error {
+	projectPath = expandPath(projectPath)
+	worktreePath = expandPath(worktreePath)
+	cfg, err := config.LoadProject(projectPath)
+	if err != nil {
+		return err
+	}
+	setup := cfg.WorktreeSetup()
+	setup.PostCreate = f.postCreate[filepath.Clean(projectPath)]
+	if setup.IsZero() {
+		return nil
+	}
+	if output == nil {
+		output = func(string) {}
+	}
+
+	var errs []error
+	errs = append(errs, copySetupFiles(projectPath, worktreePath, setup.Copy, false, output))
+	errs = append(errs, copySetupFiles(projectPath, worktreePath, setup.Symlink, true, output))
+	for _, command := range setup.PostCreate {
+		if ctx.Err() != nil {
+			return errSetupCancelled
+		}
+		output("$ " + command)
+		if err := runHook(ctx, projectPath, worktreePath, command, output); err != nil {
+			if ctx.Err() != nil {
+				return errSetupCancelled
+			}
+			errs = append(errs, fmt.Errorf("post-create hook %q failed: %w", command, err))
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// copySetupFiles copies, or symlinks when link is set, every project file
+// matching patterns into the same place in the worktree. Files the worktree
+// already has are left alone, and patterns without matches are skipped.
+func copySetupFiles(projectPath, worktreePath string, patterns []string, link bool, output func(string)) error {
+	verb := "copied"
+	if link {
+		verb = "linked"
+	}
+	var errs []error
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(projectPath, pattern))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid pattern %q: %w", pattern, err))
+			continue
+		}
+		for _, src := range matches {
+			rel, err := filepath.Rel(projectPath, src)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			dest := filepath.Join(worktreePath, rel)
+			if _, err := os.Lstat(dest); err == nil {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if link {
+				err = os.Symlink(src, dest)
+			} else {
+				err = copyTree(src, dest)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to set up %s: %w", rel, err))
+				continue
+			}
+			output(verb + " " + rel)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// copyTree copies a file, symlink or directory tree, keeping permissions.
+func copyTree(src, dest string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dest string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// runHook runs command with sh in the worktree, streaming its combined
+// output line by line. Hooks get no stdin, so prompts fail instead of
+// hanging the UI.
+func runHook(ctx context.Context, projectPath, worktreePath, command string, output func(string)) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = worktreePath
+	cmd.Env = append(os.Environ(),
+		"RIVET_PROJECT_PATH="+projectPath,
+		"RIVET_WORKTREE_PATH="+worktreePath,
+	)
+	cmd.WaitDelay = hookWaitDelay
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		_ = writer.Close()
+		waitErr <- err
+	}()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			output(line)
+		}
+	}
+	// Keep draining if a line was too long so the hook never blocks on a
+	// full pipe.
+	_, _ = io.Copy(io.Discard, reader)
+	return <-waitErr
+}
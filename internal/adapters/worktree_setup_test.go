@@ -0,0 +1,164 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func writeProjectConfig(t *testing.T, projectPath, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(projectPath, ".rivet.toml"), []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetupWorktreeCopiesAndLinksFiles(t *testing.T) {
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "project")
+	worktree := filepath.Join(tmp, "worktree")
+	for path, contents := range map[string]string{
+		".env":                   "SECRET=1\n",
+		"config/app.local.json":  "{}\n",
+		"config/db.local.json":   "{}\n",
+		"node_modules/pkg/index": "module\n",
+	} {
+		full := filepath.Join(project, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(worktree, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "config", "db.local.json"), []byte("kept\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeProjectConfig(t, project, `
+[worktree]
+copy = [".env", "config/*.local.json", "missing.txt"]
+symlink = ["node_modules"]
+`)
+
+	var lines []string
+	fs := NewOSFilesystem(core.ScanRules{})
+	err := fs.SetupWorktree(context.Background(), project, worktree, func(line string) {
+		lines = append(lines, line)
+	})
+	if err != nil {
+		t.Fatalf("SetupWorktree() error: %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(worktree, ".env")); err != nil || string(data) != "SECRET=1\n" {
+		t.Fatalf("expected .env to be copied, got %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktree, "config", "db.local.json")); string(data) != "kept\n" {
+		t.Fatalf("existing files must be left alone, got %q", data)
+	}
+	target, err := os.Readlink(filepath.Join(worktree, "node_modules"))
+	if err != nil || target != filepath.Join(project, "node_modules") {
+		t.Fatalf("expected node_modules to link to the project, got %q, %v", target, err)
+	}
+	want := []string{"copied .env", "copied config/app.local.json", "linked node_modules"}
+	if !slices.Equal(lines, want) {
+		t.Fatalf("expected output %q, got %q", want, lines)
+	}
+}
+
+func TestSetupWorktreeStreamsHookOutput(t *testing.T) {
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "project")
+	worktree := filepath.Join(tmp, "worktree")
+	for _, dir := range []string{project, worktree} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var lines []string
+	fs := NewOSFilesystem(core.ScanRules{})
+	fs.SetPostCreateCommands(map[string][]string{project: {"pwd; echo \"$RIVET_PROJECT_PATH\"", "echo oops >&2"}})
+	err := fs.SetupWorktree(context.Background(), project, worktree, func(line string) {
+		lines = append(lines, line)
+	})
+	if err != nil {
+		t.Fatalf("SetupWorktree() error: %v", err)
+	}
+	want := []string{`$ pwd; echo "$RIVET_PROJECT_PATH"`, worktree, project, "$ echo oops >&2", "oops"}
+	if !slices.Equal(lines, want) {
+		t.Fatalf("expected output %q, got %q", want, lines)
+	}
+}
+
+func TestSetupWorktreeStopsAtFailingHook(t *testing.T) {
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "project")
+	worktree := filepath.Join(tmp, "worktree")
+	for _, dir := range []string{project, worktree} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewOSFilesystem(core.ScanRules{})
+	fs.SetPostCreateCommands(map[string][]string{project: {"exit 3", "touch ran"}})
+	err := fs.SetupWorktree(context.Background(), project, worktree, nil)
+	if err == nil || !strings.Contains(err.Error(), `post-create hook "exit 3" failed`) {
+		t.Fatalf("expected the failing hook in the error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(worktree, "ran")); !os.IsNotExist(statErr) {
+		t.Fatalf("hooks after a failure must not run, got %v", statErr)
+	}
+}
+
+func TestSetupWorktreeReportsCancellation(t *testing.T) {
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "project")
+	worktree := filepath.Join(tmp, "worktree")
+	for _, dir := range []string{project, worktree} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fs := NewOSFilesystem(core.ScanRules{})
+	fs.SetPostCreateCommands(map[string][]string{project: {"echo started; exec sleep 30"}})
+	err := fs.SetupWorktree(ctx, project, worktree, func(line string) {
+		if line == "started" {
+			cancel()
+		}
+	})
+	if err == nil || err.Error() != "setup cancelled" {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+}
+
+func TestSetupWorktreeIgnoresOtherProjectsCommands(t *testing.T) {
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "project")
+	worktree := filepath.Join(tmp, "worktree")
+	for _, dir := range []string{project, worktree} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewOSFilesystem(core.ScanRules{})
+	fs.SetPostCreateCommands(map[string][]string{filepath.Join(tmp, "other"): {"touch ran"}})
+	if err := fs.SetupWorktree(context.Background(), project, worktree, nil); err != nil {
+		t.Fatalf("SetupWorktree() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "ran")); !os.IsNotExist(err) {
+		t.Fatalf("expected no commands for an unconfigured project, got %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+// Package config loads rivet's optional configuration file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// DefaultPath is where rivet looks for its configuration file.
+const DefaultPath = "~/.config/rivet/config.toml"
+
+type Config struct {
+	Scan     ScanConfig
+	Hooks    HooksConfig
+	GC       GCConfig
+	Keys     KeysConfig
+	UI       UIConfig
+	Tags     TagsConfig
+	Projects ProjectsConfig
+}
+
+// ScanConfig is the [scan] table.
+type ScanConfig struct {
+	Depth   int
+	Ignore  []string
+	Markers []string
+}
+
+// ScanRules converts the [scan] table into the rules used by project scans.
+func (c Config) ScanRules() core.ScanRules {
+	return core.ScanRules{
+		MaxDepth: c.Scan.Depth,
+		Ignore:   append([]string(nil), c.Scan.Ignore...),
+		Markers:  append([]string(nil), c.Scan.Markers...),
+	}
+}
+
+// GCConfig is the [gc] table: how many days a worktree may go unused
+// before cleanup offers it.
+type GCConfig struct {
+	InactiveDays int
+}
+
+// InactiveFor returns the [gc] inactivity limit, or the default when unset.
+func (c Config) InactiveFor() time.Duration {
+	days := c.GC.InactiveDays
+	if days == 0 {
+		days = core.DefaultInactiveDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// HooksConfig is the [hooks] table: a command list per lifecycle event and
+// a timeout in seconds for each command.
+type HooksConfig struct {
+	Timeout  int
+	Commands map[core.HookEvent][]string
+}
+
+// HookRegistry converts the [hooks] table into the hooks core runs.
+func (c Config) HookRegistry() core.Hooks {
+	hooks := core.Hooks{Timeout: time.Duration(c.Hooks.Timeout) * time.Second}
+	if len(c.Hooks.Commands) > 0 {
+		hooks.Commands = make(map[core.HookEvent][]string, len(c.Hooks.Commands))
+		for event, commands := range c.Hooks.Commands {
+			hooks.Commands[event] = append([]string(nil), commands...)
+		}
+	}
+	return hooks
+}
+
+// KeysConfig is the [keys] table: the keys bound to each remapped action.
+type KeysConfig struct {
+	Bindings map[core.KeyAction][]string
+}
+
+// KeyBindings converts the [keys] table into the bindings the UI uses.
+func (c Config) KeyBindings() core.KeyBindings {
+	if len(c.Keys.Bindings) == 0 {
+		return nil
+	}
+	bindings := make(core.KeyBindings, len(c.Keys.Bindings))
+	for action, keys := range c.Keys.Bindings {
+		bindings[action] = append([]string(nil), keys...)
+	}
+	return bindings
+}
+
+// TagsConfig is the [tags] table: the project names or paths each tag
+// applies to.
+type TagsConfig struct {
+	Projects map[string][]string
+}
+
+// TagRules converts the [tags] table into the rules that tag projects,
+// expanding a leading ~ in path patterns.
+func (c Config) TagRules() core.TagRules {
+	if len(c.Tags.Projects) == 0 {
+		return nil
+	}
+	rules := make(core.TagRules, len(c.Tags.Projects))
+	for tag, patterns := range c.Tags.Projects {
+		for _, pattern := range patterns {
+			rules[tag] = append(rules[tag], expandPath(pattern))
+		}
+	}
+	return rules
+}
+
+// ProjectsConfig is the [projects] table: settings for single projects,
+// each in a subtable named by the project path. Commands live here rather
+// than in the project's .rivet.toml so that a cloned repository cannot make
+// rivet run them.
+type ProjectsConfig struct {
+	PostCreate map[string][]string
+}
+
+// PostCreateCommands returns the commands run in every new worktree of a
+// project, keyed by the cleaned project path with a leading ~ expanded.
+func (c Config) PostCreateCommands() map[string][]string {
+	if len(c.Projects.PostCreate) == 0 {
+		return nil
+	}
+	commands := make(map[string][]string, len(c.Projects.PostCreate))
+	for path, list := range c.Projects.PostCreate {
+		commands[filepath.Clean(expandPath(path))] = append([]string(nil), list...)
+	}
+	return commands
+}
+
+// UIConfig is the [ui] table. Mouse is nil unless the table sets it.
+type UIConfig struct {
+	Mouse *bool
+}
+
+// MouseEnabled reports whether the TUI handles the mouse; it does unless
+// [ui] turns it off.
+func (c Config) MouseEnabled() bool {
+	return c.UI.Mouse == nil || *c.UI.Mouse
+}
+
+// Load reads the config file at path. A missing file yields the zero Config.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(expandPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes config file contents.
+func Parse(data []byte) (Config, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	d := decoder{doc: doc}
+	if len(doc.tables[""]) > 0 {
+		d.unknownKeys("")
+	}
+	for _, table := range doc.order {
+		switch table {
+		case "scan":
+			cfg.Scan = d.scan()
+		case "hooks":
+			cfg.Hooks = d.hooks()
+		case "gc":
+			cfg.GC = d.gc()
+		case "keys":
+			cfg.Keys = d.keys()
+		case "ui":
+			cfg.UI = d.ui()
+		case "tags":
+			cfg.Tags = d.tags()
+		case "projects":
+			cfg.Projects = d.projects()
+		default:
+			d.errs = append(d.errs, fmt.Errorf("unknown table [%s]", table))
+		}
+	}
+	return cfg, errors.Join(d.errs...)
+}
+
+type decoder struct {
+	doc  document
+	errs []error
+}
+
+func (d *decoder) scan() ScanConfig {
+	var scan ScanConfig
+	if v, ok := d.get("scan", "depth", kindInt); ok {
+		if v.num < 1 {
+			d.errs = append(d.errs, fmt.Errorf("scan.depth must be at least 1"))
+		}
+		scan.Depth = v.num
+	}
+	if v, ok := d.get("scan", "ignore", kindList); ok {
+		scan.Ignore = v.list
+	}
+	if v, ok := d.get("scan", "markers", kindList); ok {
+		scan.Markers = v.list
+	}
+	d.unknownKeys("scan", "depth", "ignore", "markers")
+	return scan
+}
+
+func (d *decoder) hooks() HooksConfig {
+	var hooks HooksConfig
+	if v, ok := d.get("hooks", "timeout", kindInt); ok {
+		if v.num < 1 {
+			d.errs = append(d.errs, fmt.Errorf("hooks.timeout must be at least 1"))
+		}
+		hooks.Timeout = v.num
+	}
+	known := []string{"timeout"}
+	for _, event := range core.HookEvents() {
+		known = append(known, string(event))
+		v, ok := d.get("hooks", string(event), kindList)
+		if !ok || len(v.list) == 0 {
+			continue
+		}
+		if hooks.Commands == nil {
+			hooks.Commands = make(map[core.HookEvent][]string)
+		}
+		hooks.Commands[event] = v.list
+	}
+	d.unknownKeys("hooks", known...)
+	return hooks
+}
+
+func (d *decoder) gc() GCConfig {
+	var gc GCConfig
+	if v, ok := d.get("gc", "inactive_days", kindInt); ok {
+		if v.num < 1 {
+			d.errs = append(d.errs, fmt.Errorf("gc.inactive_days must be at least 1"))
+		}
+		gc.InactiveDays = v.num
+	}
+	d.unknownKeys("gc", "inactive_days")
+	return gc
+}
+
+func (d *decoder) ui() UIConfig {
+	var ui UIConfig
+	if v, ok := d.get("ui", "mouse", kindBool); ok {
+		ui.Mouse = &v.boolean
+	}
+	d.unknownKeys("ui", "mouse")
+	return ui
+}
+
+func (d *decoder) tags() TagsConfig {
+	var tags TagsConfig
+	names := make([]string, 0, len(d.doc.tables["tags"]))
+	for name := range d.doc.tables["tags"] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := valueOf(d.doc.tables["tags"][name])
+		var patterns []string
+		switch v.kind {
+		case kindString:
+			patterns = []string{v.str}
+		case kindList:
+			patterns = v.list
+		default:
+			d.errs = append(d.errs, fmt.Errorf("tags.%s must be a string or an array of strings", name))
+			continue
+		}
+		if len(patterns) == 0 || slices.Contains(patterns, "") {
+			d.errs = append(d.errs, fmt.Errorf("tags.%s must name at least one project and no empty ones", name))
+			continue
+		}
+		if tags.Projects == nil {
+			tags.Projects = make(map[string][]string)
+		}
+		tags.Projects[name] = patterns
+	}
+	return tags
+}
+
+func (d *decoder) projects() ProjectsConfig {
+	var projects ProjectsConfig
+	paths := make([]string, 0, len(d.doc.tables["projects"]))
+	for path := range d.doc.tables["projects"] {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		v := valueOf(d.doc.tables["projects"][path])
+		if v.kind != kindTable {
+			d.errs = append(d.errs, fmt.Errorf("projects.%q must be a table", path))
+			continue
+		}
+		for key, raw := range v.table {
+			if key != "post_create" {
+				d.errs = append(d.errs, fmt.Errorf("unknown key projects.%q.%s", path, key))
+				continue
+			}
+			commands := valueOf(raw)
+			if commands.kind != kindList {
+				d.errs = append(d.errs, fmt.Errorf("projects.%q.post_create must be %s", path, kindList))
+				continue
+			}
+			if projects.PostCreate == nil {
+				projects.PostCreate = make(map[string][]string)
+			}
+			projects.PostCreate[path] = commands.list
+		}
+	}
+	return projects
+}
+
+func (d *decoder) keys() KeysConfig {
+	var keys KeysConfig
+	var known []string
+	for _, action := range core.KeyActions() {
+		name := string(action)
+		known = append(known, name)
+		raw, ok := d.doc.tables["keys"][name]
+		if !ok {
+			continue
+		}
+		v := valueOf(raw)
+		var bound []string
+		switch v.kind {
+		case kindString:
+			bound = []string{v.str}
+		case kindList:
+			bound = v.list
+		default:
+			d.errs = append(d.errs, fmt.Errorf("keys.%s must be a string or an array of strings", name))
+			continue
+		}
+		if len(bound) == 0 || slices.Contains(bound, "") {
+			d.errs = append(d.errs, fmt.Errorf("keys.%s must name at least one key and no empty ones", name))
+			continue
+		}
+		if keys.Bindings == nil {
+			keys.Bindings = make(map[core.KeyAction][]string)
+		}
+		keys.Bindings[action] = bound
+	}
+	d.unknownKeys("keys", known...)
+
+	for _, conflict := range core.KeyBindings(keys.Bindings).Conflicts() {
+		names := make([]string, 0, len(conflict.Actions))
+		for _, action := range conflict.Actions {
+			names = append(names, string(action))
+		}
+		d.errs = append(d.errs, fmt.Errorf("%s is bound to %s", conflict.Key, strings.Join(names, " and ")))
+	}
+	return keys
+}
+
+func (d *decoder) get(table, key string, kind valueKind) (value, bool) {
+	raw, ok := d.doc.tables[table][key]
+	if !ok {
+		return value{}, false
+	}
+	v := valueOf(raw)
+	if v.kind != kind {
+		d.errs = append(d.errs, fmt.Errorf("%s.%s must be %s", table, key, kind))
+		return value{}, false
+	}
+	return v, true
+}
+
+func (d *decoder) unknownKeys(table string, known ...string) {
+	var unknown []string
+	for key := range d.doc.tables[table] {
+		if !slices.Contains(known, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		name := key
+		if table != "" {
+			name = table + "." + key
+		}
+		d.errs = append(d.errs, fmt.Errorf("unknown key %s", name))
+	}
+}
+
+func expandPath(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
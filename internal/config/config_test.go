@@ -0,0 +1,246 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+func TestParseReadsScanTable(t *testing.T) {
+	cfg, err := Parse([]byte(`
+# rivet configuration
+[scan]
+depth = 3 # monorepo layout
+ignore = ["bazel-*", '/archive', "has # hash"]
+markers = [
+  ".jj",
+  "go.work", # Go workspaces
+  ".rivet-project",
+]
+`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if cfg.Scan.Depth != 3 {
+		t.Fatalf("expected depth 3, got %d", cfg.Scan.Depth)
+	}
+	if !slices.Equal(cfg.Scan.Ignore, []string{"bazel-*", "/archive", "has # hash"}) {
+		t.Fatalf("unexpected ignore patterns: %q", cfg.Scan.Ignore)
+	}
+	rules := cfg.ScanRules()
+	if rules.Depth() != 3 || !slices.Equal(rules.ProjectMarkers(), []string{".git", ".jj", "go.work", ".rivet-project"}) {
+		t.Fatalf("unexpected scan rules: %+v", rules)
+	}
+}
+
+func TestParseReportsSyntaxErrorLine(t *testing.T) {
+	_, err := Parse([]byte("[scan]\ndepth = 3\nignore = [\"vendor\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected a syntax error on line 3, got %v", err)
+	}
+}
+
+func TestParseReportsUnknownKeysAndTypeErrors(t *testing.T) {
+	_, err := Parse([]byte(`
+[scan]
+depth = "three"
+marker = [".jj"]
+
+[colors]
+`))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, want := range []string{
+		"scan.depth must be an integer",
+		"unknown key scan.marker",
+		"unknown table [colors]",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoadMissingFileReturnsZeroConfig(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Scan.Depth != 0 || len(cfg.Scan.Ignore) != 0 {
+		t.Fatalf("expected zero config, got %+v", cfg)
+	}
+}
+
+func TestLoadReportsPathOnParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[scan]\ndepth = 0\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), "at least 1") {
+		t.Fatalf("expected depth validation error naming the file, got %v", err)
+	}
+}
+
+func TestParseReadsHooksTable(t *testing.T) {
+	cfg, err := Parse([]byte(`
+[hooks]
+timeout = 10
+pre_worktree_create = ["./scripts/check-branch.sh"]
+agent_exit = ["notify-send 'agent finished'", "./scripts/sync.sh"]
+`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	hooks := cfg.HookRegistry()
+	if hooks.Timeout != 10*time.Second {
+		t.Fatalf("expected a 10s timeout, got %v", hooks.Timeout)
+	}
+	if !slices.Equal(hooks.Commands[core.HookAgentExit], []string{"notify-send 'agent finished'", "./scripts/sync.sh"}) {
+		t.Fatalf("unexpected agent_exit hooks: %q", hooks.Commands[core.HookAgentExit])
+	}
+	if !hooks.Has(core.HookPreWorktreeCreate) || hooks.Has(core.HookSessionOpen) {
+		t.Fatalf("unexpected hook registry: %+v", hooks)
+	}
+}
+
+func TestParseRejectsUnknownHookEvents(t *testing.T) {
+	_, err := Parse([]byte(`
+[hooks]
+timeout = 0
+post_session_open = ["true"]
+`))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, want := range []string{
+		"hooks.timeout must be at least 1",
+		"unknown key hooks.post_session_open",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestParseReadsGCTable(t *testing.T) {
+	cfg, err := Parse([]byte("[gc]\ninactive_days = 14\n"))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if got := cfg.InactiveFor(); got != 14*24*time.Hour {
+		t.Fatalf("expected 14 days, got %v", got)
+	}
+	if got := (Config{}).InactiveFor(); got != core.DefaultInactiveDays*24*time.Hour {
+		t.Fatalf("expected the default limit, got %v", got)
+	}
+	if _, err := Parse([]byte("[gc]\ninactive_days = 0\n")); err == nil {
+		t.Fatal("expected a zero limit to be rejected")
+	}
+}
+
+func TestParseReadsKeysTable(t *testing.T) {
+	cfg, err := Parse([]byte(`
+[keys]
+delete = "alt+d"
+sessions = ["alt+s", "f2"]
+`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	bindings := cfg.KeyBindings()
+	if !slices.Equal(bindings.Keys(core.KeyDelete), []string{"alt+d"}) {
+		t.Fatalf("unexpected delete keys: %q", bindings.Keys(core.KeyDelete))
+	}
+	if !slices.Equal(bindings.Keys(core.KeySessions), []string{"alt+s", "f2"}) {
+		t.Fatalf("unexpected sessions keys: %q", bindings.Keys(core.KeySessions))
+	}
+	if !slices.Equal(bindings.Keys(core.KeyQuit), []string{"ctrl+c"}) {
+		t.Fatalf("expected quit to keep its default, got %q", bindings.Keys(core.KeyQuit))
+	}
+}
+
+func TestParseRejectsConflictingKeys(t *testing.T) {
+	_, err := Parse([]byte(`
+[keys]
+review = "ctrl+s"
+kill = "ctrl+k"
+land = []
+help = 1
+`))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, want := range []string{
+		"ctrl+s is bound to sessions and review",
+		"unknown key keys.kill",
+		"keys.land must name at least one key",
+		"keys.help must be a string or an array of strings",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestParseReadsUITable(t *testing.T) {
+	if !(Config{}).MouseEnabled() {
+		t.Fatal("expected the mouse to be enabled by default")
+	}
+	cfg, err := Parse([]byte("[ui]\nmouse = false\n"))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if cfg.MouseEnabled() {
+		t.Fatal("expected the mouse to be disabled")
+	}
+	if _, err := Parse([]byte("[ui]\nmouse = \"off\"\n")); err == nil {
+		t.Fatal("expected a non-boolean mouse setting to be rejected")
+	}
+}
+
+func TestParseReadsTagsTable(t *testing.T) {
+	cfg, err := Parse([]byte("[tags]\nbackend = [\"api\", \"/work/billing-*\"]\noss = \"rivet\"\n"))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	rules := cfg.TagRules()
+	if got := rules["backend"]; len(got) != 2 || got[0] != "api" || got[1] != "/work/billing-*" {
+		t.Fatalf("unexpected backend patterns %v", got)
+	}
+	if got := rules["oss"]; len(got) != 1 || got[0] != "rivet" {
+		t.Fatalf("unexpected oss patterns %v", got)
+	}
+	if _, err := Parse([]byte("[tags]\nbackend = []\n")); err == nil {
+		t.Fatal("expected a tag without projects to be rejected")
+	}
+}
+
+func TestParseReadsPostCreateCommandsPerProject(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	cfg, err := Parse([]byte(`
+[projects."~/Projects/api/"]
+post_create = ["npm install"]
+
+[projects."/work/web"]
+post_create = []
+`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	commands := cfg.PostCreateCommands()
+	if !slices.Equal(commands["/home/me/Projects/api"], []string{"npm install"}) {
+		t.Fatalf("expected commands keyed by the expanded project path, got %q", commands)
+	}
+
+	_, err = Parse([]byte("[projects.\"/work/web\"]\npost_creat = [\"make\"]\n"))
+	if err == nil || !strings.Contains(err.Error(), `unknown key projects."/work/web".post_creat`) {
+		t.Fatalf("expected an unknown key error, got %v", err)
+	}
+}
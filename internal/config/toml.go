@@ -0,0 +1,99 @@
+package config
+
+import (
+	"github.com/BurntSushi/toml"
+)
+
+// document is a decoded config file: the keys of each top-level table and
+// the order the tables appear in. Keys outside any table are stored under
+// the root table "".
+type document struct {
+	tables map[string]map[string]any
+	order  []string
+}
+
+type value struct {
+	str     string
+	num     int
+	boolean bool
+	list    []string
+	table   map[string]any
+	kind    valueKind
+}
+
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindInt
+	kindBool
+	kindList
+	kindTable
+	kindOther
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindString:
+		return "a string"
+	case kindInt:
+		return "an integer"
+	case kindBool:
+		return "a boolean"
+	case kindList:
+		return "an array of strings"
+	case kindTable:
+		return "a table"
+	}
+	return "a value"
+}
+
+func parseDocument(data []byte) (document, error) {
+	var raw map[string]any
+	md, err := toml.Decode(string(data), &raw)
+	if err != nil {
+		return document{}, err
+	}
+	doc := document{tables: map[string]map[string]any{"": {}}}
+	for key, v := range raw {
+		if table, ok := v.(map[string]any); ok {
+			doc.tables[key] = table
+		} else {
+			doc.tables[""][key] = v
+		}
+	}
+	seen := make(map[string]bool)
+	for _, key := range md.Keys() {
+		if _, ok := raw[key[0]].(map[string]any); ok && !seen[key[0]] {
+			seen[key[0]] = true
+			doc.order = append(doc.order, key[0])
+		}
+	}
+	return doc, nil
+}
+
+// valueOf converts a decoded TOML value to a value of the kinds the config
+// uses.
+func valueOf(raw any) value {
+	switch v := raw.(type) {
+	case string:
+		return value{str: v, kind: kindString}
+	case int64:
+		return value{num: int(v), kind: kindInt}
+	case bool:
+		return value{boolean: v, kind: kindBool}
+	case map[string]any:
+		return value{table: v, kind: kindTable}
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return value{kind: kindOther}
+			}
+			list = append(list, s)
+		}
+		return value{list: list, kind: kindList}
+	}
+	return value{kind: kindOther}
+}
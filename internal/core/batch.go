@@ -0,0 +1,54 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BatchReport sums up a bulk action: what it was done for, as paths or
+// session names, and a message for every item it failed on.
+type BatchReport struct {
+	Done     []string
+	Failures []string
+}
+
+// Fail records that the action failed for the item labelled name.
+func (r *BatchReport) Fail(name string, err error) {
+	r.Failures = append(r.Failures, fmt.Sprintf("%s: %v", name, err))
+}
+
+// Summary describes what was done, e.g. "Deleted 3 workspaces.", or returns
+// "" when nothing was.
+func (r BatchReport) Summary(verb, noun string) string {
+	switch len(r.Done) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("%s 1 %s.", verb, noun)
+	default:
+		return fmt.Sprintf("%s %d %ss.", verb, len(r.Done), noun)
+	}
+}
+
+// Warning joins the failures into one line, or returns "" when there were
+// none.
+func (r BatchReport) Warning() string {
+	return strings.Join(r.Failures, "; ")
+}
+
+// toggleMark returns a copy of marks with key flipped, so models sharing
+// the old map are not changed underneath.
+func toggleMark(marks map[string]bool, key string) map[string]bool {
+	toggled := make(map[string]bool, len(marks)+1)
+	for k, on := range marks {
+		if on {
+			toggled[k] = true
+		}
+	}
+	if marks[key] {
+		delete(toggled, key)
+	} else {
+		toggled[key] = true
+	}
+	return toggled
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultInactiveDays is how long a worktree may go without commits or
+// session activity before cleanup offers it, unless configured otherwise.
+const DefaultInactiveDays = 30
+
+// StaleReason says why a worktree is offered for cleanup.
+type StaleReason string
+
+const (
+	// StaleMissing marks a worktree whose directory no longer exists.
+	StaleMissing StaleReason = "missing"
+	// StaleMerged marks a worktree whose branch is fully merged into the
+	// branch checked out in the project root.
+	StaleMerged StaleReason = "merged"
+	// StaleInactive marks a worktree nobody committed in or used a session
+	// of for longer than the inactivity limit.
+	StaleInactive StaleReason = "inactive"
+)
+
+// WorktreeActivity is what the filesystem knows about a managed worktree
+// when looking for stale ones. Unknown marks a worktree whose state could not
+// be read; it is never offered for deletion.
+type WorktreeActivity struct {
+	ProjectPath string
+	Worktree    Worktree
+	Missing     bool
+	Merged      bool
+	Dirty       bool
+	Unknown     bool
+	LastCommit  time.Time
+}
+
+// StaleWorktree is a worktree cleanup offers to delete. LastActive is the
+// latest of its last commit, the last activity of its sessions and its last
+// launch.
+type StaleWorktree struct {
+	ProjectPath string
+	Worktree    Worktree
+	Reason      StaleReason
+	LastActive  time.Time
+}
+
+// CleanupReport sums up a cleanup run: the worktrees that were deleted and
+// a message for every one that was not.
+type CleanupReport struct {
+	Removed  []StaleWorktree
+	Failures []string
+}
+
+// FindStaleWorktrees picks the worktrees that are missing, merged, or
+// inactive for longer than inactiveFor, using sessions and the launch history
+// to tell when a worktree was last used. Worktrees with uncommitted changes are never
+// offered unless their directory is gone. A zero inactiveFor disables the
+// inactivity check.
+func FindStaleWorktrees(activity []WorktreeActivity, sessions []SessionInfo, launches []Launch, inactiveFor time.Duration, now time.Time) []StaleWorktree {
+	lastUsed := make(map[string]time.Time, len(sessions)+len(launches))
+	used := func(path string, at time.Time) {
+		path = filepath.Clean(path)
+		if at.After(lastUsed[path]) {
+			lastUsed[path] = at
+		}
+	}
+	for _, session := range sessions {
+		used(session.DirPath, session.LastActive)
+	}
+	for _, launch := range launches {
+		used(launch.WorktreePath, launch.OpenedAt)
+	}
+
+	var stale []StaleWorktree
+	for _, wt := range activity {
+		lastActive := wt.LastCommit
+		if at := lastUsed[filepath.Clean(wt.Worktree.Path)]; at.After(lastActive) {
+			lastActive = at
+		}
+		candidate := StaleWorktree{ProjectPath: wt.ProjectPath, Worktree: wt.Worktree, LastActive: lastActive}
+		switch {
+		case wt.Missing:
+			candidate.Reason = StaleMissing
+		case wt.Dirty, wt.Unknown:
+			continue
+		case wt.Merged:
+			candidate.Reason = StaleMerged
+		case inactiveFor > 0 && !lastActive.IsZero() && now.Sub(lastActive) > inactiveFor:
+			candidate.Reason = StaleInactive
+		default:
+			continue
+		}
+		stale = append(stale, candidate)
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		if stale[i].ProjectPath != stale[j].ProjectPath {
+			return stale[i].ProjectPath < stale[j].ProjectPath
+		}
+		return stale[i].Worktree.Name < stale[j].Worktree.Name
+	})
+	return stale
+}
+
+// SessionsInDir returns the sessions running in dirPath, whatever they are
+// named, so deleting a worktree can kill all of them.
+func SessionsInDir(sessions []SessionInfo, dirPath string) []SessionInfo {
+	dirPath = filepath.Clean(dirPath)
+	return keepIf(sessions, func(s SessionInfo) bool { return filepath.Clean(s.DirPath) == dirPath })
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindStaleWorktreesPicksMissingMergedAndInactive(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-40 * 24 * time.Hour)
+	activity := []WorktreeActivity{
+		{ProjectPath: "/p/api", Worktree: Worktree{Path: "/wt/api--old", Name: "api--old"}, LastCommit: old},
+		{ProjectPath: "/p/api", Worktree: Worktree{Path: "/wt/api--used", Name: "api--used"}, LastCommit: old},
+		{ProjectPath: "/p/api", Worktree: Worktree{Path: "/wt/api--opened", Name: "api--opened"}, LastCommit: old},
+		{ProjectPath: "/p/api", Worktree: Worktree{Path: "/wt/api--dirty", Name: "api--dirty"}, Merged: true, Dirty: true, LastCommit: old},
+		{ProjectPath: "/p/api", Worktree: Worktree{Path: "/wt/api--broken", Name: "api--broken"}, Merged: true, Unknown: true, LastCommit: old},
+		{ProjectPath: "/p/api", Worktree: Worktree{Path: "/wt/api--done", Name: "api--done"}, Merged: true, LastCommit: now},
+		{ProjectPath: "/p/api", Worktree: Worktree{Path: "/wt/api--fresh", Name: "api--fresh"}, LastCommit: now},
+		{ProjectPath: "/p/app", Worktree: Worktree{Path: "/wt/app--gone", Name: "app--gone"}, Missing: true},
+	}
+	sessions := []SessionInfo{{DirPath: "/wt/api--used/", LastActive: now.Add(-time.Hour)}}
+	launches := []Launch{{WorktreePath: "/wt/api--opened", OpenedAt: now.Add(-24 * time.Hour)}}
+
+	stale := FindStaleWorktrees(activity, sessions, launches, 30*24*time.Hour, now)
+	got := make(map[string]StaleReason, len(stale))
+	for _, wt := range stale {
+		got[wt.Worktree.Name] = wt.Reason
+	}
+	want := map[string]StaleReason{"api--old": StaleInactive, "api--done": StaleMerged, "app--gone": StaleMissing}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for name, reason := range want {
+		if got[name] != reason {
+			t.Fatalf("expected %s to be %s, got %v", name, reason, got)
+		}
+	}
+	if stale[0].Worktree.Name != "api--done" || stale[2].ProjectPath != "/p/app" {
+		t.Fatalf("expected results ordered by project and name, got %+v", stale)
+	}
+
+	if stale := FindStaleWorktrees(activity[:1], nil, nil, 0, now); len(stale) != 0 {
+		t.Fatalf("expected a zero limit to turn the inactivity check off, got %+v", stale)
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+var cloneSchemes = []string{"https://", "http://", "ssh://", "git://", "git+ssh://", "file://"}
+
+// CloneProgress is the latest progress line reported by a running clone.
+// Percent is zero for phases that do not report one.
+type CloneProgress struct {
+	Phase   string
+	Percent int
+}
+
+// IsCloneSource reports whether s names a repository to clone rather than a
+// project to create: a URL, an scp-style address such as
+// git@github.com:org/repo.git, or a local path ending in .git, which is how
+// bare repositories are usually named. Bare repositories named otherwise are
+// recognized through EffCheckBareRepository.
+func IsCloneSource(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, " \t") {
+		return false
+	}
+	for _, scheme := range cloneSchemes {
+		if strings.HasPrefix(s, scheme) {
+			return len(s) > len(scheme)
+		}
+	}
+	if isSCPLikeAddress(s) {
+		return true
+	}
+	if filepath.IsAbs(s) || strings.HasPrefix(s, "~/") {
+		return strings.HasSuffix(strings.TrimRight(s, "/"), ".git")
+	}
+	return false
+}
+
+// mayBeBareRepository reports whether the project query is a local path that
+// only the filesystem can tell apart from a project to create.
+func mayBeBareRepository(query string) bool {
+	return (filepath.IsAbs(query) || strings.HasPrefix(query, "~/")) && !IsCloneSource(query)
+}
+
+// CloneProjectName returns the directory name git would pick for a clone of
+// source: its last path segment without the .git suffix.
+func CloneProjectName(source string) string {
+	name := strings.TrimRight(strings.TrimSpace(source), "/")
+	name = name[strings.LastIndexAny(name, "/:")+1:]
+	name = strings.TrimSuffix(name, ".git")
+	if name == "" || name == "." || name == ".." {
+		return ""
+	}
+	return name
+}
+
+// isSCPLikeAddress matches the user@host:path form git accepts without a
+// scheme. The user part is required so names containing a colon are not
+// mistaken for remotes.
+func isSCPLikeAddress(s string) bool {
+	at := strings.Index(s, "@")
+	colon := strings.Index(s, ":")
+	if at <= 0 || colon <= at+1 || colon == len(s)-1 {
+		return false
+	}
+	slash := strings.Index(s, "/")
+	return slash < 0 || colon < slash
+}
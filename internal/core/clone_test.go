@@ -0,0 +1,45 @@
+package core
+
+import "testing"
+
+func TestIsCloneSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"https://github.com/org/repo.git", true},
+		{"ssh://git@example.com/org/repo", true},
+		{"file:///srv/git/repo.git", true},
+		{"git@github.com:org/repo.git", true},
+		{"/srv/git/repo.git", true},
+		{"~/mirrors/repo.git/", true},
+		{"my-project", false},
+		{"group/my-project", false},
+		{"/srv/git/repo", false},
+		{"https://", false},
+		{"notes:draft", false},
+		{"git@github.com:org/repo.git extra", false},
+	}
+	for _, tt := range tests {
+		if got := IsCloneSource(tt.source); got != tt.want {
+			t.Errorf("IsCloneSource(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestCloneProjectName(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/org/repo.git": "repo",
+		"https://github.com/org/repo/":    "repo",
+		"git@github.com:org/repo.git":     "repo",
+		"git@example.com:repo.git":        "repo",
+		"file:///srv/git/repo.git":        "repo",
+		"/srv/git/service.git":            "service",
+		"https://example.com/.git":        "",
+	}
+	for source, want := range tests {
+		if got := CloneProjectName(source); got != want {
+			t.Errorf("CloneProjectName(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type Effect interface {
 	isEffect()
 }
@@ -16,6 +18,28 @@ type EffCreateProject struct {
 
 func (EffCreateProject) isEffect() {}
 
+// EffCloneProject clones Source into Path, reporting progress with
+// MsgCloneProgress and finishing with MsgProjectCloned.
+type EffCloneProject struct {
+	Source string
+	Path   string
+}
+
+func (EffCloneProject) isEffect() {}
+
+// EffCheckBareRepository asks whether Path, typed as the project query, is a
+// local bare repository and reports with MsgBareRepositoryChecked.
+type EffCheckBareRepository struct {
+	Path string
+}
+
+func (EffCheckBareRepository) isEffect() {}
+
+// EffCancelClone stops the running clone and removes what it wrote.
+type EffCancelClone struct{}
+
+func (EffCancelClone) isEffect() {}
+
 type EffDeleteProject struct {
 	ProjectPath string
 }
@@ -32,8 +56,11 @@ type EffQuit struct{}
 
 func (EffQuit) isEffect() {}
 
+// EffLoadWorktrees lists the worktrees of a project. All includes worktrees
+// outside the managed directory.
 type EffLoadWorktrees struct {
 	ProjectPath string
+	All         bool
 }
 
 func (EffLoadWorktrees) isEffect() {}
@@ -45,13 +72,60 @@ type EffCreateWorktree struct {
 
 func (EffCreateWorktree) isEffect() {}
 
+// EffAdoptWorktree moves an unmanaged worktree into the managed directory.
+type EffAdoptWorktree struct {
+	ProjectPath  string
+	WorktreePath string
+}
+
+func (EffAdoptWorktree) isEffect() {}
+
+// EffLandWorktree integrates a worktree's branch into the branch checked out
+// in the project root.
+type EffLandWorktree struct {
+	ProjectPath  string
+	WorktreePath string
+	Strategy     LandStrategy
+}
+
+func (EffLandWorktree) isEffect() {}
+
+// EffSetupWorktree runs the project's setup for a new worktree, reporting
+// output with MsgWorktreeSetupOutput and finishing with MsgWorktreeSetupDone.
+type EffSetupWorktree struct {
+	ProjectPath  string
+	WorktreePath string
+}
+
+func (EffSetupWorktree) isEffect() {}
+
+// EffCancelSetup stops the running worktree setup.
+type EffCancelSetup struct{}
+
+func (EffCancelSetup) isEffect() {}
+
+// EffDeleteWorktree deletes a worktree and its sessions. Branch is deleted
+// along with it as DeleteBranch says; a safe delete of an unmerged branch
+// fails before anything is removed.
 type EffDeleteWorktree struct {
 	ProjectPath  string
 	WorktreePath string
+	Branch       string
+	DeleteBranch BranchDeletion
 }
 
 func (EffDeleteWorktree) isEffect() {}
 
+// EffCheckBranchesMerged checks that the branches of Worktrees can be
+// deleted safely before anything is deleted, reporting with
+// MsgBranchesChecked.
+type EffCheckBranchesMerged struct {
+	ProjectPath string
+	Worktrees   []Worktree
+}
+
+func (EffCheckBranchesMerged) isEffect() {}
+
 type EffPrewarmAllTools struct {
 	DirPath string
 	Tools   []string
@@ -74,3 +148,187 @@ type EffAttachSession struct {
 }
 
 func (EffAttachSession) isEffect() {}
+
+type EffFindLegacySessions struct {
+	ProjectPaths []string
+}
+
+func (EffFindLegacySessions) isEffect() {}
+
+// EffMigrateSessions renames or adopts legacy sessions, as set in each
+// migration's Action, and reports with MsgSessionsMigrated.
+type EffMigrateSessions struct {
+	Sessions []SessionMigration
+}
+
+func (EffMigrateSessions) isEffect() {}
+
+// EffWatchSessions subscribes to live session change notifications.
+type EffWatchSessions struct{}
+
+func (EffWatchSessions) isEffect() {}
+
+// EffUnwatchSessions ends the live session subscription.
+type EffUnwatchSessions struct{}
+
+func (EffUnwatchSessions) isEffect() {}
+
+// EffWatchProjects starts watching the roots and managed worktrees so the
+// lists stay current without a restart.
+type EffWatchProjects struct {
+	Roots []string
+}
+
+func (EffWatchProjects) isEffect() {}
+
+// EffRefreshWorktrees reloads the worktrees of a project without resetting
+// the worktree query or selection.
+type EffRefreshWorktrees struct {
+	ProjectPath string
+	All         bool
+}
+
+func (EffRefreshWorktrees) isEffect() {}
+
+// EffRunHook runs one hook command with the payload on stdin, finishing with
+// MsgHookFinished. The command is stopped after Timeout.
+type EffRunHook struct {
+	Command string
+	Payload HookPayload
+	Timeout time.Duration
+}
+
+func (EffRunHook) isEffect() {}
+
+// EffLoadChanges lists what a worktree changed against its base branch,
+// finishing with MsgChangesLoaded.
+type EffLoadChanges struct {
+	WorktreePath string
+}
+
+func (EffLoadChanges) isEffect() {}
+
+// EffLoadFileDiff loads the diff of one file against MergeBase, finishing
+// with MsgFileDiffLoaded.
+type EffLoadFileDiff struct {
+	WorktreePath string
+	MergeBase    string
+	File         FileChange
+}
+
+func (EffLoadFileDiff) isEffect() {}
+
+// EffDiscardFile puts a file back the way it was at MergeBase.
+type EffDiscardFile struct {
+	WorktreePath string
+	MergeBase    string
+	File         FileChange
+}
+
+func (EffDiscardFile) isEffect() {}
+
+// EffCommitAll stages and commits every change in the worktree.
+type EffCommitAll struct {
+	WorktreePath string
+	Message      string
+}
+
+func (EffCommitAll) isEffect() {}
+
+// EffOpenEditor opens a file of the worktree in the user's editor.
+type EffOpenEditor struct {
+	WorktreePath string
+	Path         string
+}
+
+func (EffOpenEditor) isEffect() {}
+
+// EffLoadCleanup looks for stale worktrees in the given projects and
+// reports them with MsgCleanupLoaded.
+type EffLoadCleanup struct {
+	ProjectPaths []string
+}
+
+func (EffLoadCleanup) isEffect() {}
+
+// EffLoadRecent reads the launch history and reports it with
+// MsgRecentLoaded.
+type EffLoadRecent struct{}
+
+func (EffLoadRecent) isEffect() {}
+
+// EffLoadPins reads the pinned projects and worktrees and reports them with
+// MsgPinsLoaded.
+type EffLoadPins struct{}
+
+func (EffLoadPins) isEffect() {}
+
+// EffSetPinned pins or unpins a project or worktree path and reports with
+// MsgPinSaved.
+type EffSetPinned struct {
+	Path   string
+	Pinned bool
+}
+
+func (EffSetPinned) isEffect() {}
+
+// EffLoadTags reads the tags set in the UI and reports them with
+// MsgTagsLoaded.
+type EffLoadTags struct{}
+
+func (EffLoadTags) isEffect() {}
+
+// EffSetProjectTags replaces the tags set in the UI for a project and
+// reports with MsgProjectTagsSaved.
+type EffSetProjectTags struct {
+	ProjectPath string
+	Tags        []string
+}
+
+func (EffSetProjectTags) isEffect() {}
+
+// EffCleanupWorktrees deletes worktrees together with their sessions and,
+// when DeleteBranches is set, their branches. It reports with
+// MsgWorktreesCleaned.
+type EffCleanupWorktrees struct {
+	Worktrees      []StaleWorktree
+	DeleteBranches bool
+}
+
+func (EffCleanupWorktrees) isEffect() {}
+
+// EffDeleteProjects deletes several projects with their sessions, one after
+// the other, reporting with MsgProjectsDeleted.
+type EffDeleteProjects struct {
+	ProjectPaths []string
+}
+
+func (EffDeleteProjects) isEffect() {}
+
+// EffDeleteWorktrees deletes several worktrees of a project like
+// EffDeleteWorktree, reporting with MsgWorktreesDeleted. A worktree whose
+// branch cannot be deleted safely is skipped and reported as failed, after
+// the Failures found before the delete started.
+type EffDeleteWorktrees struct {
+	ProjectPath  string
+	Worktrees    []Worktree
+	DeleteBranch BranchDeletion
+	Failures     []string
+}
+
+func (EffDeleteWorktrees) isEffect() {}
+
+// EffKillSessions kills several sessions, reporting with MsgSessionsKilled.
+type EffKillSessions struct {
+	Sessions []SessionInfo
+}
+
+func (EffKillSessions) isEffect() {}
+
+// EffStartSessions starts sessions in the background without attaching to
+// them, reporting with MsgSessionsStarted.
+type EffStartSessions struct {
+	Specs []SessionSpec
+}
+
+func (EffStartSessions) isEffect() {}
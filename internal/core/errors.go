@@ -1,6 +1,10 @@
 package core
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ErrWorktreeExists marks errors caused by trying to create a worktree for an existing branch.
 var ErrWorktreeExists = errors.New("worktree already exists")
@@ -31,7 +35,7 @@ var ErrWorktreeDeleteRoot = errors.New("cannot delete the project root worktree"
 // ErrWorktreeDeleteOutsideRoot marks attempts to delete worktrees outside the managed directory.
 var ErrWorktreeDeleteOutsideRoot = errors.New("worktree is outside the managed directory")
 
-// ErrWorktreeUnregistered marks attempts to delete a worktree not registered in git.
+// ErrWorktreeUnregistered marks attempts to delete or adopt a worktree not registered in git.
 var ErrWorktreeUnregistered = errors.New("worktree is not registered")
 
 func IsRecoverableWorktreeDeleteError(err error) bool {
@@ -39,3 +43,54 @@ func IsRecoverableWorktreeDeleteError(err error) bool {
 		errors.Is(err, ErrWorktreeDeleteOutsideRoot) ||
 		errors.Is(err, ErrWorktreeUnregistered)
 }
+
+// ErrBranchNotMerged marks a branch git refuses to delete without force.
+var ErrBranchNotMerged = errors.New("branch is not fully merged")
+
+// BranchNotMergedError names the branch a safe delete refused to remove.
+type BranchNotMergedError struct {
+	Branch string
+}
+
+func (e BranchNotMergedError) Error() string {
+	return fmt.Sprintf("branch %s is not fully merged", e.Branch)
+}
+
+func (e BranchNotMergedError) Is(target error) bool {
+	return target == ErrBranchNotMerged
+}
+
+// ErrWorktreeAdoptRoot marks attempts to adopt the project root worktree.
+var ErrWorktreeAdoptRoot = errors.New("cannot adopt the project root worktree")
+
+// ErrWorktreeAlreadyManaged marks attempts to adopt a worktree that is already managed.
+var ErrWorktreeAlreadyManaged = errors.New("worktree is already managed")
+
+// ErrWorktreeLandRoot marks attempts to land the project root worktree.
+var ErrWorktreeLandRoot = errors.New("cannot land the project root worktree")
+
+// ErrWorktreeDirty marks attempts to land a worktree with uncommitted changes.
+var ErrWorktreeDirty = errors.New("worktree has uncommitted changes")
+
+// ErrNothingToLand marks attempts to land a branch with no commits of its own.
+var ErrNothingToLand = errors.New("branch has no commits to land")
+
+// ErrLandConflict marks a land that stopped on conflicts and was rolled back.
+var ErrLandConflict = errors.New("land stopped on conflicts")
+
+// LandConflictError lists the files that conflicted while landing.
+type LandConflictError struct {
+	Strategy LandStrategy
+	Files    []string
+}
+
+func (e LandConflictError) Error() string {
+	if len(e.Files) == 0 {
+		return fmt.Sprintf("%s stopped on conflicts and was aborted", e.Strategy)
+	}
+	return fmt.Sprintf("%s conflict in %s; the %s was aborted", e.Strategy, strings.Join(e.Files, ", "), e.Strategy)
+}
+
+func (e LandConflictError) Is(target error) bool {
+	return target == ErrLandConflict
+}
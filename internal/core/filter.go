@@ -3,6 +3,7 @@ package core
 import (
 	"sort"
 	"strings"
+	"time"
 )
 
 type scoredMatch struct {
@@ -11,8 +12,11 @@ type scoredMatch struct {
 }
 
 func FilterDirs(dirs []DirEntry, query string) []DirEntry {
+	parsed := ParseQuery(query).withoutAge()
+	dirs = keepMatching(dirs, parsed, dirRecord, time.Time{})
+	query = parsed.Text
 	if query == "" {
-		return dirs
+		return pinnedFirst(dirs, func(d DirEntry) bool { return d.Pinned })
 	}
 
 	query = strings.ToLower(query)
@@ -25,12 +29,15 @@ func FilterDirs(dirs []DirEntry, query string) []DirEntry {
 		)
 	})
 
-	return ranked
+	return pinnedFirst(ranked, func(d DirEntry) bool { return d.Pinned })
 }
 
 func FilterWorktrees(wts []Worktree, query string) []Worktree {
+	parsed := ParseQuery(query).withoutAge()
+	wts = keepMatching(wts, parsed, worktreeRecord, time.Time{})
+	query = parsed.Text
 	if query == "" {
-		return wts
+		return pinnedFirst(wts, func(wt Worktree) bool { return wt.Pinned })
 	}
 
 	query = strings.ToLower(query)
@@ -50,7 +57,7 @@ func FilterWorktrees(wts []Worktree, query string) []Worktree {
 		return score, ok
 	})
 
-	return ranked
+	return pinnedFirst(ranked, func(wt Worktree) bool { return wt.Pinned })
 }
 
 func FilterTools(tools []string, query string) []string {
@@ -68,7 +75,18 @@ func FilterTools(tools []string, query string) []string {
 	return ranked
 }
 
-func FilterSessions(sessions []SessionInfo, query string) []SessionInfo {
+// FilterSessions filters sessions by query, dating "age:" terms from now.
+func FilterSessions(sessions []SessionInfo, query string, now time.Time) []SessionInfo {
+	return filterSessionsAt(sessions, ParseQuery(query), func(SessionInfo) []string { return nil }, now)
+}
+
+// filterSessionsAt filters sessions by q, with tagsOf giving their tags and
+// now dating "age:" terms.
+func filterSessionsAt(sessions []SessionInfo, q Query, tagsOf func(SessionInfo) []string, now time.Time) []SessionInfo {
+	sessions = keepMatching(sessions, q, func(s SessionInfo) queryRecord {
+		return sessionRecord(s, tagsOf(s), now)
+	}, now)
+	query := q.Text
 	if query == "" {
 		return sessions
 	}
@@ -92,6 +110,27 @@ func FilterSessions(sessions []SessionInfo, query string) []SessionInfo {
 	return ranked
 }
 
+// keepMatching drops the items failing the terms of q.
+func keepMatching[T any](items []T, q Query, record func(T) queryRecord, now time.Time) []T {
+	if len(q.Terms) == 0 {
+		return items
+	}
+	return keepIf(items, func(item T) bool { return q.matches(record(item), now) })
+}
+
+// FilterByName ranks items whose name matches query the way the lists are
+// filtered, best match first.
+func FilterByName[T any](items []T, query string, name func(T) string) []T {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return items
+	}
+	return rankMatches(items, func(item T) (int, bool) {
+		match := matchScore(strings.ToLower(name(item)), query, true)
+		return match.score, match.ok
+	})
+}
+
 func matchScore(text, pattern string, preferExactPrefix bool) scoredMatch {
 	if text == "" || pattern == "" {
 		return scoredMatch{}
@@ -194,3 +233,31 @@ func rankMatches[T any](items []T, scorer func(T) (int, bool)) []T {
 	}
 	return result
 }
+
+// taggedDirs returns the projects with their pins and tags.
+func taggedDirs(m Model) []DirEntry {
+	return TagDirs(pinDirs(m.Dirs, m.Pinned), m.TagRules, m.ProjectTags)
+}
+
+func filterDirs(m Model) []DirEntry {
+	return FilterDirs(taggedDirs(m), m.Query)
+}
+
+func filterWorktrees(m Model) []Worktree {
+	return FilterWorktrees(pinWorktrees(m.Worktrees, m.Pinned), m.WorktreeQuery)
+}
+
+// filterSessions filters the sessions, "tag:" terms by the tags of their
+// projects and "age:" terms against the time they were listed, and groups
+// them by tag.
+// filterSessions groups the sessions by tag unless the query has text to
+// rank them by, so the best match stays on top for Enter.
+func filterSessions(m Model) []SessionInfo {
+	tagsOf := m.SessionTagger()
+	parsed := ParseQuery(m.SessionQuery)
+	filtered := filterSessionsAt(m.Sessions, parsed, tagsOf, m.SessionsListedAt)
+	if parsed.Text != "" {
+		return filtered
+	}
+	return groupSessionsByTag(filtered, tagsOf)
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFilterWorktreesMatchesSanitizedBranch(t *testing.T) {
@@ -95,7 +96,7 @@ func TestFilterSessionsRanksNamePrefixBeforePathAndTool(t *testing.T) {
 		{Name: "gamma", DirPath: "/tmp/zzz", Tool: "alpha-tool"},
 	}
 
-	filtered := FilterSessions(sessions, "alpha")
+	filtered := FilterSessions(sessions, "alpha", time.Time{})
 	if len(filtered) != 3 {
 		t.Fatalf("expected 3 matches, got %d", len(filtered))
 	}
@@ -139,3 +140,59 @@ func TestBrowsingCreateRowStillComesAfterRankedMatches(t *testing.T) {
 		t.Fatalf("expected create row to remain max selection, got %d", updated.SelectedIdx)
 	}
 }
+
+func TestFilterByNameRanksPrefixFirst(t *testing.T) {
+	names := []string{"Show sessions", "Review changes", "Rescan projects"}
+
+	filtered := FilterByName(names, "re", func(name string) string { return name })
+	if len(filtered) != 2 || filtered[0] != "Review changes" || filtered[1] != "Rescan projects" {
+		t.Fatalf("expected prefix matches in list order, got %q", filtered)
+	}
+	if all := FilterByName(names, " ", func(name string) string { return name }); len(all) != 3 {
+		t.Fatalf("expected a blank query to keep everything, got %q", all)
+	}
+}
+
+func TestFilterDirsPutsPinnedFirst(t *testing.T) {
+	dirs := []DirEntry{
+		{Name: "alpha"},
+		{Name: "beta", Pinned: true},
+		{Name: "alpine", Pinned: true},
+	}
+
+	names := func(dirs []DirEntry) string {
+		parts := make([]string, 0, len(dirs))
+		for _, dir := range dirs {
+			parts = append(parts, dir.Name)
+		}
+		return strings.Join(parts, ",")
+	}
+	if got := names(FilterDirs(dirs, "")); got != "beta,alpine,alpha" {
+		t.Fatalf("expected pinned projects first, got %s", got)
+	}
+	if got := names(FilterDirs(dirs, "alp")); got != "alpine,alpha" {
+		t.Fatalf("expected the pinned match first, got %s", got)
+	}
+	if got := names(FilterDirs(dirs, "*")); got != "beta,alpine" {
+		t.Fatalf("expected only pinned projects, got %s", got)
+	}
+	if got := names(FilterDirs(dirs, "*alp")); got != "alpine" {
+		t.Fatalf("expected only pinned matches, got %s", got)
+	}
+}
+
+func TestFilterWorktreesPutsPinnedFirst(t *testing.T) {
+	worktrees := []Worktree{
+		{Name: "feature"},
+		{Name: "fix", Pinned: true},
+	}
+
+	filtered := FilterWorktrees(worktrees, "f")
+	if len(filtered) != 2 || filtered[0].Name != "fix" {
+		t.Fatalf("expected the pinned worktree first, got %+v", filtered)
+	}
+	filtered = FilterWorktrees(worktrees, "* ")
+	if len(filtered) != 1 || filtered[0].Name != "fix" {
+		t.Fatalf("expected only the pinned worktree, got %+v", filtered)
+	}
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// HookEvent names a point in rivet's lifecycle where user commands can run.
+type HookEvent string
+
+const (
+	HookPreWorktreeCreate  HookEvent = "pre_worktree_create"
+	HookPostWorktreeCreate HookEvent = "post_worktree_create"
+	HookPreWorktreeDelete  HookEvent = "pre_worktree_delete"
+	HookPostWorktreeDelete HookEvent = "post_worktree_delete"
+	HookSessionOpen        HookEvent = "session_open"
+	HookProjectCreate      HookEvent = "project_create"
+	HookProjectDelete      HookEvent = "project_delete"
+	HookAgentExit          HookEvent = "agent_exit"
+)
+
+// DefaultHookTimeout bounds a hook command when the config sets no timeout.
+const DefaultHookTimeout = 30 * time.Second
+
+// HookEvents lists every event in the order they are documented.
+func HookEvents() []HookEvent {
+	return []HookEvent{
+		HookPreWorktreeCreate,
+		HookPostWorktreeCreate,
+		HookPreWorktreeDelete,
+		HookPostWorktreeDelete,
+		HookSessionOpen,
+		HookProjectCreate,
+		HookProjectDelete,
+		HookAgentExit,
+	}
+}
+
+// Blocking reports whether the action an event announces waits for its
+// hooks. Pre hooks run before the worktree is touched, and session_open hooks
+// run before rivet hands the terminal to the session.
+func (e HookEvent) Blocking() bool {
+	switch e {
+	case HookPreWorktreeCreate, HookPreWorktreeDelete, HookSessionOpen:
+		return true
+	}
+	return false
+}
+
+// HookPayload describes the event a hook runs for. Fields that do not apply
+// to the event are empty.
+type HookPayload struct {
+	Event        HookEvent
+	ProjectPath  string
+	WorktreePath string
+	Branch       string
+	Tool         string
+}
+
+// Hooks maps lifecycle events to the shell commands run for them.
+type Hooks struct {
+	Commands map[HookEvent][]string
+	Timeout  time.Duration
+}
+
+// Has reports whether any command is registered for event.
+func (h Hooks) Has(event HookEvent) bool {
+	return len(h.Commands[event]) > 0
+}
+
+// Effects returns an EffRunHook for every command registered for the
+// payload's event, in config order.
+func (h Hooks) Effects(payload HookPayload) []Effect {
+	commands := h.Commands[payload.Event]
+	if len(commands) == 0 {
+		return nil
+	}
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	effects := make([]Effect, 0, len(commands))
+	for _, command := range commands {
+		effects = append(effects, EffRunHook{Command: command, Payload: payload, Timeout: timeout})
+	}
+	return effects
+}
+
+// HookError describes a failed hook command.
+func HookError(event HookEvent, command string, err error) error {
+	return fmt.Errorf("%s hook %q failed: %w", event, command, err)
+}
+
+// withHooks puts the hooks for payload's event ahead of effects, so blocking
+// hooks finish before the action they announce. A warning left by earlier
+// hooks is cleared once new ones run.
+func withHooks(m Model, payload HookPayload, effects ...Effect) (Model, []Effect) {
+	return withEachHooks(m, []HookPayload{payload}, effects...)
+}
+
+// withEachHooks is withHooks for an action that touches several worktrees
+// at once.
+func withEachHooks(m Model, payloads []HookPayload, effects ...Effect) (Model, []Effect) {
+	var hooks []Effect
+	for _, payload := range payloads {
+		hooks = append(hooks, m.Hooks.Effects(payload)...)
+	}
+	if len(hooks) == 0 {
+		return m, effects
+	}
+	m.HookWarning = ""
+	return m, append(hooks, effects...)
+}
+
+// worktreeBranch returns the branch of a listed worktree, if known.
+func worktreeBranch(m Model, path string) string {
+	for _, wt := range m.Worktrees {
+		if wt.Path == path {
+			return wt.Branch
+		}
+	}
+	return ""
+}
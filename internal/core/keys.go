@@ -0,0 +1,145 @@
+package core
+
+// KeyHelp, KeyTheme and KeyPalette open the help modal, the theme picker and
+// the command palette. The UI handles them itself, so they never reach
+// UpdateKey.
+const (
+	KeyHelp    KeyAction = "help"
+	KeyTheme   KeyAction = "theme"
+	KeyPalette KeyAction = "palette"
+)
+
+// KeyActions lists every action a key can be bound to, in the order they are
+// documented.
+func KeyActions() []KeyAction {
+	return []KeyAction{
+		KeyUp,
+		KeyDown,
+		KeyPageUp,
+		KeyPageDown,
+		KeyTop,
+		KeyBottom,
+		KeyEnter,
+		KeyDelete,
+		KeyMark,
+		KeyPin,
+		KeyTag,
+		KeySessions,
+		KeyShowAll,
+		KeyAdopt,
+		KeyReview,
+		KeyLand,
+		KeyCommit,
+		KeyCleanup,
+		KeyRecent,
+		KeyRefresh,
+		KeyPalette,
+		KeyHelp,
+		KeyTheme,
+		KeyBack,
+		KeyQuit,
+	}
+}
+
+// DefaultKeys returns the keys bound to an action unless the config remaps
+// it.
+func DefaultKeys(action KeyAction) []string {
+	switch action {
+	case KeyUp:
+		return []string{"up", "ctrl+k"}
+	case KeyDown:
+		return []string{"down", "ctrl+j"}
+	case KeyPageUp:
+		return []string{"pgup", "pageup"}
+	case KeyPageDown:
+		return []string{"pgdown", "pgdn", "pagedown"}
+	case KeyTop:
+		return []string{"home"}
+	case KeyBottom:
+		return []string{"end"}
+	case KeyEnter:
+		return []string{"enter"}
+	case KeyDelete:
+		return []string{"ctrl+d"}
+	case KeyMark:
+		return []string{"tab"}
+	case KeyPin:
+		return []string{"ctrl+f"}
+	case KeyTag:
+		return []string{"ctrl+n"}
+	case KeySessions:
+		return []string{"ctrl+s"}
+	case KeyShowAll:
+		return []string{"ctrl+l"}
+	case KeyAdopt:
+		return []string{"ctrl+o"}
+	case KeyReview:
+		return []string{"ctrl+r"}
+	case KeyLand:
+		return []string{"ctrl+x"}
+	case KeyCommit:
+		return []string{"ctrl+g"}
+	case KeyCleanup:
+		return []string{"ctrl+y"}
+	case KeyRecent:
+		return []string{"ctrl+e"}
+	case KeyRefresh:
+		return []string{"f5"}
+	case KeyPalette:
+		return []string{"ctrl+p"}
+	case KeyHelp:
+		return []string{"?"}
+	case KeyTheme:
+		return []string{"ctrl+t"}
+	case KeyBack:
+		return []string{"esc"}
+	case KeyQuit:
+		return []string{"ctrl+c"}
+	}
+	return nil
+}
+
+// KeyBindings maps actions to the keys that trigger them. Actions without an
+// entry keep their default keys.
+type KeyBindings map[KeyAction][]string
+
+// Keys returns the keys bound to an action.
+func (b KeyBindings) Keys(action KeyAction) []string {
+	if keys, ok := b[action]; ok {
+		return keys
+	}
+	return DefaultKeys(action)
+}
+
+// KeyConflict is a key bound to more than one action.
+type KeyConflict struct {
+	Key     string
+	Actions []KeyAction
+}
+
+// Conflicts lists the keys bound to more than one action, in the order the
+// keys first appear in KeyActions.
+func (b KeyBindings) Conflicts() []KeyConflict {
+	var order []string
+	bound := make(map[string][]KeyAction)
+	for _, action := range KeyActions() {
+		for _, key := range b.Keys(action) {
+			actions := bound[key]
+			if len(actions) > 0 && actions[len(actions)-1] == action {
+				continue
+			}
+			if len(actions) == 0 {
+				order = append(order, key)
+			}
+			bound[key] = append(actions, action)
+		}
+	}
+
+	var conflicts []KeyConflict
+	for _, key := range order {
+		if actions := bound[key]; len(actions) > 1 {
+			conflicts = append(conflicts, KeyConflict{Key: key, Actions: actions})
+		}
+	}
+	return conflicts
+}
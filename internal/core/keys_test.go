@@ -0,0 +1,41 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDefaultKeysHaveNoConflicts(t *testing.T) {
+	for _, action := range KeyActions() {
+		if len(DefaultKeys(action)) == 0 {
+			t.Fatalf("expected default keys for %s", action)
+		}
+	}
+	if conflicts := KeyBindings(nil).Conflicts(); len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", conflicts)
+	}
+}
+
+func TestKeyBindingsRemapAndReportConflicts(t *testing.T) {
+	bindings := KeyBindings{
+		KeyDelete: {"ctrl+s", "alt+d"},
+		KeyReview: {"alt+d"},
+	}
+	if got := bindings.Keys(KeyDelete); !slices.Equal(got, []string{"ctrl+s", "alt+d"}) {
+		t.Fatalf("expected the remapped keys, got %q", got)
+	}
+	if got := bindings.Keys(KeySessions); !slices.Equal(got, []string{"ctrl+s"}) {
+		t.Fatalf("expected the default keys, got %q", got)
+	}
+
+	conflicts := bindings.Conflicts()
+	if len(conflicts) != 2 {
+		t.Fatalf("expected two conflicts, got %+v", conflicts)
+	}
+	if conflicts[0].Key != "ctrl+s" || !slices.Equal(conflicts[0].Actions, []KeyAction{KeyDelete, KeySessions}) {
+		t.Fatalf("unexpected conflict %+v", conflicts[0])
+	}
+	if conflicts[1].Key != "alt+d" || !slices.Equal(conflicts[1].Actions, []KeyAction{KeyDelete, KeyReview}) {
+		t.Fatalf("unexpected conflict %+v", conflicts[1])
+	}
+}
@@ -11,12 +11,20 @@ type Mode int
 const (
 	ModeLoading Mode = iota
 	ModeBrowsing
+	ModeProjectCloning
 	ModeProjectDeleteConfirm
 	ModeWorktree
 	ModeWorktreeDeleteConfirm
+	ModeWorktreeLand
+	ModeWorktreeSetup
+	ModeReview
 	ModeTool
 	ModeToolStarting
 	ModeSessions
+	ModeCleanup
+	ModeRecent
+	ModeProjectTag
+	ModeSessionMigrateConfirm
 	ModeError
 )
 
@@ -26,22 +34,60 @@ type Model struct {
 	Dirs                 []DirEntry
 	Filtered             []DirEntry
 	SelectedIdx          int
+	MarkedProjects       map[string]bool
+	Pinned               map[string]bool
+	TagRules             TagRules
+	ProjectTags          map[string][]string
+	TagProjectPath       string
+	TagInput             string
+	BrowseNotice         string
+	BrowseWarning        string
 	RootPaths            []string
+	Scanning             bool
+	WatchingProjects     bool
 	Err                  error
 	SelectedProject      string
 	SelectedWorktreePath string
 	WorktreeDeletePath   string
+	WorktreeDeletePaths  []string
+	WorktreeDeleteBranch BranchDeletion
+	DeleteBranchWarning  string
 	ProjectDeletePath    string
+	ProjectDeletePaths   []string
+	CloningSource        string
+	CloningPath          string
+	CloneProgress        CloneProgress
+	CloneError           string
+	BareRepository       string
 	ProjectWarning       string
 	WorktreeWarning      string
+	WorktreeNotice       string
+	LandPath             string
+	LandStrategyIdx      int
+	LandDelete           bool
+	Landing              bool
+	SetupOutput          []string
+	SetupWarning         string
+	ReviewPath           string
+	ReviewChanges        WorktreeChanges
+	ReviewIdx            int
+	ReviewDiff           string
+	ReviewLoading        bool
+	ReviewWarning        string
+	ReviewDiscardPath    string
+	ReviewCommitting     bool
+	ReviewCommitMessage  string
 	Worktrees            []Worktree
 	FilteredWT           []Worktree
 	WorktreeIdx          int
+	MarkedWorktrees      map[string]bool
 	WorktreeQuery        string
+	ShowAllWorktrees     bool
 	Tools                []string
 	FilteredTools        []string
 	ToolQuery            string
 	ToolIdx              int
+	ToolTargets          []string
 	ToolWarmStart        map[string]time.Time
 	ToolErrors           map[string]string
 	ToolWarmupTotal      int
@@ -51,21 +97,71 @@ type Model struct {
 	PendingSpec          *SessionSpec
 	SessionReturnMode    Mode
 	Sessions             []SessionInfo
+	SessionsListedAt     time.Time
 	FilteredSessions     []SessionInfo
 	SessionQuery         string
 	SessionIdx           int
+	MarkedSessions       map[string]bool
+	SessionKillNames     []string
+	SessionNotice        string
+	SessionWarning       string
+	LegacySessions       []SessionMigration
+	LegacySessionsCheck  bool
+	CleanupInactiveFor   time.Duration
+	CleanupCandidates    []StaleWorktree
+	CleanupSelected      map[string]bool
+	CleanupIdx           int
+	CleanupLoading       bool
+	CleanupConfirm       bool
+	CleanupRunning       bool
+	CleanupBranches      bool
+	CleanupNotice        string
+	CleanupWarning       string
+	RecentLaunches       []RecentLaunch
+	RecentIdx            int
+	RecentLoading        bool
+	RecentWarning        string
+	Hooks                Hooks
+	HookWarning          string
 }
 
 func NewModel(roots []string) Model {
 	tools := SupportedTools()
 	return Model{
-		Mode:          ModeLoading,
-		RootPaths:     roots,
-		Tools:         tools,
-		FilteredTools: tools,
+		Mode:               ModeLoading,
+		RootPaths:          roots,
+		Tools:              tools,
+		FilteredTools:      tools,
+		CleanupInactiveFor: DefaultInactiveDays * 24 * time.Hour,
 	}
 }
 
+// StartState picks the step the TUI opens at. The zero value opens project
+// selection. ProjectPath opens Step 2 for that project, filtered by
+// WorktreeQuery, and WorktreePath goes on to Step 3 for that worktree.
+type StartState struct {
+	ProjectPath   string
+	WorktreePath  string
+	WorktreeQuery string
+}
+
+// StartAt moves a new model to the step start names; Init then loads what
+// that step needs.
+func StartAt(m Model, start StartState) Model {
+	if start.ProjectPath == "" {
+		return m
+	}
+	m.SelectedProject = start.ProjectPath
+	m.Mode = ModeWorktree
+	m.WorktreeQuery = start.WorktreeQuery
+	m.WorktreeIdx = 0
+	if start.WorktreePath != "" {
+		m.SelectedWorktreePath = start.WorktreePath
+		m.Mode = ModeTool
+	}
+	return m
+}
+
 func (m Model) SelectedDir() (DirEntry, bool) {
 	if len(m.Filtered) == 0 || m.SelectedIdx >= len(m.Filtered) {
 		return DirEntry{}, false
@@ -80,6 +176,101 @@ func (m Model) SelectedWorktree() (Worktree, bool) {
 	return m.FilteredWT[m.WorktreeIdx], true
 }
 
+// SelectedReviewFile returns the changed file highlighted in the review.
+func (m Model) SelectedReviewFile() (FileChange, bool) {
+	files := m.ReviewChanges.Files
+	if len(files) == 0 || m.ReviewIdx < 0 || m.ReviewIdx >= len(files) {
+		return FileChange{}, false
+	}
+	return files[m.ReviewIdx], true
+}
+
+// SelectedLandStrategy returns the strategy highlighted on the land screen.
+func (m Model) SelectedLandStrategy() LandStrategy {
+	strategies := LandStrategies()
+	return strategies[clampIndex(m.LandStrategyIdx, len(strategies)-1)]
+}
+
+// SelectedCleanupWorktree returns the stale worktree highlighted in cleanup.
+func (m Model) SelectedCleanupWorktree() (StaleWorktree, bool) {
+	if len(m.CleanupCandidates) == 0 || m.CleanupIdx < 0 || m.CleanupIdx >= len(m.CleanupCandidates) {
+		return StaleWorktree{}, false
+	}
+	return m.CleanupCandidates[m.CleanupIdx], true
+}
+
+// SelectedCleanupWorktrees returns the stale worktrees marked for deletion,
+// in list order.
+func (m Model) SelectedCleanupWorktrees() []StaleWorktree {
+	var selected []StaleWorktree
+	for _, wt := range m.CleanupCandidates {
+		if m.CleanupSelected[wt.Worktree.Path] {
+			selected = append(selected, wt)
+		}
+	}
+	return selected
+}
+
+// SelectedRecentLaunch returns the launch highlighted in the recent view.
+func (m Model) SelectedRecentLaunch() (RecentLaunch, bool) {
+	if len(m.RecentLaunches) == 0 || m.RecentIdx < 0 || m.RecentIdx >= len(m.RecentLaunches) {
+		return RecentLaunch{}, false
+	}
+	return m.RecentLaunches[m.RecentIdx], true
+}
+
+// MarkedDirs returns the projects marked for a bulk action, in list order.
+// Marks survive filtering, so hidden projects are included.
+func (m Model) MarkedDirs() []DirEntry {
+	var marked []DirEntry
+	for _, dir := range m.Dirs {
+		if m.MarkedProjects[dir.Path] {
+			marked = append(marked, dir)
+		}
+	}
+	return marked
+}
+
+// MarkedWorktreeList returns the worktrees marked for a bulk action, in
+// list order.
+func (m Model) MarkedWorktreeList() []Worktree {
+	var marked []Worktree
+	for _, wt := range m.Worktrees {
+		if m.MarkedWorktrees[wt.Path] {
+			marked = append(marked, wt)
+		}
+	}
+	return marked
+}
+
+// MarkedSessionList returns the sessions marked for a bulk action, in list
+// order.
+func (m Model) MarkedSessionList() []SessionInfo {
+	var marked []SessionInfo
+	for _, session := range m.Sessions {
+		if m.MarkedSessions[session.Name] {
+			marked = append(marked, session)
+		}
+	}
+	return marked
+}
+
+// DeletableBranches returns the branches the delete confirmation can delete
+// along with the worktrees, leaving out detached ones.
+func (m Model) DeletableBranches() []string {
+	paths := m.WorktreeDeletePaths
+	if len(paths) == 0 && m.WorktreeDeletePath != "" {
+		paths = []string{m.WorktreeDeletePath}
+	}
+	var branches []string
+	for _, path := range paths {
+		if branch := worktreeBranch(m, path); deletableBranch(branch) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
 func (m Model) SelectedTool() (string, bool) {
 	if len(m.FilteredTools) == 0 || m.ToolIdx >= len(m.FilteredTools) {
 		return "", false
@@ -94,12 +285,31 @@ func (m Model) SelectedSession() (SessionInfo, bool) {
 	return m.FilteredSessions[m.SessionIdx], true
 }
 
+// CloneSource returns the repository typed in the project query when the
+// create row clones it instead of initializing a new project: a URL, or a
+// local path the filesystem reported as a bare repository.
+func (m Model) CloneSource() (string, bool) {
+	source := strings.TrimSpace(m.Query)
+	if len(m.RootPaths) == 0 || CloneProjectName(source) == "" {
+		return "", false
+	}
+	if !IsCloneSource(source) && source != m.BareRepository {
+		return "", false
+	}
+	return source, true
+}
+
 func (m Model) CreateProjectPath() (string, bool) {
 	if m.Query == "" || len(m.RootPaths) == 0 {
 		return "", false
 	}
 	query := strings.TrimSpace(m.Query)
-	if query == "" || filepath.IsAbs(query) {
+	if ParseQuery(query).Qualified() {
+		return "", false
+	}
+	if source, ok := m.CloneSource(); ok {
+		query = CloneProjectName(source)
+	} else if query == "" || filepath.IsAbs(query) || IsCloneSource(query) {
 		return "", false
 	}
 	path := filepath.Join(m.RootPaths[0], query)
@@ -113,7 +323,7 @@ func (m Model) CreateProjectPath() (string, bool) {
 
 func (m Model) CreateWorktreeName() (string, bool) {
 	name := strings.TrimSpace(m.WorktreeQuery)
-	if name == "" {
+	if name == "" || ParseQuery(name).Qualified() {
 		return "", false
 	}
 	sanitized := SanitizeWorktreeName(name)
@@ -13,6 +13,22 @@ type MsgScanCompleted struct {
 
 func (MsgScanCompleted) isMsg() {}
 
+// MsgScanProgress carries a batch of projects found while a scan is still
+// running. MsgScanCompleted follows with the full result.
+type MsgScanProgress struct {
+	Dirs []DirEntry
+}
+
+func (MsgScanProgress) isMsg() {}
+
+// MsgProjectIndexLoaded carries the cached result of an earlier scan, shown
+// while the fresh scan runs.
+type MsgProjectIndexLoaded struct {
+	Dirs []DirEntry
+}
+
+func (MsgProjectIndexLoaded) isMsg() {}
+
 type MsgProjectCreated struct {
 	ProjectPath string
 	Err         error
@@ -20,6 +36,37 @@ type MsgProjectCreated struct {
 
 func (MsgProjectCreated) isMsg() {}
 
+// MsgCloneProgress reports the progress of the running clone.
+type MsgCloneProgress struct {
+	Progress CloneProgress
+}
+
+func (MsgCloneProgress) isMsg() {}
+
+// MsgProjectCloned ends a clone started from the create row.
+type MsgProjectCloned struct {
+	ProjectPath string
+	Err         error
+}
+
+func (MsgProjectCloned) isMsg() {}
+
+// MsgBareRepositoryChecked answers EffCheckBareRepository.
+type MsgBareRepositoryChecked struct {
+	Path string
+	Bare bool
+}
+
+func (MsgBareRepositoryChecked) isMsg() {}
+
+// MsgBranchesChecked answers EffCheckBranchesMerged with the reason, by
+// worktree path, for every branch a safe delete would refuse.
+type MsgBranchesChecked struct {
+	Failed map[string]error
+}
+
+func (MsgBranchesChecked) isMsg() {}
+
 type MsgProjectDeleted struct {
 	ProjectPath string
 	Err         error
@@ -47,6 +94,17 @@ const (
 	KeySessions KeyAction = "sessions"
 	KeyBack     KeyAction = "back"
 	KeyQuit     KeyAction = "quit"
+	KeyShowAll  KeyAction = "show_all"
+	KeyAdopt    KeyAction = "adopt"
+	KeyReview   KeyAction = "review"
+	KeyLand     KeyAction = "land"
+	KeyCommit   KeyAction = "commit"
+	KeyCleanup  KeyAction = "cleanup"
+	KeyMark     KeyAction = "mark"
+	KeyPin      KeyAction = "pin"
+	KeyTag      KeyAction = "tag"
+	KeyRefresh  KeyAction = "refresh"
+	KeyRecent   KeyAction = "recent"
 )
 
 type MsgQueryChanged struct {
@@ -64,15 +122,54 @@ type MsgWorktreesLoaded struct {
 func (MsgWorktreesLoaded) isMsg() {}
 
 type MsgWorktreeCreated struct {
-	Path string
-	Err  error
+	Path   string
+	Branch string
+	Err    error
 }
 
 func (MsgWorktreeCreated) isMsg() {}
 
+// MsgWorktreeAdopted reports a worktree moved from Path into the managed
+// directory at NewPath.
+type MsgWorktreeAdopted struct {
+	Path    string
+	NewPath string
+	Err     error
+}
+
+func (MsgWorktreeAdopted) isMsg() {}
+
+// MsgWorktreeLanded reports the result of landing a worktree's branch.
+type MsgWorktreeLanded struct {
+	WorktreePath string
+	Result       LandResult
+	Err          error
+}
+
+func (MsgWorktreeLanded) isMsg() {}
+
+// MsgWorktreeSetupOutput carries lines printed while a new worktree is set up.
+type MsgWorktreeSetupOutput struct {
+	Lines []string
+}
+
+func (MsgWorktreeSetupOutput) isMsg() {}
+
+// MsgWorktreeSetupDone ends the setup of a new worktree. Err is shown as a
+// warning in Step 3; the worktree is usable either way.
+type MsgWorktreeSetupDone struct {
+	WorktreePath string
+	Err          error
+}
+
+func (MsgWorktreeSetupDone) isMsg() {}
+
+// MsgWorktreeDeleted reports a deleted worktree. BranchErr is set when the
+// worktree is gone but its branch could not be deleted.
 type MsgWorktreeDeleted struct {
-	Path string
-	Err  error
+	Path      string
+	Err       error
+	BranchErr error
 }
 
 func (MsgWorktreeDeleted) isMsg() {}
@@ -115,8 +212,11 @@ type MsgToolDelayElapsed struct {
 
 func (MsgToolDelayElapsed) isMsg() {}
 
+// MsgSessionsLoaded carries the running sessions and the time they were
+// listed at, which "age:" filters are measured from.
 type MsgSessionsLoaded struct {
 	Sessions []SessionInfo
+	Now      time.Time
 	Err      error
 }
 
@@ -127,3 +227,215 @@ type MsgSessionQueryChanged struct {
 }
 
 func (MsgSessionQueryChanged) isMsg() {}
+
+type MsgLegacySessionsFound struct {
+	Sessions []SessionMigration
+	Err      error
+}
+
+func (MsgLegacySessionsFound) isMsg() {}
+
+// MsgSessionsMigrated reports a legacy session migration: the sessions that
+// could not be migrated and a message for each of them.
+type MsgSessionsMigrated struct {
+	Migrated int
+	Failed   []SessionMigration
+	Failures []string
+}
+
+func (MsgSessionsMigrated) isMsg() {}
+
+// MsgSessionsChanged reports that the set of sessions or their windows changed.
+type MsgSessionsChanged struct {
+	Event string
+}
+
+func (MsgSessionsChanged) isMsg() {}
+
+// MsgProjectsChanged reports projects that appeared or disappeared under the
+// watched roots.
+type MsgProjectsChanged struct {
+	Added   []DirEntry
+	Removed []string
+}
+
+func (MsgProjectsChanged) isMsg() {}
+
+// MsgWorktreesChanged reports a change in the managed worktree directory.
+type MsgWorktreesChanged struct{}
+
+func (MsgWorktreesChanged) isMsg() {}
+
+type MsgWorktreesRefreshed struct {
+	ProjectPath string
+	All         bool
+	Worktrees   []Worktree
+	Warning     string
+	Err         error
+}
+
+func (MsgWorktreesRefreshed) isMsg() {}
+
+// MsgHookFinished reports the result of an EffRunHook.
+type MsgHookFinished struct {
+	Event   HookEvent
+	Command string
+	Err     error
+}
+
+func (MsgHookFinished) isMsg() {}
+
+// MsgChangesLoaded carries the changes of the worktree under review.
+type MsgChangesLoaded struct {
+	WorktreePath string
+	Changes      WorktreeChanges
+	Err          error
+}
+
+func (MsgChangesLoaded) isMsg() {}
+
+// MsgFileDiffLoaded carries the diff of one file under review.
+type MsgFileDiffLoaded struct {
+	WorktreePath string
+	Path         string
+	Diff         string
+	Err          error
+}
+
+func (MsgFileDiffLoaded) isMsg() {}
+
+type MsgFileDiscarded struct {
+	WorktreePath string
+	Path         string
+	Err          error
+}
+
+func (MsgFileDiscarded) isMsg() {}
+
+type MsgChangesCommitted struct {
+	WorktreePath string
+	Err          error
+}
+
+func (MsgChangesCommitted) isMsg() {}
+
+// MsgCommitMessageChanged reports the commit message typed during review.
+type MsgCommitMessageChanged struct {
+	Message string
+}
+
+func (MsgCommitMessageChanged) isMsg() {}
+
+// MsgEditorClosed reports that the editor opened from the review exited.
+type MsgEditorClosed struct {
+	WorktreePath string
+	Err          error
+}
+
+func (MsgEditorClosed) isMsg() {}
+
+// MsgCleanupLoaded carries what is needed to find stale worktrees: the
+// activity of every managed worktree, the running sessions, the launch
+// history and the time they were read at.
+type MsgCleanupLoaded struct {
+	Activity []WorktreeActivity
+	Sessions []SessionInfo
+	Launches []Launch
+	Now      time.Time
+	Err      error
+}
+
+func (MsgCleanupLoaded) isMsg() {}
+
+// MsgRecentLoaded carries the launch history, newest first, and the running
+// sessions that tell which launches are still alive.
+type MsgRecentLoaded struct {
+	Launches []Launch
+	Sessions []SessionInfo
+	Err      error
+}
+
+func (MsgRecentLoaded) isMsg() {}
+
+// MsgPinsLoaded carries the paths of the pinned projects and worktrees.
+type MsgPinsLoaded struct {
+	Paths []string
+	Err   error
+}
+
+func (MsgPinsLoaded) isMsg() {}
+
+// MsgPinSaved reports whether a pin change was saved.
+type MsgPinSaved struct {
+	Err error
+}
+
+func (MsgPinSaved) isMsg() {}
+
+// MsgTagsLoaded carries the tags set in the UI for each project path.
+type MsgTagsLoaded struct {
+	Tags map[string][]string
+	Err  error
+}
+
+func (MsgTagsLoaded) isMsg() {}
+
+// MsgProjectTagsSaved reports whether a project's tags were saved.
+type MsgProjectTagsSaved struct {
+	Err error
+}
+
+func (MsgProjectTagsSaved) isMsg() {}
+
+// MsgTagInputChanged reports the text typed in the tag editor.
+type MsgTagInputChanged struct {
+	Input string
+}
+
+func (MsgTagInputChanged) isMsg() {}
+
+// MsgWorktreesCleaned reports the result of a cleanup run.
+type MsgWorktreesCleaned struct {
+	Report CleanupReport
+}
+
+func (MsgWorktreesCleaned) isMsg() {}
+
+// MsgProjectsDeleted reports the result of a bulk project delete; Done
+// holds the deleted project paths.
+type MsgProjectsDeleted struct {
+	Report BatchReport
+}
+
+func (MsgProjectsDeleted) isMsg() {}
+
+// MsgWorktreesDeleted reports the result of a bulk worktree delete; Done
+// holds the deleted worktree paths.
+type MsgWorktreesDeleted struct {
+	Report BatchReport
+}
+
+func (MsgWorktreesDeleted) isMsg() {}
+
+// MsgSessionsKilled reports the result of a bulk session kill; Done holds
+// the killed session names.
+type MsgSessionsKilled struct {
+	Report BatchReport
+}
+
+func (MsgSessionsKilled) isMsg() {}
+
+// MsgSessionsStarted reports the sessions started for a bulk open; Done
+// holds their worktree paths.
+type MsgSessionsStarted struct {
+	Report BatchReport
+}
+
+func (MsgSessionsStarted) isMsg() {}
+
+// MsgRowSelected highlights a row of the current list, as clicking it does.
+type MsgRowSelected struct {
+	Index int
+}
+
+func (MsgRowSelected) isMsg() {}
@@ -0,0 +1,63 @@
+package core
+
+// PinnedPrefix starts a query that only matches pinned entries, as in "*"
+// or "*api". ParseQuery reads it as "status:pinned".
+const PinnedPrefix = "*"
+
+// keepIf returns the items for which keep is true.
+func keepIf[T any](items []T, keep func(T) bool) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// pinnedFirst moves pinned items ahead of the others, keeping the order
+// within each group.
+func pinnedFirst[T any](items []T, pinned func(T) bool) []T {
+	first := keepIf(items, pinned)
+	if len(first) == 0 || len(first) == len(items) {
+		return items
+	}
+	sorted := append(first, make([]T, 0, len(items)-len(first))...)
+	for _, item := range items {
+		if !pinned(item) {
+			sorted = append(sorted, item)
+		}
+	}
+	return sorted
+}
+
+// pinDirs returns dirs with Pinned set from pins.
+func pinDirs(dirs []DirEntry, pins map[string]bool) []DirEntry {
+	pinned := make([]DirEntry, len(dirs))
+	for i, dir := range dirs {
+		dir.Pinned = pins[dir.Path]
+		pinned[i] = dir
+	}
+	return pinned
+}
+
+// pinWorktrees returns wts with Pinned set from pins.
+func pinWorktrees(wts []Worktree, pins map[string]bool) []Worktree {
+	pinned := make([]Worktree, len(wts))
+	for i, wt := range wts {
+		wt.Pinned = pins[wt.Path]
+		pinned[i] = wt
+	}
+	return pinned
+}
+
+// togglePin pins or unpins path and keeps the cursor on the same entry as
+// the lists reorder.
+func togglePin(m Model, path string) (Model, []Effect) {
+	m.Pinned = toggleMark(m.Pinned, path)
+	m = refilterDirs(m)
+	if m.SelectedProject != "" {
+		m = refilterWorktrees(m)
+	}
+	return m, []Effect{EffSetPinned{Path: path, Pinned: m.Pinned[path]}}
+}
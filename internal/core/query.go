@@ -0,0 +1,283 @@
+package core
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query fields a term can qualify, as in "branch:client-x".
+const (
+	FieldProject = "project"
+	FieldBranch  = "branch"
+	FieldTool    = "tool"
+	FieldStatus  = "status"
+	FieldPath    = "path"
+	FieldAge     = "age"
+	FieldTag     = "tag"
+)
+
+var queryFields = []string{FieldProject, FieldBranch, FieldTool, FieldStatus, FieldPath, FieldAge, FieldTag}
+
+// Statuses matched by "status:".
+const (
+	StatusPinned    = "pinned"
+	StatusTagged    = "tagged"
+	StatusManaged   = "managed"
+	StatusUnmanaged = "unmanaged"
+	StatusActive    = "active"
+	StatusIdle      = "idle"
+)
+
+// sessionIdleAfter is how long a session goes without activity before
+// "status:idle" matches it.
+const sessionIdleAfter = time.Hour
+
+// Query is a filter query parsed into free text, ranked by the fuzzy
+// scorer, and terms every match must satisfy.
+type Query struct {
+	Text  string
+	Terms []QueryTerm
+	// qualified is set when any token was meant as a term, even one
+	// dropped as incomplete.
+	qualified bool
+}
+
+// QueryTerm is one qualified or negated token of a query. Field is empty
+// for a negated word, which excludes entries containing it.
+type QueryTerm struct {
+	Field  string
+	Value  string
+	Negate bool
+	// Age terms match entries last active less than Age ago, or more than
+	// Age ago when Older is set.
+	Age   time.Duration
+	Older bool
+}
+
+// ParseQuery splits query into its terms and free text. Tokens such as
+// "branch:x", "age:<2d" and "-word" become terms; a qualifier with nothing
+// after it yet is dropped. A leading PinnedPrefix stands for
+// "status:pinned". Without any terms, the text is query unchanged.
+func ParseQuery(query string) Query {
+	var q Query
+	var text []string
+	fields := strings.Fields(query)
+	if len(fields) > 0 && strings.HasPrefix(fields[0], PinnedPrefix) {
+		q.qualified = true
+		q.Terms = append(q.Terms, QueryTerm{Field: FieldStatus, Value: StatusPinned})
+		fields[0] = strings.TrimPrefix(fields[0], PinnedPrefix)
+		if fields[0] == "" {
+			fields = fields[1:]
+		}
+	}
+	for _, field := range fields {
+		term, ok, isTerm := parseQueryTerm(field)
+		if !isTerm {
+			text = append(text, field)
+			continue
+		}
+		q.qualified = true
+		if ok {
+			q.Terms = append(q.Terms, term)
+		}
+	}
+	if !q.qualified {
+		q.Text = query
+		return q
+	}
+	q.Text = strings.Join(text, " ")
+	return q
+}
+
+// withoutAge drops the age terms from q, for lists whose rows carry no
+// activity time to compare against.
+func (q Query) withoutAge() Query {
+	q.Terms = keepIf(q.Terms, func(t QueryTerm) bool { return t.Field != FieldAge })
+	return q
+}
+
+// AgeTermWarning explains that the age terms in query are ignored. It is
+// empty unless query has one; only sessions have an age to filter on.
+func AgeTermWarning(query string) string {
+	for _, term := range ParseQuery(query).Terms {
+		if term.Field == FieldAge {
+			return "age: only filters sessions; ignored here"
+		}
+	}
+	return ""
+}
+
+// parseQueryTerm reads token as a term. isTerm reports whether token is
+// meant as one; ok is false when it is still incomplete.
+func parseQueryTerm(token string) (term QueryTerm, ok, isTerm bool) {
+	if rest, negated := strings.CutPrefix(token, "-"); negated {
+		term.Negate = true
+		token = rest
+		if token == "" {
+			return term, false, true
+		}
+	}
+	name, value, found := strings.Cut(token, ":")
+	name = strings.ToLower(name)
+	if !found || !slices.Contains(queryFields, name) {
+		if !term.Negate {
+			return term, false, false
+		}
+		term.Value = strings.ToLower(token)
+		return term, true, true
+	}
+	term.Field = name
+	term.Value = strings.ToLower(value)
+	if term.Field == FieldAge {
+		age, older, valid := parseAge(term.Value)
+		term.Age, term.Older = age, older
+		return term, valid, true
+	}
+	return term, term.Value != "", true
+}
+
+// parseAge reads "<2d", ">3h" or "90m". A bare duration means less than.
+// Days and weeks are accepted besides the units of time.ParseDuration.
+func parseAge(value string) (time.Duration, bool, bool) {
+	older := false
+	switch {
+	case strings.HasPrefix(value, ">"):
+		older = true
+		value = value[1:]
+	case strings.HasPrefix(value, "<"):
+		value = value[1:]
+	}
+	if value == "" {
+		return 0, false, false
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil || n < 0 {
+				return 0, false, false
+			}
+			return time.Duration(n * float64(unit)), older, true
+		}
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, false, false
+	}
+	return age, older, true
+}
+
+// Qualified reports whether the query has terms, so it filters rather than
+// names something to create.
+func (q Query) Qualified() bool {
+	return q.qualified
+}
+
+// Tags returns the tags the query requires.
+func (q Query) Tags() []string {
+	var tags []string
+	for _, term := range q.Terms {
+		if term.Field == FieldTag && !term.Negate {
+			tags = mergeTags(tags, []string{term.Value})
+		}
+	}
+	return tags
+}
+
+// queryRecord holds what the terms of a query are checked against for one
+// entry. Fields the entry does not have stay empty and match nothing.
+type queryRecord struct {
+	fields     map[string]string
+	tags       []string
+	statuses   []string
+	text       []string
+	lastActive time.Time
+}
+
+// matches reports whether r satisfies every term of q; now dates age terms.
+func (q Query) matches(r queryRecord, now time.Time) bool {
+	for _, term := range q.Terms {
+		if term.matches(r, now) == term.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t QueryTerm) matches(r queryRecord, now time.Time) bool {
+	switch t.Field {
+	case "":
+		return slices.ContainsFunc(r.text, func(s string) bool {
+			return strings.Contains(strings.ToLower(s), t.Value)
+		})
+	case FieldTag:
+		return hasTag(r.tags, t.Value)
+	case FieldStatus:
+		return slices.Contains(r.statuses, t.Value)
+	case FieldAge:
+		if now.IsZero() || r.lastActive.IsZero() {
+			return false
+		}
+		if t.Older {
+			return now.Sub(r.lastActive) > t.Age
+		}
+		return now.Sub(r.lastActive) < t.Age
+	}
+	return strings.Contains(strings.ToLower(r.fields[t.Field]), t.Value)
+}
+
+func dirRecord(d DirEntry) queryRecord {
+	r := queryRecord{
+		fields: map[string]string{FieldProject: d.Name, FieldPath: d.Path},
+		tags:   d.Tags,
+		text:   []string{d.Name, d.Path},
+	}
+	if d.Pinned {
+		r.statuses = append(r.statuses, StatusPinned)
+	}
+	if len(d.Tags) > 0 {
+		r.statuses = append(r.statuses, StatusTagged)
+	}
+	return r
+}
+
+func worktreeRecord(wt Worktree) queryRecord {
+	r := queryRecord{
+		fields:   map[string]string{FieldBranch: wt.Branch, FieldPath: wt.Path},
+		text:     []string{wt.Name, wt.Branch},
+		statuses: []string{StatusManaged},
+	}
+	if wt.Unmanaged {
+		r.statuses[0] = StatusUnmanaged
+	}
+	if wt.Pinned {
+		r.statuses = append(r.statuses, StatusPinned)
+	}
+	return r
+}
+
+func sessionRecord(s SessionInfo, tags []string, now time.Time) queryRecord {
+	r := queryRecord{
+		fields: map[string]string{
+			FieldProject: s.Project,
+			FieldBranch:  s.Branch,
+			FieldTool:    s.Tool,
+			FieldPath:    s.DirPath,
+		},
+		tags:       tags,
+		text:       []string{s.Name, s.DirPath, s.Project, s.Branch, s.Tool},
+		lastActive: s.LastActive,
+	}
+	if len(tags) > 0 {
+		r.statuses = append(r.statuses, StatusTagged)
+	}
+	if !now.IsZero() && !s.LastActive.IsZero() {
+		if now.Sub(s.LastActive) > sessionIdleAfter {
+			r.statuses = append(r.statuses, StatusIdle)
+		} else {
+			r.statuses = append(r.statuses, StatusActive)
+		}
+	}
+	return r
+}
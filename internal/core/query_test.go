@@ -0,0 +1,119 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuerySplitsTermsAndText(t *testing.T) {
+	q := ParseQuery("Tool:claude api -wip age:>1d branch:")
+	if q.Text != "api" {
+		t.Fatalf("expected only the free text left, got %q", q.Text)
+	}
+	if len(q.Terms) != 3 {
+		t.Fatalf("expected tool, negation and age terms with the empty branch dropped, got %+v", q.Terms)
+	}
+	if term := q.Terms[0]; term.Field != FieldTool || term.Value != "claude" || term.Negate {
+		t.Fatalf("unexpected tool term %+v", term)
+	}
+	if term := q.Terms[1]; term.Field != "" || term.Value != "wip" || !term.Negate {
+		t.Fatalf("unexpected negated word %+v", term)
+	}
+	if term := q.Terms[2]; term.Field != FieldAge || term.Age != 24*time.Hour || !term.Older {
+		t.Fatalf("unexpected age term %+v", term)
+	}
+	if q := ParseQuery("host:api a-b"); q.Qualified() || q.Text != "host:api a-b" {
+		t.Fatalf("expected unknown qualifiers and inner dashes to stay text, got %+v", q)
+	}
+}
+
+func TestParseQueryReadsPinnedPrefixAsStatus(t *testing.T) {
+	want := QueryTerm{Field: FieldStatus, Value: StatusPinned}
+	for _, query := range []string{"*api", "* api", "status:pinned api"} {
+		q := ParseQuery(query)
+		if len(q.Terms) != 1 || q.Terms[0] != want || q.Text != "api" {
+			t.Fatalf("expected %q to be the pinned status and api, got %+v", query, q)
+		}
+	}
+	if q := ParseQuery("*"); !q.Qualified() || q.Text != "" {
+		t.Fatalf("expected a lone prefix to only keep pinned entries, got %+v", q)
+	}
+}
+
+func TestFilterSessionsCombinesQualifiersWithFuzzyText(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	sessions := []SessionInfo{
+		{Name: "a", Project: "client-x", Branch: "client-x/login", Tool: "claude", LastActive: now.Add(-48 * time.Hour)},
+		{Name: "b", Project: "client-x", Branch: "client-x/billing", Tool: "claude", LastActive: now.Add(-time.Hour / 2)},
+		{Name: "c", Project: "client-x", Branch: "client-x/search", Tool: "amp", LastActive: now.Add(-72 * time.Hour)},
+		{Name: "d", Project: "internal", Branch: "main", Tool: "claude", LastActive: now.Add(-72 * time.Hour)},
+	}
+	noTags := func(SessionInfo) []string { return nil }
+
+	filtered := filterSessionsAt(sessions, ParseQuery("tool:claude branch:client-x age:>1d"), noTags, now)
+	if len(filtered) != 1 || filtered[0].Name != "a" {
+		t.Fatalf("expected the idle claude session on a client-x branch, got %+v", filtered)
+	}
+	filtered = filterSessionsAt(sessions, ParseQuery("project:client -amp status:idle"), noTags, now)
+	if len(filtered) != 1 || filtered[0].Name != "a" {
+		t.Fatalf("expected negation and status to combine, got %+v", filtered)
+	}
+	filtered = filterSessionsAt(sessions, ParseQuery("-tool:amp bil"), noTags, now)
+	if len(filtered) != 1 || filtered[0].Name != "b" {
+		t.Fatalf("expected the fuzzy text to rank what the terms keep, got %+v", filtered)
+	}
+	if filtered = FilterSessions(sessions, "age:<1d", now); len(filtered) != 1 || filtered[0].Name != "b" {
+		t.Fatalf("expected age terms to be dated from now, got %+v", filtered)
+	}
+}
+
+func TestFilterWorktreesByBranchAndStatus(t *testing.T) {
+	worktrees := []Worktree{
+		{Path: "/wt/login", Name: "login", Branch: "client-x/login"},
+		{Path: "/wt/main", Name: "main", Branch: "main", Unmanaged: true},
+		{Path: "/wt/search", Name: "search", Branch: "client-y/search"},
+	}
+
+	filtered := FilterWorktrees(worktrees, "-branch:client-y status:managed")
+	if len(filtered) != 1 || filtered[0].Name != "login" {
+		t.Fatalf("expected the managed worktree off client-y, got %+v", filtered)
+	}
+}
+
+func TestFilterDirsAndWorktreesIgnoreAgeTerms(t *testing.T) {
+	dirs := []DirEntry{{Path: "/p/api", Name: "api"}, {Path: "/p/web", Name: "web"}}
+	worktrees := []Worktree{
+		{Path: "/wt/login", Name: "login", Branch: "login"},
+		{Path: "/wt/search", Name: "search", Branch: "search"},
+	}
+
+	for _, query := range []string{"age:<2d", "-age:<2d"} {
+		if filtered := FilterDirs(dirs, query); len(filtered) != 2 {
+			t.Fatalf("expected %q to leave every project, got %+v", query, filtered)
+		}
+		if filtered := FilterWorktrees(worktrees, query); len(filtered) != 2 {
+			t.Fatalf("expected %q to leave every workspace, got %+v", query, filtered)
+		}
+		if AgeTermWarning(query) == "" {
+			t.Fatalf("expected %q to warn that age terms are ignored", query)
+		}
+	}
+	if filtered := FilterDirs(dirs, "age:<2d we"); len(filtered) != 1 || filtered[0].Name != "web" {
+		t.Fatalf("expected the text next to an age term to still rank projects, got %+v", filtered)
+	}
+	if warning := AgeTermWarning("tag:backend"); warning != "" {
+		t.Fatalf("expected no warning without an age term, got %q", warning)
+	}
+}
+
+func TestCreateNamesIgnoreQualifiedQueries(t *testing.T) {
+	m := NewModel([]string{"/projects"})
+	m.Query = "path:work"
+	if _, ok := m.CreateProjectPath(); ok {
+		t.Fatalf("expected a qualified project query not to offer a create row")
+	}
+	m.WorktreeQuery = "-wip"
+	if _, ok := m.CreateWorktreeName(); ok {
+		t.Fatalf("expected a negated worktree query not to offer a create row")
+	}
+}
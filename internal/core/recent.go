@@ -0,0 +1,73 @@
+package core
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// MaxRecentLaunches is how many launches the launch history keeps.
+const MaxRecentLaunches = 5
+
+// Launch is a project, worktree and tool combination opened earlier.
+type Launch struct {
+	ProjectPath  string
+	WorktreePath string
+	Branch       string
+	Tool         string
+	OpenedAt     time.Time
+}
+
+// Spec returns the session that reopens the launch.
+func (l Launch) Spec() SessionSpec {
+	return SessionSpec{DirPath: l.WorktreePath, Tool: l.Tool}
+}
+
+// Label names the launch as project/branch.
+func (l Launch) Label() string {
+	branch := l.Branch
+	if branch == "" {
+		branch = filepath.Base(l.WorktreePath)
+	}
+	if l.ProjectPath == "" {
+		return branch
+	}
+	return filepath.Base(l.ProjectPath) + "/" + branch
+}
+
+// RecordLaunch puts launch at the front of history, newest first. An
+// earlier launch of the same worktree and tool is dropped, and so is
+// anything past MaxRecentLaunches.
+func RecordLaunch(history []Launch, launch Launch) []Launch {
+	recorded := []Launch{launch}
+	for _, earlier := range history {
+		if len(recorded) == MaxRecentLaunches {
+			break
+		}
+		if filepath.Clean(earlier.WorktreePath) == filepath.Clean(launch.WorktreePath) && earlier.Tool == launch.Tool {
+			continue
+		}
+		recorded = append(recorded, earlier)
+	}
+	return recorded
+}
+
+// RecentLaunch is a launch from the history and whether a session still
+// runs in its worktree.
+type RecentLaunch struct {
+	Launch
+	Alive bool
+}
+
+// MarkAlive pairs each launch with whether one of sessions runs in its
+// worktree.
+func MarkAlive(launches []Launch, sessions []SessionInfo) []RecentLaunch {
+	running := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		running[filepath.Clean(session.DirPath)] = true
+	}
+	recent := make([]RecentLaunch, 0, len(launches))
+	for _, launch := range launches {
+		recent = append(recent, RecentLaunch{Launch: launch, Alive: running[filepath.Clean(launch.WorktreePath)]})
+	}
+	return recent
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordLaunchKeepsNewestUniqueLaunches(t *testing.T) {
+	var history []Launch
+	for i, tool := range []string{"amp", "claude", "codex", "opencode", "none", "amp"} {
+		history = RecordLaunch(history, Launch{WorktreePath: "/wt/api--main", Tool: tool, OpenedAt: time.Unix(int64(i), 0)})
+	}
+	if len(history) != MaxRecentLaunches {
+		t.Fatalf("expected %d launches, got %d", MaxRecentLaunches, len(history))
+	}
+	if history[0].Tool != "amp" || history[0].OpenedAt != time.Unix(5, 0) {
+		t.Fatalf("expected the last launch first, got %+v", history[0])
+	}
+	for _, launch := range history[1:] {
+		if launch.Tool == "amp" {
+			t.Fatalf("expected the earlier amp launch to be dropped, got %+v", history)
+		}
+	}
+}
+
+func TestMarkAliveMatchesSessionsByWorktree(t *testing.T) {
+	launches := []Launch{
+		{ProjectPath: "/projects/api", WorktreePath: "/wt/api--main", Branch: "main", Tool: "amp"},
+		{ProjectPath: "/projects/web", WorktreePath: "/wt/web--fix", Tool: "claude"},
+	}
+	recent := MarkAlive(launches, []SessionInfo{{Name: "api/main", DirPath: "/wt/api--main/"}})
+	if !recent[0].Alive || recent[1].Alive {
+		t.Fatalf("expected only the api launch to be alive, got %+v", recent)
+	}
+	if got := recent[0].Label(); got != "api/main" {
+		t.Fatalf("unexpected label %q", got)
+	}
+	if got := recent[1].Label(); got != "web/web--fix" {
+		t.Fatalf("expected the worktree name without a branch, got %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+const DefaultScanDepth = 2
+
+// DefaultIgnorePatterns lists directories that are never searched for
+// projects unless a negated pattern re-includes them.
+var DefaultIgnorePatterns = []string{
+	".*",
+	"node_modules",
+	"vendor",
+	"__pycache__",
+	"target",
+}
+
+// DefaultProjectMarkers are the entries that make a directory a project.
+var DefaultProjectMarkers = []string{".git"}
+
+// ScanRules controls which directories a project scan descends into and
+// which of them count as projects.
+type ScanRules struct {
+	MaxDepth int
+	// Ignore holds gitignore-style patterns matched against directory paths
+	// relative to the scan root. They extend DefaultIgnorePatterns; a
+	// leading "!" re-includes a previously ignored directory.
+	Ignore []string
+	// Markers extend DefaultProjectMarkers.
+	Markers []string
+}
+
+// Depth returns the configured max depth, or DefaultScanDepth when unset.
+func (r ScanRules) Depth() int {
+	if r.MaxDepth <= 0 {
+		return DefaultScanDepth
+	}
+	return r.MaxDepth
+}
+
+// ProjectMarkers returns the default markers followed by the configured ones.
+func (r ScanRules) ProjectMarkers() []string {
+	markers := append([]string(nil), DefaultProjectMarkers...)
+	for _, marker := range r.Markers {
+		marker = strings.TrimSpace(marker)
+		if marker == "" || slices.Contains(markers, marker) {
+			continue
+		}
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+// Ignored reports whether the directory at relPath, relative to the scan
+// root and using forward slashes, should be skipped.
+func (r ScanRules) Ignored(relPath string) bool {
+	relPath = strings.Trim(path.Clean("/"+relPath), "/")
+	if relPath == "" {
+		return false
+	}
+	ignored := false
+	for _, pattern := range append(append([]string(nil), DefaultIgnorePatterns...), r.Ignore...) {
+		negate, matched := matchIgnorePattern(pattern, relPath)
+		if matched {
+			ignored = !negate
+		}
+	}
+	return ignored
+}
+
+// matchIgnorePattern applies one gitignore-style pattern. Patterns without a
+// slash match any single path element; patterns with a slash are anchored at
+// the scan root; "**" matches any number of elements.
+func matchIgnorePattern(pattern, relPath string) (negate, matched bool) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return false, false
+	}
+	if after, ok := strings.CutPrefix(pattern, "!"); ok {
+		negate = true
+		pattern = after
+	}
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return negate, false
+	}
+
+	if !strings.Contains(pattern, "/") {
+		name := relPath[strings.LastIndex(relPath, "/")+1:]
+		ok, err := path.Match(pattern, name)
+		return negate, err == nil && ok
+	}
+
+	pattern = strings.TrimPrefix(pattern, "/")
+	return negate, matchSegments(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], segments[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScanRulesIgnoredAppliesGitignoreStylePatterns(t *testing.T) {
+	rules := ScanRules{Ignore: []string{"bazel-*", "/archive", "tools/**/gen", "build/", "!vendor"}}
+
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{"bazel-out", true},
+		{"apps/bazel-bin", true},
+		{"archive", true},
+		{"apps/archive", false},
+		{"tools/gen", true},
+		{"tools/a/b/gen", true},
+		{"apps/gen", false},
+		{"build", true},
+		{"node_modules", true},
+		{".cache", true},
+		{"vendor", false},
+		{"apps/api", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		if got := rules.Ignored(tc.path); got != tc.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestScanRulesDefaults(t *testing.T) {
+	var rules ScanRules
+	if rules.Depth() != DefaultScanDepth {
+		t.Fatalf("expected default depth %d, got %d", DefaultScanDepth, rules.Depth())
+	}
+	if !slices.Equal(rules.ProjectMarkers(), []string{".git"}) {
+		t.Fatalf("expected .git marker by default, got %v", rules.ProjectMarkers())
+	}
+
+	rules = ScanRules{MaxDepth: 3, Markers: []string{".jj", ".git", " go.work "}}
+	if rules.Depth() != 3 {
+		t.Fatalf("expected depth 3, got %d", rules.Depth())
+	}
+	if !slices.Equal(rules.ProjectMarkers(), []string{".git", ".jj", "go.work"}) {
+		t.Fatalf("unexpected markers: %v", rules.ProjectMarkers())
+	}
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// TagPrefix qualifies a query token that only matches entries with that
+// tag, as in "tag:backend".
+const TagPrefix = "tag:"
+
+// TagRules maps a tag to the projects it applies to. A pattern with a slash
+// matches the project path and any other pattern the project name, both in
+// filepath.Match syntax.
+type TagRules map[string][]string
+
+// Match returns the tags whose patterns match dir, sorted.
+func (r TagRules) Match(dir DirEntry) []string {
+	var tags []string
+	for tag, patterns := range r {
+		for _, pattern := range patterns {
+			target := dir.Name
+			if strings.Contains(pattern, "/") {
+				target = dir.Path
+				pattern = filepath.Clean(pattern)
+			}
+			if ok, _ := filepath.Match(pattern, target); ok {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// TagDirs returns dirs with Tags set from rules and from the tags saved for
+// each project path.
+func TagDirs(dirs []DirEntry, rules TagRules, saved map[string][]string) []DirEntry {
+	tagged := make([]DirEntry, len(dirs))
+	for i, dir := range dirs {
+		dir.Tags = mergeTags(rules.Match(dir), saved[dir.Path])
+		tagged[i] = dir
+	}
+	return tagged
+}
+
+// ParseTags splits the text typed in the tag editor into tags. Tags are
+// separated by commas or spaces, and a leading "#" or "tag:" is dropped.
+func ParseTags(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	var tags []string
+	for _, field := range fields {
+		field = strings.TrimPrefix(field, "#")
+		if rest, ok := cutPrefixFold(field, TagPrefix); ok {
+			field = rest
+		}
+		tags = mergeTags(tags, []string{field})
+	}
+	return tags
+}
+
+// mergeTags appends the tags of extra missing from tags, ignoring case.
+func mergeTags(tags, extra []string) []string {
+	for _, tag := range extra {
+		if tag != "" && !hasTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func hasTag(tags []string, want string) bool {
+	return slices.ContainsFunc(tags, func(tag string) bool { return strings.EqualFold(tag, want) })
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// SessionTagger returns a function giving the tags of the project a
+// session runs in, found by path or else by name.
+func (m Model) SessionTagger() func(SessionInfo) []string {
+	byPath := make(map[string][]string)
+	byName := make(map[string][]string)
+	for _, dir := range taggedDirs(m) {
+		if len(dir.Tags) == 0 {
+			continue
+		}
+		byPath[dir.Path] = dir.Tags
+		byName[dir.Name] = dir.Tags
+	}
+	return func(session SessionInfo) []string {
+		if tags, ok := byPath[session.DirPath]; ok {
+			return tags
+		}
+		return byName[session.Project]
+	}
+}
+
+// TagGroup names the group listed under tags: the first tag in
+// alphabetical order, or "" without tags.
+func TagGroup(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return slices.MinFunc(tags, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+}
+
+// groupSessionsByTag orders sessions by the TagGroup of their tags, untagged
+// sessions last, keeping the order within each group.
+func groupSessionsByTag(sessions []SessionInfo, tagsOf func(SessionInfo) []string) []SessionInfo {
+	grouped := slices.Clone(sessions)
+	slices.SortStableFunc(grouped, func(a, b SessionInfo) int {
+		ga, gb := strings.ToLower(TagGroup(tagsOf(a))), strings.ToLower(TagGroup(tagsOf(b)))
+		switch {
+		case ga == gb:
+			return 0
+		case ga == "":
+			return 1
+		case gb == "":
+			return -1
+		}
+		return strings.Compare(ga, gb)
+	})
+	return grouped
+}
+
+// setProjectTags records the tags saved for path, copying the map so
+// earlier models keep theirs.
+func setProjectTags(saved map[string][]string, path string, tags []string) map[string][]string {
+	updated := make(map[string][]string, len(saved)+1)
+	for p, t := range saved {
+		updated[p] = t
+	}
+	if len(tags) == 0 {
+		delete(updated, path)
+	} else {
+		updated[path] = tags
+	}
+	return updated
+}
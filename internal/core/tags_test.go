@@ -0,0 +1,65 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTagRulesMatchNamesAndPaths(t *testing.T) {
+	rules := TagRules{
+		"backend":  {"api", "billing-*"},
+		"client-x": {"/work/client-x/*"},
+		"oss":      {"rivet"},
+	}
+
+	cases := []struct {
+		dir  DirEntry
+		want []string
+	}{
+		{DirEntry{Path: "/projects/api", Name: "api"}, []string{"backend"}},
+		{DirEntry{Path: "/work/client-x/billing-core", Name: "billing-core"}, []string{"backend", "client-x"}},
+		{DirEntry{Path: "/projects/web", Name: "web"}, nil},
+	}
+	for _, tc := range cases {
+		if got := rules.Match(tc.dir); !slices.Equal(got, tc.want) {
+			t.Fatalf("expected %v for %s, got %v", tc.want, tc.dir.Path, got)
+		}
+	}
+}
+
+func TestParseTagsSplitsAndDedupes(t *testing.T) {
+	got := ParseTags("backend, #oss tag:client-x Backend,,")
+	if want := []string{"backend", "oss", "client-x"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseQuerySeparatesTagTokens(t *testing.T) {
+	q := ParseQuery("tag:backend  api TAG:oss")
+	if q.Text != "api" || !slices.Equal(q.Tags(), []string{"backend", "oss"}) {
+		t.Fatalf("unexpected query %+v", q)
+	}
+	if q := ParseQuery("feature test "); q.Text != "feature test " || q.Qualified() {
+		t.Fatalf("expected an unqualified query to stay as typed, got %+v", q)
+	}
+}
+
+func TestFilterDirsByTag(t *testing.T) {
+	dirs := []DirEntry{
+		{Path: "/projects/api", Name: "api", Tags: []string{"backend"}},
+		{Path: "/projects/web", Name: "web", Tags: []string{"frontend"}},
+		{Path: "/projects/auth", Name: "auth", Tags: []string{"backend", "oss"}},
+	}
+
+	filtered := FilterDirs(dirs, "tag:backend")
+	if len(filtered) != 2 || filtered[0].Name != "api" || filtered[1].Name != "auth" {
+		t.Fatalf("expected the backend projects, got %+v", filtered)
+	}
+	filtered = FilterDirs(dirs, "tag:backend au")
+	if len(filtered) != 1 || filtered[0].Name != "auth" {
+		t.Fatalf("expected tag and text to combine, got %+v", filtered)
+	}
+	if filtered = FilterDirs(dirs, "tag:"); len(filtered) != 3 {
+		t.Fatalf("expected an empty tag to match every project, got %+v", filtered)
+	}
+}
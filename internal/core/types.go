@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -11,6 +12,10 @@ type DirEntry struct {
 	Score    int
 	Exists   bool
 	LastUsed time.Time
+	// Pinned lists the project ahead of the others.
+	Pinned bool
+	// Tags group the project, from the config or set in the UI.
+	Tags []string
 }
 
 type SessionSpec struct {
@@ -32,6 +37,11 @@ type Worktree struct {
 	Path   string
 	Name   string
 	Branch string
+	// Unmanaged marks worktrees outside the managed directory, listed only
+	// when all worktrees are shown.
+	Unmanaged bool
+	// Pinned lists the worktree ahead of the others.
+	Pinned bool
 }
 
 type WorktreeListing struct {
@@ -121,3 +131,116 @@ func SanitizeWorktreeName(name string) string {
 	clean = strings.ReplaceAll(clean, " ", "-")
 	return clean
 }
+
+// SessionMigrationAction selects how a legacy-named session is brought under the current naming scheme.
+type SessionMigrationAction string
+
+const (
+	// SessionMigrationRename renames the tmux session to the current scheme.
+	SessionMigrationRename SessionMigrationAction = "rename"
+	// SessionMigrationAdopt keeps the legacy name and records it as the session for its worktree.
+	SessionMigrationAdopt SessionMigrationAction = "adopt"
+)
+
+// SessionMigration describes a running session for a known worktree whose name
+// no longer matches the one rivet would derive for it.
+type SessionMigration struct {
+	Name        string
+	DirPath     string
+	CurrentName string
+	Action      SessionMigrationAction
+}
+
+// WorktreeSetup prepares a new worktree: Copy and Symlink are globs relative
+// to the project root, PostCreate are shell commands run in the worktree.
+type WorktreeSetup struct {
+	Copy       []string
+	Symlink    []string
+	PostCreate []string
+}
+
+func (s WorktreeSetup) IsZero() bool {
+	return len(s.Copy) == 0 && len(s.Symlink) == 0 && len(s.PostCreate) == 0
+}
+
+// BranchDeletion says what happens to a worktree's branch when the worktree
+// is deleted.
+type BranchDeletion string
+
+const (
+	// BranchKeep leaves the branch behind.
+	BranchKeep BranchDeletion = ""
+	// BranchDelete deletes the branch only if it is fully merged.
+	BranchDelete BranchDeletion = "delete"
+	// BranchForceDelete deletes the branch even with unmerged commits.
+	BranchForceDelete BranchDeletion = "force"
+)
+
+// Next cycles keep, delete, force delete and back to keep.
+func (d BranchDeletion) Next() BranchDeletion {
+	switch d {
+	case BranchKeep:
+		return BranchDelete
+	case BranchDelete:
+		return BranchForceDelete
+	default:
+		return BranchKeep
+	}
+}
+
+// FileStatus is how a file changed relative to the base of a review.
+type FileStatus string
+
+const (
+	FileModified  FileStatus = "modified"
+	FileAdded     FileStatus = "added"
+	FileDeleted   FileStatus = "deleted"
+	FileRenamed   FileStatus = "renamed"
+	FileUntracked FileStatus = "untracked"
+)
+
+// FileChange is a file that differs between a worktree and its base.
+// OldPath is set for renames.
+type FileChange struct {
+	Path    string
+	OldPath string
+	Status  FileStatus
+}
+
+// WorktreeChanges lists everything a worktree changed since it branched off
+// Base, committed or not. MergeBase is the commit the diffs are taken from.
+type WorktreeChanges struct {
+	Base      string
+	MergeBase string
+	Files     []FileChange
+}
+
+// LandStrategy is how a worktree's branch is integrated into the branch
+// checked out in the project root.
+type LandStrategy string
+
+const (
+	LandMerge  LandStrategy = "merge"
+	LandSquash LandStrategy = "squash"
+	LandRebase LandStrategy = "rebase"
+)
+
+// LandStrategies lists the strategies in the order they are offered.
+func LandStrategies() []LandStrategy {
+	return []LandStrategy{LandMerge, LandSquash, LandRebase}
+}
+
+func ParseLandStrategy(value string) (LandStrategy, error) {
+	for _, strategy := range LandStrategies() {
+		if string(strategy) == value {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown land strategy %q (want merge, squash or rebase)", value)
+}
+
+// LandResult names the branches of a finished land.
+type LandResult struct {
+	Branch string
+	Base   string
+}
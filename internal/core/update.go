@@ -1,25 +1,98 @@
 package core
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
 
 func Update(m Model, msg Msg) (Model, []Effect) {
 	switch msg := msg.(type) {
+	case MsgScanProgress:
+		if !m.Scanning {
+			return m, nil
+		}
+		m.Dirs = mergeDirs(m.Dirs, msg.Dirs)
+		m = refilterDirs(m)
+		if m.Mode == ModeLoading {
+			m.Mode = ModeBrowsing
+		}
+		return m, nil
+
+	case MsgProjectIndexLoaded:
+		if !m.Scanning || len(m.Dirs) > 0 || len(msg.Dirs) == 0 {
+			return m, nil
+		}
+		m.Dirs = msg.Dirs
+		m = refilterDirs(m)
+		if m.Mode == ModeLoading {
+			m.Mode = ModeBrowsing
+		}
+		return m, nil
+
 	case MsgScanCompleted:
+		m.Scanning = false
 		if msg.Err != nil {
 			m.Mode = ModeError
 			m.Err = msg.Err
 			return m, nil
 		}
-		m.Mode = ModeBrowsing
+		if m.Mode == ModeLoading {
+			m.Mode = ModeBrowsing
+		}
 		m.Dirs = msg.Dirs
-		m.Filtered = FilterDirs(m.Dirs, m.Query)
-		m.SelectedIdx = 0
+		m = refilterDirs(m)
+		var effects []Effect
+		if !m.WatchingProjects {
+			m.WatchingProjects = true
+			effects = append(effects, EffWatchProjects{Roots: m.RootPaths})
+		}
+		if !m.LegacySessionsCheck && len(m.Dirs) > 0 {
+			m.LegacySessionsCheck = true
+			effects = append(effects, EffFindLegacySessions{ProjectPaths: dirPaths(m.Dirs)})
+		}
+		return m, effects
+
+	case MsgProjectsChanged:
+		m.Dirs = removeDirs(m.Dirs, msg.Removed)
+		m.Dirs = mergeDirs(m.Dirs, msg.Added)
+		m = refilterDirs(m)
+		return m, nil
+
+	case MsgWorktreesChanged:
+		if m.SelectedProject == "" {
+			return m, nil
+		}
+		return m, []Effect{EffRefreshWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees}}
+
+	case MsgWorktreesRefreshed:
+		// Refreshes are best effort; an explicit load reports errors.
+		if msg.Err != nil || msg.ProjectPath != m.SelectedProject || msg.All != m.ShowAllWorktrees {
+			return m, nil
+		}
+		m.ProjectWarning = msg.Warning
+		m.Worktrees = msg.Worktrees
+		m = refilterWorktrees(m)
 		return m, nil
 
 	case MsgQueryChanged:
 		m.Query = msg.Query
-		m.Filtered = FilterDirs(m.Dirs, m.Query)
+		m.CloneError = ""
+		m.BrowseNotice = ""
+		m.Filtered = filterDirs(m)
 		m.SelectedIdx = 0
+		if query := strings.TrimSpace(m.Query); mayBeBareRepository(query) {
+			return m, []Effect{EffCheckBareRepository{Path: query}}
+		}
+		return m, nil
+
+	case MsgBareRepositoryChecked:
+		if msg.Bare && msg.Path == strings.TrimSpace(m.Query) {
+			m.BareRepository = msg.Path
+		}
 		return m, nil
 
 	case MsgKeyPress:
@@ -32,13 +105,29 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 			m.Err = msg.Err
 			return m, nil
 		}
-		m.SelectedProject = msg.ProjectPath
-		m.Mode = ModeWorktree
-		m.WorktreeQuery = ""
-		m.WorktreeIdx = 0
-		m.ProjectWarning = ""
-		m.WorktreeWarning = ""
-		return m, []Effect{EffLoadWorktrees{ProjectPath: msg.ProjectPath}}
+		return openNewProject(m, msg.ProjectPath)
+
+	case MsgCloneProgress:
+		if m.Mode != ModeProjectCloning {
+			return m, nil
+		}
+		m.CloneProgress = msg.Progress
+		return m, nil
+
+	case MsgProjectCloned:
+		// A clone cancelled with esc may still report back; ignore it.
+		if m.Mode != ModeProjectCloning || msg.ProjectPath != m.CloningPath {
+			return m, nil
+		}
+		m = clearClone(m)
+		if msg.Err != nil {
+			// Clone failures are usually a mistyped URL or missing access,
+			// so keep the user in Step 1 to fix the query.
+			m.Mode = ModeBrowsing
+			m.CloneError = msg.Err.Error()
+			return m, nil
+		}
+		return openNewProject(m, msg.ProjectPath)
 
 	case MsgProjectDeleted:
 		if msg.Err != nil {
@@ -57,7 +146,21 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 		m.SelectedWorktreePath = ""
 		m.WorktreeDeletePath = ""
 		m.WorktreeWarning = ""
-		return m, []Effect{EffScanDirs{Roots: m.RootPaths}}
+		m.Scanning = true
+		return withHooks(m, HookPayload{Event: HookProjectDelete, ProjectPath: msg.ProjectPath}, EffScanDirs{Roots: m.RootPaths})
+
+	case MsgProjectsDeleted:
+		m.Mode = ModeBrowsing
+		m.ProjectDeletePaths = nil
+		m.MarkedProjects = nil
+		m.BrowseNotice = msg.Report.Summary("Deleted", "project")
+		m.BrowseWarning = msg.Report.Warning()
+		m.Scanning = true
+		payloads := make([]HookPayload, 0, len(msg.Report.Done))
+		for _, path := range msg.Report.Done {
+			payloads = append(payloads, HookPayload{Event: HookProjectDelete, ProjectPath: path})
+		}
+		return withEachHooks(m, payloads, EffScanDirs{Roots: m.RootPaths})
 
 	case MsgWorktreesLoaded:
 		if msg.Err != nil {
@@ -68,14 +171,14 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 		m.ProjectWarning = msg.Warning
 		m.WorktreeWarning = ""
 		m.Worktrees = msg.Worktrees
-		m.FilteredWT = FilterWorktrees(m.Worktrees, m.WorktreeQuery)
+		m.FilteredWT = filterWorktrees(m)
 		m.WorktreeIdx = 0
 		return m, nil
 
 	case MsgWorktreeQueryChanged:
 		m.WorktreeQuery = msg.Query
 		m.WorktreeWarning = ""
-		m.FilteredWT = FilterWorktrees(m.Worktrees, m.WorktreeQuery)
+		m.FilteredWT = filterWorktrees(m)
 		m.WorktreeIdx = 0
 		return m, nil
 
@@ -92,13 +195,47 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 		}
 		m.SelectedWorktreePath = msg.Path
 		m.WorktreeWarning = ""
-		return enterToolMode(m)
+		m.Mode = ModeWorktreeSetup
+		m.SetupOutput = nil
+		return withHooks(m, HookPayload{
+			Event:        HookPostWorktreeCreate,
+			ProjectPath:  m.SelectedProject,
+			WorktreePath: msg.Path,
+			Branch:       msg.Branch,
+		}, EffSetupWorktree{ProjectPath: m.SelectedProject, WorktreePath: msg.Path})
+
+	case MsgWorktreeSetupOutput:
+		if m.Mode != ModeWorktreeSetup {
+			return m, nil
+		}
+		m.SetupOutput = append(m.SetupOutput, msg.Lines...)
+		if extra := len(m.SetupOutput) - setupOutputLimit; extra > 0 {
+			m.SetupOutput = append([]string(nil), m.SetupOutput[extra:]...)
+		}
+		return m, nil
+
+	case MsgWorktreeSetupDone:
+		if m.Mode != ModeWorktreeSetup || msg.WorktreePath != m.SelectedWorktreePath {
+			return m, nil
+		}
+		m.SetupOutput = nil
+		m, effects := enterToolMode(m)
+		if msg.Err != nil {
+			m.SetupWarning = msg.Err.Error()
+		}
+		return m, effects
 
 	case MsgWorktreeDeleted:
+		if errors.Is(msg.Err, ErrBranchNotMerged) && m.Mode == ModeWorktreeDeleteConfirm {
+			// Nothing was deleted; let the user keep or force delete the
+			// branch.
+			m.DeleteBranchWarning = msg.Err.Error()
+			return m, nil
+		}
 		if msg.Err != nil {
+			m = clearWorktreeDelete(m)
 			if IsRecoverableWorktreeDeleteError(msg.Err) {
 				m.Mode = ModeWorktree
-				m.WorktreeDeletePath = ""
 				m.WorktreeWarning = msg.Err.Error()
 				return m, nil
 			}
@@ -106,10 +243,96 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 			m.Err = msg.Err
 			return m, nil
 		}
+		m = clearWorktreeDelete(m)
 		m.Mode = ModeWorktree
-		m.WorktreeDeletePath = ""
 		m.WorktreeWarning = ""
-		return m, []Effect{EffLoadWorktrees{ProjectPath: m.SelectedProject}}
+		if msg.BranchErr != nil {
+			m.WorktreeWarning = "Workspace deleted, but its branch was kept: " + msg.BranchErr.Error()
+		}
+		return withHooks(m, HookPayload{
+			Event:        HookPostWorktreeDelete,
+			ProjectPath:  m.SelectedProject,
+			WorktreePath: msg.Path,
+			Branch:       worktreeBranch(m, msg.Path),
+		}, EffLoadWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees})
+
+	case MsgBranchesChecked:
+		if m.Mode != ModeWorktreeDeleteConfirm {
+			return m, nil
+		}
+		return deleteWorktrees(m, msg.Failed)
+
+	case MsgWorktreesDeleted:
+		m = clearWorktreeDelete(m)
+		m.Mode = ModeWorktree
+		m.MarkedWorktrees = nil
+		m.WorktreeNotice = msg.Report.Summary("Deleted", "workspace")
+		m.WorktreeWarning = msg.Report.Warning()
+		payloads := make([]HookPayload, 0, len(msg.Report.Done))
+		for _, path := range msg.Report.Done {
+			payloads = append(payloads, HookPayload{
+				Event:        HookPostWorktreeDelete,
+				ProjectPath:  m.SelectedProject,
+				WorktreePath: path,
+				Branch:       worktreeBranch(m, path),
+			})
+		}
+		return withEachHooks(m, payloads, EffLoadWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees})
+
+	case MsgWorktreeLanded:
+		if m.Mode != ModeWorktreeLand || msg.WorktreePath != m.LandPath {
+			return m, nil
+		}
+		deleteAfter := m.LandDelete
+		strategy := m.SelectedLandStrategy()
+		m = clearLand(m)
+		m.Mode = ModeWorktree
+		if msg.Err != nil {
+			// Conflicts and dirty trees are rolled back, so the workspace
+			// stays usable.
+			m.WorktreeWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.WorktreeWarning = ""
+		m.WorktreeNotice = fmt.Sprintf("Landed %s into %s (%s).", msg.Result.Branch, msg.Result.Base, strategy)
+		if deleteAfter {
+			m.WorktreeDeletePath = msg.WorktreePath
+			return withHooks(m, HookPayload{
+				Event:        HookPreWorktreeDelete,
+				ProjectPath:  m.SelectedProject,
+				WorktreePath: msg.WorktreePath,
+				Branch:       worktreeBranch(m, msg.WorktreePath),
+			}, EffDeleteWorktree{ProjectPath: m.SelectedProject, WorktreePath: msg.WorktreePath})
+		}
+		return m, []Effect{EffLoadWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees}}
+
+	case MsgWorktreeAdopted:
+		if msg.Err != nil {
+			m.WorktreeWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.WorktreeWarning = ""
+		selected, hasSelection := m.SelectedWorktree()
+		// The previous model shares the backing array, so edit a copy.
+		m.Worktrees = slices.Clone(m.Worktrees)
+		for i, wt := range m.Worktrees {
+			if wt.Path == msg.Path {
+				wt.Path = msg.NewPath
+				wt.Name = filepath.Base(msg.NewPath)
+				wt.Unmanaged = false
+				m.Worktrees[i] = wt
+			}
+		}
+		m = refilterWorktrees(m)
+		if hasSelection && selected.Path == msg.Path {
+			for i, wt := range m.FilteredWT {
+				if wt.Path == msg.NewPath {
+					m.WorktreeIdx = i
+					break
+				}
+			}
+		}
+		return m, []Effect{EffRefreshWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees}}
 
 	case MsgToolQueryChanged:
 		m.ToolQuery = msg.Query
@@ -157,7 +380,7 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 			spec := *m.PendingSpec
 			m.PendingSpec = nil
 			m.ToolError = ""
-			return m, []Effect{EffOpenSession{Spec: spec}}
+			return openSession(m, spec)
 		}
 		return m, nil
 
@@ -166,7 +389,7 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 			spec := *m.PendingSpec
 			m.PendingSpec = nil
 			m.ToolError = ""
-			return m, []Effect{EffOpenSession{Spec: spec}}
+			return openSession(m, spec)
 		}
 		return m, nil
 
@@ -176,17 +399,269 @@ func Update(m Model, msg Msg) (Model, []Effect) {
 			m.Err = msg.Err
 			return m, nil
 		}
+		selected, hadSelection := m.SelectedSession()
 		m.Sessions = msg.Sessions
-		m.FilteredSessions = FilterSessions(m.Sessions, m.SessionQuery)
+		m.SessionsListedAt = msg.Now
+		m.FilteredSessions = filterSessions(m)
 		m.SessionIdx = 0
+		if hadSelection {
+			m.SessionIdx = indexOfSession(m.FilteredSessions, selected.Name)
+		}
+		return m, nil
+
+	case MsgSessionsKilled:
+		m.SessionKillNames = nil
+		m.MarkedSessions = nil
+		m.SessionNotice = msg.Report.Summary("Killed", "session")
+		m.SessionWarning = msg.Report.Warning()
+		if m.Mode != ModeSessions {
+			return m, nil
+		}
+		return m, []Effect{EffListSessions{}}
+
+	case MsgSessionsStarted:
+		// The spec to attach to is only set once the other sessions run, so
+		// prewarm results for it cannot open it early.
+		tool, ok := m.SelectedTool()
+		if m.Mode != ModeToolStarting || m.PendingSpec != nil || !ok {
+			return m, nil
+		}
+		if len(msg.Report.Failures) > 0 {
+			m.Mode = ModeTool
+			m.ToolError = msg.Report.Warning()
+			return m, nil
+		}
+		spec := SessionSpec{DirPath: m.SelectedWorktreePath, Tool: tool}
+		if ToolNeedsWarmup(tool) {
+			m.PendingSpec = &spec
+			return m, []Effect{EffCheckToolReady{Spec: spec}}
+		}
+		return openSession(m, spec)
+
+	case MsgRowSelected:
+		return selectRow(m, msg.Index), nil
+
+	case MsgSessionsChanged:
+		if m.Mode != ModeSessions {
+			return m, nil
+		}
+		return m, []Effect{EffListSessions{}}
+
+	case MsgLegacySessionsFound:
+		// The legacy session check is advisory; a failure must not block browsing.
+		if msg.Err != nil {
+			m.LegacySessions = nil
+			return m, nil
+		}
+		m.LegacySessions = msg.Sessions
+		return m, nil
+
+	case MsgSessionsMigrated:
+		m.LegacySessions = msg.Failed
+		m.BrowseNotice = ""
+		switch msg.Migrated {
+		case 0:
+		case 1:
+			m.BrowseNotice = "Migrated 1 legacy session."
+		default:
+			m.BrowseNotice = fmt.Sprintf("Migrated %d legacy sessions.", msg.Migrated)
+		}
+		m.BrowseWarning = strings.Join(msg.Failures, "; ")
 		return m, nil
 
 	case MsgSessionQueryChanged:
 		m.SessionQuery = msg.Query
-		m.FilteredSessions = FilterSessions(m.Sessions, m.SessionQuery)
+		m.FilteredSessions = filterSessions(m)
 		m.SessionIdx = 0
 		return m, nil
 
+	case MsgChangesLoaded:
+		if m.Mode != ModeReview || msg.WorktreePath != m.ReviewPath {
+			return m, nil
+		}
+		m.ReviewLoading = false
+		if msg.Err != nil {
+			m.ReviewChanges = WorktreeChanges{}
+			m.ReviewDiff = ""
+			m.ReviewWarning = msg.Err.Error()
+			return m, nil
+		}
+		selected, hadSelection := m.SelectedReviewFile()
+		m.ReviewChanges = msg.Changes
+		m.ReviewIdx = 0
+		if hadSelection {
+			m.ReviewIdx = indexOfFileChange(msg.Changes.Files, selected.Path)
+		}
+		return loadSelectedDiff(m)
+
+	case MsgFileDiffLoaded:
+		file, ok := m.SelectedReviewFile()
+		if m.Mode != ModeReview || msg.WorktreePath != m.ReviewPath || !ok || file.Path != msg.Path {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.ReviewDiff = ""
+			m.ReviewWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.ReviewDiff = msg.Diff
+		return m, nil
+
+	case MsgFileDiscarded:
+		if m.Mode != ModeReview || msg.WorktreePath != m.ReviewPath {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.ReviewWarning = msg.Err.Error()
+			return m, nil
+		}
+		return reloadReview(m)
+
+	case MsgChangesCommitted:
+		if m.Mode != ModeReview || msg.WorktreePath != m.ReviewPath {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.ReviewLoading = false
+			m.ReviewWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.ReviewCommitMessage = ""
+		return reloadReview(m)
+
+	case MsgCommitMessageChanged:
+		m.ReviewCommitMessage = msg.Message
+		return m, nil
+
+	case MsgEditorClosed:
+		if m.Mode != ModeReview || msg.WorktreePath != m.ReviewPath {
+			return m, nil
+		}
+		m, effects := reloadReview(m)
+		if msg.Err != nil {
+			m.ReviewWarning = msg.Err.Error()
+		}
+		return m, effects
+
+	case MsgPinsLoaded:
+		if msg.Err != nil {
+			m.BrowseWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.Pinned = make(map[string]bool, len(msg.Paths))
+		for _, path := range msg.Paths {
+			m.Pinned[path] = true
+		}
+		m = refilterDirs(m)
+		if m.SelectedProject != "" {
+			m = refilterWorktrees(m)
+		}
+		return m, nil
+
+	case MsgTagsLoaded:
+		if msg.Err != nil {
+			m.BrowseWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.ProjectTags = msg.Tags
+		m = refilterDirs(m)
+		if m.Mode == ModeSessions {
+			selected, hadSelection := m.SelectedSession()
+			m.FilteredSessions = filterSessions(m)
+			m.SessionIdx = 0
+			if hadSelection {
+				m.SessionIdx = indexOfSession(m.FilteredSessions, selected.Name)
+			}
+		}
+		return m, nil
+
+	case MsgProjectTagsSaved:
+		if msg.Err != nil {
+			m.BrowseWarning = msg.Err.Error()
+		}
+		return m, nil
+
+	case MsgTagInputChanged:
+		if m.Mode == ModeProjectTag {
+			m.TagInput = msg.Input
+		}
+		return m, nil
+
+	case MsgPinSaved:
+		if msg.Err == nil {
+			return m, nil
+		}
+		if m.Mode == ModeWorktree {
+			m.WorktreeWarning = msg.Err.Error()
+		} else {
+			m.BrowseWarning = msg.Err.Error()
+		}
+		return m, nil
+
+	case MsgCleanupLoaded:
+		if m.Mode != ModeCleanup {
+			return m, nil
+		}
+		m.CleanupLoading = false
+		if msg.Err != nil {
+			m.CleanupWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.CleanupCandidates = FindStaleWorktrees(msg.Activity, msg.Sessions, msg.Launches, m.CleanupInactiveFor, msg.Now)
+		// Missing and merged worktrees are marked up front; inactive ones
+		// may still be wanted, so they are left for the user to pick.
+		m.CleanupSelected = make(map[string]bool, len(m.CleanupCandidates))
+		for _, wt := range m.CleanupCandidates {
+			if wt.Reason != StaleInactive {
+				m.CleanupSelected[wt.Worktree.Path] = true
+			}
+		}
+		m.CleanupIdx = clampIndex(m.CleanupIdx, len(m.CleanupCandidates)-1)
+		return m, nil
+
+	case MsgRecentLoaded:
+		if m.Mode != ModeRecent {
+			return m, nil
+		}
+		m.RecentLoading = false
+		if msg.Err != nil {
+			m.RecentWarning = msg.Err.Error()
+			return m, nil
+		}
+		m.RecentWarning = ""
+		m.RecentLaunches = MarkAlive(msg.Launches, msg.Sessions)
+		m.RecentIdx = clampIndex(m.RecentIdx, len(m.RecentLaunches)-1)
+		return m, nil
+
+	case MsgWorktreesCleaned:
+		if m.Mode != ModeCleanup {
+			return m, nil
+		}
+		m.CleanupRunning = false
+		m.CleanupLoading = true
+		m.CleanupNotice = ""
+		switch removed := len(msg.Report.Removed); removed {
+		case 0:
+		case 1:
+			m.CleanupNotice = "Deleted 1 worktree."
+		default:
+			m.CleanupNotice = fmt.Sprintf("Deleted %d worktrees.", removed)
+		}
+		m.CleanupWarning = strings.Join(msg.Report.Failures, "; ")
+		payloads := make([]HookPayload, 0, len(msg.Report.Removed))
+		for _, wt := range msg.Report.Removed {
+			payloads = append(payloads, cleanupHookPayload(HookPostWorktreeDelete, wt))
+		}
+		return withEachHooks(m, payloads, EffLoadCleanup{ProjectPaths: dirPaths(m.Dirs)})
+
+	case MsgHookFinished:
+		// Hooks are the user's own scripts; a failure is reported but never
+		// undoes or blocks the action that triggered it.
+		if msg.Err != nil {
+			m.HookWarning = HookError(msg.Event, msg.Command, msg.Err).Error()
+		}
+		return m, nil
+
 	}
 
 	return m, nil
@@ -198,6 +673,9 @@ func UpdateKey(m Model, key KeyAction) (Model, []Effect, bool) {
 
 const pageJump = 5
 
+// setupOutputLimit caps the worktree setup output kept for display.
+const setupOutputLimit = 200
+
 func clampIndex(idx, maxIdx int) int {
 	if maxIdx < 0 {
 		return 0
@@ -215,22 +693,65 @@ func moveIndex(idx, maxIdx, delta int) int {
 	return clampIndex(idx+delta, maxIdx)
 }
 
+// selectRow highlights a row of the list shown in the current mode. Modes
+// without a selectable list ignore it.
+func selectRow(m Model, idx int) Model {
+	switch m.Mode {
+	case ModeBrowsing:
+		maxIdx := len(m.Filtered) - 1
+		if _, ok := m.CreateProjectPath(); ok {
+			maxIdx = len(m.Filtered)
+		}
+		m.SelectedIdx = clampIndex(idx, maxIdx)
+	case ModeWorktree:
+		maxIdx := len(m.FilteredWT) - 1
+		if _, ok := m.CreateWorktreeName(); ok {
+			maxIdx = len(m.FilteredWT)
+		}
+		m.WorktreeIdx = clampIndex(idx, maxIdx)
+	case ModeTool:
+		m.ToolIdx = clampIndex(idx, len(m.FilteredTools)-1)
+		m.ToolError = ""
+	case ModeSessions:
+		m.SessionIdx = clampIndex(idx, len(m.FilteredSessions)-1)
+	case ModeRecent:
+		m.RecentIdx = clampIndex(idx, len(m.RecentLaunches)-1)
+	}
+	return m
+}
+
 func handleKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	switch m.Mode {
 	case ModeBrowsing:
 		return handleBrowsingKey(m, key)
+	case ModeProjectCloning:
+		return handleProjectCloningKey(m, key)
 	case ModeProjectDeleteConfirm:
 		return handleProjectDeleteConfirmKey(m, key)
+	case ModeProjectTag:
+		return handleProjectTagKey(m, key)
+	case ModeSessionMigrateConfirm:
+		return handleSessionMigrateConfirmKey(m, key)
 	case ModeWorktree:
 		return handleWorktreeKey(m, key)
 	case ModeWorktreeDeleteConfirm:
 		return handleWorktreeDeleteConfirmKey(m, key)
+	case ModeWorktreeLand:
+		return handleWorktreeLandKey(m, key)
+	case ModeWorktreeSetup:
+		return handleWorktreeSetupKey(m, key)
+	case ModeReview:
+		return handleReviewKey(m, key)
 	case ModeTool:
 		return handleToolKey(m, key)
 	case ModeToolStarting:
 		return handleToolStartingKey(m, key)
 	case ModeSessions:
 		return handleSessionsKey(m, key)
+	case ModeCleanup:
+		return handleCleanupKey(m, key)
+	case ModeRecent:
+		return handleRecentKey(m, key)
 	}
 	return m, nil, false
 }
@@ -279,48 +800,219 @@ func handleBrowsingKey(m Model, key KeyAction) (Model, []Effect, bool) {
 			m.Mode = ModeWorktree
 			m.WorktreeQuery = ""
 			m.WorktreeIdx = 0
-			return m, []Effect{EffLoadWorktrees{ProjectPath: dir.Path}}, true
+			return m, []Effect{EffLoadWorktrees{ProjectPath: dir.Path, All: m.ShowAllWorktrees}}, true
 		}
 		if path, ok := m.CreateProjectPath(); ok {
+			if source, ok := m.CloneSource(); ok {
+				m.Mode = ModeProjectCloning
+				m.CloningSource = source
+				m.CloningPath = path
+				m.CloneProgress = CloneProgress{}
+				m.CloneError = ""
+				return m, []Effect{EffCloneProject{Source: source, Path: path}}, true
+			}
 			return m, []Effect{EffCreateProject{Path: path}}, true
 		}
 		return m, nil, true
 	case KeyDelete:
+		m.BrowseNotice = ""
+		m.BrowseWarning = ""
+		if marked := m.MarkedDirs(); len(marked) > 0 {
+			m.Mode = ModeProjectDeleteConfirm
+			m.ProjectDeletePaths = dirPaths(marked)
+			return m, nil, true
+		}
 		if dir, ok := m.SelectedDir(); ok {
 			m.Mode = ModeProjectDeleteConfirm
 			m.ProjectDeletePath = dir.Path
 			return m, nil, true
 		}
 		return m, nil, true
+	case KeyMark:
+		if dir, ok := m.SelectedDir(); ok {
+			m.MarkedProjects = toggleMark(m.MarkedProjects, dir.Path)
+		}
+		return m, nil, true
+	case KeyPin:
+		dir, ok := m.SelectedDir()
+		if !ok {
+			return m, nil, true
+		}
+		m.BrowseWarning = ""
+		m, effects := togglePin(m, dir.Path)
+		return m, effects, true
+	case KeyTag:
+		dir, ok := m.SelectedDir()
+		if !ok {
+			return m, nil, true
+		}
+		m.BrowseWarning = ""
+		m.Mode = ModeProjectTag
+		m.TagProjectPath = dir.Path
+		m.TagInput = strings.Join(m.ProjectTags[dir.Path], ", ")
+		return m, nil, true
 	case KeySessions:
 		return enterSessionsMode(m)
-	case KeyBack, KeyQuit:
+	case KeyAdopt:
+		if len(m.LegacySessions) > 0 {
+			m.BrowseNotice = ""
+			m.BrowseWarning = ""
+			m.Mode = ModeSessionMigrateConfirm
+		}
+		return m, nil, true
+	case KeyCleanup:
+		m = clearCleanup(m)
+		m.Mode = ModeCleanup
+		m.CleanupLoading = true
+		return m, []Effect{EffLoadCleanup{ProjectPaths: dirPaths(m.Dirs)}}, true
+	case KeyRecent:
+		m = clearRecent(m)
+		m.Mode = ModeRecent
+		m.RecentLoading = true
+		return m, []Effect{EffLoadRecent{}}, true
+	case KeyRefresh:
+		if m.Scanning {
+			return m, nil, true
+		}
+		m.Scanning = true
+		return m, []Effect{EffScanDirs{Roots: m.RootPaths}}, true
+	case KeyBack:
+		// esc drops the marks before it quits.
+		if len(m.MarkedProjects) > 0 {
+			m.MarkedProjects = nil
+			return m, nil, true
+		}
+		return m, []Effect{EffQuit{}}, true
+	case KeyQuit:
 		return m, []Effect{EffQuit{}}, true
 	}
 	return m, nil, false
 }
 
-func handleProjectDeleteConfirmKey(m Model, key KeyAction) (Model, []Effect, bool) {
+func handleProjectCloningKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	switch key {
-	case KeyEnter:
-		if m.ProjectDeletePath != "" {
-			return m, []Effect{EffDeleteProject{ProjectPath: m.ProjectDeletePath}}, true
-		}
-		m.Mode = ModeBrowsing
-		return m, nil, true
 	case KeyBack:
+		m = clearClone(m)
 		m.Mode = ModeBrowsing
-		m.ProjectDeletePath = ""
-		return m, nil, true
+		return m, []Effect{EffCancelClone{}}, true
 	case KeyQuit:
-		return m, []Effect{EffQuit{}}, true
+		return m, []Effect{EffCancelClone{}, EffQuit{}}, true
 	}
-	return m, nil, false
+	return m, nil, true
 }
 
-func handleWorktreeKey(m Model, key KeyAction) (Model, []Effect, bool) {
+// openNewProject moves to Step 2 for a project that was just created or
+// cloned.
+func openNewProject(m Model, projectPath string) (Model, []Effect) {
+	m.SelectedProject = projectPath
+	m.Mode = ModeWorktree
+	m.WorktreeQuery = ""
+	m.WorktreeIdx = 0
+	m.ProjectWarning = ""
+	m.WorktreeWarning = ""
+	return withHooks(m, HookPayload{Event: HookProjectCreate, ProjectPath: projectPath},
+		EffLoadWorktrees{ProjectPath: projectPath, All: m.ShowAllWorktrees})
+}
+
+// openSession hands the spec to the UI to open, after the session_open
+// hooks.
+func openSession(m Model, spec SessionSpec) (Model, []Effect) {
+	return withHooks(m, HookPayload{
+		Event:        HookSessionOpen,
+		ProjectPath:  m.SelectedProject,
+		WorktreePath: spec.DirPath,
+		Branch:       worktreeBranch(m, spec.DirPath),
+		Tool:         spec.Tool,
+	}, EffOpenSession{Spec: spec})
+}
+
+func clearClone(m Model) Model {
+	m.CloningSource = ""
+	m.CloningPath = ""
+	m.CloneProgress = CloneProgress{}
+	return m
+}
+
+// handleProjectTagKey saves the tags typed for a project on enter. Other
+// keys are left to the tag editor.
+func handleProjectTagKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	switch key {
-	case KeyUp:
+	case KeyEnter:
+		path := m.TagProjectPath
+		tags := ParseTags(m.TagInput)
+		m.ProjectTags = setProjectTags(m.ProjectTags, path, tags)
+		m = clearProjectTag(m)
+		m = refilterDirs(m)
+		return m, []Effect{EffSetProjectTags{ProjectPath: path, Tags: tags}}, true
+	case KeyBack:
+		return clearProjectTag(m), nil, true
+	case KeyQuit:
+		return m, []Effect{EffQuit{}}, true
+	}
+	return m, nil, false
+}
+
+func clearProjectTag(m Model) Model {
+	m.Mode = ModeBrowsing
+	m.TagProjectPath = ""
+	m.TagInput = ""
+	return m
+}
+
+func handleProjectDeleteConfirmKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	switch key {
+	case KeyEnter:
+		if len(m.ProjectDeletePaths) > 0 {
+			return m, []Effect{EffDeleteProjects{ProjectPaths: m.ProjectDeletePaths}}, true
+		}
+		if m.ProjectDeletePath != "" {
+			return m, []Effect{EffDeleteProject{ProjectPath: m.ProjectDeletePath}}, true
+		}
+		m.Mode = ModeBrowsing
+		return m, nil, true
+	case KeyBack:
+		m.Mode = ModeBrowsing
+		m.ProjectDeletePath = ""
+		m.ProjectDeletePaths = nil
+		return m, nil, true
+	case KeyQuit:
+		return m, []Effect{EffQuit{}}, true
+	}
+	return m, nil, false
+}
+
+// handleSessionMigrateConfirmKey renames the legacy sessions on enter or
+// adopts their names on the adopt key, like `rv sessions migrate`.
+func handleSessionMigrateConfirmKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	switch key {
+	case KeyEnter:
+		m, effects := migrateLegacySessions(m, SessionMigrationRename)
+		return m, effects, true
+	case KeyAdopt:
+		m, effects := migrateLegacySessions(m, SessionMigrationAdopt)
+		return m, effects, true
+	case KeyBack:
+		m.Mode = ModeBrowsing
+		return m, nil, true
+	case KeyQuit:
+		return m, []Effect{EffQuit{}}, true
+	}
+	return m, nil, false
+}
+
+func migrateLegacySessions(m Model, action SessionMigrationAction) (Model, []Effect) {
+	sessions := make([]SessionMigration, 0, len(m.LegacySessions))
+	for _, migration := range m.LegacySessions {
+		migration.Action = action
+		sessions = append(sessions, migration)
+	}
+	m.Mode = ModeBrowsing
+	return m, []Effect{EffMigrateSessions{Sessions: sessions}}
+}
+
+func handleWorktreeKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	switch key {
+	case KeyUp:
 		if m.WorktreeIdx > 0 {
 			m.WorktreeIdx--
 		}
@@ -357,6 +1049,20 @@ func handleWorktreeKey(m Model, key KeyAction) (Model, []Effect, bool) {
 		m.WorktreeIdx = clampIndex(maxIdx, maxIdx)
 		return m, nil, true
 	case KeyEnter:
+		if marked := m.MarkedWorktreeList(); len(marked) > 0 {
+			// The session attached to at the end is the highlighted one if
+			// it is marked, else the first marked.
+			primary := marked[0].Path
+			if wt, ok := m.SelectedWorktree(); ok && m.MarkedWorktrees[wt.Path] {
+				primary = wt.Path
+			}
+			m.SelectedWorktreePath = primary
+			m, effects := enterToolMode(m)
+			for _, wt := range marked {
+				m.ToolTargets = append(m.ToolTargets, wt.Path)
+			}
+			return m, effects, true
+		}
 		if wt, ok := m.SelectedWorktree(); ok {
 			m.SelectedWorktreePath = wt.Path
 			m, effects := enterToolMode(m)
@@ -364,21 +1070,96 @@ func handleWorktreeKey(m Model, key KeyAction) (Model, []Effect, bool) {
 		}
 		if name, ok := m.CreateWorktreeName(); ok {
 			m.WorktreeWarning = ""
-			return m, []Effect{EffCreateWorktree{
+			m.WorktreeNotice = ""
+			m, effects := withHooks(m, HookPayload{
+				Event:       HookPreWorktreeCreate,
 				ProjectPath: m.SelectedProject,
-				BranchName:  name,
-			}}, true
+				Branch:      name,
+			}, EffCreateWorktree{ProjectPath: m.SelectedProject, BranchName: name})
+			return m, effects, true
 		}
 		return m, nil, true
 	case KeyDelete:
+		if marked := m.MarkedWorktreeList(); len(marked) > 0 {
+			m = clearWorktreeDelete(m)
+			m.Mode = ModeWorktreeDeleteConfirm
+			for _, wt := range marked {
+				m.WorktreeDeletePaths = append(m.WorktreeDeletePaths, wt.Path)
+			}
+			m.WorktreeWarning = ""
+			m.WorktreeNotice = ""
+			return m, nil, true
+		}
 		if wt, ok := m.SelectedWorktree(); ok {
+			m = clearWorktreeDelete(m)
 			m.Mode = ModeWorktreeDeleteConfirm
 			m.WorktreeDeletePath = wt.Path
 			m.WorktreeWarning = ""
+			m.WorktreeNotice = ""
 			return m, nil, true
 		}
 		return m, nil, true
+	case KeyShowAll:
+		m.ShowAllWorktrees = !m.ShowAllWorktrees
+		m.WorktreeWarning = ""
+		m.WorktreeNotice = ""
+		return m, []Effect{EffRefreshWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees}}, true
+	case KeyRefresh:
+		return m, []Effect{EffRefreshWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees}}, true
+	case KeyReview:
+		wt, ok := m.SelectedWorktree()
+		if !ok {
+			return m, nil, true
+		}
+		m.Mode = ModeReview
+		m.ReviewPath = wt.Path
+		m.WorktreeWarning = ""
+		m.WorktreeNotice = ""
+		m, effects := reloadReview(m)
+		return m, effects, true
+	case KeyLand:
+		wt, ok := m.SelectedWorktree()
+		if !ok {
+			return m, nil, true
+		}
+		m.WorktreeNotice = ""
+		if wt.Path == m.SelectedProject {
+			m.WorktreeWarning = ErrWorktreeLandRoot.Error()
+			return m, nil, true
+		}
+		m.Mode = ModeWorktreeLand
+		m.LandPath = wt.Path
+		m.LandStrategyIdx = 0
+		m.LandDelete = false
+		m.WorktreeWarning = ""
+		return m, nil, true
+	case KeyAdopt:
+		wt, ok := m.SelectedWorktree()
+		if !ok || !wt.Unmanaged {
+			return m, nil, true
+		}
+		m.WorktreeWarning = ""
+		m.WorktreeNotice = ""
+		return m, []Effect{EffAdoptWorktree{ProjectPath: m.SelectedProject, WorktreePath: wt.Path}}, true
+	case KeyMark:
+		if wt, ok := m.SelectedWorktree(); ok {
+			m.MarkedWorktrees = toggleMark(m.MarkedWorktrees, wt.Path)
+			m.WorktreeNotice = ""
+		}
+		return m, nil, true
+	case KeyPin:
+		wt, ok := m.SelectedWorktree()
+		if !ok {
+			return m, nil, true
+		}
+		m.WorktreeWarning = ""
+		m, effects := togglePin(m, wt.Path)
+		return m, effects, true
 	case KeyBack:
+		if len(m.MarkedWorktrees) > 0 {
+			m.MarkedWorktrees = nil
+			return m, nil, true
+		}
 		m.Mode = ModeBrowsing
 		m.WorktreeQuery = ""
 		m.Worktrees = nil
@@ -388,6 +1169,7 @@ func handleWorktreeKey(m Model, key KeyAction) (Model, []Effect, bool) {
 		m.SelectedWorktreePath = ""
 		m.WorktreeDeletePath = ""
 		m.WorktreeWarning = ""
+		m.WorktreeNotice = ""
 		return m, nil, true
 	case KeySessions:
 		return enterSessionsMode(m)
@@ -397,20 +1179,165 @@ func handleWorktreeKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	return m, nil, false
 }
 
-func handleWorktreeDeleteConfirmKey(m Model, key KeyAction) (Model, []Effect, bool) {
+func handleReviewKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	if m.ReviewDiscardPath != "" {
+		switch key {
+		case KeyEnter:
+			file, ok := m.SelectedReviewFile()
+			m.ReviewDiscardPath = ""
+			if !ok {
+				return m, nil, true
+			}
+			return m, []Effect{EffDiscardFile{WorktreePath: m.ReviewPath, MergeBase: m.ReviewChanges.MergeBase, File: file}}, true
+		case KeyBack:
+			m.ReviewDiscardPath = ""
+			return m, nil, true
+		case KeyQuit:
+			return m, []Effect{EffQuit{}}, true
+		}
+		return m, nil, true
+	}
+
+	if m.ReviewCommitting {
+		switch key {
+		case KeyEnter:
+			message := strings.TrimSpace(m.ReviewCommitMessage)
+			if message == "" {
+				return m, nil, true
+			}
+			m.ReviewCommitting = false
+			m.ReviewLoading = true
+			m.ReviewWarning = ""
+			return m, []Effect{EffCommitAll{WorktreePath: m.ReviewPath, Message: message}}, true
+		case KeyBack:
+			m.ReviewCommitting = false
+			return m, nil, true
+		case KeyQuit:
+			return m, []Effect{EffQuit{}}, true
+		}
+		// Everything else edits the commit message.
+		return m, nil, false
+	}
+
+	files := m.ReviewChanges.Files
 	switch key {
+	case KeyUp:
+		return selectReviewFile(m, max(m.ReviewIdx-1, 0))
+	case KeyDown:
+		return selectReviewFile(m, moveIndex(m.ReviewIdx, len(files)-1, 1))
+	case KeyTop:
+		return selectReviewFile(m, 0)
+	case KeyBottom:
+		return selectReviewFile(m, clampIndex(len(files)-1, len(files)-1))
 	case KeyEnter:
-		if m.WorktreeDeletePath != "" {
-			return m, []Effect{EffDeleteWorktree{
-				ProjectPath:  m.SelectedProject,
-				WorktreePath: m.WorktreeDeletePath,
-			}}, true
+		file, ok := m.SelectedReviewFile()
+		if !ok || file.Status == FileDeleted {
+			return m, nil, true
+		}
+		return m, []Effect{EffOpenEditor{WorktreePath: m.ReviewPath, Path: file.Path}}, true
+	case KeyDelete:
+		if file, ok := m.SelectedReviewFile(); ok {
+			m.ReviewDiscardPath = file.Path
+			m.ReviewWarning = ""
 		}
+		return m, nil, true
+	case KeyCommit:
+		if len(files) > 0 && !m.ReviewLoading {
+			m.ReviewCommitting = true
+			m.ReviewWarning = ""
+		}
+		return m, nil, true
+	case KeyBack:
+		m = clearReview(m)
 		m.Mode = ModeWorktree
 		return m, nil, true
+	case KeyQuit:
+		return m, []Effect{EffQuit{}}, true
+	}
+	return m, nil, true
+}
+
+// reloadReview lists the reviewed worktree's changes again, keeping the
+// selection where possible.
+func reloadReview(m Model) (Model, []Effect) {
+	m.ReviewLoading = true
+	m.ReviewWarning = ""
+	return m, []Effect{EffLoadChanges{WorktreePath: m.ReviewPath}}
+}
+
+func loadSelectedDiff(m Model) (Model, []Effect) {
+	file, ok := m.SelectedReviewFile()
+	if !ok {
+		m.ReviewDiff = ""
+		return m, nil
+	}
+	return m, []Effect{EffLoadFileDiff{WorktreePath: m.ReviewPath, MergeBase: m.ReviewChanges.MergeBase, File: file}}
+}
+
+func selectReviewFile(m Model, idx int) (Model, []Effect, bool) {
+	if len(m.ReviewChanges.Files) == 0 || idx == m.ReviewIdx {
+		return m, nil, true
+	}
+	m.ReviewIdx = idx
+	m.ReviewWarning = ""
+	m, effects := loadSelectedDiff(m)
+	return m, effects, true
+}
+
+func clearReview(m Model) Model {
+	m.ReviewPath = ""
+	m.ReviewChanges = WorktreeChanges{}
+	m.ReviewIdx = 0
+	m.ReviewDiff = ""
+	m.ReviewLoading = false
+	m.ReviewWarning = ""
+	m.ReviewDiscardPath = ""
+	m.ReviewCommitting = false
+	m.ReviewCommitMessage = ""
+	return m
+}
+
+func indexOfFileChange(files []FileChange, path string) int {
+	for i, file := range files {
+		if file.Path == path {
+			return i
+		}
+	}
+	return 0
+}
+
+func handleWorktreeDeleteConfirmKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	switch key {
+	case KeyEnter:
+		if m.WorktreeDeletePath == "" && len(m.WorktreeDeletePaths) == 0 {
+			m.Mode = ModeWorktree
+			return m, nil, true
+		}
+		m.DeleteBranchWarning = ""
+		// A safe branch delete can still refuse, so it is checked before
+		// the pre delete hooks announce a delete that would not happen.
+		if m.WorktreeDeleteBranch == BranchDelete {
+			var check []Worktree
+			for _, wt := range worktreesToDelete(m) {
+				if deletableBranch(wt.Branch) {
+					check = append(check, wt)
+				}
+			}
+			if len(check) > 0 {
+				return m, []Effect{EffCheckBranchesMerged{ProjectPath: m.SelectedProject, Worktrees: check}}, true
+			}
+		}
+		m, effects := deleteWorktrees(m, nil)
+		return m, effects, true
+	case KeyDelete:
+		if len(m.DeletableBranches()) > 0 {
+			m.WorktreeDeleteBranch = m.WorktreeDeleteBranch.Next()
+			m.DeleteBranchWarning = ""
+		}
+		return m, nil, true
 	case KeyBack:
+		m = clearWorktreeDelete(m)
 		m.Mode = ModeWorktree
-		m.WorktreeDeletePath = ""
 		return m, nil, true
 	case KeyQuit:
 		return m, []Effect{EffQuit{}}, true
@@ -418,6 +1345,126 @@ func handleWorktreeDeleteConfirmKey(m Model, key KeyAction) (Model, []Effect, bo
 	return m, nil, false
 }
 
+// worktreesToDelete lists the worktrees the delete confirmation is for.
+func worktreesToDelete(m Model) []Worktree {
+	paths := m.WorktreeDeletePaths
+	if len(paths) == 0 {
+		paths = []string{m.WorktreeDeletePath}
+	}
+	worktrees := make([]Worktree, 0, len(paths))
+	for _, path := range paths {
+		worktrees = append(worktrees, Worktree{Path: path, Name: filepath.Base(path), Branch: worktreeBranch(m, path)})
+	}
+	return worktrees
+}
+
+// deleteWorktrees runs the pre delete hooks of the worktrees being deleted
+// and then deletes them. Worktrees in failed were refused by the branch
+// check: a single one is reported like a failed delete, and in a bulk
+// delete they are left out and reported as failed.
+func deleteWorktrees(m Model, failed map[string]error) (Model, []Effect) {
+	if len(m.WorktreeDeletePaths) == 0 {
+		path := m.WorktreeDeletePath
+		if err := failed[path]; err != nil {
+			return Update(m, MsgWorktreeDeleted{Path: path, Err: err})
+		}
+		branch := worktreeBranch(m, path)
+		deletion := m.WorktreeDeleteBranch
+		if !deletableBranch(branch) {
+			deletion = BranchKeep
+		}
+		return withHooks(m, HookPayload{
+			Event:        HookPreWorktreeDelete,
+			ProjectPath:  m.SelectedProject,
+			WorktreePath: path,
+			Branch:       branch,
+		}, EffDeleteWorktree{
+			ProjectPath:  m.SelectedProject,
+			WorktreePath: path,
+			Branch:       branch,
+			DeleteBranch: deletion,
+		})
+	}
+
+	var report BatchReport
+	worktrees := make([]Worktree, 0, len(m.WorktreeDeletePaths))
+	payloads := make([]HookPayload, 0, len(m.WorktreeDeletePaths))
+	for _, wt := range worktreesToDelete(m) {
+		if err := failed[wt.Path]; err != nil {
+			report.Fail(wt.Name, err)
+			continue
+		}
+		worktrees = append(worktrees, wt)
+		payloads = append(payloads, HookPayload{
+			Event:        HookPreWorktreeDelete,
+			ProjectPath:  m.SelectedProject,
+			WorktreePath: wt.Path,
+			Branch:       wt.Branch,
+		})
+	}
+	return withEachHooks(m, payloads, EffDeleteWorktrees{
+		ProjectPath:  m.SelectedProject,
+		Worktrees:    worktrees,
+		DeleteBranch: m.WorktreeDeleteBranch,
+		Failures:     report.Failures,
+	})
+}
+
+func clearWorktreeDelete(m Model) Model {
+	m.WorktreeDeletePath = ""
+	m.WorktreeDeletePaths = nil
+	m.WorktreeDeleteBranch = BranchKeep
+	m.DeleteBranchWarning = ""
+	return m
+}
+
+// deletableBranch reports whether a worktree's branch can be deleted along
+// with it; detached worktrees have none.
+func deletableBranch(branch string) bool {
+	return branch != "" && branch != "(detached)"
+}
+
+func handleWorktreeLandKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	if key == KeyQuit {
+		return m, []Effect{EffQuit{}}, true
+	}
+	if m.Landing {
+		return m, nil, true
+	}
+	strategies := LandStrategies()
+	switch key {
+	case KeyUp:
+		m.LandStrategyIdx = moveIndex(m.LandStrategyIdx, len(strategies)-1, -1)
+	case KeyDown:
+		m.LandStrategyIdx = moveIndex(m.LandStrategyIdx, len(strategies)-1, 1)
+	case KeyTop:
+		m.LandStrategyIdx = 0
+	case KeyBottom:
+		m.LandStrategyIdx = len(strategies) - 1
+	case KeyDelete:
+		m.LandDelete = !m.LandDelete
+	case KeyEnter:
+		m.Landing = true
+		return m, []Effect{EffLandWorktree{
+			ProjectPath:  m.SelectedProject,
+			WorktreePath: m.LandPath,
+			Strategy:     m.SelectedLandStrategy(),
+		}}, true
+	case KeyBack:
+		m = clearLand(m)
+		m.Mode = ModeWorktree
+	}
+	return m, nil, true
+}
+
+func clearLand(m Model) Model {
+	m.LandPath = ""
+	m.LandStrategyIdx = 0
+	m.LandDelete = false
+	m.Landing = false
+	return m
+}
+
 func handleToolKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	switch key {
 	case KeyUp:
@@ -454,6 +1501,10 @@ func handleToolKey(m Model, key KeyAction) (Model, []Effect, bool) {
 				return m, nil, true
 			}
 
+			if len(m.ToolTargets) > 1 {
+				m, effects := startMarkedSessions(m, tool)
+				return m, effects, true
+			}
 			spec := SessionSpec{
 				DirPath: m.SelectedWorktreePath,
 				Tool:    tool,
@@ -461,7 +1512,8 @@ func handleToolKey(m Model, key KeyAction) (Model, []Effect, bool) {
 			if !ToolNeedsWarmup(tool) {
 				m.PendingSpec = nil
 				m.ToolError = ""
-				return m, []Effect{EffOpenSession{Spec: spec}}, true
+				m, effects := openSession(m, spec)
+				return m, effects, true
 			}
 			m.PendingSpec = &spec
 			m.Mode = ModeToolStarting
@@ -471,9 +1523,11 @@ func handleToolKey(m Model, key KeyAction) (Model, []Effect, bool) {
 		return m, nil, true
 	case KeyBack:
 		m.Mode = ModeWorktree
+		m.ToolTargets = nil
 		m.ToolQuery = ""
 		m.ToolIdx = 0
 		m.ToolError = ""
+		m.SetupWarning = ""
 		return m, nil, true
 	case KeySessions:
 		return enterSessionsMode(m)
@@ -483,6 +1537,31 @@ func handleToolKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	return m, nil, false
 }
 
+// startMarkedSessions starts the tool in every marked worktree but the
+// selected one in the background; MsgSessionsStarted then opens the selected
+// one as usual.
+func startMarkedSessions(m Model, tool string) (Model, []Effect) {
+	var specs []SessionSpec
+	var payloads []HookPayload
+	for _, path := range m.ToolTargets {
+		if path == m.SelectedWorktreePath {
+			continue
+		}
+		specs = append(specs, SessionSpec{DirPath: path, Tool: tool, Detach: true})
+		payloads = append(payloads, HookPayload{
+			Event:        HookSessionOpen,
+			ProjectPath:  m.SelectedProject,
+			WorktreePath: path,
+			Branch:       worktreeBranch(m, path),
+			Tool:         tool,
+		})
+	}
+	m.Mode = ModeToolStarting
+	m.PendingSpec = nil
+	m.ToolError = ""
+	return withEachHooks(m, payloads, EffStartSessions{Specs: specs})
+}
+
 func handleToolStartingKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	switch key {
 	case KeyBack:
@@ -497,6 +1576,29 @@ func handleToolStartingKey(m Model, key KeyAction) (Model, []Effect, bool) {
 }
 
 func handleSessionsKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	if len(m.SessionKillNames) > 0 {
+		switch key {
+		case KeyEnter:
+			kill := make(map[string]bool, len(m.SessionKillNames))
+			for _, name := range m.SessionKillNames {
+				kill[name] = true
+			}
+			var sessions []SessionInfo
+			for _, session := range m.Sessions {
+				if kill[session.Name] {
+					sessions = append(sessions, session)
+				}
+			}
+			m.SessionKillNames = nil
+			return m, []Effect{EffKillSessions{Sessions: sessions}}, true
+		case KeyBack:
+			m.SessionKillNames = nil
+		case KeyQuit:
+			return m, []Effect{EffQuit{}}, true
+		}
+		return m, nil, true
+	}
+
 	switch key {
 	case KeyUp:
 		if m.SessionIdx > 0 {
@@ -523,7 +1625,31 @@ func handleSessionsKey(m Model, key KeyAction) (Model, []Effect, bool) {
 			return m, []Effect{EffAttachSession{Session: session}}, true
 		}
 		return m, nil, true
+	case KeyMark:
+		if session, ok := m.SelectedSession(); ok {
+			m.MarkedSessions = toggleMark(m.MarkedSessions, session.Name)
+		}
+		return m, nil, true
+	case KeyRefresh:
+		return m, []Effect{EffListSessions{}}, true
+	case KeyDelete:
+		m.SessionNotice = ""
+		m.SessionWarning = ""
+		if marked := m.MarkedSessionList(); len(marked) > 0 {
+			for _, session := range marked {
+				m.SessionKillNames = append(m.SessionKillNames, session.Name)
+			}
+			return m, nil, true
+		}
+		if session, ok := m.SelectedSession(); ok {
+			m.SessionKillNames = []string{session.Name}
+		}
+		return m, nil, true
 	case KeyBack:
+		if len(m.MarkedSessions) > 0 {
+			m.MarkedSessions = nil
+			return m, nil, true
+		}
 		return leaveSessionsMode(m)
 	case KeyQuit:
 		return m, []Effect{EffQuit{}}, true
@@ -531,11 +1657,164 @@ func handleSessionsKey(m Model, key KeyAction) (Model, []Effect, bool) {
 	return m, nil, false
 }
 
+// handleCleanupKey moves through the stale worktrees, marks the ones to
+// delete and asks once more before deleting them all.
+func handleCleanupKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	if key == KeyQuit {
+		return m, []Effect{EffQuit{}}, true
+	}
+	if m.CleanupRunning {
+		return m, nil, true
+	}
+	if m.CleanupConfirm {
+		switch key {
+		case KeyEnter:
+			selected := m.SelectedCleanupWorktrees()
+			m.CleanupConfirm = false
+			m.CleanupRunning = true
+			m.CleanupNotice = ""
+			m.CleanupWarning = ""
+			payloads := make([]HookPayload, 0, len(selected))
+			for _, wt := range selected {
+				payloads = append(payloads, cleanupHookPayload(HookPreWorktreeDelete, wt))
+			}
+			m, effects := withEachHooks(m, payloads, EffCleanupWorktrees{Worktrees: selected, DeleteBranches: m.CleanupBranches})
+			return m, effects, true
+		case KeyBack:
+			m.CleanupConfirm = false
+		}
+		return m, nil, true
+	}
+
+	maxIdx := len(m.CleanupCandidates) - 1
+	switch key {
+	case KeyUp:
+		m.CleanupIdx = moveIndex(m.CleanupIdx, maxIdx, -1)
+	case KeyDown:
+		m.CleanupIdx = moveIndex(m.CleanupIdx, maxIdx, 1)
+	case KeyPageUp:
+		m.CleanupIdx = moveIndex(m.CleanupIdx, maxIdx, -pageJump)
+	case KeyPageDown:
+		m.CleanupIdx = moveIndex(m.CleanupIdx, maxIdx, pageJump)
+	case KeyTop:
+		m.CleanupIdx = 0
+	case KeyBottom:
+		m.CleanupIdx = clampIndex(maxIdx, maxIdx)
+	case KeyMark:
+		if wt, ok := m.SelectedCleanupWorktree(); ok {
+			m.CleanupSelected = toggleMark(m.CleanupSelected, wt.Worktree.Path)
+		}
+	case KeyDelete:
+		m.CleanupBranches = !m.CleanupBranches
+	case KeyEnter:
+		if m.CleanupLoading || len(m.SelectedCleanupWorktrees()) == 0 {
+			return m, nil, true
+		}
+		m.CleanupConfirm = true
+	case KeyBack:
+		m = clearCleanup(m)
+		m.Mode = ModeBrowsing
+	}
+	return m, nil, true
+}
+
+func clearCleanup(m Model) Model {
+	m.CleanupCandidates = nil
+	m.CleanupSelected = nil
+	m.CleanupIdx = 0
+	m.CleanupLoading = false
+	m.CleanupConfirm = false
+	m.CleanupRunning = false
+	m.CleanupBranches = false
+	m.CleanupNotice = ""
+	m.CleanupWarning = ""
+	return m
+}
+
+// handleRecentKey reopens a launch from the history in one step, skipping
+// project, workspace and tool selection.
+func handleRecentKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	maxIdx := len(m.RecentLaunches) - 1
+	switch key {
+	case KeyUp:
+		m.RecentIdx = moveIndex(m.RecentIdx, maxIdx, -1)
+	case KeyDown:
+		m.RecentIdx = moveIndex(m.RecentIdx, maxIdx, 1)
+	case KeyPageUp:
+		m.RecentIdx = moveIndex(m.RecentIdx, maxIdx, -pageJump)
+	case KeyPageDown:
+		m.RecentIdx = moveIndex(m.RecentIdx, maxIdx, pageJump)
+	case KeyTop:
+		m.RecentIdx = 0
+	case KeyBottom:
+		m.RecentIdx = clampIndex(maxIdx, maxIdx)
+	case KeyEnter:
+		recent, ok := m.SelectedRecentLaunch()
+		if !ok {
+			return m, nil, true
+		}
+		launch := recent.Launch
+		m.SelectedProject = launch.ProjectPath
+		m.SelectedWorktreePath = launch.WorktreePath
+		m, effects := withHooks(m, HookPayload{
+			Event:        HookSessionOpen,
+			ProjectPath:  launch.ProjectPath,
+			WorktreePath: launch.WorktreePath,
+			Branch:       launch.Branch,
+			Tool:         launch.Tool,
+		}, EffOpenSession{Spec: launch.Spec()})
+		return m, effects, true
+	case KeyRefresh:
+		if m.RecentLoading {
+			return m, nil, true
+		}
+		m.RecentLoading = true
+		return m, []Effect{EffLoadRecent{}}, true
+	case KeyBack:
+		m = clearRecent(m)
+		m.Mode = ModeBrowsing
+	case KeyQuit:
+		return m, []Effect{EffQuit{}}, true
+	}
+	return m, nil, true
+}
+
+func clearRecent(m Model) Model {
+	m.RecentLaunches = nil
+	m.RecentIdx = 0
+	m.RecentLoading = false
+	m.RecentWarning = ""
+	return m
+}
+
+func cleanupHookPayload(event HookEvent, wt StaleWorktree) HookPayload {
+	return HookPayload{
+		Event:        event,
+		ProjectPath:  wt.ProjectPath,
+		WorktreePath: wt.Worktree.Path,
+		Branch:       wt.Worktree.Branch,
+	}
+}
+
+// handleWorktreeSetupKey lets esc cut a slow setup short; the setup reports
+// back as cancelled and Step 3 opens with that warning.
+func handleWorktreeSetupKey(m Model, key KeyAction) (Model, []Effect, bool) {
+	switch key {
+	case KeyBack:
+		return m, []Effect{EffCancelSetup{}}, true
+	case KeyQuit:
+		return m, []Effect{EffCancelSetup{}, EffQuit{}}, true
+	}
+	return m, nil, true
+}
+
 func enterToolMode(m Model) (Model, []Effect) {
 	m.Mode = ModeTool
+	m.SetupWarning = ""
 	m.ToolQuery = ""
 	m.FilteredTools = FilterTools(m.Tools, m.ToolQuery)
 	m.ToolIdx = 0
+	m.ToolTargets = nil
 	m.ToolError = ""
 	m.PendingSpec = nil
 	m.ToolWarmStart = make(map[string]time.Time, len(m.Tools))
@@ -563,22 +1842,152 @@ func toolsNeedingWarmup(tools []string) []string {
 func enterSessionsMode(m Model) (Model, []Effect, bool) {
 	m.SessionReturnMode = m.Mode
 	m.Mode = ModeSessions
+	m = clearSessionMarks(m)
 	m.SessionQuery = ""
 	m.SessionIdx = 0
 	m.Sessions = nil
 	m.FilteredSessions = nil
-	return m, []Effect{EffListSessions{}}, true
+	return m, []Effect{EffListSessions{}, EffWatchSessions{}}, true
 }
 
 func leaveSessionsMode(m Model) (Model, []Effect, bool) {
 	m.Mode = m.SessionReturnMode
+	m = clearSessionMarks(m)
 	m.SessionQuery = ""
 	m.SessionIdx = 0
 	m.Sessions = nil
 	m.FilteredSessions = nil
-	return m, nil, true
+	return m, []Effect{EffUnwatchSessions{}}, true
+}
+
+func clearSessionMarks(m Model) Model {
+	m.MarkedSessions = nil
+	m.SessionKillNames = nil
+	m.SessionNotice = ""
+	m.SessionWarning = ""
+	return m
+}
+
+// indexOfSession finds a session by name so live refreshes keep the cursor
+// on the same row; it falls back to the first row.
+func indexOfSession(sessions []SessionInfo, name string) int {
+	for i, session := range sessions {
+		if session.Name == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// mergeDirs appends scanned entries that are not already listed.
+func mergeDirs(dirs, batch []DirEntry) []DirEntry {
+	if len(batch) == 0 {
+		return dirs
+	}
+	seen := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		seen[dir.Path] = true
+	}
+	for _, dir := range batch {
+		if seen[dir.Path] {
+			continue
+		}
+		seen[dir.Path] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func removeDirs(dirs []DirEntry, paths []string) []DirEntry {
+	if len(paths) == 0 {
+		return dirs
+	}
+	removed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		removed[path] = true
+	}
+	kept := make([]DirEntry, 0, len(dirs))
+	for _, dir := range dirs {
+		if !removed[dir.Path] {
+			kept = append(kept, dir)
+		}
+	}
+	return kept
+}
+
+// refilterDirs reapplies the project query and keeps the cursor on the same
+// project, or on the create row, while the list changes underneath it.
+func refilterDirs(m Model) Model {
+	selected, hasSelection := m.SelectedDir()
+	onCreateRow := !hasSelection && m.SelectedIdx > 0 && m.SelectedIdx == len(m.Filtered)
+	m.Filtered = filterDirs(m)
+	m.SelectedIdx = 0
+	if onCreateRow {
+		if _, ok := m.CreateProjectPath(); ok {
+			m.SelectedIdx = len(m.Filtered)
+		}
+		return m
+	}
+	if !hasSelection {
+		return m
+	}
+	for i, dir := range m.Filtered {
+		if dir.Path == selected.Path {
+			m.SelectedIdx = i
+			break
+		}
+	}
+	return m
+}
+
+// refilterWorktrees is the worktree counterpart of refilterDirs.
+func refilterWorktrees(m Model) Model {
+	selected, hasSelection := m.SelectedWorktree()
+	onCreateRow := !hasSelection && m.WorktreeIdx > 0 && m.WorktreeIdx == len(m.FilteredWT)
+	m.FilteredWT = filterWorktrees(m)
+	m.WorktreeIdx = 0
+	if onCreateRow {
+		if _, ok := m.CreateWorktreeName(); ok {
+			m.WorktreeIdx = len(m.FilteredWT)
+		}
+		return m
+	}
+	if !hasSelection {
+		return m
+	}
+	for i, wt := range m.FilteredWT {
+		if wt.Path == selected.Path {
+			m.WorktreeIdx = i
+			break
+		}
+	}
+	return m
+}
+
+func dirPaths(dirs []DirEntry) []string {
+	if len(dirs) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		paths = append(paths, dir.Path)
+	}
+	return paths
 }
 
 func Init(m Model) (Model, []Effect) {
-	return m, []Effect{EffScanDirs{Roots: m.RootPaths}}
+	m.Scanning = true
+	effects := []Effect{EffScanDirs{Roots: m.RootPaths}}
+	// A model started past Step 1 still scans, so going back finds the
+	// project list.
+	if m.SelectedProject != "" {
+		effects = append(effects, EffLoadWorktrees{ProjectPath: m.SelectedProject, All: m.ShowAllWorktrees})
+	}
+	if m.Mode == ModeTool {
+		var toolEffects []Effect
+		m, toolEffects = enterToolMode(m)
+		effects = append(effects, toolEffects...)
+	}
+	effects = append(effects, EffLoadPins{}, EffLoadTags{})
+	return m, effects
 }
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func worktreeModeModel(t *testing.T, worktrees []Worktree) Model {
+	t.Helper()
+	m := NewModel([]string{"/projects"})
+	m.Mode = ModeWorktree
+	m.SelectedProject = "/projects/api"
+	m, _ = Update(m, MsgWorktreesLoaded{Worktrees: worktrees})
+	return m
+}
+
+func TestShowAllTogglesUnmanagedWorktrees(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{{Path: "/projects/api", Name: "api", Branch: "main"}})
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyShowAll})
+	if !m.ShowAllWorktrees {
+		t.Fatal("expected all worktrees to be shown")
+	}
+	if len(effects) != 1 || effects[0] != (EffRefreshWorktrees{ProjectPath: "/projects/api", All: true}) {
+		t.Fatalf("expected a refresh including unmanaged worktrees, got %+v", effects)
+	}
+
+	// A refresh started before the toggle must not hide the new listing.
+	stale, _ := Update(m, MsgWorktreesRefreshed{ProjectPath: "/projects/api", Worktrees: nil})
+	if len(stale.Worktrees) != 1 {
+		t.Fatalf("expected stale refresh to be ignored, got %+v", stale.Worktrees)
+	}
+
+	m, _ = Update(m, MsgWorktreesRefreshed{ProjectPath: "/projects/api", All: true, Worktrees: []Worktree{
+		{Path: "/projects/api", Name: "api", Branch: "main"},
+		{Path: "/tmp/spike", Name: "spike", Branch: "spike", Unmanaged: true},
+	}})
+	if len(m.FilteredWT) != 2 {
+		t.Fatalf("expected unmanaged worktree to be listed, got %+v", m.FilteredWT)
+	}
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyBack})
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{{Path: "/projects/web", Name: "web"}}})
+	_, effects = Update(m, MsgKeyPress{Key: KeyEnter})
+	if len(effects) != 1 || effects[0] != (EffLoadWorktrees{ProjectPath: "/projects/web", All: true}) {
+		t.Fatalf("expected the setting to apply to the next project, got %+v", effects)
+	}
+}
+
+func TestAdoptOnlyAppliesToUnmanagedWorktrees(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{
+		{Path: "/projects/api", Name: "api", Branch: "main"},
+		{Path: "/tmp/spike", Name: "spike", Branch: "spike", Unmanaged: true},
+	})
+
+	_, effects := Update(m, MsgKeyPress{Key: KeyAdopt})
+	if len(effects) != 0 {
+		t.Fatalf("expected no effect for a managed worktree, got %+v", effects)
+	}
+
+	m.WorktreeIdx = 1
+	_, effects = Update(m, MsgKeyPress{Key: KeyAdopt})
+	if len(effects) != 1 || effects[0] != (EffAdoptWorktree{ProjectPath: "/projects/api", WorktreePath: "/tmp/spike"}) {
+		t.Fatalf("expected EffAdoptWorktree, got %+v", effects)
+	}
+}
+
+func TestWorktreeAdoptedKeepsSelectionOnMovedWorktree(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{
+		{Path: "/projects/api", Name: "api", Branch: "main"},
+		{Path: "/tmp/spike", Name: "spike", Branch: "spike", Unmanaged: true},
+	})
+	m.ShowAllWorktrees = true
+	m.WorktreeIdx = 1
+	before := m
+
+	m, effects := Update(m, MsgWorktreeAdopted{Path: "/tmp/spike", NewPath: "/home/u/.rivet/worktrees/api-abc123--spike"})
+	if before.Worktrees[1].Path != "/tmp/spike" || before.FilteredWT[1].Path != "/tmp/spike" {
+		t.Fatalf("expected the previous model to be left alone, got %+v and %+v", before.Worktrees, before.FilteredWT)
+	}
+	selected, ok := m.SelectedWorktree()
+	if !ok || selected.Path != "/home/u/.rivet/worktrees/api-abc123--spike" || selected.Unmanaged {
+		t.Fatalf("expected selection on the adopted worktree, got %+v", selected)
+	}
+	if len(effects) != 1 || effects[0] != (EffRefreshWorktrees{ProjectPath: "/projects/api", All: true}) {
+		t.Fatalf("expected a refresh, got %+v", effects)
+	}
+}
+
+func TestWorktreeAdoptFailureIsAWarning(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{{Path: "/tmp/spike", Name: "spike", Unmanaged: true}})
+
+	m, effects := Update(m, MsgWorktreeAdopted{Path: "/tmp/spike", Err: errors.New("git worktree move failed: locked")})
+	if len(effects) != 0 {
+		t.Fatalf("expected no effects, got %+v", effects)
+	}
+	if m.Mode != ModeWorktree || m.WorktreeWarning != "git worktree move failed: locked" {
+		t.Fatalf("expected a warning in step 2, got mode %v warning %q", m.Mode, m.WorktreeWarning)
+	}
+}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchReportSummary(t *testing.T) {
+	var report BatchReport
+	if got := report.Summary("Deleted", "workspace"); got != "" {
+		t.Fatalf("expected no summary for an empty report, got %q", got)
+	}
+	report.Done = []string{"/wt/a"}
+	if got := report.Summary("Deleted", "workspace"); got != "Deleted 1 workspace." {
+		t.Fatalf("unexpected summary %q", got)
+	}
+	report.Done = append(report.Done, "/wt/b")
+	report.Fail("c", errors.New("locked"))
+	report.Fail("d", errors.New("gone"))
+	if got := report.Summary("Killed", "session"); got != "Killed 2 sessions." {
+		t.Fatalf("unexpected summary %q", got)
+	}
+	if got := report.Warning(); got != "c: locked; d: gone" {
+		t.Fatalf("unexpected warning %q", got)
+	}
+}
+
+func bulkWorktreeModel(t *testing.T) Model {
+	t.Helper()
+	return worktreeModeModel(t, []Worktree{
+		{Path: "/wt/api--a", Name: "api--a", Branch: "a"},
+		{Path: "/wt/api--b", Name: "api--b", Branch: "b"},
+		{Path: "/wt/api--c", Name: "api--c", Branch: "(detached)"},
+	})
+}
+
+func TestMarkTogglesWorktrees(t *testing.T) {
+	m := bulkWorktreeModel(t)
+
+	m, _, handled := UpdateKey(m, KeyMark)
+	if !handled {
+		t.Fatal("expected tab to be handled")
+	}
+	m, _, _ = UpdateKey(m, KeyDown)
+	m, _, _ = UpdateKey(m, KeyDown)
+	m, _, _ = UpdateKey(m, KeyMark)
+	if marked := m.MarkedWorktreeList(); len(marked) != 2 || marked[0].Path != "/wt/api--a" || marked[1].Path != "/wt/api--c" {
+		t.Fatalf("unexpected marks %+v", marked)
+	}
+
+	m, _, _ = UpdateKey(m, KeyMark)
+	if marked := m.MarkedWorktreeList(); len(marked) != 1 {
+		t.Fatalf("expected tab to unmark, got %+v", marked)
+	}
+
+	m, _, _ = UpdateKey(m, KeyBack)
+	if m.Mode != ModeWorktree || len(m.MarkedWorktrees) != 0 {
+		t.Fatalf("expected esc to clear the marks first, got mode %v and %v", m.Mode, m.MarkedWorktrees)
+	}
+}
+
+func TestBulkWorktreeDeleteEmitsOneBatch(t *testing.T) {
+	m := bulkWorktreeModel(t)
+	m.MarkedWorktrees = map[string]bool{"/wt/api--a": true, "/wt/api--c": true}
+
+	m, _, _ = UpdateKey(m, KeyDelete)
+	if m.Mode != ModeWorktreeDeleteConfirm || len(m.WorktreeDeletePaths) != 2 {
+		t.Fatalf("expected a bulk confirmation, got mode %v with %v", m.Mode, m.WorktreeDeletePaths)
+	}
+	if branches := m.DeletableBranches(); len(branches) != 1 || branches[0] != "a" {
+		t.Fatalf("expected only the attached branch to be deletable, got %v", branches)
+	}
+	m, _, _ = UpdateKey(m, KeyDelete)
+
+	m, effects, _ := UpdateKey(m, KeyEnter)
+	check, ok := effects[0].(EffCheckBranchesMerged)
+	if len(effects) != 1 || !ok || len(check.Worktrees) != 1 || check.Worktrees[0].Branch != "a" {
+		t.Fatalf("expected only the attached branch to be checked first, got %+v", effects)
+	}
+	_, effects = Update(m, MsgBranchesChecked{})
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	eff, ok := effects[0].(EffDeleteWorktrees)
+	if !ok {
+		t.Fatalf("expected EffDeleteWorktrees, got %T", effects[0])
+	}
+	if eff.ProjectPath != "/projects/api" || len(eff.Worktrees) != 2 || eff.DeleteBranch != BranchDelete {
+		t.Fatalf("unexpected effect %+v", eff)
+	}
+	if eff.Worktrees[0].Branch != "a" || eff.Worktrees[1].Path != "/wt/api--c" {
+		t.Fatalf("unexpected worktrees %+v", eff.Worktrees)
+	}
+}
+
+func TestMsgWorktreesDeletedReportsAndReloads(t *testing.T) {
+	m := bulkWorktreeModel(t)
+	m.Mode = ModeWorktreeDeleteConfirm
+	m.MarkedWorktrees = map[string]bool{"/wt/api--a": true, "/wt/api--b": true}
+	m.WorktreeDeletePaths = []string{"/wt/api--a", "/wt/api--b"}
+
+	report := BatchReport{Done: []string{"/wt/api--a"}}
+	report.Fail("api--b", BranchNotMergedError{Branch: "b"})
+	updated, effects := Update(m, MsgWorktreesDeleted{Report: report})
+	if updated.Mode != ModeWorktree || len(updated.WorktreeDeletePaths) != 0 || len(updated.MarkedWorktrees) != 0 {
+		t.Fatalf("expected the bulk delete to be cleared, got %+v", updated)
+	}
+	if updated.WorktreeNotice != "Deleted 1 workspace." {
+		t.Fatalf("unexpected notice %q", updated.WorktreeNotice)
+	}
+	if updated.WorktreeWarning != "api--b: branch b is not fully merged" {
+		t.Fatalf("unexpected warning %q", updated.WorktreeWarning)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected a reload, got %d effects", len(effects))
+	}
+	if _, ok := effects[0].(EffLoadWorktrees); !ok {
+		t.Fatalf("expected EffLoadWorktrees, got %T", effects[0])
+	}
+}
+
+func TestBulkOpenStartsOtherSessionsFirst(t *testing.T) {
+	m := bulkWorktreeModel(t)
+	m.MarkedWorktrees = map[string]bool{"/wt/api--a": true, "/wt/api--b": true}
+	m.WorktreeIdx = 1
+
+	m, _, _ = UpdateKey(m, KeyEnter)
+	if m.Mode != ModeTool || m.SelectedWorktreePath != "/wt/api--b" || len(m.ToolTargets) != 2 {
+		t.Fatalf("expected Step 3 for the highlighted marked worktree, got mode %v, %q, %v", m.Mode, m.SelectedWorktreePath, m.ToolTargets)
+	}
+	m.FilteredTools = []string{"opencode"}
+	m.ToolIdx = 0
+
+	m, effects, _ := UpdateKey(m, KeyEnter)
+	if m.Mode != ModeToolStarting || m.PendingSpec != nil {
+		t.Fatalf("expected to wait for the other sessions, got mode %v", m.Mode)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	start, ok := effects[0].(EffStartSessions)
+	if !ok {
+		t.Fatalf("expected EffStartSessions, got %T", effects[0])
+	}
+	if len(start.Specs) != 1 || start.Specs[0].DirPath != "/wt/api--a" || !start.Specs[0].Detach {
+		t.Fatalf("unexpected specs %+v", start.Specs)
+	}
+
+	updated, effects := Update(m, MsgSessionsStarted{Report: BatchReport{Done: []string{"/wt/api--a"}}})
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if ToolNeedsWarmup("opencode") {
+		if _, ok := effects[0].(EffCheckToolReady); !ok || updated.PendingSpec == nil {
+			t.Fatalf("expected the selected session to warm up, got %T", effects[0])
+		}
+		return
+	}
+	open, ok := effects[0].(EffOpenSession)
+	if !ok || open.Spec.DirPath != "/wt/api--b" {
+		t.Fatalf("expected the selected session to open, got %+v", effects[0])
+	}
+}
+
+func TestBulkOpenFailureStaysInToolMode(t *testing.T) {
+	m := bulkWorktreeModel(t)
+	m.Mode = ModeToolStarting
+	m.SelectedWorktreePath = "/wt/api--b"
+	m.ToolTargets = []string{"/wt/api--a", "/wt/api--b"}
+	m.FilteredTools = []string{"opencode"}
+
+	var report BatchReport
+	report.Fail("api--a", errors.New("tmux not running"))
+	updated, effects := Update(m, MsgSessionsStarted{Report: report})
+	if updated.Mode != ModeTool || updated.ToolError != "api--a: tmux not running" {
+		t.Fatalf("expected the failure in Step 3, got mode %v and %q", updated.Mode, updated.ToolError)
+	}
+	if len(effects) != 0 {
+		t.Fatalf("expected no effects, got %d", len(effects))
+	}
+}
+
+func TestBulkProjectDelete(t *testing.T) {
+	m := Model{
+		Mode:      ModeBrowsing,
+		RootPaths: []string{"/projects"},
+		Dirs:      []DirEntry{{Path: "/projects/api", Name: "api"}, {Path: "/projects/web", Name: "web"}},
+	}
+	m.Filtered = m.Dirs
+
+	m, _, _ = UpdateKey(m, KeyMark)
+	m, _, _ = UpdateKey(m, KeyDown)
+	m, _, _ = UpdateKey(m, KeyMark)
+	m, _, _ = UpdateKey(m, KeyDelete)
+	if m.Mode != ModeProjectDeleteConfirm || len(m.ProjectDeletePaths) != 2 {
+		t.Fatalf("expected a bulk confirmation, got mode %v with %v", m.Mode, m.ProjectDeletePaths)
+	}
+
+	_, effects, _ := UpdateKey(m, KeyEnter)
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if eff, ok := effects[0].(EffDeleteProjects); !ok || len(eff.ProjectPaths) != 2 {
+		t.Fatalf("expected EffDeleteProjects for both, got %+v", effects[0])
+	}
+
+	updated, effects := Update(m, MsgProjectsDeleted{Report: BatchReport{Done: []string{"/projects/api", "/projects/web"}}})
+	if updated.Mode != ModeBrowsing || len(updated.MarkedProjects) != 0 || !updated.Scanning {
+		t.Fatalf("expected a rescan in Step 1, got %+v", updated)
+	}
+	if updated.BrowseNotice != "Deleted 2 projects." {
+		t.Fatalf("unexpected notice %q", updated.BrowseNotice)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected a rescan, got %d effects", len(effects))
+	}
+}
+
+func TestBulkSessionKillAsksFirst(t *testing.T) {
+	sessions := []SessionInfo{
+		{Name: "api/a", DirPath: "/wt/api--a"},
+		{Name: "api/b", DirPath: "/wt/api--b"},
+	}
+	m := Model{Mode: ModeSessions, Sessions: sessions, FilteredSessions: sessions}
+
+	m, _, _ = UpdateKey(m, KeyDelete)
+	if len(m.SessionKillNames) != 1 || m.SessionKillNames[0] != "api/a" {
+		t.Fatalf("expected to confirm killing the highlighted session, got %v", m.SessionKillNames)
+	}
+	m, _, _ = UpdateKey(m, KeyBack)
+	if m.Mode != ModeSessions || len(m.SessionKillNames) != 0 {
+		t.Fatalf("expected esc to cancel the kill, got mode %v", m.Mode)
+	}
+
+	m, _, _ = UpdateKey(m, KeyMark)
+	m, _, _ = UpdateKey(m, KeyDown)
+	m, _, _ = UpdateKey(m, KeyMark)
+	m, _, _ = UpdateKey(m, KeyDelete)
+	m, effects, _ := UpdateKey(m, KeyEnter)
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if eff, ok := effects[0].(EffKillSessions); !ok || len(eff.Sessions) != 2 {
+		t.Fatalf("expected EffKillSessions for both, got %+v", effects[0])
+	}
+
+	updated, effects := Update(m, MsgSessionsKilled{Report: BatchReport{Done: []string{"api/a", "api/b"}}})
+	if updated.SessionNotice != "Killed 2 sessions." || len(updated.MarkedSessions) != 0 {
+		t.Fatalf("unexpected result %q with marks %v", updated.SessionNotice, updated.MarkedSessions)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected the sessions to be listed again, got %d effects", len(effects))
+	}
+}
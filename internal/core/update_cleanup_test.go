@@ -0,0 +1,106 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func cleanupModeModel(t *testing.T) Model {
+	t.Helper()
+	m := NewModel([]string{"/projects"})
+	m.Mode = ModeBrowsing
+	m.Dirs = []DirEntry{{Path: "/projects/api", Name: "api"}}
+	m, effects := Update(m, MsgKeyPress{Key: KeyCleanup})
+	if m.Mode != ModeCleanup || !m.CleanupLoading {
+		t.Fatalf("expected the cleanup view to load, got mode %v", m.Mode)
+	}
+	if len(effects) != 1 || !reflect.DeepEqual(effects[0], EffLoadCleanup{ProjectPaths: []string{"/projects/api"}}) {
+		t.Fatalf("expected stale worktrees to load, got %+v", effects)
+	}
+	now := time.Now()
+	m, _ = Update(m, MsgCleanupLoaded{
+		Activity: []WorktreeActivity{
+			{ProjectPath: "/projects/api", Worktree: Worktree{Path: "/wt/api--done", Name: "api--done", Branch: "done"}, Merged: true, LastCommit: now},
+			{ProjectPath: "/projects/api", Worktree: Worktree{Path: "/wt/api--old", Name: "api--old", Branch: "old"}, LastCommit: now.Add(-60 * 24 * time.Hour)},
+		},
+		Now: now,
+	})
+	return m
+}
+
+func TestCleanupMarksMergedWorktreesUpFront(t *testing.T) {
+	m := cleanupModeModel(t)
+	if len(m.CleanupCandidates) != 2 || m.CleanupLoading {
+		t.Fatalf("expected two candidates, got %+v", m.CleanupCandidates)
+	}
+	selected := m.SelectedCleanupWorktrees()
+	if len(selected) != 1 || selected[0].Worktree.Name != "api--done" {
+		t.Fatalf("expected only the merged worktree to be marked, got %+v", selected)
+	}
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyDown})
+	m, _ = Update(m, MsgKeyPress{Key: KeyMark})
+	if len(m.SelectedCleanupWorktrees()) != 2 {
+		t.Fatal("expected tab to mark the inactive worktree")
+	}
+	m, _ = Update(m, MsgKeyPress{Key: KeyMark})
+	if len(m.SelectedCleanupWorktrees()) != 1 {
+		t.Fatal("expected tab to unmark it again")
+	}
+}
+
+func TestCleanupConfirmsBeforeDeleting(t *testing.T) {
+	m := cleanupModeModel(t)
+	m.Hooks = Hooks{Commands: map[HookEvent][]string{HookPreWorktreeDelete: {"backup"}, HookPostWorktreeDelete: {"notify"}}}
+	m, _ = Update(m, MsgKeyPress{Key: KeyDelete})
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	if !m.CleanupConfirm || len(effects) != 0 {
+		t.Fatalf("expected a confirmation first, got %+v", effects)
+	}
+	cancelled, _ := Update(m, MsgKeyPress{Key: KeyBack})
+	if cancelled.CleanupConfirm || cancelled.Mode != ModeCleanup {
+		t.Fatal("expected esc to cancel the confirmation only")
+	}
+
+	m, effects = Update(m, MsgKeyPress{Key: KeyEnter})
+	if !m.CleanupRunning || len(effects) != 2 {
+		t.Fatalf("expected the pre hook and the cleanup, got %+v", effects)
+	}
+	if hook, ok := effects[0].(EffRunHook); !ok || hook.Payload.WorktreePath != "/wt/api--done" {
+		t.Fatalf("expected a pre_worktree_delete hook first, got %+v", effects[0])
+	}
+	cleanup, ok := effects[1].(EffCleanupWorktrees)
+	if !ok || !cleanup.DeleteBranches || len(cleanup.Worktrees) != 1 || cleanup.Worktrees[0].Worktree.Path != "/wt/api--done" {
+		t.Fatalf("expected the marked worktree to be cleaned up with its branch, got %+v", effects[1])
+	}
+
+	m, effects = Update(m, MsgWorktreesCleaned{Report: CleanupReport{
+		Removed:  cleanup.Worktrees,
+		Failures: []string{"api--done: branch done kept: not fully merged"},
+	}})
+	if m.CleanupRunning || m.CleanupNotice != "Deleted 1 worktree." || m.CleanupWarning == "" {
+		t.Fatalf("expected the report to be shown, got notice %q warning %q", m.CleanupNotice, m.CleanupWarning)
+	}
+	if len(effects) != 2 {
+		t.Fatalf("expected the post hook and a reload, got %+v", effects)
+	}
+	if _, ok := effects[1].(EffLoadCleanup); !ok {
+		t.Fatalf("expected the stale worktrees to reload, got %+v", effects[1])
+	}
+}
+
+func TestCleanupLoadErrorAndBack(t *testing.T) {
+	m := cleanupModeModel(t)
+	m, _ = Update(m, MsgCleanupLoaded{Err: errors.New("boom")})
+	if m.CleanupWarning != "boom" {
+		t.Fatalf("expected a warning, got %q", m.CleanupWarning)
+	}
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyBack})
+	if m.Mode != ModeBrowsing || m.CleanupCandidates != nil || m.CleanupWarning != "" {
+		t.Fatalf("expected cleanup state to be cleared, got mode %v", m.Mode)
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func cloneReadyModel(t *testing.T) Model {
+	t.Helper()
+	m, _ := Init(NewModel([]string{"/projects"}))
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{{Path: "/projects/api", Name: "api"}}})
+	m, _ = Update(m, MsgQueryChanged{Query: "git@github.com:org/service.git"})
+	return m
+}
+
+func TestCreateRowClonesRepositoryURL(t *testing.T) {
+	m := cloneReadyModel(t)
+
+	path, ok := m.CreateProjectPath()
+	if !ok || path != "/projects/service" {
+		t.Fatalf("expected create row for /projects/service, got %q, %v", path, ok)
+	}
+	source, ok := m.CloneSource()
+	if !ok || source != "git@github.com:org/service.git" {
+		t.Fatalf("expected clone source, got %q, %v", source, ok)
+	}
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	if m.Mode != ModeProjectCloning {
+		t.Fatalf("expected cloning mode, got %v", m.Mode)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	eff, ok := effects[0].(EffCloneProject)
+	if !ok {
+		t.Fatalf("expected EffCloneProject, got %T", effects[0])
+	}
+	if eff.Source != "git@github.com:org/service.git" || eff.Path != "/projects/service" {
+		t.Fatalf("unexpected clone effect: %+v", eff)
+	}
+
+	m, _ = Update(m, MsgCloneProgress{Progress: CloneProgress{Phase: "Receiving objects", Percent: 40}})
+	if m.CloneProgress.Percent != 40 {
+		t.Fatalf("expected progress to be recorded, got %+v", m.CloneProgress)
+	}
+
+	m, effects = Update(m, MsgProjectCloned{ProjectPath: "/projects/service"})
+	if m.Mode != ModeWorktree || m.SelectedProject != "/projects/service" {
+		t.Fatalf("expected worktree mode for the clone, got mode %v project %q", m.Mode, m.SelectedProject)
+	}
+	if m.CloningPath != "" || m.CloneProgress != (CloneProgress{}) {
+		t.Fatalf("expected clone state to be cleared, got %+v", m)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if _, ok := effects[0].(EffLoadWorktrees); !ok {
+		t.Fatalf("expected EffLoadWorktrees, got %T", effects[0])
+	}
+}
+
+func TestCreateRowClonesBareRepositoryReportedByFilesystem(t *testing.T) {
+	m := cloneReadyModel(t)
+	m, effects := Update(m, MsgQueryChanged{Query: "/srv/git/mirror"})
+	if len(effects) != 1 || effects[0] != (EffCheckBareRepository{Path: "/srv/git/mirror"}) {
+		t.Fatalf("expected the path to be checked, got %+v", effects)
+	}
+	if _, ok := m.CloneSource(); ok {
+		t.Fatal("expected no clone row before the filesystem answers")
+	}
+
+	m, _ = Update(m, MsgBareRepositoryChecked{Path: "/srv/git/other", Bare: true})
+	if _, ok := m.CloneSource(); ok {
+		t.Fatal("expected an answer for an older query to be ignored")
+	}
+	m, _ = Update(m, MsgBareRepositoryChecked{Path: "/srv/git/mirror", Bare: true})
+	if path, ok := m.CreateProjectPath(); !ok || path != "/projects/mirror" {
+		t.Fatalf("expected a clone row for the bare repository, got %q, %v", path, ok)
+	}
+	if source, ok := m.CloneSource(); !ok || source != "/srv/git/mirror" {
+		t.Fatalf("expected the bare repository as clone source, got %q, %v", source, ok)
+	}
+}
+
+func TestCreateRowSkipsExistingCloneTarget(t *testing.T) {
+	m := cloneReadyModel(t)
+	m, _ = Update(m, MsgQueryChanged{Query: "https://github.com/org/api.git"})
+
+	if _, ok := m.CreateProjectPath(); ok {
+		t.Fatal("expected no create row when the clone target is already a project")
+	}
+}
+
+func TestCreateRowIgnoresURLWithoutRepositoryName(t *testing.T) {
+	m := cloneReadyModel(t)
+	m, _ = Update(m, MsgQueryChanged{Query: "https://example.com/.git"})
+
+	if _, ok := m.CreateProjectPath(); ok {
+		t.Fatal("expected no create row for a URL without a repository name")
+	}
+}
+
+func TestCloneFailureReturnsToBrowsing(t *testing.T) {
+	m := cloneReadyModel(t)
+	m, _ = Update(m, MsgKeyPress{Key: KeyEnter})
+
+	m, effects := Update(m, MsgProjectCloned{ProjectPath: "/projects/service", Err: errors.New("git clone failed: repository not found")})
+	if len(effects) != 0 {
+		t.Fatalf("expected no effects, got %d", len(effects))
+	}
+	if m.Mode != ModeBrowsing {
+		t.Fatalf("expected browsing mode, got %v", m.Mode)
+	}
+	if m.CloneError != "git clone failed: repository not found" {
+		t.Fatalf("expected clone error, got %q", m.CloneError)
+	}
+
+	m, _ = Update(m, MsgQueryChanged{Query: "git@github.com:org/servic.git"})
+	if m.CloneError != "" {
+		t.Fatalf("expected editing the query to clear the error, got %q", m.CloneError)
+	}
+}
+
+func TestCancelCloneIgnoresLateResult(t *testing.T) {
+	m := cloneReadyModel(t)
+	m, _ = Update(m, MsgKeyPress{Key: KeyEnter})
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyBack})
+	if m.Mode != ModeBrowsing {
+		t.Fatalf("expected browsing mode after cancel, got %v", m.Mode)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if _, ok := effects[0].(EffCancelClone); !ok {
+		t.Fatalf("expected EffCancelClone, got %T", effects[0])
+	}
+
+	m, effects = Update(m, MsgProjectCloned{ProjectPath: "/projects/service", Err: errors.New("signal: killed")})
+	if len(effects) != 0 || m.Mode != ModeBrowsing || m.CloneError != "" {
+		t.Fatalf("expected late result to be ignored, got mode %v error %q", m.Mode, m.CloneError)
+	}
+}
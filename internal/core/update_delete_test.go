@@ -1,6 +1,9 @@
 package core
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestWorktreeDeleteKeyEntersConfirm(t *testing.T) {
 	m := Model{
@@ -277,3 +280,96 @@ func TestSelectedWorktreeIgnoresNegativeIndex(t *testing.T) {
 		t.Fatal("expected negative index to return no selected worktree")
 	}
 }
+
+func TestWorktreeDeleteConfirmCyclesBranchDeletion(t *testing.T) {
+	m := Model{
+		Mode:               ModeWorktreeDeleteConfirm,
+		SelectedProject:    "/projects/demo",
+		WorktreeDeletePath: "/projects/demo/feature",
+		Worktrees:          []Worktree{{Path: "/projects/demo/feature", Name: "feature", Branch: "feature"}},
+	}
+
+	want := []BranchDeletion{BranchDelete, BranchForceDelete, BranchKeep, BranchDelete}
+	for _, deletion := range want {
+		var handled bool
+		m, _, handled = UpdateKey(m, KeyDelete)
+		if !handled {
+			t.Fatal("expected ctrl+d to be handled")
+		}
+		if m.WorktreeDeleteBranch != deletion {
+			t.Fatalf("expected %q, got %q", deletion, m.WorktreeDeleteBranch)
+		}
+	}
+
+	m, effects, _ := UpdateKey(m, KeyEnter)
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if _, ok := effects[0].(EffCheckBranchesMerged); !ok {
+		t.Fatalf("expected the branch to be checked first, got %T", effects[0])
+	}
+	_, effects = Update(m, MsgBranchesChecked{})
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	eff, ok := effects[0].(EffDeleteWorktree)
+	if !ok {
+		t.Fatalf("expected EffDeleteWorktree, got %T", effects[0])
+	}
+	if eff.Branch != "feature" || eff.DeleteBranch != BranchDelete {
+		t.Fatalf("unexpected effect payload: %+v", eff)
+	}
+}
+
+func TestWorktreeDeleteConfirmKeepsDetachedBranch(t *testing.T) {
+	m := Model{
+		Mode:               ModeWorktreeDeleteConfirm,
+		WorktreeDeletePath: "/projects/demo/feature",
+		Worktrees:          []Worktree{{Path: "/projects/demo/feature", Name: "feature", Branch: "(detached)"}},
+	}
+
+	updated, _, _ := UpdateKey(m, KeyDelete)
+	if updated.WorktreeDeleteBranch != BranchKeep {
+		t.Fatalf("expected a detached worktree to keep its branch, got %q", updated.WorktreeDeleteBranch)
+	}
+}
+
+func TestMsgWorktreeDeletedUnmergedBranchStaysInConfirm(t *testing.T) {
+	base := Model{
+		Mode:                 ModeWorktreeDeleteConfirm,
+		WorktreeDeletePath:   "/projects/demo/feature",
+		WorktreeDeleteBranch: BranchDelete,
+	}
+
+	updated, effects := Update(base, MsgWorktreeDeleted{Path: "/projects/demo/feature", Err: BranchNotMergedError{Branch: "feature"}})
+	if updated.Mode != ModeWorktreeDeleteConfirm {
+		t.Fatalf("expected to stay in confirm mode, got %v", updated.Mode)
+	}
+	if updated.WorktreeDeletePath != "/projects/demo/feature" || updated.WorktreeDeleteBranch != BranchDelete {
+		t.Fatalf("expected the confirm state to be kept, got %+v", updated)
+	}
+	if updated.DeleteBranchWarning != "branch feature is not fully merged" {
+		t.Fatalf("unexpected warning %q", updated.DeleteBranchWarning)
+	}
+	if len(effects) != 0 {
+		t.Fatalf("expected no effects, got %d", len(effects))
+	}
+}
+
+func TestMsgWorktreeDeletedBranchErrorWarns(t *testing.T) {
+	base := Model{Mode: ModeWorktreeDeleteConfirm, SelectedProject: "/projects/demo", WorktreeDeleteBranch: BranchForceDelete}
+
+	updated, effects := Update(base, MsgWorktreeDeleted{Path: "/projects/demo/feature", BranchErr: errTestError("locked")})
+	if updated.Mode != ModeWorktree {
+		t.Fatalf("expected worktree mode, got %v", updated.Mode)
+	}
+	if updated.WorktreeDeleteBranch != BranchKeep {
+		t.Fatalf("expected the branch choice to be reset, got %q", updated.WorktreeDeleteBranch)
+	}
+	if !strings.Contains(updated.WorktreeWarning, "branch was kept: locked") {
+		t.Fatalf("unexpected warning %q", updated.WorktreeWarning)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected a reload, got %d effects", len(effects))
+	}
+}
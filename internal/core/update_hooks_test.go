@@ -0,0 +1,139 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func hookedModel(t *testing.T, commands map[HookEvent][]string) Model {
+	t.Helper()
+	m := worktreeModeModel(t, []Worktree{{Path: "/projects/api/.rivet/api--feature", Name: "api--feature", Branch: "feature"}})
+	m.Hooks = Hooks{Commands: commands}
+	return m
+}
+
+func TestCreateWorktreeRunsPreHookFirst(t *testing.T) {
+	m := hookedModel(t, map[HookEvent][]string{HookPreWorktreeCreate: {"./check.sh", "./lint.sh"}})
+	m, _ = Update(m, MsgWorktreeQueryChanged{Query: "spike"})
+
+	_, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	payload := HookPayload{Event: HookPreWorktreeCreate, ProjectPath: "/projects/api", Branch: "spike"}
+	want := []Effect{
+		EffRunHook{Command: "./check.sh", Payload: payload, Timeout: DefaultHookTimeout},
+		EffRunHook{Command: "./lint.sh", Payload: payload, Timeout: DefaultHookTimeout},
+		EffCreateWorktree{ProjectPath: "/projects/api", BranchName: "spike"},
+	}
+	if len(effects) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, effects)
+	}
+	for i := range want {
+		if effects[i] != want[i] {
+			t.Fatalf("effect %d: expected %+v, got %+v", i, want[i], effects[i])
+		}
+	}
+}
+
+func TestPreDeleteHookWaitsForTheBranchCheck(t *testing.T) {
+	m := hookedModel(t, map[HookEvent][]string{HookPreWorktreeDelete: {"./backup.sh"}})
+	m.Mode = ModeWorktreeDeleteConfirm
+	m.WorktreeDeletePath = "/projects/api/.rivet/api--feature"
+	m.WorktreeDeleteBranch = BranchDelete
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	if len(effects) != 1 {
+		t.Fatalf("expected only the branch check, got %+v", effects)
+	}
+	if _, ok := effects[0].(EffCheckBranchesMerged); !ok {
+		t.Fatalf("expected the branch check before any hook, got %T", effects[0])
+	}
+
+	refused, effects := Update(m, MsgBranchesChecked{Failed: map[string]error{m.WorktreeDeletePath: BranchNotMergedError{Branch: "feature"}}})
+	if len(effects) != 0 || refused.Mode != ModeWorktreeDeleteConfirm || refused.DeleteBranchWarning == "" {
+		t.Fatalf("expected a refused delete to run no hook and stay in confirm, got %+v", effects)
+	}
+
+	_, effects = Update(m, MsgBranchesChecked{})
+	if len(effects) != 2 {
+		t.Fatalf("expected the hook and the delete, got %+v", effects)
+	}
+	if hook, ok := effects[0].(EffRunHook); !ok || hook.Payload.Event != HookPreWorktreeDelete {
+		t.Fatalf("expected the pre delete hook first, got %+v", effects[0])
+	}
+	if _, ok := effects[1].(EffDeleteWorktree); !ok {
+		t.Fatalf("expected the delete after the hook, got %T", effects[1])
+	}
+}
+
+func TestWorktreeLifecycleHookPayloads(t *testing.T) {
+	m := hookedModel(t, map[HookEvent][]string{
+		HookPostWorktreeCreate: {"notify created"},
+		HookPostWorktreeDelete: {"notify deleted"},
+	})
+	m.Hooks.Timeout = 5 * time.Second
+
+	_, effects := Update(m, MsgWorktreeCreated{Path: "/projects/api/.rivet/api--spike", Branch: "spike"})
+	want := EffRunHook{
+		Command: "notify created",
+		Payload: HookPayload{Event: HookPostWorktreeCreate, ProjectPath: "/projects/api", WorktreePath: "/projects/api/.rivet/api--spike", Branch: "spike"},
+		Timeout: 5 * time.Second,
+	}
+	if len(effects) != 2 || effects[0] != want {
+		t.Fatalf("expected %+v before setup, got %+v", want, effects)
+	}
+
+	_, effects = Update(m, MsgWorktreeDeleted{Path: "/projects/api/.rivet/api--feature"})
+	want = EffRunHook{
+		Command: "notify deleted",
+		Payload: HookPayload{Event: HookPostWorktreeDelete, ProjectPath: "/projects/api", WorktreePath: "/projects/api/.rivet/api--feature", Branch: "feature"},
+		Timeout: 5 * time.Second,
+	}
+	if len(effects) != 2 || effects[0] != want {
+		t.Fatalf("expected %+v before the reload, got %+v", want, effects)
+	}
+}
+
+func TestOpenSessionRunsSessionOpenHook(t *testing.T) {
+	m := hookedModel(t, map[HookEvent][]string{HookSessionOpen: {"log-open"}})
+	m, _ = Update(m, MsgKeyPress{Key: KeyEnter})
+	if m.Mode != ModeTool {
+		t.Fatalf("expected tool mode, got %v", m.Mode)
+	}
+	m, _ = Update(m, MsgToolQueryChanged{Query: ToolNone})
+
+	_, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	if len(effects) != 2 {
+		t.Fatalf("expected a hook and the session, got %+v", effects)
+	}
+	hook, ok := effects[0].(EffRunHook)
+	if !ok || hook.Payload != (HookPayload{
+		Event:        HookSessionOpen,
+		ProjectPath:  "/projects/api",
+		WorktreePath: "/projects/api/.rivet/api--feature",
+		Branch:       "feature",
+		Tool:         ToolNone,
+	}) {
+		t.Fatalf("unexpected hook effect %+v", effects[0])
+	}
+	if _, ok := effects[1].(EffOpenSession); !ok {
+		t.Fatalf("expected the session to open after the hook, got %+v", effects[1])
+	}
+}
+
+func TestFailedHookIsAWarning(t *testing.T) {
+	m := hookedModel(t, map[HookEvent][]string{HookPreWorktreeCreate: {"./check.sh"}})
+
+	m, effects := Update(m, MsgHookFinished{Event: HookPreWorktreeCreate, Command: "./check.sh", Err: errors.New("exit status 1")})
+	if len(effects) != 0 || m.Mode != ModeWorktree {
+		t.Fatalf("expected the failure to leave the flow alone, got mode %v effects %+v", m.Mode, effects)
+	}
+	if m.HookWarning != `pre_worktree_create hook "./check.sh" failed: exit status 1` {
+		t.Fatalf("unexpected warning %q", m.HookWarning)
+	}
+
+	m, _ = Update(m, MsgWorktreeQueryChanged{Query: "spike"})
+	m, _ = Update(m, MsgKeyPress{Key: KeyEnter})
+	if m.HookWarning != "" {
+		t.Fatalf("expected the warning to clear when hooks run again, got %q", m.HookWarning)
+	}
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"testing"
+)
+
+func landModeModel(t *testing.T) Model {
+	t.Helper()
+	m := worktreeModeModel(t, []Worktree{{Path: "/wt/feature", Name: "feature", Branch: "feature"}})
+	m, _ = Update(m, MsgKeyPress{Key: KeyLand})
+	return m
+}
+
+func TestLandKeyRejectsProjectRoot(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{{Path: "/projects/api", Name: "api", Branch: "main"}})
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyLand})
+	if m.Mode != ModeWorktree || len(effects) != 0 {
+		t.Fatalf("expected to stay in Step 2, got mode %v effects %+v", m.Mode, effects)
+	}
+	if m.WorktreeWarning != ErrWorktreeLandRoot.Error() {
+		t.Fatalf("expected a root warning, got %q", m.WorktreeWarning)
+	}
+}
+
+func TestLandEnterLandsWithSelectedStrategy(t *testing.T) {
+	m := landModeModel(t)
+	if m.Mode != ModeWorktreeLand || m.LandPath != "/wt/feature" {
+		t.Fatalf("expected the land prompt for the worktree, got mode %v path %q", m.Mode, m.LandPath)
+	}
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyBottom})
+	m, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	want := EffLandWorktree{ProjectPath: "/projects/api", WorktreePath: "/wt/feature", Strategy: LandRebase}
+	if len(effects) != 1 || effects[0] != want || !m.Landing {
+		t.Fatalf("expected a rebase to start, got %+v", effects)
+	}
+
+	_, effects = Update(m, MsgKeyPress{Key: KeyBack})
+	if len(effects) != 0 {
+		t.Fatalf("expected keys to be ignored while landing, got %+v", effects)
+	}
+}
+
+func TestLandConflictReturnsToWorktreesWithWarning(t *testing.T) {
+	m := landModeModel(t)
+	m, _ = Update(m, MsgKeyPress{Key: KeyDelete})
+	m, _ = Update(m, MsgKeyPress{Key: KeyEnter})
+
+	conflict := LandConflictError{Strategy: LandMerge, Files: []string{"app.go"}}
+	m, effects := Update(m, MsgWorktreeLanded{WorktreePath: "/wt/feature", Err: conflict})
+	if m.Mode != ModeWorktree || len(effects) != 0 {
+		t.Fatalf("expected Step 2 without deleting, got mode %v effects %+v", m.Mode, effects)
+	}
+	if m.WorktreeWarning != conflict.Error() || m.LandPath != "" {
+		t.Fatalf("expected a conflict warning and cleared state, got %q %q", m.WorktreeWarning, m.LandPath)
+	}
+}
+
+func TestLandSuccessReloadsOrDeletes(t *testing.T) {
+	result := LandResult{Branch: "feature", Base: "main"}
+
+	m := landModeModel(t)
+	m, _ = Update(m, MsgKeyPress{Key: KeyEnter})
+	m, effects := Update(m, MsgWorktreeLanded{WorktreePath: "/wt/feature", Result: result})
+	if m.WorktreeNotice != "Landed feature into main (merge)." {
+		t.Fatalf("unexpected notice %q", m.WorktreeNotice)
+	}
+	if len(effects) != 1 || effects[0] != (EffLoadWorktrees{ProjectPath: "/projects/api"}) {
+		t.Fatalf("expected worktrees to reload, got %+v", effects)
+	}
+
+	m = landModeModel(t)
+	m, _ = Update(m, MsgKeyPress{Key: KeyDelete})
+	m, _ = Update(m, MsgKeyPress{Key: KeyEnter})
+	m, effects = Update(m, MsgWorktreeLanded{WorktreePath: "/wt/feature", Result: result})
+	want := EffDeleteWorktree{ProjectPath: "/projects/api", WorktreePath: "/wt/feature"}
+	if len(effects) != 1 || effects[0] != want || m.WorktreeDeletePath != "/wt/feature" {
+		t.Fatalf("expected the worktree to be deleted, got %+v", effects)
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScanCompletedRequestsLegacySessionCheckOnce(t *testing.T) {
+	m := NewModel([]string{"/projects"})
+	dirs := []DirEntry{{Path: "/projects/demo", Name: "demo"}}
+
+	updated, effects := Update(m, MsgScanCompleted{Dirs: dirs})
+	if len(effects) != 2 {
+		t.Fatalf("expected two effects, got %d", len(effects))
+	}
+	eff, ok := effects[1].(EffFindLegacySessions)
+	if !ok {
+		t.Fatalf("expected EffFindLegacySessions, got %T", effects[1])
+	}
+	if len(eff.ProjectPaths) != 1 || eff.ProjectPaths[0] != "/projects/demo" {
+		t.Fatalf("unexpected project paths: %v", eff.ProjectPaths)
+	}
+
+	_, effects = Update(updated, MsgScanCompleted{Dirs: dirs})
+	if len(effects) != 0 {
+		t.Fatalf("expected legacy check and watcher to start only once, got %d effects", len(effects))
+	}
+}
+
+func TestLegacySessionsFoundStoresSessionsAndIgnoresErrors(t *testing.T) {
+	m := Model{Mode: ModeBrowsing}
+	legacy := []SessionMigration{{Name: "demo__amp", DirPath: "/projects/demo"}}
+
+	updated, effects := Update(m, MsgLegacySessionsFound{Sessions: legacy})
+	if len(effects) != 0 {
+		t.Fatalf("expected no effects, got %d", len(effects))
+	}
+	if len(updated.LegacySessions) != 1 {
+		t.Fatalf("expected legacy sessions to be stored, got %+v", updated.LegacySessions)
+	}
+
+	updated, _ = Update(updated, MsgLegacySessionsFound{Err: errors.New("tmux failed")})
+	if updated.Mode != ModeBrowsing {
+		t.Fatalf("expected failed check to keep browsing mode, got %v", updated.Mode)
+	}
+	if len(updated.LegacySessions) != 0 {
+		t.Fatalf("expected failed check to clear legacy sessions")
+	}
+}
+
+func TestLegacySessionsCanBeRenamedOrAdoptedFromStepOne(t *testing.T) {
+	legacy := []SessionMigration{
+		{Name: "demo__amp", DirPath: "/projects/demo", CurrentName: "-projects-demo"},
+		{Name: "api__claude", DirPath: "/projects/api", CurrentName: "-projects-api"},
+	}
+
+	m := Model{Mode: ModeBrowsing}
+	if m, _ = Update(m, MsgKeyPress{Key: KeyAdopt}); m.Mode != ModeBrowsing {
+		t.Fatalf("expected no confirmation without legacy sessions, got mode %v", m.Mode)
+	}
+
+	m = Model{Mode: ModeBrowsing, LegacySessions: legacy}
+	m, _ = Update(m, MsgKeyPress{Key: KeyAdopt})
+	if m.Mode != ModeSessionMigrateConfirm {
+		t.Fatalf("expected the migration confirmation, got mode %v", m.Mode)
+	}
+	if back, _ := Update(m, MsgKeyPress{Key: KeyBack}); back.Mode != ModeBrowsing || len(back.LegacySessions) != 2 {
+		t.Fatalf("expected esc to go back without migrating, got %+v", back)
+	}
+
+	for key, action := range map[KeyAction]SessionMigrationAction{KeyEnter: SessionMigrationRename, KeyAdopt: SessionMigrationAdopt} {
+		updated, effects := Update(m, MsgKeyPress{Key: key})
+		if updated.Mode != ModeBrowsing || len(effects) != 1 {
+			t.Fatalf("expected %s to migrate and return to step 1, got mode %v and %+v", key, updated.Mode, effects)
+		}
+		eff, ok := effects[0].(EffMigrateSessions)
+		if !ok || len(eff.Sessions) != 2 || eff.Sessions[0].Action != action || eff.Sessions[1].Action != action {
+			t.Fatalf("expected every legacy session to %s, got %+v", action, effects[0])
+		}
+		if m.LegacySessions[0].Action != "" {
+			t.Fatalf("expected the previous model to be left alone, got %+v", m.LegacySessions)
+		}
+	}
+
+	m, _ = Update(m, MsgSessionsMigrated{Migrated: 1, Failed: legacy[1:], Failures: []string{"api__claude: duplicate session"}})
+	if len(m.LegacySessions) != 1 || m.LegacySessions[0].Name != "api__claude" {
+		t.Fatalf("expected only the failed session to stay legacy, got %+v", m.LegacySessions)
+	}
+	if m.BrowseNotice != "Migrated 1 legacy session." || m.BrowseWarning != "api__claude: duplicate session" {
+		t.Fatalf("unexpected notice %q and warning %q", m.BrowseNotice, m.BrowseWarning)
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPinProjectMovesItFirstAndSavesIt(t *testing.T) {
+	m := NewModel([]string{"/projects"})
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{
+		{Path: "/projects/api", Name: "api"},
+		{Path: "/projects/web", Name: "web"},
+	}})
+	m, _, _ = UpdateKey(m, KeyDown)
+
+	m, effects, _ := UpdateKey(m, KeyPin)
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if eff, ok := effects[0].(EffSetPinned); !ok || eff.Path != "/projects/web" || !eff.Pinned {
+		t.Fatalf("expected web to be pinned, got %+v", effects[0])
+	}
+	if m.Filtered[0].Path != "/projects/web" || !m.Filtered[0].Pinned {
+		t.Fatalf("expected web first and pinned, got %+v", m.Filtered)
+	}
+	if dir, _ := m.SelectedDir(); dir.Path != "/projects/web" {
+		t.Fatalf("expected the cursor to follow web, got %q", dir.Path)
+	}
+
+	m, effects, _ = UpdateKey(m, KeyPin)
+	if eff, ok := effects[0].(EffSetPinned); !ok || eff.Pinned {
+		t.Fatalf("expected web to be unpinned, got %+v", effects[0])
+	}
+	if m.Filtered[0].Path != "/projects/api" {
+		t.Fatalf("expected the scan order back, got %+v", m.Filtered)
+	}
+}
+
+func TestPinsLoadedReorderWorktrees(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{
+		{Path: "/wt/api--main", Name: "api--main", Branch: "main"},
+		{Path: "/wt/api--fix", Name: "api--fix", Branch: "fix"},
+	})
+
+	m, _ = Update(m, MsgPinsLoaded{Paths: []string{"/wt/api--fix"}})
+	if len(m.FilteredWT) != 2 || m.FilteredWT[0].Path != "/wt/api--fix" {
+		t.Fatalf("expected the pinned worktree first, got %+v", m.FilteredWT)
+	}
+
+	m, _ = Update(m, MsgWorktreeQueryChanged{Query: "*"})
+	if len(m.FilteredWT) != 1 || m.FilteredWT[0].Path != "/wt/api--fix" {
+		t.Fatalf("expected only the pinned worktree, got %+v", m.FilteredWT)
+	}
+	if _, ok := m.CreateWorktreeName(); ok {
+		t.Fatalf("expected no create row for a pinned filter")
+	}
+
+	m, _ = Update(m, MsgPinSaved{Err: errors.New("disk full")})
+	if m.WorktreeWarning != "disk full" {
+		t.Fatalf("expected the save error as a warning, got %q", m.WorktreeWarning)
+	}
+}
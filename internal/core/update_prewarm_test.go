@@ -12,7 +12,8 @@ func TestEnterToolModeInitializesWarmupCounts(t *testing.T) {
 		Tools:           []string{"opencode", "claude", ToolNone},
 	}
 
-	updated, effects := Update(m, MsgWorktreeCreated{Path: "/projects/demo/wt"})
+	updated, _ := Update(m, MsgWorktreeCreated{Path: "/projects/demo/wt"})
+	updated, effects := Update(updated, MsgWorktreeSetupDone{WorktreePath: "/projects/demo/wt"})
 	if updated.Mode != ModeTool {
 		t.Fatalf("expected tool mode, got %v", updated.Mode)
 	}
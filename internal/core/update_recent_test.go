@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func recentModeModel(t *testing.T) Model {
+	t.Helper()
+	m := Model{Mode: ModeBrowsing, RootPaths: []string{"/projects"}}
+	m, effects, _ := UpdateKey(m, KeyRecent)
+	if m.Mode != ModeRecent || !m.RecentLoading {
+		t.Fatalf("expected the recent view to load, got mode %v", m.Mode)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if _, ok := effects[0].(EffLoadRecent); !ok {
+		t.Fatalf("expected EffLoadRecent, got %T", effects[0])
+	}
+	m, _ = Update(m, MsgRecentLoaded{
+		Launches: []Launch{
+			{ProjectPath: "/projects/api", WorktreePath: "/wt/api--main", Branch: "main", Tool: "amp"},
+			{ProjectPath: "/projects/web", WorktreePath: "/wt/web--fix", Branch: "fix", Tool: "claude"},
+		},
+		Sessions: []SessionInfo{{Name: "web/fix", DirPath: "/wt/web--fix"}},
+	})
+	return m
+}
+
+func TestRecentEnterReopensLaunch(t *testing.T) {
+	m := recentModeModel(t)
+	if len(m.RecentLaunches) != 2 || m.RecentLaunches[0].Alive || !m.RecentLaunches[1].Alive {
+		t.Fatalf("unexpected launches %+v", m.RecentLaunches)
+	}
+
+	m, _, _ = UpdateKey(m, KeyDown)
+	m, effects, _ := UpdateKey(m, KeyEnter)
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	open, ok := effects[0].(EffOpenSession)
+	if !ok {
+		t.Fatalf("expected EffOpenSession, got %T", effects[0])
+	}
+	if open.Spec != (SessionSpec{DirPath: "/wt/web--fix", Tool: "claude"}) {
+		t.Fatalf("unexpected spec %+v", open.Spec)
+	}
+	if m.SelectedProject != "/projects/web" {
+		t.Fatalf("expected the launch's project to be selected, got %q", m.SelectedProject)
+	}
+}
+
+func TestRecentBackAndLoadErrors(t *testing.T) {
+	m := recentModeModel(t)
+	m, _, _ = UpdateKey(m, KeyBack)
+	if m.Mode != ModeBrowsing || len(m.RecentLaunches) != 0 {
+		t.Fatalf("expected esc to return to Step 1, got mode %v", m.Mode)
+	}
+
+	m, _, _ = UpdateKey(m, KeyRecent)
+	m, _ = Update(m, MsgRecentLoaded{Err: errors.New("corrupt history")})
+	if m.RecentLoading || m.RecentWarning != "corrupt history" {
+		t.Fatalf("expected the error as a warning, got %q", m.RecentWarning)
+	}
+	if _, effects, _ := UpdateKey(m, KeyEnter); len(effects) != 0 {
+		t.Fatalf("expected enter to do nothing without launches, got %+v", effects)
+	}
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func reviewModeModel(t *testing.T, files []FileChange) Model {
+	t.Helper()
+	m := worktreeModeModel(t, []Worktree{{Path: "/wt/feature", Name: "feature", Branch: "feature"}})
+	m, _ = Update(m, MsgKeyPress{Key: KeyReview})
+	m, _ = Update(m, MsgChangesLoaded{
+		WorktreePath: "/wt/feature",
+		Changes:      WorktreeChanges{Base: "main", MergeBase: "abc123", Files: files},
+	})
+	return m
+}
+
+func TestReviewKeyLoadsChangesOfSelectedWorktree(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{{Path: "/wt/feature", Name: "feature", Branch: "feature"}})
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyReview})
+	if m.Mode != ModeReview || m.ReviewPath != "/wt/feature" || !m.ReviewLoading {
+		t.Fatalf("expected review of the selected worktree, got mode %v path %q", m.Mode, m.ReviewPath)
+	}
+	if len(effects) != 1 || effects[0] != (EffLoadChanges{WorktreePath: "/wt/feature"}) {
+		t.Fatalf("expected changes to load, got %+v", effects)
+	}
+
+	file := FileChange{Path: "main.go", Status: FileModified}
+	m, effects = Update(m, MsgChangesLoaded{
+		WorktreePath: "/wt/feature",
+		Changes:      WorktreeChanges{Base: "main", MergeBase: "abc123", Files: []FileChange{file}},
+	})
+	if m.ReviewLoading {
+		t.Fatal("expected loading to finish")
+	}
+	want := EffLoadFileDiff{WorktreePath: "/wt/feature", MergeBase: "abc123", File: file}
+	if len(effects) != 1 || effects[0] != want {
+		t.Fatalf("expected the first file's diff to load, got %+v", effects)
+	}
+
+	m, _ = Update(m, MsgFileDiffLoaded{WorktreePath: "/wt/feature", Path: "main.go", Diff: "+new"})
+	if m.ReviewDiff != "+new" {
+		t.Fatalf("expected diff to be shown, got %q", m.ReviewDiff)
+	}
+}
+
+func TestReviewIgnoresStaleDiffs(t *testing.T) {
+	m := reviewModeModel(t, []FileChange{
+		{Path: "a.go", Status: FileModified},
+		{Path: "b.go", Status: FileAdded},
+	})
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyDown})
+	if m.ReviewIdx != 1 || len(effects) != 1 {
+		t.Fatalf("expected second file with a diff load, got idx %d effects %+v", m.ReviewIdx, effects)
+	}
+
+	m, _ = Update(m, MsgFileDiffLoaded{WorktreePath: "/wt/feature", Path: "a.go", Diff: "+old"})
+	if m.ReviewDiff != "" {
+		t.Fatalf("expected the diff of a.go to be ignored, got %q", m.ReviewDiff)
+	}
+}
+
+func TestReviewDiscardAsksForConfirmation(t *testing.T) {
+	file := FileChange{Path: "a.go", Status: FileModified}
+	m := reviewModeModel(t, []FileChange{file})
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyDelete})
+	if m.ReviewDiscardPath != "a.go" || len(effects) != 0 {
+		t.Fatalf("expected a confirmation prompt, got %q %+v", m.ReviewDiscardPath, effects)
+	}
+
+	cancelled, _ := Update(m, MsgKeyPress{Key: KeyBack})
+	if cancelled.ReviewDiscardPath != "" || cancelled.Mode != ModeReview {
+		t.Fatalf("expected esc to cancel the discard, got %q mode %v", cancelled.ReviewDiscardPath, cancelled.Mode)
+	}
+
+	m, effects = Update(m, MsgKeyPress{Key: KeyEnter})
+	want := EffDiscardFile{WorktreePath: "/wt/feature", MergeBase: "abc123", File: file}
+	if len(effects) != 1 || effects[0] != want {
+		t.Fatalf("expected the file to be discarded, got %+v", effects)
+	}
+
+	m, effects = Update(m, MsgFileDiscarded{WorktreePath: "/wt/feature", Path: "a.go"})
+	if len(effects) != 1 || effects[0] != (EffLoadChanges{WorktreePath: "/wt/feature"}) {
+		t.Fatalf("expected changes to reload, got %+v", effects)
+	}
+
+	failed, _ := Update(m, MsgFileDiscarded{WorktreePath: "/wt/feature", Path: "a.go", Err: errors.New("boom")})
+	if failed.ReviewWarning != "boom" {
+		t.Fatalf("expected a warning, got %q", failed.ReviewWarning)
+	}
+}
+
+func TestReviewCommitNeedsMessage(t *testing.T) {
+	m := reviewModeModel(t, []FileChange{{Path: "a.go", Status: FileModified}})
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyCommit})
+	if !m.ReviewCommitting {
+		t.Fatal("expected the commit prompt")
+	}
+
+	_, _, handled := UpdateKey(m, "")
+	if handled {
+		t.Fatal("expected typing to reach the commit message input")
+	}
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	if len(effects) != 0 || !m.ReviewCommitting {
+		t.Fatalf("expected an empty message to be rejected, got %+v", effects)
+	}
+
+	m, _ = Update(m, MsgCommitMessageChanged{Message: "  Add a.go  "})
+	m, effects = Update(m, MsgKeyPress{Key: KeyEnter})
+	if len(effects) != 1 || effects[0] != (EffCommitAll{WorktreePath: "/wt/feature", Message: "Add a.go"}) {
+		t.Fatalf("expected a commit, got %+v", effects)
+	}
+	if m.ReviewCommitting {
+		t.Fatal("expected the prompt to close")
+	}
+
+	m, effects = Update(m, MsgChangesCommitted{WorktreePath: "/wt/feature"})
+	if m.ReviewCommitMessage != "" || len(effects) != 1 {
+		t.Fatalf("expected message cleared and changes reloaded, got %q %+v", m.ReviewCommitMessage, effects)
+	}
+}
+
+func TestReviewEnterOpensEditorExceptForDeletedFiles(t *testing.T) {
+	m := reviewModeModel(t, []FileChange{
+		{Path: "a.go", Status: FileModified},
+		{Path: "gone.go", Status: FileDeleted},
+	})
+
+	_, effects := Update(m, MsgKeyPress{Key: KeyEnter})
+	if len(effects) != 1 || effects[0] != (EffOpenEditor{WorktreePath: "/wt/feature", Path: "a.go"}) {
+		t.Fatalf("expected the editor to open, got %+v", effects)
+	}
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyBottom})
+	_, effects = Update(m, MsgKeyPress{Key: KeyEnter})
+	if len(effects) != 0 {
+		t.Fatalf("expected no editor for a deleted file, got %+v", effects)
+	}
+}
+
+func TestReviewReloadKeepsSelectionAndBackClearsState(t *testing.T) {
+	m := reviewModeModel(t, []FileChange{
+		{Path: "a.go", Status: FileModified},
+		{Path: "b.go", Status: FileModified},
+	})
+	m, _ = Update(m, MsgKeyPress{Key: KeyDown})
+
+	m, _ = Update(m, MsgEditorClosed{WorktreePath: "/wt/feature"})
+	m, _ = Update(m, MsgChangesLoaded{
+		WorktreePath: "/wt/feature",
+		Changes: WorktreeChanges{MergeBase: "abc123", Files: []FileChange{
+			{Path: "b.go", Status: FileModified},
+			{Path: "c.go", Status: FileUntracked},
+		}},
+	})
+	if file, _ := m.SelectedReviewFile(); file.Path != "b.go" {
+		t.Fatalf("expected b.go to stay selected, got %q", file.Path)
+	}
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyBack})
+	if m.Mode != ModeWorktree || m.ReviewPath != "" || len(m.ReviewChanges.Files) != 0 {
+		t.Fatalf("expected review state to be cleared, got mode %v %+v", m.Mode, m.ReviewChanges)
+	}
+}
@@ -0,0 +1,141 @@
+package core
+
+import "testing"
+
+func TestScanProgressShowsPartialResultsAndAppliesQuery(t *testing.T) {
+	m, _ := Init(NewModel([]string{"/projects"}))
+	m.Query = "api"
+
+	updated, effects := Update(m, MsgScanProgress{Dirs: []DirEntry{
+		{Path: "/projects/web", Name: "web"},
+		{Path: "/projects/api", Name: "api"},
+	}})
+	if len(effects) != 0 {
+		t.Fatalf("expected no effects, got %d", len(effects))
+	}
+	if updated.Mode != ModeBrowsing {
+		t.Fatalf("expected browsing mode after first batch, got %v", updated.Mode)
+	}
+	if !updated.Scanning {
+		t.Fatalf("expected scan to still be running")
+	}
+	if len(updated.Dirs) != 2 {
+		t.Fatalf("expected two dirs, got %d", len(updated.Dirs))
+	}
+	if len(updated.Filtered) != 1 || updated.Filtered[0].Name != "api" {
+		t.Fatalf("expected query to filter partial results, got %+v", updated.Filtered)
+	}
+
+	updated, _ = Update(updated, MsgScanProgress{Dirs: []DirEntry{
+		{Path: "/projects/api", Name: "api"},
+		{Path: "/projects/api-gateway", Name: "api-gateway"},
+	}})
+	if len(updated.Dirs) != 3 {
+		t.Fatalf("expected duplicate entries to be skipped, got %+v", updated.Dirs)
+	}
+}
+
+func TestScanProgressKeepsSelectedProject(t *testing.T) {
+	m, _ := Init(NewModel([]string{"/projects"}))
+	m, _ = Update(m, MsgScanProgress{Dirs: []DirEntry{
+		{Path: "/projects/b", Name: "b"},
+		{Path: "/projects/c", Name: "c"},
+	}})
+	m.SelectedIdx = 1
+
+	m, _ = Update(m, MsgScanProgress{Dirs: []DirEntry{{Path: "/projects/a", Name: "a"}}})
+	selected, ok := m.SelectedDir()
+	if !ok || selected.Name != "c" {
+		t.Fatalf("expected selection to stay on c, got %+v", selected)
+	}
+
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{
+		{Path: "/projects/a", Name: "a"},
+		{Path: "/projects/b", Name: "b"},
+		{Path: "/projects/c", Name: "c"},
+	}})
+	if m.Scanning {
+		t.Fatalf("expected scan to be finished")
+	}
+	selected, ok = m.SelectedDir()
+	if !ok || selected.Name != "c" {
+		t.Fatalf("expected selection to survive completion, got %+v", selected)
+	}
+}
+
+func TestScanCompletedDoesNotLeaveCurrentStep(t *testing.T) {
+	m := Model{Mode: ModeWorktree, Scanning: true, LegacySessionsCheck: true}
+
+	updated, _ := Update(m, MsgScanCompleted{Dirs: []DirEntry{{Path: "/projects/demo", Name: "demo"}}})
+	if updated.Mode != ModeWorktree {
+		t.Fatalf("expected to stay in worktree mode, got %v", updated.Mode)
+	}
+	if len(updated.Dirs) != 1 {
+		t.Fatalf("expected dirs to be stored, got %+v", updated.Dirs)
+	}
+}
+
+func TestScanProgressIgnoredWhenNotScanning(t *testing.T) {
+	m := Model{Mode: ModeBrowsing}
+
+	updated, _ := Update(m, MsgScanProgress{Dirs: []DirEntry{{Path: "/projects/demo", Name: "demo"}}})
+	if len(updated.Dirs) != 0 {
+		t.Fatalf("expected late progress to be ignored, got %+v", updated.Dirs)
+	}
+}
+
+func TestProjectIndexLoadedShowsCachedProjectsUntilScanCompletes(t *testing.T) {
+	m, _ := Init(NewModel([]string{"/projects"}))
+
+	m, _ = Update(m, MsgProjectIndexLoaded{Dirs: []DirEntry{
+		{Path: "/projects/api", Name: "api", Exists: true},
+		{Path: "/projects/old", Name: "old", Exists: false},
+	}})
+	if m.Mode != ModeBrowsing {
+		t.Fatalf("expected cached projects to be browsable, got %v", m.Mode)
+	}
+	if len(m.Filtered) != 2 {
+		t.Fatalf("expected cached projects to be listed, got %+v", m.Filtered)
+	}
+
+	m, _ = Update(m, MsgScanProgress{Dirs: []DirEntry{{Path: "/projects/new", Name: "new", Exists: true}}})
+	if len(m.Dirs) != 3 {
+		t.Fatalf("expected new project to be added to cached list, got %+v", m.Dirs)
+	}
+
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{
+		{Path: "/projects/api", Name: "api", Exists: true},
+		{Path: "/projects/new", Name: "new", Exists: true},
+	}})
+	for _, dir := range m.Dirs {
+		if dir.Name == "old" {
+			t.Fatalf("expected stale project to be dropped after rescan")
+		}
+	}
+}
+
+func TestProjectIndexLoadedIgnoredWhenProjectsAlreadyListed(t *testing.T) {
+	m := Model{Mode: ModeBrowsing, Scanning: true, Dirs: []DirEntry{{Path: "/projects/api", Name: "api"}}}
+
+	updated, _ := Update(m, MsgProjectIndexLoaded{Dirs: []DirEntry{{Path: "/projects/deleted", Name: "deleted"}}})
+	if len(updated.Dirs) != 1 || updated.Dirs[0].Name != "api" {
+		t.Fatalf("expected cache to be ignored on rescans, got %+v", updated.Dirs)
+	}
+}
+
+func TestRefreshRescansProjectsOnce(t *testing.T) {
+	m := Model{Mode: ModeBrowsing, RootPaths: []string{"/projects"}}
+
+	m, effects, handled := UpdateKey(m, KeyRefresh)
+	if !handled || !m.Scanning || len(effects) != 1 {
+		t.Fatalf("expected a rescan, got handled=%v scanning=%v effects=%v", handled, m.Scanning, effects)
+	}
+	if eff, ok := effects[0].(EffScanDirs); !ok || eff.Roots[0] != "/projects" {
+		t.Fatalf("expected EffScanDirs, got %#v", effects[0])
+	}
+
+	_, effects, _ = UpdateKey(m, KeyRefresh)
+	if len(effects) != 0 {
+		t.Fatalf("expected no second scan while scanning, got %v", effects)
+	}
+}
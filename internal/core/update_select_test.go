@@ -0,0 +1,32 @@
+package core
+
+import "testing"
+
+func TestRowSelectedClampsToTheCurrentList(t *testing.T) {
+	m := worktreeModeModel(t, []Worktree{
+		{Path: "/wt/api--a", Name: "api--a", Branch: "a"},
+		{Path: "/wt/api--b", Name: "api--b", Branch: "b"},
+	})
+
+	m, _ = Update(m, MsgRowSelected{Index: 1})
+	if m.WorktreeIdx != 1 {
+		t.Fatalf("expected the second worktree, got %d", m.WorktreeIdx)
+	}
+	m, _ = Update(m, MsgRowSelected{Index: 5})
+	if m.WorktreeIdx != 1 {
+		t.Fatalf("expected the index to stay on the last row, got %d", m.WorktreeIdx)
+	}
+
+	m, _ = Update(m, MsgWorktreeQueryChanged{Query: "feature"})
+	m, _ = Update(m, MsgRowSelected{Index: 5})
+	if _, ok := m.CreateWorktreeName(); !ok || m.WorktreeIdx != len(m.FilteredWT) {
+		t.Fatalf("expected the create row to be selectable, got %d", m.WorktreeIdx)
+	}
+
+	m.Mode = ModeCleanup
+	m.CleanupIdx = 0
+	m, _ = Update(m, MsgRowSelected{Index: 1})
+	if m.CleanupIdx != 0 {
+		t.Fatalf("expected modes without a list to ignore the selection, got %d", m.CleanupIdx)
+	}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func setupModeModel(t *testing.T) Model {
+	t.Helper()
+	m := worktreeModeModel(t, nil)
+	m.Tools = []string{"opencode", ToolNone}
+	m, effects := Update(m, MsgWorktreeCreated{Path: "/projects/api/.rivet/api--feature"})
+	if m.Mode != ModeWorktreeSetup {
+		t.Fatalf("expected setup mode, got %v", m.Mode)
+	}
+	want := EffSetupWorktree{ProjectPath: "/projects/api", WorktreePath: "/projects/api/.rivet/api--feature"}
+	if len(effects) != 1 || effects[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, effects)
+	}
+	return m
+}
+
+func TestWorktreeSetupOutputIsCapped(t *testing.T) {
+	m := setupModeModel(t)
+
+	lines := make([]string, setupOutputLimit+5)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	m, _ = Update(m, MsgWorktreeSetupOutput{Lines: lines})
+	if len(m.SetupOutput) != setupOutputLimit {
+		t.Fatalf("expected %d lines, got %d", setupOutputLimit, len(m.SetupOutput))
+	}
+	if m.SetupOutput[0] != "line 5" {
+		t.Fatalf("expected the oldest lines to be dropped, got %q", m.SetupOutput[0])
+	}
+}
+
+func TestWorktreeSetupFailureIsAWarning(t *testing.T) {
+	m := setupModeModel(t)
+
+	// A result for another worktree must not end this setup.
+	stale, _ := Update(m, MsgWorktreeSetupDone{WorktreePath: "/projects/api/.rivet/api--old"})
+	if stale.Mode != ModeWorktreeSetup {
+		t.Fatalf("expected stale result to be ignored, got %v", stale.Mode)
+	}
+
+	m, _ = Update(m, MsgWorktreeSetupDone{
+		WorktreePath: "/projects/api/.rivet/api--feature",
+		Err:          errors.New(`post-create hook "npm install" failed: exit status 1`),
+	})
+	if m.Mode != ModeTool {
+		t.Fatalf("expected tool mode, got %v", m.Mode)
+	}
+	if m.SetupWarning != `post-create hook "npm install" failed: exit status 1` {
+		t.Fatalf("unexpected warning %q", m.SetupWarning)
+	}
+
+	m, _ = Update(m, MsgKeyPress{Key: KeyBack})
+	if m.SetupWarning != "" {
+		t.Fatalf("expected the warning to clear when leaving Step 3, got %q", m.SetupWarning)
+	}
+}
+
+func TestWorktreeSetupCancelKeys(t *testing.T) {
+	m := setupModeModel(t)
+
+	m, effects := Update(m, MsgKeyPress{Key: KeyDown})
+	if m.Mode != ModeWorktreeSetup || len(effects) != 0 {
+		t.Fatalf("expected other keys to be ignored, got %v %+v", m.Mode, effects)
+	}
+
+	_, effects = Update(m, MsgKeyPress{Key: KeyBack})
+	if len(effects) != 1 || effects[0] != (EffCancelSetup{}) {
+		t.Fatalf("expected setup to be cancelled, got %+v", effects)
+	}
+
+	_, effects = Update(m, MsgKeyPress{Key: KeyQuit})
+	if len(effects) != 2 || effects[0] != (EffCancelSetup{}) || effects[1] != (EffQuit{}) {
+		t.Fatalf("expected cancel then quit, got %+v", effects)
+	}
+}
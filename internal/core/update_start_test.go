@@ -0,0 +1,53 @@
+package core
+
+import "testing"
+
+func TestStartAtWorktreeStepLoadsProjectWorktrees(t *testing.T) {
+	m := StartAt(NewModel([]string{"/projects"}), StartState{ProjectPath: "/projects/api", WorktreeQuery: "feature"})
+	if m.Mode != ModeWorktree || m.SelectedProject != "/projects/api" {
+		t.Fatalf("expected Step 2 for api, got mode %v and %q", m.Mode, m.SelectedProject)
+	}
+
+	m, effects := Init(m)
+	if len(effects) != 4 {
+		t.Fatalf("expected a scan, a worktree load, a pin load and a tag load, got %+v", effects)
+	}
+	if load, ok := effects[1].(EffLoadWorktrees); !ok || load.ProjectPath != "/projects/api" {
+		t.Fatalf("expected the worktrees of api to load, got %+v", effects[1])
+	}
+
+	m, _ = Update(m, MsgWorktreesLoaded{Worktrees: []Worktree{{Path: "/wt/api--main", Name: "api--main", Branch: "main"}}})
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{{Path: "/projects/api", Name: "api"}}})
+	if m.Mode != ModeWorktree {
+		t.Fatalf("expected the scan to leave Step 2 open, got mode %v", m.Mode)
+	}
+	if name, ok := m.CreateWorktreeName(); !ok || name != "feature" {
+		t.Fatalf("expected the prefilled query to offer creating feature, got %q", name)
+	}
+}
+
+func TestStartAtToolStepPrewarmsTools(t *testing.T) {
+	m := StartAt(NewModel([]string{"/projects"}), StartState{ProjectPath: "/projects/api", WorktreePath: "/wt/api--main"})
+	m, effects := Init(m)
+	if m.Mode != ModeTool || m.SelectedWorktreePath != "/wt/api--main" {
+		t.Fatalf("expected Step 3 for the worktree, got mode %v and %q", m.Mode, m.SelectedWorktreePath)
+	}
+	if len(effects) != 5 {
+		t.Fatalf("expected a scan, a worktree load, a prewarm, a pin load and a tag load, got %+v", effects)
+	}
+	if prewarm, ok := effects[2].(EffPrewarmAllTools); !ok || prewarm.DirPath != "/wt/api--main" {
+		t.Fatalf("expected the tools to prewarm in the worktree, got %+v", effects[2])
+	}
+
+	m, _, _ = UpdateKey(m, KeyBack)
+	if m.Mode != ModeWorktree {
+		t.Fatalf("expected esc to go back to Step 2, got mode %v", m.Mode)
+	}
+}
+
+func TestStartAtZeroValueKeepsProjectSelection(t *testing.T) {
+	m := StartAt(NewModel([]string{"/projects"}), StartState{})
+	if m.Mode != ModeLoading || m.SelectedProject != "" {
+		t.Fatalf("expected the default start, got mode %v", m.Mode)
+	}
+}
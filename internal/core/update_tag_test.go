@@ -0,0 +1,106 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTagProjectSavesTagsAndFiltersByThem(t *testing.T) {
+	m := NewModel([]string{"/projects"})
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{
+		{Path: "/projects/api", Name: "api"},
+		{Path: "/projects/web", Name: "web"},
+	}})
+
+	m, _, _ = UpdateKey(m, KeyTag)
+	if m.Mode != ModeProjectTag || m.TagProjectPath != "/projects/api" {
+		t.Fatalf("expected the tag editor for api, got mode %v and %q", m.Mode, m.TagProjectPath)
+	}
+	m, _ = Update(m, MsgTagInputChanged{Input: "backend, oss"})
+	m, effects, _ := UpdateKey(m, KeyEnter)
+	if m.Mode != ModeBrowsing {
+		t.Fatalf("expected to return to Step 1, got mode %v", m.Mode)
+	}
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	eff, ok := effects[0].(EffSetProjectTags)
+	if !ok || eff.ProjectPath != "/projects/api" || !slices.Equal(eff.Tags, []string{"backend", "oss"}) {
+		t.Fatalf("expected api's tags to be saved, got %+v", effects[0])
+	}
+
+	m, _ = Update(m, MsgQueryChanged{Query: "tag:oss"})
+	if len(m.Filtered) != 1 || m.Filtered[0].Path != "/projects/api" {
+		t.Fatalf("expected only api, got %+v", m.Filtered)
+	}
+	if _, ok := m.CreateProjectPath(); ok {
+		t.Fatal("expected no create row for a tag query")
+	}
+}
+
+func TestTagEditorCancelKeepsTags(t *testing.T) {
+	m := NewModel([]string{"/projects"})
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{{Path: "/projects/api", Name: "api"}}})
+	m, _ = Update(m, MsgTagsLoaded{Tags: map[string][]string{"/projects/api": {"backend"}}})
+
+	m, _, _ = UpdateKey(m, KeyTag)
+	if m.TagInput != "backend" {
+		t.Fatalf("expected the saved tags to be prefilled, got %q", m.TagInput)
+	}
+	m, _ = Update(m, MsgTagInputChanged{Input: ""})
+	m, effects, _ := UpdateKey(m, KeyBack)
+	if m.Mode != ModeBrowsing || len(effects) != 0 {
+		t.Fatalf("expected esc to cancel, got mode %v and %+v", m.Mode, effects)
+	}
+	if !slices.Equal(m.ProjectTags["/projects/api"], []string{"backend"}) {
+		t.Fatalf("expected api to keep its tags, got %v", m.ProjectTags)
+	}
+}
+
+func TestSessionsGroupByProjectTag(t *testing.T) {
+	m := NewModel([]string{"/projects"})
+	m.TagRules = TagRules{"backend": {"api"}, "client-x": {"web"}}
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{
+		{Path: "/projects/api", Name: "api"},
+		{Path: "/projects/web", Name: "web"},
+		{Path: "/projects/docs", Name: "docs"},
+	}})
+	m, _, _ = UpdateKey(m, KeySessions)
+	m, _ = Update(m, MsgSessionsLoaded{Sessions: []SessionInfo{
+		{Name: "docs", DirPath: "/projects/docs", Project: "docs"},
+		{Name: "web", DirPath: "/wt/web--fix", Project: "web"},
+		{Name: "api", DirPath: "/projects/api", Project: "api"},
+	}})
+
+	var names []string
+	for _, session := range m.FilteredSessions {
+		names = append(names, session.Name)
+	}
+	if want := []string{"api", "web", "docs"}; !slices.Equal(names, want) {
+		t.Fatalf("expected sessions grouped by tag %v, got %v", want, names)
+	}
+
+	m, _ = Update(m, MsgSessionQueryChanged{Query: "tag:client-x"})
+	if len(m.FilteredSessions) != 1 || m.FilteredSessions[0].Name != "web" {
+		t.Fatalf("expected only the client-x session, got %+v", m.FilteredSessions)
+	}
+}
+
+func TestSessionsKeepRankOrderWhenFiltered(t *testing.T) {
+	m := NewModel([]string{"/projects"})
+	m.TagRules = TagRules{"backend": {"api"}}
+	m, _ = Update(m, MsgScanCompleted{Dirs: []DirEntry{
+		{Path: "/projects/api", Name: "api"},
+		{Path: "/projects/docs", Name: "docs"},
+	}})
+	m, _, _ = UpdateKey(m, KeySessions)
+	m, _ = Update(m, MsgSessionsLoaded{Sessions: []SessionInfo{
+		{Name: "api-docs", DirPath: "/projects/api", Project: "api"},
+		{Name: "docs", DirPath: "/projects/docs", Project: "docs"},
+	}})
+
+	m, _ = Update(m, MsgSessionQueryChanged{Query: "docs"})
+	if len(m.FilteredSessions) != 2 || m.FilteredSessions[0].Name != "docs" {
+		t.Fatalf("expected the untagged exact match first, got %+v", m.FilteredSessions)
+	}
+}
@@ -161,12 +161,15 @@ func TestSessionsModeEnterAndLeaveRestoresPreviousMode(t *testing.T) {
 	if entered.SessionQuery != "" || entered.SessionIdx != 0 {
 		t.Fatalf("expected session state reset, got query=%q idx=%d", entered.SessionQuery, entered.SessionIdx)
 	}
-	if len(effects) != 1 {
-		t.Fatalf("expected one effect, got %d", len(effects))
+	if len(effects) != 2 {
+		t.Fatalf("expected two effects, got %d", len(effects))
 	}
 	if _, ok := effects[0].(EffListSessions); !ok {
 		t.Fatalf("expected EffListSessions, got %T", effects[0])
 	}
+	if _, ok := effects[1].(EffWatchSessions); !ok {
+		t.Fatalf("expected EffWatchSessions, got %T", effects[1])
+	}
 
 	entered.SessionQuery = "filter"
 	entered.SessionIdx = 1
@@ -186,8 +189,11 @@ func TestSessionsModeEnterAndLeaveRestoresPreviousMode(t *testing.T) {
 	if len(left.Sessions) != 0 || len(left.FilteredSessions) != 0 {
 		t.Fatalf("expected cleared sessions slices")
 	}
-	if len(leaveEffects) != 0 {
-		t.Fatalf("expected no effects on leave, got %d", len(leaveEffects))
+	if len(leaveEffects) != 1 {
+		t.Fatalf("expected one effect on leave, got %d", len(leaveEffects))
+	}
+	if _, ok := leaveEffects[0].(EffUnwatchSessions); !ok {
+		t.Fatalf("expected EffUnwatchSessions, got %T", leaveEffects[0])
 	}
 }
 
@@ -243,14 +249,56 @@ func TestSessionsNavigationKeysMoveSelection(t *testing.T) {
 	}
 }
 
+func TestSessionsChangedRefreshesOnlyInSessionsMode(t *testing.T) {
+	m := Model{Mode: ModeSessions}
+	_, effects := Update(m, MsgSessionsChanged{Event: "sessions-changed"})
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	if _, ok := effects[0].(EffListSessions); !ok {
+		t.Fatalf("expected EffListSessions, got %T", effects[0])
+	}
+
+	m.Mode = ModeBrowsing
+	_, effects = Update(m, MsgSessionsChanged{Event: "window-add"})
+	if len(effects) != 0 {
+		t.Fatalf("expected no refresh outside sessions mode, got %d effects", len(effects))
+	}
+}
+
+func TestSessionsLoadedKeepsSelectedSessionAndQuery(t *testing.T) {
+	m := Model{
+		Mode:             ModeSessions,
+		SessionQuery:     "s",
+		Sessions:         []SessionInfo{{Name: "s1"}, {Name: "s2"}},
+		FilteredSessions: []SessionInfo{{Name: "s1"}, {Name: "s2"}},
+		SessionIdx:       1,
+	}
+
+	updated, _ := Update(m, MsgSessionsLoaded{Sessions: []SessionInfo{{Name: "s0"}, {Name: "s1"}, {Name: "s2"}}})
+	if updated.SessionQuery != "s" {
+		t.Fatalf("expected query to be preserved, got %q", updated.SessionQuery)
+	}
+	selected, ok := updated.SelectedSession()
+	if !ok || selected.Name != "s2" {
+		t.Fatalf("expected selection to stay on s2, got %+v", selected)
+	}
+}
+
 func TestInitEmitsScanDirsEffect(t *testing.T) {
 	m := NewModel([]string{"/projects"})
 	updated, effects := Init(m)
 	if updated.Mode != ModeLoading {
 		t.Fatalf("expected mode loading, got %v", updated.Mode)
 	}
-	if len(effects) != 1 {
-		t.Fatalf("expected one effect, got %d", len(effects))
+	if len(effects) != 3 {
+		t.Fatalf("expected three effects, got %d", len(effects))
+	}
+	if _, ok := effects[1].(EffLoadPins); !ok {
+		t.Fatalf("expected EffLoadPins, got %T", effects[1])
+	}
+	if _, ok := effects[2].(EffLoadTags); !ok {
+		t.Fatalf("expected EffLoadTags, got %T", effects[2])
 	}
 	eff, ok := effects[0].(EffScanDirs)
 	if !ok {
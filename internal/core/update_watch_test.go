@@ -0,0 +1,131 @@
+package core
+
+import "testing"
+
+func TestProjectsChangedKeepsQueryAndSelection(t *testing.T) {
+	m := Model{
+		Mode:  ModeBrowsing,
+		Query: "a",
+		Dirs: []DirEntry{
+			{Path: "/projects/alpha", Name: "alpha"},
+			{Path: "/projects/gamma", Name: "gamma"},
+			{Path: "/projects/zeta", Name: "zeta"},
+		},
+	}
+	m.Filtered = FilterDirs(m.Dirs, m.Query)
+	for i, dir := range m.Filtered {
+		if dir.Name == "gamma" {
+			m.SelectedIdx = i
+		}
+	}
+
+	updated, effects := Update(m, MsgProjectsChanged{
+		Added:   []DirEntry{{Path: "/projects/aardvark", Name: "aardvark"}},
+		Removed: []string{"/projects/zeta"},
+	})
+	if len(effects) != 0 {
+		t.Fatalf("expected no effects, got %d", len(effects))
+	}
+	if updated.Query != "a" {
+		t.Fatalf("expected query to be preserved, got %q", updated.Query)
+	}
+	for _, dir := range updated.Dirs {
+		if dir.Name == "zeta" {
+			t.Fatalf("expected removed project to be dropped")
+		}
+	}
+	selected, ok := updated.SelectedDir()
+	if !ok || selected.Name != "gamma" {
+		t.Fatalf("expected selection to stay on gamma, got %+v", selected)
+	}
+}
+
+func TestProjectsChangedKeepsCreateRowSelected(t *testing.T) {
+	m := Model{
+		Mode:      ModeBrowsing,
+		Query:     "new",
+		RootPaths: []string{"/projects"},
+		Dirs:      []DirEntry{{Path: "/projects/newsletter", Name: "newsletter"}},
+	}
+	m.Filtered = FilterDirs(m.Dirs, m.Query)
+	m.SelectedIdx = len(m.Filtered)
+
+	updated, _ := Update(m, MsgProjectsChanged{Added: []DirEntry{{Path: "/projects/news", Name: "news"}}})
+	if updated.SelectedIdx != len(updated.Filtered) {
+		t.Fatalf("expected create row to stay selected, got idx %d of %d", updated.SelectedIdx, len(updated.Filtered))
+	}
+}
+
+func TestScanCompletedStartsProjectWatchOnce(t *testing.T) {
+	m, _ := Init(NewModel([]string{"/projects"}))
+
+	updated, effects := Update(m, MsgScanCompleted{})
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	eff, ok := effects[0].(EffWatchProjects)
+	if !ok {
+		t.Fatalf("expected EffWatchProjects, got %T", effects[0])
+	}
+	if len(eff.Roots) != 1 || eff.Roots[0] != "/projects" {
+		t.Fatalf("unexpected watch roots: %v", eff.Roots)
+	}
+
+	updated.Scanning = true
+	_, effects = Update(updated, MsgScanCompleted{})
+	if len(effects) != 0 {
+		t.Fatalf("expected watcher to start only once, got %d effects", len(effects))
+	}
+}
+
+func TestWorktreesChangedRefreshesSelectedProject(t *testing.T) {
+	m := Model{Mode: ModeWorktree, SelectedProject: "/projects/demo"}
+
+	_, effects := Update(m, MsgWorktreesChanged{})
+	if len(effects) != 1 {
+		t.Fatalf("expected one effect, got %d", len(effects))
+	}
+	eff, ok := effects[0].(EffRefreshWorktrees)
+	if !ok || eff.ProjectPath != "/projects/demo" {
+		t.Fatalf("expected refresh of /projects/demo, got %#v", effects[0])
+	}
+
+	_, effects = Update(Model{Mode: ModeBrowsing}, MsgWorktreesChanged{})
+	if len(effects) != 0 {
+		t.Fatalf("expected no refresh without a selected project, got %d effects", len(effects))
+	}
+}
+
+func TestWorktreesRefreshedKeepsSelection(t *testing.T) {
+	m := Model{
+		Mode:            ModeWorktree,
+		SelectedProject: "/projects/demo",
+		Worktrees: []Worktree{
+			{Path: "/wt/main", Name: "main"},
+			{Path: "/wt/feature", Name: "feature"},
+		},
+	}
+	m.FilteredWT = FilterWorktrees(m.Worktrees, "")
+	m.WorktreeIdx = 1
+
+	updated, _ := Update(m, MsgWorktreesRefreshed{
+		ProjectPath: "/projects/demo",
+		Worktrees: []Worktree{
+			{Path: "/wt/main", Name: "main"},
+			{Path: "/wt/bugfix", Name: "bugfix"},
+			{Path: "/wt/feature", Name: "feature"},
+		},
+	})
+	selected, ok := updated.SelectedWorktree()
+	if !ok || selected.Name != "feature" {
+		t.Fatalf("expected selection to stay on feature, got %+v", selected)
+	}
+	if len(updated.Worktrees) != 3 {
+		t.Fatalf("expected refreshed worktrees, got %+v", updated.Worktrees)
+	}
+
+	stale, _ := Update(updated, MsgWorktreesRefreshed{ProjectPath: "/projects/other"})
+	if len(stale.Worktrees) != 3 {
+		t.Fatalf("expected refresh for another project to be ignored")
+	}
+}
@@ -1,6 +1,10 @@
 package ports
 
-import "github.com/ariguillegp/rivet/internal/core"
+import (
+	"context"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
 
 type Filesystem interface {
 	ScanDirs(roots []string, maxDepth int) ([]core.DirEntry, error)
@@ -12,3 +16,104 @@ type Filesystem interface {
 	DeleteWorktree(projectPath, worktreePath string) error
 	PruneWorktrees(projectPath string) error
 }
+
+// DirStreamer is implemented by filesystems that can report scan results
+// incrementally and stop early when ctx is cancelled.
+type DirStreamer interface {
+	ScanDirsStream(ctx context.Context, roots []string, maxDepth int, emit func([]core.DirEntry)) ([]core.DirEntry, error)
+}
+
+// ProjectIndex is implemented by filesystems that keep the result of the
+// last scan so it can be shown before a fresh scan finishes.
+type ProjectIndex interface {
+	CachedDirs(roots []string, maxDepth int) ([]core.DirEntry, bool)
+	StoreDirs(roots []string, maxDepth int, dirs []core.DirEntry) error
+}
+
+// ProjectCloner is implemented by filesystems that can clone a repository
+// into a new project directory, reporting progress as the clone runs.
+// IsBareRepository recognizes local repositories that core.IsCloneSource
+// cannot tell from a project path, and IsCloneOf checks that an existing
+// project is a clone of the same source before it is reused.
+type ProjectCloner interface {
+	CloneProject(ctx context.Context, source, path string, progress func(core.CloneProgress)) (string, error)
+	IsBareRepository(path string) bool
+	IsCloneOf(path, source string) bool
+}
+
+// WorktreeAdopter is implemented by filesystems that can list worktrees
+// created outside rivet and move them into the managed directory.
+type WorktreeAdopter interface {
+	ListAllWorktrees(projectPath string) (core.WorktreeListing, error)
+	AdoptWorktree(projectPath, worktreePath string) (string, error)
+}
+
+// WorktreeLander is implemented by filesystems that can integrate a
+// worktree's branch into the branch checked out in the project root.
+type WorktreeLander interface {
+	LandWorktree(projectPath, worktreePath string, strategy core.LandStrategy) (core.LandResult, error)
+}
+
+// WorktreeCollector is implemented by filesystems that can report how stale
+// a project's managed worktrees are, so unused ones can be cleaned up.
+type WorktreeCollector interface {
+	WorktreeActivity(projectPath string) ([]core.WorktreeActivity, error)
+}
+
+// BranchDeleter is implemented by filesystems that can delete a project's
+// local branches. Without force only fully merged branches are deleted, and
+// BranchMerged tells up front whether that is the case.
+type BranchDeleter interface {
+	BranchMerged(projectPath, branch string) (bool, error)
+	DeleteBranch(projectPath, branch string, force bool) error
+}
+
+// WorktreePreparer is implemented by filesystems that set up new worktrees
+// from the project's configuration, passing hook output to output line by
+// line. An error is a warning: the worktree itself exists either way.
+type WorktreePreparer interface {
+	SetupWorktree(ctx context.Context, projectPath, worktreePath string, output func(string)) error
+}
+
+// WorktreeReviewer is implemented by filesystems that can show a worktree's
+// changes against its base branch, discard them file by file and commit them.
+type WorktreeReviewer interface {
+	ListChanges(worktreePath string) (core.WorktreeChanges, error)
+	FileDiff(worktreePath, mergeBase string, file core.FileChange) (string, error)
+	DiscardFile(worktreePath, mergeBase string, file core.FileChange) error
+	CommitAll(worktreePath, message string) error
+}
+
+// ProjectWatcher is implemented by filesystems that can report projects and
+// managed worktrees appearing or disappearing while rivet is open.
+type ProjectWatcher interface {
+	WatchProjects(roots []string, maxDepth int) (ProjectSubscription, error)
+}
+
+// ProjectSubscription delivers project changes until closed.
+type ProjectSubscription interface {
+	Events() <-chan ProjectEvent
+	Close() error
+}
+
+// ProjectEvent lists projects found or removed since the previous event.
+// Worktrees is set when the managed worktree directory changed.
+type ProjectEvent struct {
+	Added     []core.DirEntry
+	Removed   []string
+	Worktrees bool
+}
+
+// PinStore is implemented by filesystems that remember which projects and
+// worktrees are pinned.
+type PinStore interface {
+	Pins() ([]string, error)
+	SetPinned(path string, pinned bool) error
+}
+
+// TagStore is implemented by filesystems that remember the tags set on
+// projects from the UI.
+type TagStore interface {
+	ProjectTags() (map[string][]string, error)
+	SetProjectTags(projectPath string, tags []string) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// HookRunner runs lifecycle hook commands. RunHook returns once the command
+// exits or ctx ends, whichever comes first.
+type HookRunner interface {
+	RunHook(ctx context.Context, command string, payload core.HookPayload) error
+}
@@ -5,7 +5,33 @@ import "github.com/ariguillegp/rivet/internal/core"
 type SessionManager interface {
 	OpenSession(spec core.SessionSpec) error
 	PrewarmSession(spec core.SessionSpec) (bool, error)
+	PrewarmTools(dirPath string, tools []string) (map[string]bool, error)
 	KillSession(spec core.SessionSpec) error
+	// KillSessionByName kills the session with exactly this name, whatever
+	// directory it runs in.
+	KillSessionByName(name string) error
 	ListSessions() ([]core.SessionInfo, error)
 	AttachSession(name string) error
+	FindLegacySessions(worktreePaths []string) ([]core.SessionMigration, error)
+	MigrateSession(migration core.SessionMigration) error
+}
+
+// SessionWatcher is implemented by session managers that can push change
+// notifications instead of being polled.
+type SessionWatcher interface {
+	WatchSessions() (SessionSubscription, error)
+}
+
+// SessionSubscription delivers session change event names until closed.
+type SessionSubscription interface {
+	Events() <-chan string
+	Close() error
+}
+
+// LaunchHistory is implemented by session managers that remember the
+// sessions opened recently, newest first, so they can be reopened in one
+// step.
+type LaunchHistory interface {
+	RecentLaunches() ([]core.Launch, error)
+	RecordLaunch(spec core.SessionSpec) error
 }
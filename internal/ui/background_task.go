@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"sync"
+)
+
+// backgroundTask tracks a long-running command, such as the streaming project
+// scan or a clone, so that a newer run or quitting can cancel it. Like
+// subscriptionSlot it is shared by pointer.
+type backgroundTask struct {
+	mu     sync.Mutex
+	gen    int
+	cancel context.CancelFunc
+}
+
+// start cancels any running task and returns the context and generation of
+// a new one.
+func (s *backgroundTask) start() (context.Context, int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	previous := s.cancel
+	s.gen++
+	gen := s.gen
+	s.cancel = cancel
+	s.mu.Unlock()
+	if previous != nil {
+		previous()
+	}
+	return ctx, gen
+}
+
+func (s *backgroundTask) isCurrent(gen int) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancel != nil && s.gen == gen
+}
+
+// finish releases the task with the given generation if it is still current.
+func (s *backgroundTask) finish(gen int) {
+	s.mu.Lock()
+	var cancel context.CancelFunc
+	if s.gen == gen {
+		cancel = s.cancel
+		s.cancel = nil
+	}
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// interrupt cancels the running task but keeps it current, so the result it
+// reports after cancellation is still delivered.
+func (s *backgroundTask) interrupt() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (s *backgroundTask) stop() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,6 +21,18 @@ type keyMap struct {
 	Select   key.Binding
 	Delete   key.Binding
 	Sessions key.Binding
+	ShowAll  key.Binding
+	Adopt    key.Binding
+	Review   key.Binding
+	Land     key.Binding
+	Commit   key.Binding
+	Cleanup  key.Binding
+	Recent   key.Binding
+	Refresh  key.Binding
+	Palette  key.Binding
+	Mark     key.Binding
+	Pin      key.Binding
+	Tag      key.Binding
 	Toggle   key.Binding
 	Back     key.Binding
 	Quit     key.Binding
@@ -25,25 +40,65 @@ type keyMap struct {
 	Type     key.Binding
 }
 
-func newKeyMap() keyMap {
+// newKeyMap binds every action to its keys in bindings, falling back to the
+// defaults. Help labels follow the bound keys.
+func newKeyMap(bindings core.KeyBindings) keyMap {
+	bind := func(action core.KeyAction, desc string) key.Binding {
+		keys := bindings.Keys(action)
+		return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKey(keys), desc))
+	}
 	return keyMap{
-		Up:       key.NewBinding(key.WithKeys("up", "ctrl+k"), key.WithHelp("↑/ctrl+k", "up")),
-		Down:     key.NewBinding(key.WithKeys("down", "ctrl+j"), key.WithHelp("↓/ctrl+j", "down")),
-		PageUp:   key.NewBinding(key.WithKeys("pgup", "pageup"), key.WithHelp("pgup", "page up")),
-		PageDown: key.NewBinding(key.WithKeys("pgdown", "pgdn", "pagedown"), key.WithHelp("pgdn", "page down")),
-		Top:      key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "top")),
-		Bottom:   key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "bottom")),
-		Select:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
-		Delete:   key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "delete")),
-		Sessions: key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "sessions")),
-		Toggle:   key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
-		Back:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
-		Quit:     key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
-		Theme:    key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "theme")),
+		Up:       bind(core.KeyUp, "up"),
+		Down:     bind(core.KeyDown, "down"),
+		PageUp:   bind(core.KeyPageUp, "page up"),
+		PageDown: bind(core.KeyPageDown, "page down"),
+		Top:      bind(core.KeyTop, "top"),
+		Bottom:   bind(core.KeyBottom, "bottom"),
+		Select:   bind(core.KeyEnter, "select"),
+		Delete:   bind(core.KeyDelete, "delete"),
+		Sessions: bind(core.KeySessions, "sessions"),
+		ShowAll:  bind(core.KeyShowAll, "all worktrees"),
+		Adopt:    bind(core.KeyAdopt, "adopt"),
+		Review:   bind(core.KeyReview, "review"),
+		Land:     bind(core.KeyLand, "land"),
+		Commit:   bind(core.KeyCommit, "commit"),
+		Cleanup:  bind(core.KeyCleanup, "cleanup"),
+		Recent:   bind(core.KeyRecent, "recent"),
+		Refresh:  bind(core.KeyRefresh, "refresh"),
+		Palette:  bind(core.KeyPalette, "commands"),
+		Mark:     bind(core.KeyMark, "mark"),
+		Pin:      bind(core.KeyPin, "pin"),
+		Tag:      bind(core.KeyTag, "tag"),
+		Toggle:   bind(core.KeyHelp, "help"),
+		Back:     bind(core.KeyBack, "back"),
+		Quit:     bind(core.KeyQuit, "quit"),
+		Theme:    bind(core.KeyTheme, "theme"),
 		Type:     key.NewBinding(key.WithKeys("type"), key.WithHelp("type", "filter")),
 	}
 }
 
+// helpKey joins the keys of a binding for the help bar, showing arrows as
+// glyphs and each page key spelling once.
+func helpKey(keys []string) string {
+	var names []string
+	for _, k := range keys {
+		switch k {
+		case "up":
+			k = "↑"
+		case "down":
+			k = "↓"
+		case "pageup":
+			k = "pgup"
+		case "pgdown", "pagedown":
+			k = "pgdn"
+		}
+		if !slices.Contains(names, k) {
+			names = append(names, k)
+		}
+	}
+	return strings.Join(names, "/")
+}
+
 func (k keyMap) actionForCore(msg tea.KeyMsg) (core.KeyAction, bool) {
 	switch {
 	case key.Matches(msg, k.Up):
@@ -64,6 +119,28 @@ func (k keyMap) actionForCore(msg tea.KeyMsg) (core.KeyAction, bool) {
 		return core.KeyDelete, true
 	case key.Matches(msg, k.Sessions):
 		return core.KeySessions, true
+	case key.Matches(msg, k.ShowAll):
+		return core.KeyShowAll, true
+	case key.Matches(msg, k.Adopt):
+		return core.KeyAdopt, true
+	case key.Matches(msg, k.Review):
+		return core.KeyReview, true
+	case key.Matches(msg, k.Land):
+		return core.KeyLand, true
+	case key.Matches(msg, k.Commit):
+		return core.KeyCommit, true
+	case key.Matches(msg, k.Cleanup):
+		return core.KeyCleanup, true
+	case key.Matches(msg, k.Recent):
+		return core.KeyRecent, true
+	case key.Matches(msg, k.Mark):
+		return core.KeyMark, true
+	case key.Matches(msg, k.Pin):
+		return core.KeyPin, true
+	case key.Matches(msg, k.Tag):
+		return core.KeyTag, true
+	case key.Matches(msg, k.Refresh):
+		return core.KeyRefresh, true
 	case key.Matches(msg, k.Back):
 		return core.KeyBack, true
 	case key.Matches(msg, k.Quit):
@@ -78,15 +155,27 @@ func (k keyMap) shortHelp(mode core.Mode) []key.Binding {
 	case core.ModeLoading:
 		return []key.Binding{k.binding(k.Back, "quit")}
 	case core.ModeBrowsing:
-		return []key.Binding{k.Select, k.Delete, k.Sessions, k.Toggle, k.binding(k.Back, "quit")}
+		return []key.Binding{k.Select, k.Delete, k.Mark, k.Sessions, k.Recent, k.Toggle, k.binding(k.Back, "quit")}
 	case core.ModeWorktree:
-		return []key.Binding{k.Select, k.Delete, k.Sessions, k.Toggle, k.Back}
+		return []key.Binding{k.Select, k.Delete, k.Mark, k.Review, k.Sessions, k.Toggle, k.Back}
+	case core.ModeWorktreeLand:
+		return []key.Binding{k.binding(k.Select, "land"), k.binding(k.Delete, "toggle delete"), k.binding(k.Back, "cancel")}
+	case core.ModeReview:
+		return []key.Binding{k.binding(k.Select, "edit"), k.binding(k.Delete, "discard"), k.Commit, k.binding(k.PageDown, "scroll"), k.Toggle, k.Back}
 	case core.ModeTool:
 		return []key.Binding{k.binding(k.Select, "open"), k.Sessions, k.Toggle, k.Back}
-	case core.ModeToolStarting:
+	case core.ModeProjectCloning, core.ModeWorktreeSetup, core.ModeToolStarting:
 		return []key.Binding{k.binding(k.Back, "cancel"), k.Quit}
 	case core.ModeSessions:
-		return []key.Binding{k.binding(k.Select, "attach"), k.Toggle, k.Back}
+		return []key.Binding{k.binding(k.Select, "attach"), k.binding(k.Delete, "kill"), k.Mark, k.Toggle, k.Back}
+	case core.ModeCleanup:
+		return []key.Binding{k.Mark, k.binding(k.Select, "delete marked"), k.binding(k.Delete, "toggle branches"), k.Toggle, k.Back}
+	case core.ModeRecent:
+		return []key.Binding{k.binding(k.Select, "reopen"), k.Refresh, k.Toggle, k.Back}
+	case core.ModeProjectTag:
+		return []key.Binding{k.binding(k.Select, "save"), k.binding(k.Back, "cancel")}
+	case core.ModeSessionMigrateConfirm:
+		return []key.Binding{k.binding(k.Select, "rename"), k.Adopt, k.binding(k.Back, "cancel")}
 	default:
 		return []key.Binding{k.binding(k.Back, "quit")}
 	}
@@ -96,22 +185,159 @@ func (k keyMap) sessionsEmptyShortHelp() []key.Binding {
 	return []key.Binding{k.Toggle, k.Back}
 }
 
-func (k keyMap) fullHelp(mode core.Mode) [][]key.Binding {
-	common := [][]key.Binding{
-		{k.Up, k.Down, k.PageUp, k.PageDown, k.Select},
-		{k.Top, k.Bottom, k.Back, k.Quit, k.Toggle},
-		{k.Type, k.Sessions, k.Delete, k.Theme},
+// command is an entry of the action registry behind the help modal and the
+// command palette. Entries without a name only appear in the help modal.
+type command struct {
+	binding key.Binding
+	action  core.KeyAction
+	name    string
+}
+
+// commands lists the actions available in a mode, grouped into the rows of
+// the help modal.
+func (k keyMap) commands(mode core.Mode) [][]command {
+	entry := func(b key.Binding, action core.KeyAction, name string) command {
+		return command{binding: b, action: action, name: name}
+	}
+	up := entry(k.Up, core.KeyUp, "")
+	down := entry(k.Down, core.KeyDown, "")
+	pageUp := entry(k.PageUp, core.KeyPageUp, "")
+	pageDown := entry(k.PageDown, core.KeyPageDown, "")
+	top := entry(k.Top, core.KeyTop, "")
+	bottom := entry(k.Bottom, core.KeyBottom, "")
+	typing := entry(k.Type, "", "")
+	sessions := entry(k.Sessions, core.KeySessions, "Show sessions")
+	theme := entry(k.Theme, core.KeyTheme, "Switch theme")
+	palette := entry(k.Palette, core.KeyPalette, "")
+	help := entry(k.Toggle, core.KeyHelp, "Show help")
+	quit := entry(k.Quit, core.KeyQuit, "Quit")
+	cancel := entry(k.binding(k.Back, "cancel"), core.KeyBack, "Cancel")
+	// Lists share the navigation rows; sel and back name what enter and esc
+	// do there.
+	list := func(sel, back command) [][]command {
+		return [][]command{
+			{up, down, pageUp, pageDown, sel},
+			{top, bottom, back, quit, help},
+		}
 	}
+
 	switch mode {
-	case core.ModeLoading, core.ModeError:
-		return [][]key.Binding{{k.binding(k.Back, "quit"), k.Quit}, {k.Toggle}}
-	case core.ModeProjectDeleteConfirm, core.ModeWorktreeDeleteConfirm:
-		return [][]key.Binding{{k.binding(k.Select, "delete"), k.binding(k.Back, "cancel"), k.Quit}}
-	case core.ModeToolStarting:
-		return [][]key.Binding{{k.Back, k.Quit}}
+	case core.ModeBrowsing:
+		return append(list(entry(k.Select, core.KeyEnter, "Open project"), entry(k.Back, core.KeyBack, "")),
+			[]command{typing, sessions, entry(k.Delete, core.KeyDelete, "Delete project"), entry(k.Mark, core.KeyMark, "Mark project"), theme},
+			[]command{entry(k.Pin, core.KeyPin, "Pin project"), entry(k.Tag, core.KeyTag, "Tag project"), entry(k.Cleanup, core.KeyCleanup, "Clean up workspaces"), entry(k.Recent, core.KeyRecent, "Recent workspaces"), entry(k.Refresh, core.KeyRefresh, "Rescan projects"), palette},
+			[]command{entry(k.binding(k.Adopt, "legacy sessions"), core.KeyAdopt, "Migrate legacy sessions")},
+		)
+	case core.ModeProjectTag:
+		return [][]command{{entry(k.binding(k.Select, "save"), core.KeyEnter, "Save tags"), cancel, quit}}
+	case core.ModeProjectDeleteConfirm:
+		return [][]command{{entry(k.binding(k.Select, "delete"), core.KeyEnter, "Delete project"), cancel, quit}}
+	case core.ModeSessionMigrateConfirm:
+		return [][]command{{
+			entry(k.binding(k.Select, "rename"), core.KeyEnter, "Rename legacy sessions"),
+			entry(k.Adopt, core.KeyAdopt, "Adopt legacy session names"),
+			cancel,
+			quit,
+		}}
+	case core.ModeWorktree:
+		return append(list(entry(k.Select, core.KeyEnter, "Open workspace"), entry(k.Back, core.KeyBack, "Back to projects")),
+			[]command{typing, sessions, entry(k.Delete, core.KeyDelete, "Delete workspace"), entry(k.Mark, core.KeyMark, "Mark workspace"), theme},
+			[]command{
+				entry(k.Pin, core.KeyPin, "Pin workspace"),
+				entry(k.ShowAll, core.KeyShowAll, "Show all worktrees"),
+				entry(k.Adopt, core.KeyAdopt, "Adopt worktree"),
+				entry(k.Review, core.KeyReview, "Review changes"),
+				entry(k.Land, core.KeyLand, "Land workspace"),
+				entry(k.Refresh, core.KeyRefresh, "Reload workspaces"),
+			},
+			[]command{palette},
+		)
+	case core.ModeWorktreeDeleteConfirm:
+		return [][]command{{
+			entry(k.binding(k.Select, "delete"), core.KeyEnter, "Delete workspace"),
+			entry(k.binding(k.Delete, "keep/delete/force delete branch"), core.KeyDelete, "Cycle branch deletion"),
+			cancel,
+			quit,
+		}}
+	case core.ModeProjectCloning, core.ModeWorktreeSetup, core.ModeToolStarting:
+		return [][]command{{entry(k.Back, core.KeyBack, "Cancel"), quit}}
+	case core.ModeWorktreeLand:
+		return [][]command{{
+			up,
+			down,
+			entry(k.binding(k.Select, "land"), core.KeyEnter, "Land workspace"),
+			entry(k.binding(k.Delete, "toggle delete"), core.KeyDelete, "Toggle deleting the workspace"),
+			cancel,
+			quit,
+		}}
+	case core.ModeCleanup:
+		return [][]command{
+			{up, down, pageUp, pageDown, top, bottom},
+			{
+				entry(k.Mark, core.KeyMark, "Mark workspace"),
+				entry(k.binding(k.Select, "delete marked"), core.KeyEnter, "Delete marked workspaces"),
+				entry(k.binding(k.Delete, "toggle branches"), core.KeyDelete, "Toggle deleting branches"),
+			},
+			{entry(k.Back, core.KeyBack, "Back to projects"), quit, help, theme},
+		}
+	case core.ModeRecent:
+		return append(list(entry(k.binding(k.Select, "reopen"), core.KeyEnter, "Reopen workspace"), entry(k.Back, core.KeyBack, "Back to projects")),
+			[]command{entry(k.Refresh, core.KeyRefresh, "Refresh recent workspaces"), theme, palette},
+		)
+	case core.ModeReview:
+		return [][]command{
+			{up, down, top, bottom},
+			{entry(k.binding(k.PageUp, "scroll up"), core.KeyPageUp, ""), entry(k.binding(k.PageDown, "scroll down"), core.KeyPageDown, "")},
+			{
+				entry(k.binding(k.Select, "edit"), core.KeyEnter, "Edit file"),
+				entry(k.binding(k.Delete, "discard"), core.KeyDelete, "Discard file changes"),
+				entry(k.Commit, core.KeyCommit, "Commit all changes"),
+			},
+			{entry(k.Back, core.KeyBack, "Back to workspaces"), quit, help, theme},
+		}
+	case core.ModeTool:
+		return append(list(entry(k.Select, core.KeyEnter, "Open tool"), entry(k.Back, core.KeyBack, "Back to workspaces")),
+			[]command{typing, sessions, theme, palette},
+		)
+	case core.ModeSessions:
+		return append(list(entry(k.Select, core.KeyEnter, "Attach session"), entry(k.Back, core.KeyBack, "Close sessions")),
+			[]command{typing, entry(k.binding(k.Delete, "kill"), core.KeyDelete, "Kill session"), entry(k.Mark, core.KeyMark, "Mark session"), theme, entry(k.Refresh, core.KeyRefresh, "Refresh sessions")},
+			[]command{palette},
+		)
 	default:
-		return common
+		return [][]command{{entry(k.binding(k.Back, "quit"), core.KeyBack, ""), quit}, {help}}
+	}
+}
+
+func (k keyMap) fullHelp(mode core.Mode) [][]key.Binding {
+	groups := k.commands(mode)
+	rows := make([][]key.Binding, 0, len(groups))
+	for _, group := range groups {
+		row := make([]key.Binding, 0, len(group))
+		for _, cmd := range group {
+			row = append(row, cmd.binding)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// paletteCommands returns the named commands of a mode in help order, with
+// the ones every mode has (back, quit, help, theme) last.
+func (k keyMap) paletteCommands(mode core.Mode) []command {
+	var commands, general []command
+	for _, group := range k.commands(mode) {
+		for _, cmd := range group {
+			switch {
+			case cmd.name == "":
+			case cmd.action == core.KeyBack || cmd.action == core.KeyQuit || cmd.action == core.KeyHelp || cmd.action == core.KeyTheme:
+				general = append(general, cmd)
+			default:
+				commands = append(commands, cmd)
+			}
+		}
 	}
+	return append(commands, general...)
 }
 
 func (k keyMap) binding(b key.Binding, desc string) key.Binding {
@@ -121,6 +347,11 @@ func (k keyMap) binding(b key.Binding, desc string) key.Binding {
 	return clone
 }
 
+// keyName returns the keys of a binding as hints in the views spell them.
+func keyName(b key.Binding) string {
+	return b.Help().Key
+}
+
 func (m Model) shortHelpView() string {
 	return m.help.ShortHelpView(m.keymap.shortHelp(m.core.Mode))
 }
@@ -129,8 +360,17 @@ func (m Model) sessionsEmptyShortHelpView() string {
 	return m.help.ShortHelpView(m.keymap.sessionsEmptyShortHelp())
 }
 
+// helpColumns is how many help groups fit side by side in the help modal;
+// further groups continue below.
+const helpColumns = 3
+
 func (m Model) fullHelpView() string {
-	return m.help.FullHelpView(m.keymap.fullHelp(m.core.Mode))
+	groups := m.keymap.fullHelp(m.core.Mode)
+	blocks := make([]string, 0, (len(groups)+helpColumns-1)/helpColumns)
+	for start := 0; start < len(groups); start += helpColumns {
+		blocks = append(blocks, m.help.FullHelpView(groups[start:min(start+helpColumns, len(groups))]))
+	}
+	return strings.Join(blocks, "\n\n")
 }
 
 func newHelpModel() help.Model {
@@ -26,6 +26,9 @@ type suggestionItem struct {
 	primary     string
 	detail      string
 	actionLabel string
+	// marked shows the row as marked for a bulk action.
+	marked bool
+	pinned bool
 }
 
 func (i suggestionItem) FilterValue() string {
@@ -47,6 +50,12 @@ func (d suggestionDelegate) Render(w io.Writer, m listmodel.Model, index int, it
 	if selected {
 		prefix = "> "
 	}
+	if row.marked {
+		prefix += d.styles.Success.Render(markGlyph) + " "
+	}
+	if row.pinned {
+		prefix += d.styles.Warning.Render(pinGlyph) + " "
+	}
 
 	if row.actionLabel != "" {
 		actionStyle := d.styles.Action
@@ -78,11 +87,18 @@ func (d suggestionDelegate) Render(w io.Writer, m listmodel.Model, index int, it
 	_, _ = fmt.Fprint(w, prefix+strings.Join(parts, " "))
 }
 
+// markGlyph flags rows marked for a bulk action.
+const markGlyph = "✓"
+
+// pinGlyph flags pinned projects and worktrees.
+const pinGlyph = "★"
+
 const compactSessionMinWidth = 95
 
 func newSessionTable(styles Styles) table.Model {
 	columns := []table.Column{
-		{Title: "Project", Width: 40},
+		{Title: "Tag", Width: 12},
+		{Title: "Project", Width: 28},
 		{Title: "Branch", Width: 22},
 		{Title: "Last active", Width: 16},
 	}
@@ -93,6 +109,19 @@ func newSessionTable(styles Styles) table.Model {
 	return t
 }
 
+func newCleanupTable(styles Styles) table.Model {
+	columns := []table.Column{
+		{Title: "", Width: 3},
+		{Title: "Project", Width: 20},
+		{Title: "Workspace", Width: 26},
+		{Title: "Reason", Width: 9},
+		{Title: "Last active", Width: 16},
+	}
+	t := table.New(table.WithColumns(columns), table.WithRows(nil), table.WithFocused(true), table.WithHeight(defaultListSuggestions))
+	t.SetStyles(newSessionTableStyles(styles))
+	return t
+}
+
 func newSessionTableStyles(styles Styles) table.Styles {
 	ts := table.DefaultStyles()
 	headerFg := styles.Body.GetForeground()
@@ -245,25 +274,40 @@ func toItems(rows []suggestionItem) []listmodel.Item {
 }
 
 func (m *Model) applyListStyles() {
-	lists := []*listmodel.Model{&m.projectList, &m.worktreeList, &m.toolList, &m.sessionList, &m.themeList}
+	lists := []*listmodel.Model{&m.projectList, &m.worktreeList, &m.toolList, &m.sessionList, &m.reviewList, &m.landList, &m.recentList, &m.themeList, &m.paletteList}
 	for _, l := range lists {
 		l.SetDelegate(suggestionDelegate{styles: m.styles})
 		l.SetHeight(listHeight(m.listLimit(), len(l.Items())))
 	}
 	m.sessionTable.SetStyles(newSessionTableStyles(m.styles))
 	m.sessionTable.SetHeight(tableHeight(m.listLimit(), len(m.sessionTable.Rows())))
+	m.cleanupTable.SetStyles(newSessionTableStyles(m.styles))
+	m.cleanupTable.SetHeight(tableHeight(m.listLimit(), len(m.cleanupTable.Rows())))
 	if m.width > 0 {
 		m.sessionTable.SetWidth(max(0, m.width-8))
+		m.cleanupTable.SetWidth(max(0, m.width-8))
 	}
 }
 
 func (m *Model) syncProjectList() {
 	rows := make([]suggestionItem, 0, len(m.core.Filtered)+1)
 	for _, dir := range m.core.Filtered {
-		rows = append(rows, suggestionItem{primary: dir.Name, detail: m.displayPath(dir.Path)})
+		detail := m.displayPath(dir.Path)
+		if !dir.Exists {
+			detail += " (missing)"
+		}
+		if len(dir.Tags) > 0 {
+			detail += " #" + strings.Join(dir.Tags, " #")
+		}
+		rows = append(rows, suggestionItem{primary: dir.Name, detail: detail, marked: m.core.MarkedProjects[dir.Path], pinned: dir.Pinned})
 	}
 	if createPath, ok := m.core.CreateProjectPath(); ok {
-		rows = append(rows, suggestionItem{primary: m.displayPath(createPath), actionLabel: "create"})
+		row := suggestionItem{primary: m.displayPath(createPath), actionLabel: "create"}
+		if source, ok := m.core.CloneSource(); ok {
+			row.detail = source
+			row.actionLabel = "clone"
+		}
+		rows = append(rows, row)
 	}
 	m.projectList.SetItems(toItems(rows))
 	m.projectList.SetHeight(listHeight(m.listLimit(), len(rows)))
@@ -273,7 +317,11 @@ func (m *Model) syncProjectList() {
 func (m *Model) syncWorktreeList() {
 	rows := make([]suggestionItem, 0, len(m.core.FilteredWT)+1)
 	for _, wt := range m.core.FilteredWT {
-		rows = append(rows, suggestionItem{primary: m.worktreeDisplayLabel(wt)})
+		row := suggestionItem{primary: m.worktreeDisplayLabel(wt), marked: m.core.MarkedWorktrees[wt.Path], pinned: wt.Pinned}
+		if wt.Unmanaged {
+			row.detail = m.displayPath(wt.Path) + " (unmanaged)"
+		}
+		rows = append(rows, row)
 	}
 	if name, ok := m.core.CreateWorktreeName(); ok {
 		rows = append(rows, suggestionItem{primary: name, actionLabel: "create"})
@@ -296,14 +344,34 @@ func (m *Model) syncToolList() {
 func (m *Model) syncSessionList() {
 	compactRows := make([]suggestionItem, 0, len(m.core.FilteredSessions))
 	tableRows := make([]table.Row, 0, len(m.core.FilteredSessions))
-	for _, session := range m.core.FilteredSessions {
+	tagsOf := m.core.SessionTagger()
+	prevGroup := ""
+	for i, session := range m.core.FilteredSessions {
 		label := core.SessionDisplayLabel(session)
 		if label == "" {
 			label = m.displayPath(session.DirPath)
 		}
-		compactRows = append(compactRows, suggestionItem{primary: label})
+		// Sessions come grouped by tag unless ranked by a query; the tag
+		// heads each run of rows that share it.
+		group := core.TagGroup(tagsOf(session))
+		if i > 0 && group == prevGroup {
+			group = ""
+		} else {
+			prevGroup = group
+		}
+		marked := m.core.MarkedSessions[session.Name]
+		row := suggestionItem{primary: label, marked: marked}
+		if group != "" {
+			row.detail = "#" + group
+		}
+		compactRows = append(compactRows, row)
+		project := m.sessionProjectLabel(session)
+		if marked {
+			project = markGlyph + " " + project
+		}
 		tableRows = append(tableRows, table.Row{
-			m.sessionProjectLabel(session),
+			group,
+			project,
 			m.sessionBranchLabel(session),
 			sessionLastActiveLabel(session.LastActive),
 		})
@@ -317,9 +385,13 @@ func (m *Model) syncSessionList() {
 }
 
 func (m *Model) syncSessionTableCursor(idx int) {
-	total := len(m.sessionTable.Rows())
+	syncTableCursor(&m.sessionTable, idx)
+}
+
+func syncTableCursor(t *table.Model, idx int) {
+	total := len(t.Rows())
 	if total == 0 {
-		m.sessionTable.SetCursor(0)
+		t.SetCursor(0)
 		return
 	}
 	if idx < 0 {
@@ -331,12 +403,84 @@ func (m *Model) syncSessionTableCursor(idx int) {
 
 	// Bubble's table keeps viewport offset when SetCursor is used directly.
 	// Re-anchor at top first, then move to the target row so it's visible.
-	m.sessionTable.GotoTop()
+	t.GotoTop()
 	if idx > 0 {
-		m.sessionTable.MoveDown(idx)
+		t.MoveDown(idx)
 	}
 }
 
+func (m *Model) syncCleanupTable() {
+	rows := make([]table.Row, 0, len(m.core.CleanupCandidates))
+	for _, wt := range m.core.CleanupCandidates {
+		mark := "[ ]"
+		if m.core.CleanupSelected[wt.Worktree.Path] {
+			mark = "[x]"
+		}
+		rows = append(rows, table.Row{
+			mark,
+			filepath.Base(wt.ProjectPath),
+			m.worktreeDisplayLabel(wt.Worktree),
+			string(wt.Reason),
+			sessionLastActiveLabel(wt.LastActive),
+		})
+	}
+	m.cleanupTable.SetRows(rows)
+	m.cleanupTable.SetHeight(tableHeight(m.listLimit(), len(rows)))
+	syncTableCursor(&m.cleanupTable, m.core.CleanupIdx)
+}
+
+func (m *Model) syncRecentList() {
+	rows := make([]suggestionItem, 0, len(m.core.RecentLaunches))
+	for _, recent := range m.core.RecentLaunches {
+		state := "stopped"
+		if recent.Alive {
+			state = "running"
+		}
+		detail := recent.Tool + ", " + sessionLastActiveLabel(recent.OpenedAt) + ", " + state
+		rows = append(rows, suggestionItem{primary: recent.Label(), detail: detail})
+	}
+	m.recentList.SetItems(toItems(rows))
+	m.recentList.SetHeight(listHeight(m.listLimit(), len(rows)))
+	m.recentList.Select(m.core.RecentIdx)
+}
+
+var landStrategyDetails = map[core.LandStrategy]string{
+	core.LandMerge:  "merge commit on the base branch",
+	core.LandSquash: "one commit with all the changes",
+	core.LandRebase: "rebase onto the base branch, then fast-forward",
+}
+
+func (m *Model) syncLandList() {
+	strategies := core.LandStrategies()
+	rows := make([]suggestionItem, 0, len(strategies))
+	for _, strategy := range strategies {
+		rows = append(rows, suggestionItem{primary: string(strategy), detail: landStrategyDetails[strategy]})
+	}
+	m.landList.SetItems(toItems(rows))
+	m.landList.SetHeight(len(rows))
+	m.landList.Select(m.core.LandStrategyIdx)
+}
+
+func (m *Model) syncReviewList() {
+	rows := make([]suggestionItem, 0, len(m.core.ReviewChanges.Files))
+	for _, file := range m.core.ReviewChanges.Files {
+		detail := string(file.Status)
+		if file.Status == core.FileRenamed && file.OldPath != "" {
+			detail = "renamed from " + file.OldPath
+		}
+		rows = append(rows, suggestionItem{primary: file.Path, detail: detail})
+	}
+	m.reviewList.SetItems(toItems(rows))
+	m.reviewList.SetHeight(listHeight(m.reviewListLimit(), len(rows)))
+	m.reviewList.Select(m.core.ReviewIdx)
+}
+
+// reviewListLimit keeps the changed file list short so the diff below it
+// gets most of the box.
+func (m Model) reviewListLimit() int {
+	return max(m.listLimit()/2, 3)
+}
+
 func (m *Model) syncThemeList() {
 	rows := make([]suggestionItem, 0, len(m.filteredThemes))
 	for _, theme := range m.filteredThemes {
@@ -354,5 +498,10 @@ func (m *Model) syncLists() {
 	m.syncWorktreeList()
 	m.syncToolList()
 	m.syncSessionList()
+	m.syncReviewList()
+	m.syncLandList()
+	m.syncCleanupTable()
+	m.syncRecentList()
 	m.syncThemeList()
+	m.syncPaletteList()
 }
@@ -114,6 +114,21 @@ func (m Model) visibleWindow() (start, end int) {
 	return start, end
 }
 
+// VisibleRows renders the rows View shows, along with the index of the
+// first one.
+func (m Model) VisibleRows() (start int, rows []string) {
+	if len(m.items) == 0 || m.delegate == nil {
+		return 0, nil
+	}
+	start, end := m.visibleWindow()
+	for i := start; i < end; i++ {
+		var row bytes.Buffer
+		m.delegate.Render(&row, m, i, m.items[i])
+		rows = append(rows, row.String())
+	}
+	return start, rows
+}
+
 func (m Model) View() string {
 	if len(m.items) == 0 || m.delegate == nil {
 		return ""
@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -28,18 +31,28 @@ type Model struct {
 	toolInput            textinput.Model
 	sessionInput         textinput.Model
 	themeInput           textinput.Model
+	paletteInput         textinput.Model
+	commitInput          textinput.Model
+	tagInput             textinput.Model
 	projectList          listmodel.Model
 	worktreeList         listmodel.Model
 	toolList             listmodel.Model
 	sessionList          listmodel.Model
+	reviewList           listmodel.Model
+	landList             listmodel.Model
 	sessionTable         table.Model
+	cleanupTable         table.Model
+	recentList           listmodel.Model
 	themeList            listmodel.Model
+	paletteList          listmodel.Model
+	paletteCommands      []command
 	spinner              spinner.Model
 	progress             progress.Model
 	toolStartingAt       time.Time
 	toolStartingDuration time.Duration
 	fs                   ports.Filesystem
 	sessions             ports.SessionManager
+	hookRunner           ports.HookRunner
 	maxDepth             int
 	width                int
 	height               int
@@ -52,14 +65,84 @@ type Model struct {
 	themePickerPrevIdx   int
 	showHelp             bool
 	showThemePicker      bool
+	showPalette          bool
+	lastClick            mouseClick
 	homeDir              string
 	help                 help.Model
 	viewport             viewport.Model
 	viewportContentSig   string
 	keymap               keyMap
+	sessionWatch         *subscriptionSlot[ports.SessionSubscription]
+	projectWatch         *subscriptionSlot[ports.ProjectSubscription]
+	projectScan          *backgroundTask
+	projectClone         *backgroundTask
+	worktreeSetup        *backgroundTask
 }
 
-func New(roots []string, fs ports.Filesystem, sessions ports.SessionManager) Model {
+// Option configures a Model created by New.
+type Option func(*Model)
+
+// WithMaxDepth sets how many directory levels project scans descend below
+// each root.
+func WithMaxDepth(depth int) Option {
+	return func(m *Model) {
+		if depth > 0 {
+			m.maxDepth = depth
+		}
+	}
+}
+
+// WithHooks registers lifecycle hooks and the runner that executes them.
+func WithHooks(hooks core.Hooks, runner ports.HookRunner) Option {
+	return func(m *Model) {
+		m.core.Hooks = hooks
+		m.hookRunner = runner
+	}
+}
+
+// WithTagRules sets the config rules that tag projects.
+func WithTagRules(rules core.TagRules) Option {
+	return func(m *Model) {
+		m.core.TagRules = rules
+	}
+}
+
+// WithInactiveFor sets how long a worktree may go unused before the
+// cleanup view offers it.
+func WithInactiveFor(inactiveFor time.Duration) Option {
+	return func(m *Model) {
+		if inactiveFor > 0 {
+			m.core.CleanupInactiveFor = inactiveFor
+		}
+	}
+}
+
+// WithKeyBindings replaces the default keys of the remapped actions.
+func WithKeyBindings(bindings core.KeyBindings) Option {
+	return func(m *Model) {
+		m.keymap = newKeyMap(bindings)
+		m.themeList.KeyMap.CursorUp = m.keymap.Up
+		m.themeList.KeyMap.CursorDown = m.keymap.Down
+	}
+}
+
+// WithStart opens the TUI past project selection, at the step start names.
+func WithStart(start core.StartState) Option {
+	return func(m *Model) {
+		m.core = core.StartAt(m.core, start)
+		switch m.core.Mode {
+		case core.ModeWorktree:
+			m.input.Blur()
+			m.worktreeInput.SetValue(start.WorktreeQuery)
+			m.worktreeInput.Focus()
+		case core.ModeTool:
+			m.input.Blur()
+			m.toolInput.Focus()
+		}
+	}
+}
+
+func New(roots []string, fs ports.Filesystem, sessions ports.SessionManager, opts ...Option) Model {
 	ti := textinput.New()
 	ti.Prompt = ""
 	ti.Focus()
@@ -72,6 +155,12 @@ func New(roots []string, fs ports.Filesystem, sessions ports.SessionManager) Mod
 	sti.Prompt = ""
 	thi := textinput.New()
 	thi.Prompt = ""
+	cti := textinput.New()
+	cti.Prompt = ""
+	tgi := textinput.New()
+	tgi.Prompt = ""
+	pti := textinput.New()
+	pti.Prompt = ""
 
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
@@ -83,7 +172,7 @@ func New(roots []string, fs ports.Filesystem, sessions ports.SessionManager) Mod
 	allThemes := Themes()
 	styles := NewStyles(allThemes[0])
 	h := newHelpModel()
-	km := newKeyMap()
+	km := newKeyMap(nil)
 	vp := viewport.New(0, 0)
 
 	m := Model{
@@ -93,17 +182,25 @@ func New(roots []string, fs ports.Filesystem, sessions ports.SessionManager) Mod
 		toolInput:          tti,
 		sessionInput:       sti,
 		themeInput:         thi,
+		paletteInput:       pti,
+		commitInput:        cti,
+		tagInput:           tgi,
 		projectList:        newSuggestionList(styles),
 		worktreeList:       newSuggestionList(styles),
 		toolList:           newSuggestionList(styles),
 		sessionList:        newSuggestionList(styles),
+		reviewList:         newSuggestionList(styles),
+		landList:           newSuggestionList(styles),
 		sessionTable:       newSessionTable(styles),
+		cleanupTable:       newCleanupTable(styles),
+		recentList:         newSuggestionList(styles),
 		themeList:          newSuggestionList(styles),
+		paletteList:        newSuggestionList(styles),
 		spinner:            sp,
 		progress:           pr,
 		fs:                 fs,
 		sessions:           sessions,
-		maxDepth:           2,
+		maxDepth:           core.DefaultScanDepth,
 		styles:             styles,
 		themes:             allThemes,
 		filteredThemes:     allThemes,
@@ -113,6 +210,14 @@ func New(roots []string, fs ports.Filesystem, sessions ports.SessionManager) Mod
 		help:               h,
 		viewport:           vp,
 		keymap:             km,
+		sessionWatch:       &subscriptionSlot[ports.SessionSubscription]{},
+		projectWatch:       &subscriptionSlot[ports.ProjectSubscription]{},
+		projectScan:        &backgroundTask{},
+		projectClone:       &backgroundTask{},
+		worktreeSetup:      &backgroundTask{},
+	}
+	for _, opt := range opts {
+		opt(&m)
 	}
 	m.syncProgressTheme(allThemes[0])
 	m.applyHelpStyles()
@@ -126,6 +231,9 @@ func (m *Model) blurInputs() {
 	m.toolInput.Blur()
 	m.sessionInput.Blur()
 	m.themeInput.Blur()
+	m.paletteInput.Blur()
+	m.commitInput.Blur()
+	m.tagInput.Blur()
 }
 
 func (m *Model) restoreInputFocus() {
@@ -136,8 +244,14 @@ func (m *Model) restoreInputFocus() {
 		m.worktreeInput.Focus()
 	case core.ModeTool:
 		m.toolInput.Focus()
+	case core.ModeReview:
+		if m.core.ReviewCommitting {
+			m.commitInput.Focus()
+		}
 	case core.ModeSessions:
 		m.sessionInput.Focus()
+	case core.ModeProjectTag:
+		m.tagInput.Focus()
 	}
 }
 
@@ -146,7 +260,7 @@ func (m Model) isViewportActive() bool {
 		return true
 	}
 	switch m.core.Mode {
-	case core.ModeError, core.ModeProjectDeleteConfirm, core.ModeWorktreeDeleteConfirm:
+	case core.ModeError, core.ModeProjectDeleteConfirm, core.ModeWorktreeDeleteConfirm, core.ModeSessionMigrateConfirm:
 		return true
 	default:
 		return false
@@ -197,6 +311,35 @@ func (m *Model) updateViewport(msg tea.KeyMsg) tea.Cmd {
 	}
 }
 
+// isDiffScrollActive reports whether page keys scroll the review diff; they
+// stay with the commit message and discard prompt while those are open.
+func (m Model) isDiffScrollActive() bool {
+	return m.core.Mode == core.ModeReview && !m.core.ReviewCommitting && m.core.ReviewDiscardPath == ""
+}
+
+func (m *Model) scrollDiff(msg tea.KeyMsg) {
+	m.syncDiffViewport()
+	switch {
+	case key.Matches(msg, m.keymap.PageDown):
+		m.viewport.PageDown()
+	case key.Matches(msg, m.keymap.PageUp):
+		m.viewport.PageUp()
+	}
+}
+
+// syncDiffViewport loads the selected file's diff into the viewport, going
+// back to the top when another file is selected.
+func (m *Model) syncDiffViewport() {
+	width, height := m.diffViewportSize()
+	m.viewport.Width = width
+	m.viewport.Height = height
+	m.viewport.SetContent(m.diffContent(width))
+	if signature := m.diffSignature(); signature != m.viewportContentSig {
+		m.viewport.GotoTop()
+		m.viewportContentSig = signature
+	}
+}
+
 func (m *Model) syncViewportContent() {
 	content, signature, ok := m.currentViewportContent()
 	if !ok {
@@ -214,6 +357,136 @@ func (m *Model) syncViewportContent() {
 	m.viewportContentSig = signature
 }
 
+// applyKeyAction hands a key action to core and moves the input focus to
+// follow the mode change. The caller runs the returned effects.
+func (m *Model) applyKeyAction(action core.KeyAction) ([]core.Effect, bool) {
+	prevMode := m.core.Mode
+	wasCommitting := m.core.ReviewCommitting
+
+	coreModel, effects, handled := core.UpdateKey(m.core, action)
+	m.core = coreModel
+	m.syncLists()
+	if spec := extractSessionSpec(effects); spec != nil {
+		m.SelectedSpec = spec
+	}
+
+	if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeWorktree {
+		m.input.Blur()
+		m.worktreeInput.Focus()
+	}
+	if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeProjectDeleteConfirm {
+		m.input.Blur()
+	}
+	if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeProjectCloning {
+		m.input.Blur()
+	}
+	if prevMode == core.ModeProjectCloning && m.core.Mode == core.ModeBrowsing {
+		m.input.Focus()
+	}
+	if prevMode == core.ModeWorktree && m.core.Mode == core.ModeBrowsing {
+		m.worktreeInput.SetValue("")
+		m.worktreeInput.Blur()
+		m.input.Focus()
+	}
+	if prevMode == core.ModeWorktree && m.core.Mode == core.ModeTool {
+		m.worktreeInput.Blur()
+		m.toolInput.SetValue("")
+		m.toolInput.Focus()
+	}
+	if prevMode == core.ModeTool && m.core.Mode == core.ModeToolStarting {
+		m.toolInput.Blur()
+		m.beginToolStartingProgress(time.Now())
+	}
+	if prevMode == core.ModeToolStarting && m.core.Mode != core.ModeToolStarting {
+		if m.core.Mode == core.ModeTool {
+			m.toolInput.Focus()
+		}
+		m.toolStartingAt = time.Time{}
+		m.toolStartingDuration = 0
+	}
+	if prevMode == core.ModeWorktree && m.core.Mode == core.ModeWorktreeDeleteConfirm {
+		m.worktreeInput.Blur()
+	}
+	if prevMode != core.ModeSessions && m.core.Mode == core.ModeSessions {
+		m.blurInputs()
+		m.sessionInput.SetValue("")
+		m.sessionInput.Focus()
+	}
+	if prevMode == core.ModeSessions && m.core.Mode != core.ModeSessions {
+		m.sessionInput.SetValue("")
+		m.sessionInput.Blur()
+		if m.core.Mode == core.ModeBrowsing {
+			m.input.Focus()
+		}
+		if m.core.Mode == core.ModeWorktree {
+			m.worktreeInput.Focus()
+		}
+		if m.core.Mode == core.ModeTool {
+			m.toolInput.Focus()
+		}
+	}
+	if prevMode == core.ModeTool && m.core.Mode == core.ModeWorktree {
+		m.toolInput.SetValue("")
+		m.toolInput.Blur()
+		m.worktreeInput.Focus()
+	}
+	if prevMode == core.ModeWorktreeDeleteConfirm && m.core.Mode == core.ModeWorktree {
+		m.worktreeInput.Focus()
+	}
+	if prevMode == core.ModeWorktree && m.core.Mode == core.ModeWorktreeLand {
+		m.worktreeInput.Blur()
+	}
+	if prevMode == core.ModeWorktreeLand && m.core.Mode == core.ModeWorktree {
+		m.worktreeInput.Focus()
+	}
+	if prevMode == core.ModeWorktree && m.core.Mode == core.ModeReview {
+		m.worktreeInput.Blur()
+	}
+	if prevMode == core.ModeReview && m.core.Mode == core.ModeWorktree {
+		m.worktreeInput.Focus()
+	}
+	if !wasCommitting && m.core.ReviewCommitting {
+		m.commitInput.SetValue("")
+		m.commitInput.Focus()
+	}
+	if wasCommitting && !m.core.ReviewCommitting {
+		m.commitInput.Blur()
+	}
+	if prevMode == core.ModeProjectDeleteConfirm && m.core.Mode == core.ModeBrowsing {
+		m.input.Focus()
+	}
+	if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeSessionMigrateConfirm {
+		m.input.Blur()
+	}
+	if prevMode == core.ModeSessionMigrateConfirm && m.core.Mode == core.ModeBrowsing {
+		m.input.Focus()
+	}
+	if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeCleanup {
+		m.input.Blur()
+	}
+	if prevMode == core.ModeCleanup && m.core.Mode == core.ModeBrowsing {
+		m.input.Focus()
+	}
+	if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeProjectTag {
+		m.input.Blur()
+		m.tagInput.SetValue(m.core.TagInput)
+		m.tagInput.CursorEnd()
+		m.tagInput.Focus()
+	}
+	if prevMode == core.ModeProjectTag && m.core.Mode == core.ModeBrowsing {
+		m.tagInput.Blur()
+		m.input.Focus()
+	}
+	if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeRecent {
+		m.input.Blur()
+	}
+	if prevMode == core.ModeRecent && m.core.Mode == core.ModeBrowsing {
+		m.input.Focus()
+	}
+
+	return effects, handled
+}
+
 func (m *Model) openThemePicker() {
 	if len(m.themes) == 0 {
 		return
@@ -354,7 +627,7 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
-	if !m.showHelp && !m.isViewportActive() && m.viewportContentSig != "" {
+	if !m.showHelp && !m.isViewportActive() && m.core.Mode != core.ModeReview && m.viewportContentSig != "" {
 		m.viewportContentSig = ""
 	}
 
@@ -371,6 +644,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
+	case tea.MouseMsg:
+		cmd := m.updateMouse(msg)
+		return m, cmd
+
 	case tea.KeyMsg:
 		if m.showHelp {
 			switch {
@@ -411,6 +688,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		if m.showPalette {
+			switch {
+			case key.Matches(msg, m.keymap.Back):
+				m.closePalette()
+				return m, nil
+			case key.Matches(msg, m.keymap.Select):
+				cmd := m.runPaletteCommand()
+				return m, cmd
+			case key.Matches(msg, m.keymap.Up):
+				m.movePaletteSelection(-1)
+				return m, nil
+			case key.Matches(msg, m.keymap.Down):
+				m.movePaletteSelection(1)
+				return m, nil
+			case key.Matches(msg, m.keymap.Quit):
+				return m, tea.Quit
+			}
+
+			var cmd tea.Cmd
+			m.paletteInput, cmd = m.paletteInput.Update(msg)
+			m.refreshPaletteFilter()
+			return m, cmd
+		}
+
+		if key.Matches(msg, m.keymap.Palette) && m.core.Mode != core.ModeLoading {
+			m.openPalette()
+			return m, nil
+		}
+
 		if key.Matches(msg, m.keymap.Toggle) && m.core.Mode != core.ModeLoading {
 			m.showHelp = true
 			m.blurInputs()
@@ -427,79 +733,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
-		prevMode := m.core.Mode
+		if m.isDiffScrollActive() && key.Matches(msg, m.keymap.PageUp, m.keymap.PageDown) {
+			m.scrollDiff(msg)
+			return m, nil
+		}
 
 		action, mapped := m.keymap.actionForCore(msg)
-		coreModel, effects, handled := core.UpdateKey(m.core, action)
+		effects, handled := m.applyKeyAction(action)
 		if !mapped {
 			handled = false
 		}
-		m.core = coreModel
-		m.syncLists()
-		if spec := extractSessionSpec(effects); spec != nil {
-			m.SelectedSpec = spec
-		}
-
-		if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeWorktree {
-			m.input.Blur()
-			m.worktreeInput.Focus()
-		}
-		if prevMode == core.ModeBrowsing && m.core.Mode == core.ModeProjectDeleteConfirm {
-			m.input.Blur()
-		}
-		if prevMode == core.ModeWorktree && m.core.Mode == core.ModeBrowsing {
-			m.worktreeInput.SetValue("")
-			m.worktreeInput.Blur()
-			m.input.Focus()
-		}
-		if prevMode == core.ModeWorktree && m.core.Mode == core.ModeTool {
-			m.worktreeInput.Blur()
-			m.toolInput.SetValue("")
-			m.toolInput.Focus()
-		}
-		if prevMode == core.ModeTool && m.core.Mode == core.ModeToolStarting {
-			m.toolInput.Blur()
-			m.beginToolStartingProgress(time.Now())
-		}
-		if prevMode == core.ModeToolStarting && m.core.Mode != core.ModeToolStarting {
-			if m.core.Mode == core.ModeTool {
-				m.toolInput.Focus()
-			}
-			m.toolStartingAt = time.Time{}
-			m.toolStartingDuration = 0
-		}
-		if prevMode == core.ModeWorktree && m.core.Mode == core.ModeWorktreeDeleteConfirm {
-			m.worktreeInput.Blur()
-		}
-		if prevMode != core.ModeSessions && m.core.Mode == core.ModeSessions {
-			m.blurInputs()
-			m.sessionInput.SetValue("")
-			m.sessionInput.Focus()
-		}
-		if prevMode == core.ModeSessions && m.core.Mode != core.ModeSessions {
-			m.sessionInput.SetValue("")
-			m.sessionInput.Blur()
-			if m.core.Mode == core.ModeBrowsing {
-				m.input.Focus()
-			}
-			if m.core.Mode == core.ModeWorktree {
-				m.worktreeInput.Focus()
-			}
-			if m.core.Mode == core.ModeTool {
-				m.toolInput.Focus()
-			}
-		}
-		if prevMode == core.ModeTool && m.core.Mode == core.ModeWorktree {
-			m.toolInput.SetValue("")
-			m.toolInput.Blur()
-			m.worktreeInput.Focus()
-		}
-		if prevMode == core.ModeWorktreeDeleteConfirm && m.core.Mode == core.ModeWorktree {
-			m.worktreeInput.Focus()
-		}
-		if prevMode == core.ModeProjectDeleteConfirm && m.core.Mode == core.ModeBrowsing {
-			m.input.Focus()
-		}
 
 		if !handled {
 			var cmd tea.Cmd
@@ -525,6 +768,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				coreModel, effects := core.Update(m.core, core.MsgToolQueryChanged{Query: m.toolInput.Value()})
 				m.core = coreModel
 				cmds = append(cmds, m.runEffects(effects))
+			case core.ModeReview:
+				if !m.core.ReviewCommitting {
+					break
+				}
+				m.commitInput, cmd = m.commitInput.Update(msg)
+				cmds = append(cmds, cmd)
+
+				coreModel, effects := core.Update(m.core, core.MsgCommitMessageChanged{Message: m.commitInput.Value()})
+				m.core = coreModel
+				cmds = append(cmds, m.runEffects(effects))
 			case core.ModeSessions:
 				m.sessionInput, cmd = m.sessionInput.Update(msg)
 				cmds = append(cmds, cmd)
@@ -532,6 +785,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				coreModel, effects := core.Update(m.core, core.MsgSessionQueryChanged{Query: m.sessionInput.Value()})
 				m.core = coreModel
 				cmds = append(cmds, m.runEffects(effects))
+			case core.ModeProjectTag:
+				m.tagInput, cmd = m.tagInput.Update(msg)
+				cmds = append(cmds, cmd)
+
+				coreModel, effects := core.Update(m.core, core.MsgTagInputChanged{Input: m.tagInput.Value()})
+				m.core = coreModel
+				cmds = append(cmds, m.runEffects(effects))
 			}
 		}
 
@@ -539,7 +799,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.runEffects(effects))
 		return m, tea.Batch(cmds...)
 
+	case scanProgressMsg:
+		if !m.projectScan.isCurrent(msg.gen) {
+			return m, nil
+		}
+		var coreMsg core.Msg = core.MsgScanProgress{Dirs: msg.dirs}
+		if msg.cached {
+			coreMsg = core.MsgProjectIndexLoaded{Dirs: msg.dirs}
+		}
+		coreModel, effects := core.Update(m.core, coreMsg)
+		m.core = coreModel
+		m.syncLists()
+		return m, tea.Batch(m.runEffects(effects), waitForScanCmd(msg.gen, msg.updates))
+
 	case scanCompletedMsg:
+		if msg.gen != 0 {
+			if !m.projectScan.isCurrent(msg.gen) {
+				return m, nil
+			}
+			m.projectScan.finish(msg.gen)
+		}
 		coreModel, effects := core.Update(m.core, core.MsgScanCompleted{
 			Dirs: msg.dirs,
 			Err:  msg.err,
@@ -563,6 +842,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd := m.runEffects(effects)
 		return m, cmd
 
+	case cloneProgressMsg:
+		if !m.projectClone.isCurrent(msg.gen) {
+			return m, nil
+		}
+		coreModel, effects := core.Update(m.core, core.MsgCloneProgress{Progress: msg.progress})
+		m.core = coreModel
+		return m, tea.Batch(m.runEffects(effects), waitForCloneCmd(msg.gen, msg.updates))
+
+	case cloneCompletedMsg:
+		if !m.projectClone.isCurrent(msg.gen) {
+			return m, nil
+		}
+		m.projectClone.finish(msg.gen)
+		return m.applyProjectCloned(core.MsgProjectCloned{ProjectPath: m.core.CloningPath, Err: msg.err})
+
+	case core.MsgProjectCloned:
+		return m.applyProjectCloned(msg)
+
 	case projectDeletedMsg:
 		coreModel, effects := core.Update(m.core, core.MsgProjectDeleted{
 			ProjectPath: msg.projectPath,
@@ -596,26 +893,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case worktreeCreatedMsg:
 		coreModel, effects := core.Update(m.core, core.MsgWorktreeCreated{
-			Path: msg.path,
-			Err:  msg.err,
+			Path:   msg.path,
+			Branch: msg.branch,
+			Err:    msg.err,
 		})
 		m.core = coreModel
 		m.syncLists()
 		if spec := extractSessionSpec(effects); spec != nil {
 			m.SelectedSpec = spec
 		}
-		if m.core.Mode == core.ModeTool {
+		if m.core.Mode == core.ModeWorktreeSetup {
 			m.worktreeInput.Blur()
-			m.toolInput.SetValue("")
-			m.toolInput.Focus()
 		}
 		cmd := m.runEffects(effects)
 		return m, cmd
 
+	case setupOutputMsg:
+		if !m.worktreeSetup.isCurrent(msg.gen) {
+			return m, nil
+		}
+		coreModel, effects := core.Update(m.core, core.MsgWorktreeSetupOutput{Lines: msg.lines})
+		m.core = coreModel
+		return m, tea.Batch(m.runEffects(effects), waitForSetupCmd(msg.gen, msg.updates))
+
+	case setupDoneMsg:
+		if !m.worktreeSetup.isCurrent(msg.gen) {
+			return m, nil
+		}
+		m.worktreeSetup.finish(msg.gen)
+		return m.applySetupDone(core.MsgWorktreeSetupDone{WorktreePath: m.core.SelectedWorktreePath, Err: msg.err})
+
+	case core.MsgWorktreeSetupDone:
+		return m.applySetupDone(msg)
+
 	case worktreeDeletedMsg:
 		coreModel, effects := core.Update(m.core, core.MsgWorktreeDeleted{
-			Path: msg.path,
-			Err:  msg.err,
+			Path:      msg.path,
+			Err:       msg.err,
+			BranchErr: msg.branchErr,
 		})
 		m.core = coreModel
 		m.syncLists()
@@ -660,44 +975,273 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd := m.runEffects(effects)
 		return m, cmd
 
-	case sessionsLoadedMsg:
-		coreModel, effects := core.Update(m.core, core.MsgSessionsLoaded{
-			Sessions: msg.sessions,
-			Err:      msg.err,
-		})
+	case core.MsgLegacySessionsFound:
+		coreModel, effects := core.Update(m.core, msg)
 		m.core = coreModel
-		m.syncLists()
 		cmd := m.runEffects(effects)
 		return m, cmd
 
-	case sessionAttachedMsg:
-		if msg.err == nil {
-			m.SelectedSpec = nil
-			m.SelectedSessionName = msg.session.Name
-			return m, tea.Quit
-		}
-		m.core.Mode = core.ModeError
-		m.core.Err = msg.err
-		return m, nil
-	}
+	case core.MsgBareRepositoryChecked:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
 
-	return m, nil
-}
+	case core.MsgBranchesChecked:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
 
-type scanCompletedMsg struct {
-	dirs []core.DirEntry
-	err  error
-}
+	case core.MsgWorktreesRefreshed:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
 
-type projectCreatedMsg struct {
-	projectPath string
-	err         error
-}
+	case core.MsgWorktreeAdopted:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
 
-type projectDeletedMsg struct {
-	projectPath string
-	err         error
-}
+	case core.MsgChangesLoaded, core.MsgFileDiffLoaded, core.MsgFileDiscarded, core.MsgChangesCommitted, core.MsgEditorClosed:
+		coreModel, effects := core.Update(m.core, msg.(core.Msg))
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case core.MsgProjectsDeleted:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		m.input.Focus()
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case core.MsgSessionsKilled:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case core.MsgWorktreesDeleted:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		m.worktreeInput.Focus()
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case core.MsgSessionsStarted:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		if spec := extractSessionSpec(effects); spec != nil {
+			m.SelectedSpec = spec
+		}
+		if m.core.Mode == core.ModeTool {
+			m.toolInput.Focus()
+			m.toolStartingAt = time.Time{}
+			m.toolStartingDuration = 0
+		}
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case core.MsgCleanupLoaded, core.MsgWorktreesCleaned, core.MsgRecentLoaded, core.MsgPinsLoaded, core.MsgPinSaved, core.MsgTagsLoaded, core.MsgProjectTagsSaved, core.MsgSessionsMigrated:
+		coreModel, effects := core.Update(m.core, msg.(core.Msg))
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case core.MsgWorktreeLanded:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		m.syncLists()
+		if m.core.Mode == core.ModeWorktree {
+			m.worktreeInput.Focus()
+		}
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case core.MsgHookFinished:
+		coreModel, effects := core.Update(m.core, msg)
+		m.core = coreModel
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case projectWatchStartedMsg:
+		closePrevious := m.projectWatch.replace(msg.sub)
+		return m, tea.Batch(closePrevious, waitForProjectEventCmd(msg.sub))
+
+	case projectEventMsg:
+		if !m.projectWatch.isCurrent(msg.sub) {
+			return m, nil
+		}
+		cmds = append(cmds, waitForProjectEventCmd(msg.sub))
+		if len(msg.event.Added) > 0 || len(msg.event.Removed) > 0 {
+			coreModel, effects := core.Update(m.core, core.MsgProjectsChanged{
+				Added:   msg.event.Added,
+				Removed: msg.event.Removed,
+			})
+			m.core = coreModel
+			cmds = append(cmds, m.runEffects(effects))
+		}
+		if msg.event.Worktrees {
+			coreModel, effects := core.Update(m.core, core.MsgWorktreesChanged{})
+			m.core = coreModel
+			cmds = append(cmds, m.runEffects(effects))
+		}
+		m.syncLists()
+		return m, tea.Batch(cmds...)
+
+	case sessionsLoadedMsg:
+		coreModel, effects := core.Update(m.core, core.MsgSessionsLoaded{
+			Sessions: msg.sessions,
+			Now:      time.Now(),
+			Err:      msg.err,
+		})
+		m.core = coreModel
+		m.syncLists()
+		cmd := m.runEffects(effects)
+		return m, cmd
+
+	case sessionWatchStartedMsg:
+		if m.core.Mode != core.ModeSessions {
+			return m, closeSubscriptionCmd(msg.sub)
+		}
+		closePrevious := m.sessionWatch.replace(msg.sub)
+		return m, tea.Batch(closePrevious, waitForSessionEventCmd(msg.sub))
+
+	case sessionEventMsg:
+		if !m.sessionWatch.isCurrent(msg.sub) {
+			return m, nil
+		}
+		coreModel, effects := core.Update(m.core, core.MsgSessionsChanged{Event: msg.event})
+		m.core = coreModel
+		m.syncLists()
+		return m, tea.Batch(m.runEffects(effects), waitForSessionEventCmd(msg.sub))
+
+	case sessionAttachedMsg:
+		if msg.err == nil {
+			m.SelectedSpec = nil
+			m.SelectedSessionName = msg.session.Name
+			return m, tea.Quit
+		}
+		m.core.Mode = core.ModeError
+		m.core.Err = msg.err
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m Model) applySetupDone(msg core.MsgWorktreeSetupDone) (tea.Model, tea.Cmd) {
+	coreModel, effects := core.Update(m.core, msg)
+	m.core = coreModel
+	m.syncLists()
+	if m.core.Mode == core.ModeTool {
+		m.toolInput.SetValue("")
+		m.toolInput.Focus()
+	}
+	cmd := m.runEffects(effects)
+	return m, cmd
+}
+
+func (m Model) applyProjectCloned(msg core.MsgProjectCloned) (tea.Model, tea.Cmd) {
+	coreModel, effects := core.Update(m.core, msg)
+	m.core = coreModel
+	m.syncLists()
+	switch m.core.Mode {
+	case core.ModeWorktree:
+		m.worktreeInput.Focus()
+	case core.ModeBrowsing:
+		m.input.Focus()
+	}
+	cmd := m.runEffects(effects)
+	return m, cmd
+}
+
+type scanCompletedMsg struct {
+	gen  int
+	dirs []core.DirEntry
+	err  error
+}
+
+type scanProgressMsg struct {
+	gen     int
+	dirs    []core.DirEntry
+	cached  bool
+	updates <-chan scanUpdate
+}
+
+// scanUpdate is a batch of newly found projects, the cached index when
+// cached is set, or the final result of a streaming scan when done is set.
+type scanUpdate struct {
+	dirs   []core.DirEntry
+	err    error
+	cached bool
+	done   bool
+}
+
+const scanUpdateBuffer = 16
+
+type projectCreatedMsg struct {
+	projectPath string
+	err         error
+}
+
+type cloneProgressMsg struct {
+	gen      int
+	progress core.CloneProgress
+	updates  <-chan cloneUpdate
+}
+
+type cloneCompletedMsg struct {
+	gen int
+	err error
+}
+
+// cloneUpdate is a progress report of a running clone, or its result when
+// done is set.
+type cloneUpdate struct {
+	progress core.CloneProgress
+	err      error
+	done     bool
+}
+
+type setupOutputMsg struct {
+	gen     int
+	lines   []string
+	updates <-chan setupUpdate
+}
+
+type setupDoneMsg struct {
+	gen int
+	err error
+}
+
+// setupUpdate is a line of worktree setup output, or the result of the setup
+// when done is set.
+type setupUpdate struct {
+	line string
+	err  error
+	done bool
+}
+
+const setupUpdateBuffer = 64
+
+type projectDeletedMsg struct {
+	projectPath string
+	err         error
+}
 
 type worktreesLoadedMsg struct {
 	worktrees []core.Worktree
@@ -706,13 +1250,15 @@ type worktreesLoadedMsg struct {
 }
 
 type worktreeCreatedMsg struct {
-	path string
-	err  error
+	path   string
+	branch string
+	err    error
 }
 
 type worktreeDeletedMsg struct {
-	path string
-	err  error
+	path      string
+	err       error
+	branchErr error
 }
 
 type sessionsLoadedMsg struct {
@@ -725,25 +1271,95 @@ type sessionAttachedMsg struct {
 	err     error
 }
 
+type sessionWatchStartedMsg struct {
+	sub ports.SessionSubscription
+}
+
+type sessionEventMsg struct {
+	sub   ports.SessionSubscription
+	event string
+}
+
+type projectWatchStartedMsg struct {
+	sub ports.ProjectSubscription
+}
+
+type projectEventMsg struct {
+	sub   ports.ProjectSubscription
+	event ports.ProjectEvent
+}
+
 var errNoSessions = errors.New("session manager not configured")
 
 func (m Model) runEffects(effects []core.Effect) tea.Cmd {
 	var cmds []tea.Cmd
+	// Blocking hooks run one after another before everything else.
+	var blocking []tea.Cmd
 
 	for _, eff := range effects {
 		switch e := eff.(type) {
+		case core.EffRunHook:
+			if e.Payload.Event.Blocking() {
+				blocking = append(blocking, m.runHookCmd(e))
+			} else {
+				cmds = append(cmds, m.runHookCmd(e))
+			}
 		case core.EffScanDirs:
 			cmds = append(cmds, m.scanDirsCmd(e.Roots))
 		case core.EffCreateProject:
 			cmds = append(cmds, m.createProjectCmd(e.Path))
+		case core.EffCloneProject:
+			cmds = append(cmds, m.cloneProjectCmd(e.Source, e.Path))
+		case core.EffCheckBareRepository:
+			cmds = append(cmds, m.checkBareRepositoryCmd(e.Path))
+		case core.EffCancelClone:
+			cmds = append(cmds, m.cancelCloneCmd())
 		case core.EffDeleteProject:
 			cmds = append(cmds, m.deleteProjectCmd(e.ProjectPath))
+		case core.EffDeleteProjects:
+			cmds = append(cmds, m.deleteProjectsCmd(e.ProjectPaths))
 		case core.EffLoadWorktrees:
-			cmds = append(cmds, m.loadWorktreesCmd(e.ProjectPath))
+			cmds = append(cmds, m.loadWorktreesCmd(e.ProjectPath, e.All))
 		case core.EffCreateWorktree:
 			cmds = append(cmds, m.createWorktreeCmd(e.ProjectPath, e.BranchName))
+		case core.EffAdoptWorktree:
+			cmds = append(cmds, m.adoptWorktreeCmd(e.ProjectPath, e.WorktreePath))
+		case core.EffLandWorktree:
+			cmds = append(cmds, m.landWorktreeCmd(e.ProjectPath, e.WorktreePath, e.Strategy))
+		case core.EffSetupWorktree:
+			cmds = append(cmds, m.setupWorktreeCmd(e.ProjectPath, e.WorktreePath))
+		case core.EffCancelSetup:
+			cmds = append(cmds, m.cancelSetupCmd())
 		case core.EffDeleteWorktree:
-			cmds = append(cmds, m.deleteWorktreeCmd(e.ProjectPath, e.WorktreePath))
+			cmds = append(cmds, m.deleteWorktreeCmd(e))
+		case core.EffDeleteWorktrees:
+			cmds = append(cmds, m.deleteWorktreesCmd(e))
+		case core.EffCheckBranchesMerged:
+			cmds = append(cmds, m.checkBranchesMergedCmd(e))
+		case core.EffLoadCleanup:
+			cmds = append(cmds, m.loadCleanupCmd(e.ProjectPaths))
+		case core.EffLoadRecent:
+			cmds = append(cmds, m.loadRecentCmd())
+		case core.EffLoadPins:
+			cmds = append(cmds, m.loadPinsCmd())
+		case core.EffSetPinned:
+			cmds = append(cmds, m.setPinnedCmd(e.Path, e.Pinned))
+		case core.EffLoadTags:
+			cmds = append(cmds, m.loadTagsCmd())
+		case core.EffSetProjectTags:
+			cmds = append(cmds, m.setProjectTagsCmd(e.ProjectPath, e.Tags))
+		case core.EffCleanupWorktrees:
+			cmds = append(cmds, m.cleanupWorktreesCmd(e.Worktrees, e.DeleteBranches))
+		case core.EffLoadChanges:
+			cmds = append(cmds, m.loadChangesCmd(e.WorktreePath))
+		case core.EffLoadFileDiff:
+			cmds = append(cmds, m.loadFileDiffCmd(e.WorktreePath, e.MergeBase, e.File))
+		case core.EffDiscardFile:
+			cmds = append(cmds, m.discardFileCmd(e.WorktreePath, e.MergeBase, e.File))
+		case core.EffCommitAll:
+			cmds = append(cmds, m.commitAllCmd(e.WorktreePath, e.Message))
+		case core.EffOpenEditor:
+			cmds = append(cmds, openEditorCmd(e.WorktreePath, e.Path))
 		case core.EffPrewarmAllTools:
 			cmds = append(cmds, m.prewarmAllToolsCmd(e.DirPath, e.Tools))
 		case core.EffCheckToolReady:
@@ -752,6 +1368,22 @@ func (m Model) runEffects(effects []core.Effect) tea.Cmd {
 			cmds = append(cmds, m.listSessionsCmd())
 		case core.EffAttachSession:
 			cmds = append(cmds, m.attachSessionCmd(e.Session))
+		case core.EffKillSessions:
+			cmds = append(cmds, m.killSessionsCmd(e.Sessions))
+		case core.EffStartSessions:
+			cmds = append(cmds, m.startSessionsCmd(e.Specs))
+		case core.EffWatchSessions:
+			cmds = append(cmds, m.watchSessionsCmd())
+		case core.EffUnwatchSessions:
+			cmds = append(cmds, m.unwatchSessionsCmd())
+		case core.EffWatchProjects:
+			cmds = append(cmds, m.watchProjectsCmd(e.Roots))
+		case core.EffRefreshWorktrees:
+			cmds = append(cmds, m.refreshWorktreesCmd(e.ProjectPath, e.All))
+		case core.EffFindLegacySessions:
+			cmds = append(cmds, m.findLegacySessionsCmd(e.ProjectPaths))
+		case core.EffMigrateSessions:
+			cmds = append(cmds, m.migrateSessionsCmd(e.Sessions))
 		case core.EffOpenSession:
 			cmds = append(cmds, tea.Quit)
 		case core.EffQuit:
@@ -759,6 +1391,9 @@ func (m Model) runEffects(effects []core.Effect) tea.Cmd {
 		}
 	}
 
+	if len(blocking) > 0 {
+		return tea.Sequence(append(blocking, tea.Batch(cmds...))...)
+	}
 	if len(cmds) == 0 {
 		return nil
 	}
@@ -776,12 +1411,84 @@ func extractSessionSpec(effects []core.Effect) *core.SessionSpec {
 }
 
 func (m Model) scanDirsCmd(roots []string) tea.Cmd {
+	if streamer, ok := m.fs.(ports.DirStreamer); ok {
+		return m.streamDirsCmd(streamer, roots)
+	}
 	return func() tea.Msg {
 		dirs, err := m.fs.ScanDirs(roots, m.maxDepth)
 		return scanCompletedMsg{dirs: dirs, err: err}
 	}
 }
 
+func (m Model) streamDirsCmd(streamer ports.DirStreamer, roots []string) tea.Cmd {
+	maxDepth := m.maxDepth
+	scan := m.projectScan
+	return func() tea.Msg {
+		ctx, gen := scan.start()
+		updates := make(chan scanUpdate, scanUpdateBuffer)
+		index, hasIndex := streamer.(ports.ProjectIndex)
+		go func() {
+			// Closing tells the reader a cancelled scan sends nothing more.
+			defer close(updates)
+			if hasIndex {
+				if cached, ok := index.CachedDirs(roots, maxDepth); ok {
+					updates <- scanUpdate{dirs: cached, cached: true}
+				}
+			}
+			dirs, err := streamer.ScanDirsStream(ctx, roots, maxDepth, func(batch []core.DirEntry) {
+				select {
+				case updates <- scanUpdate{dirs: batch}:
+				case <-ctx.Done():
+				}
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil && hasIndex {
+				_ = index.StoreDirs(roots, maxDepth, dirs)
+			}
+			select {
+			case updates <- scanUpdate{dirs: dirs, err: err, done: true}:
+			case <-ctx.Done():
+			}
+		}()
+		return waitForScanCmd(gen, updates)()
+	}
+}
+
+// waitForScanCmd delivers the next scan update, folding batches that are
+// already queued into one message so a fast scan does not flood the UI. It
+// stops once a cancelled scan closes updates.
+func waitForScanCmd(gen int, updates <-chan scanUpdate) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return nil
+		}
+		if update.done {
+			return scanCompletedMsg{gen: gen, dirs: update.dirs, err: update.err}
+		}
+		if update.cached {
+			return scanProgressMsg{gen: gen, dirs: update.dirs, cached: true, updates: updates}
+		}
+		dirs := update.dirs
+		for {
+			select {
+			case next, ok := <-updates:
+				if !ok {
+					return scanProgressMsg{gen: gen, dirs: dirs, updates: updates}
+				}
+				if next.done {
+					return scanCompletedMsg{gen: gen, dirs: next.dirs, err: next.err}
+				}
+				dirs = append(dirs, next.dirs...)
+			default:
+				return scanProgressMsg{gen: gen, dirs: dirs, updates: updates}
+			}
+		}
+	}
+}
+
 func (m Model) createProjectCmd(path string) tea.Cmd {
 	return func() tea.Msg {
 		projectPath, err := m.fs.CreateProject(path)
@@ -789,80 +1496,575 @@ func (m Model) createProjectCmd(path string) tea.Cmd {
 	}
 }
 
-func (m Model) deleteProjectCmd(projectPath string) tea.Cmd {
+var errCloneUnsupported = errors.New("cloning repositories is not supported")
+
+func (m Model) cloneProjectCmd(source, path string) tea.Cmd {
+	cloner, ok := m.fs.(ports.ProjectCloner)
+	if !ok {
+		return func() tea.Msg {
+			return core.MsgProjectCloned{ProjectPath: path, Err: errCloneUnsupported}
+		}
+	}
+	clone := m.projectClone
 	return func() tea.Msg {
-		if m.sessions != nil {
-			paths, err := m.fs.ListWorktreePaths(projectPath)
-			if err != nil {
-				return projectDeletedMsg{projectPath: projectPath, err: err}
-			}
-			for _, path := range paths {
-				for _, tool := range m.core.Tools {
-					spec := core.SessionSpec{DirPath: path, Tool: tool}
-					if err := m.sessions.KillSession(spec); err != nil {
-						return projectDeletedMsg{projectPath: projectPath, err: err}
-					}
+		ctx, gen := clone.start()
+		updates := make(chan cloneUpdate, 1)
+		go func() {
+			defer close(updates)
+			_, err := cloner.CloneProject(ctx, source, path, func(progress core.CloneProgress) {
+				select {
+				case updates <- cloneUpdate{progress: progress}:
+				case <-ctx.Done():
+				}
+			})
+			select {
+			case updates <- cloneUpdate{err: err, done: true}:
+			case <-ctx.Done():
+			}
+		}()
+		return waitForCloneCmd(gen, updates)()
+	}
+}
+
+func (m Model) checkBareRepositoryCmd(path string) tea.Cmd {
+	cloner, ok := m.fs.(ports.ProjectCloner)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return core.MsgBareRepositoryChecked{Path: path, Bare: cloner.IsBareRepository(path)}
+	}
+}
+
+// waitForCloneCmd delivers the next clone update, skipping progress reports
+// that were superseded while the UI was busy. The channel is closed without a
+// result when the clone is cancelled.
+func waitForCloneCmd(gen int, updates <-chan cloneUpdate) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return nil
+		}
+		for !update.done {
+			select {
+			case next, ok := <-updates:
+				if !ok {
+					return nil
 				}
+				update = next
+			default:
+				return cloneProgressMsg{gen: gen, progress: update.progress, updates: updates}
 			}
 		}
+		return cloneCompletedMsg{gen: gen, err: update.err}
+	}
+}
 
-		err := m.fs.DeleteProject(projectPath)
-		return projectDeletedMsg{projectPath: projectPath, err: err}
+func (m Model) cancelCloneCmd() tea.Cmd {
+	clone := m.projectClone
+	return func() tea.Msg {
+		clone.stop()
+		return nil
 	}
 }
 
-func (m Model) loadWorktreesCmd(projectPath string) tea.Cmd {
+func (m Model) deleteProjectCmd(projectPath string) tea.Cmd {
+	return func() tea.Msg {
+		return projectDeletedMsg{projectPath: projectPath, err: m.deleteProject(projectPath)}
+	}
+}
+
+// deleteProjectsCmd deletes the projects one by one, keeping going past
+// failures so the report covers every project.
+func (m Model) deleteProjectsCmd(projectPaths []string) tea.Cmd {
 	return func() tea.Msg {
-		listing, err := m.fs.ListWorktrees(projectPath)
+		var report core.BatchReport
+		for _, path := range projectPaths {
+			if err := m.deleteProject(path); err != nil {
+				report.Fail(filepath.Base(path), err)
+				continue
+			}
+			report.Done = append(report.Done, path)
+		}
+		return core.MsgProjectsDeleted{Report: report}
+	}
+}
+
+// deleteProject kills the sessions of every worktree of the project, then
+// deletes it.
+func (m Model) deleteProject(projectPath string) error {
+	if m.sessions != nil {
+		paths, err := m.fs.ListWorktreePaths(projectPath)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			for _, tool := range m.core.Tools {
+				spec := core.SessionSpec{DirPath: path, Tool: tool}
+				if err := m.sessions.KillSession(spec); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return m.fs.DeleteProject(projectPath)
+}
+
+func (m Model) loadWorktreesCmd(projectPath string, all bool) tea.Cmd {
+	return func() tea.Msg {
+		listing, err := m.listWorktrees(projectPath, all)
 		return worktreesLoadedMsg{worktrees: listing.Worktrees, warning: listing.Warning, err: err}
 	}
 }
 
+func (m Model) refreshWorktreesCmd(projectPath string, all bool) tea.Cmd {
+	return func() tea.Msg {
+		listing, err := m.listWorktrees(projectPath, all)
+		return core.MsgWorktreesRefreshed{
+			ProjectPath: projectPath,
+			All:         all,
+			Worktrees:   listing.Worktrees,
+			Warning:     listing.Warning,
+			Err:         err,
+		}
+	}
+}
+
+// listWorktrees includes unmanaged worktrees when all is set and the
+// filesystem can list them.
+func (m Model) listWorktrees(projectPath string, all bool) (core.WorktreeListing, error) {
+	if adopter, ok := m.fs.(ports.WorktreeAdopter); ok && all {
+		return adopter.ListAllWorktrees(projectPath)
+	}
+	return m.fs.ListWorktrees(projectPath)
+}
+
+var errAdoptUnsupported = errors.New("adopting worktrees is not supported")
+
+func (m Model) adoptWorktreeCmd(projectPath, worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		adopter, ok := m.fs.(ports.WorktreeAdopter)
+		if !ok {
+			return core.MsgWorktreeAdopted{Path: worktreePath, Err: errAdoptUnsupported}
+		}
+		newPath, err := adopter.AdoptWorktree(projectPath, worktreePath)
+		return core.MsgWorktreeAdopted{Path: worktreePath, NewPath: newPath, Err: err}
+	}
+}
+
+var errLandUnsupported = errors.New("landing worktrees is not supported")
+
+func (m Model) landWorktreeCmd(projectPath, worktreePath string, strategy core.LandStrategy) tea.Cmd {
+	return func() tea.Msg {
+		lander, ok := m.fs.(ports.WorktreeLander)
+		if !ok {
+			return core.MsgWorktreeLanded{WorktreePath: worktreePath, Err: errLandUnsupported}
+		}
+		result, err := lander.LandWorktree(projectPath, worktreePath, strategy)
+		return core.MsgWorktreeLanded{WorktreePath: worktreePath, Result: result, Err: err}
+	}
+}
+
+func (m Model) runHookCmd(e core.EffRunHook) tea.Cmd {
+	runner := m.hookRunner
+	if runner == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), e.Timeout)
+		defer cancel()
+		err := runner.RunHook(ctx, e.Command, e.Payload)
+		return core.MsgHookFinished{Event: e.Payload.Event, Command: e.Command, Err: err}
+	}
+}
+
 func (m Model) createWorktreeCmd(projectPath, branchName string) tea.Cmd {
 	return func() tea.Msg {
 		path, err := m.fs.CreateWorktree(projectPath, branchName)
-		return worktreeCreatedMsg{path: path, err: err}
+		return worktreeCreatedMsg{path: path, branch: branchName, err: err}
 	}
 }
 
-func (m Model) deleteWorktreeCmd(projectPath, worktreePath string) tea.Cmd {
+func (m Model) setupWorktreeCmd(projectPath, worktreePath string) tea.Cmd {
+	preparer, ok := m.fs.(ports.WorktreePreparer)
+	if !ok {
+		return func() tea.Msg {
+			return core.MsgWorktreeSetupDone{WorktreePath: worktreePath}
+		}
+	}
+	setup := m.worktreeSetup
 	return func() tea.Msg {
-		if m.sessions != nil {
-			for _, tool := range m.core.Tools {
-				spec := core.SessionSpec{DirPath: worktreePath, Tool: tool}
-				if err := m.sessions.KillSession(spec); err != nil {
-					return worktreeDeletedMsg{path: worktreePath, err: err}
+		ctx, gen := setup.start()
+		updates := make(chan setupUpdate, setupUpdateBuffer)
+		go func() {
+			defer close(updates)
+			err := preparer.SetupWorktree(ctx, projectPath, worktreePath, func(line string) {
+				select {
+				case updates <- setupUpdate{line: line}:
+				case <-ctx.Done():
+				}
+			})
+			// A cancelled setup still reports back so Step 3 opens with the
+			// warning; only quitting drops the result.
+			updates <- setupUpdate{err: err, done: true}
+		}()
+		return waitForSetupCmd(gen, updates)()
+	}
+}
+
+// waitForSetupCmd delivers the next batch of setup output, or the result.
+func waitForSetupCmd(gen int, updates <-chan setupUpdate) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return nil
+		}
+		if update.done {
+			return setupDoneMsg{gen: gen, err: update.err}
+		}
+		lines := []string{update.line}
+		for {
+			select {
+			case next, ok := <-updates:
+				if !ok {
+					return nil
+				}
+				if next.done {
+					// The output is only shown while the setup runs.
+					return setupDoneMsg{gen: gen, err: next.err}
 				}
+				lines = append(lines, next.line)
+			default:
+				return setupOutputMsg{gen: gen, lines: lines, updates: updates}
 			}
 		}
-
-		err := m.fs.DeleteWorktree(projectPath, worktreePath)
-		return worktreeDeletedMsg{path: worktreePath, err: err}
 	}
 }
 
-func (m Model) prewarmAllToolsCmd(dirPath string, tools []string) tea.Cmd {
-	if m.sessions == nil {
+func (m Model) cancelSetupCmd() tea.Cmd {
+	setup := m.worktreeSetup
+	return func() tea.Msg {
+		setup.interrupt()
 		return nil
 	}
-	cmds := make([]tea.Cmd, 0, len(tools))
-	for _, tool := range tools {
-		spec := core.SessionSpec{DirPath: dirPath, Tool: tool, Detach: true}
-		cmds = append(cmds, func() tea.Msg {
-			created, err := m.sessions.PrewarmSession(spec)
+}
+
+var errBranchDeleteUnsupported = errors.New("deleting branches is not supported")
+
+// deleteWorktreeCmd kills the worktree's sessions, deletes it and then its
+// branch if asked to. A safe branch delete is checked first, so an unmerged
+// branch leaves everything in place.
+func (m Model) deleteWorktreeCmd(e core.EffDeleteWorktree) tea.Cmd {
+	return func() tea.Msg {
+		return m.deleteWorktree(e)
+	}
+}
+
+// checkBranchesMergedCmd finds the branches a safe delete would refuse.
+// Without branch support nothing is refused here; the delete reports it.
+func (m Model) checkBranchesMergedCmd(e core.EffCheckBranchesMerged) tea.Cmd {
+	return func() tea.Msg {
+		deleter, ok := m.fs.(ports.BranchDeleter)
+		if !ok {
+			return core.MsgBranchesChecked{}
+		}
+		failed := make(map[string]error)
+		for _, wt := range e.Worktrees {
+			merged, err := deleter.BranchMerged(e.ProjectPath, wt.Branch)
+			switch {
+			case err != nil:
+				failed[wt.Path] = err
+			case !merged:
+				failed[wt.Path] = core.BranchNotMergedError{Branch: wt.Branch}
+			}
+		}
+		return core.MsgBranchesChecked{Failed: failed}
+	}
+}
+
+// deleteWorktreesCmd deletes the worktrees one by one like
+// deleteWorktreeCmd. A branch that could not be deleted is reported as a
+// failure, but the worktree still counts as deleted.
+func (m Model) deleteWorktreesCmd(e core.EffDeleteWorktrees) tea.Cmd {
+	return func() tea.Msg {
+		report := core.BatchReport{Failures: append([]string(nil), e.Failures...)}
+		for _, wt := range e.Worktrees {
+			deletion := e.DeleteBranch
+			if wt.Branch == "" || wt.Branch == "(detached)" {
+				deletion = core.BranchKeep
+			}
+			deleted := m.deleteWorktree(core.EffDeleteWorktree{
+				ProjectPath:  e.ProjectPath,
+				WorktreePath: wt.Path,
+				Branch:       wt.Branch,
+				DeleteBranch: deletion,
+			})
+			if deleted.err != nil {
+				report.Fail(wt.Name, deleted.err)
+				continue
+			}
+			report.Done = append(report.Done, wt.Path)
+			if deleted.branchErr != nil {
+				report.Fail(wt.Name, fmt.Errorf("branch %s kept: %w", wt.Branch, deleted.branchErr))
+			}
+		}
+		return core.MsgWorktreesDeleted{Report: report}
+	}
+}
+
+// deleteWorktree does the work of deleteWorktreeCmd; branchErr is set when
+// only the branch was kept.
+func (m Model) deleteWorktree(e core.EffDeleteWorktree) worktreeDeletedMsg {
+	deleter, canDeleteBranch := m.fs.(ports.BranchDeleter)
+	if e.DeleteBranch == core.BranchDelete && canDeleteBranch {
+		merged, err := deleter.BranchMerged(e.ProjectPath, e.Branch)
+		if err != nil {
+			return worktreeDeletedMsg{path: e.WorktreePath, err: err}
+		}
+		if !merged {
+			return worktreeDeletedMsg{path: e.WorktreePath, err: core.BranchNotMergedError{Branch: e.Branch}}
+		}
+	}
+
+	if m.sessions != nil {
+		for _, tool := range m.core.Tools {
+			spec := core.SessionSpec{DirPath: e.WorktreePath, Tool: tool}
+			if err := m.sessions.KillSession(spec); err != nil {
+				return worktreeDeletedMsg{path: e.WorktreePath, err: err}
+			}
+		}
+	}
+
+	if err := m.fs.DeleteWorktree(e.ProjectPath, e.WorktreePath); err != nil {
+		return worktreeDeletedMsg{path: e.WorktreePath, err: err}
+	}
+	if e.DeleteBranch == core.BranchKeep {
+		return worktreeDeletedMsg{path: e.WorktreePath}
+	}
+	if !canDeleteBranch {
+		return worktreeDeletedMsg{path: e.WorktreePath, branchErr: errBranchDeleteUnsupported}
+	}
+	err := deleter.DeleteBranch(e.ProjectPath, e.Branch, e.DeleteBranch == core.BranchForceDelete)
+	return worktreeDeletedMsg{path: e.WorktreePath, branchErr: err}
+}
+
+var errCleanupUnsupported = errors.New("cleaning up worktrees is not supported")
+
+func (m Model) loadCleanupCmd(projectPaths []string) tea.Cmd {
+	return func() tea.Msg {
+		collector, ok := m.fs.(ports.WorktreeCollector)
+		if !ok {
+			return core.MsgCleanupLoaded{Err: errCleanupUnsupported}
+		}
+		var activity []core.WorktreeActivity
+		for _, projectPath := range projectPaths {
+			found, err := collector.WorktreeActivity(projectPath)
 			if err != nil {
-				return core.MsgToolPrewarmFailed{Tool: tool, Err: err}
+				return core.MsgCleanupLoaded{Err: err}
 			}
-			if created {
-				return core.MsgToolPrewarmStarted{Tool: tool, StartedAt: time.Now()}
+			activity = append(activity, found...)
+		}
+		var sessions []core.SessionInfo
+		var launches []core.Launch
+		if m.sessions != nil {
+			// Without sessions, inactivity falls back to the last commit.
+			sessions, _ = m.sessions.ListSessions()
+			if history, ok := m.sessions.(ports.LaunchHistory); ok {
+				launches, _ = history.RecentLaunches()
 			}
-			return core.MsgToolPrewarmExisting{Tool: tool}
-		})
+		}
+		return core.MsgCleanupLoaded{Activity: activity, Sessions: sessions, Launches: launches, Now: time.Now()}
 	}
-	if len(cmds) == 0 {
+}
+
+// loadPinsCmd reads the pins; without a pin store nothing is pinned.
+func (m Model) loadPinsCmd() tea.Cmd {
+	store, ok := m.fs.(ports.PinStore)
+	if !ok {
 		return nil
 	}
-	return tea.Batch(cmds...)
+	return func() tea.Msg {
+		paths, err := store.Pins()
+		return core.MsgPinsLoaded{Paths: paths, Err: err}
+	}
+}
+
+func (m Model) setPinnedCmd(path string, pinned bool) tea.Cmd {
+	store, ok := m.fs.(ports.PinStore)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return core.MsgPinSaved{Err: store.SetPinned(path, pinned)}
+	}
+}
+
+// loadTagsCmd reads the tags set in the UI; without a tag store only the
+// config tags apply.
+func (m Model) loadTagsCmd() tea.Cmd {
+	store, ok := m.fs.(ports.TagStore)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		tags, err := store.ProjectTags()
+		return core.MsgTagsLoaded{Tags: tags, Err: err}
+	}
+}
+
+func (m Model) setProjectTagsCmd(projectPath string, tags []string) tea.Cmd {
+	store, ok := m.fs.(ports.TagStore)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return core.MsgProjectTagsSaved{Err: store.SetProjectTags(projectPath, tags)}
+	}
+}
+
+var errRecentUnsupported = errors.New("recent workspaces are not supported")
+
+func (m Model) loadRecentCmd() tea.Cmd {
+	return func() tea.Msg {
+		history, ok := m.sessions.(ports.LaunchHistory)
+		if !ok {
+			return core.MsgRecentLoaded{Err: errRecentUnsupported}
+		}
+		launches, err := history.RecentLaunches()
+		if err != nil {
+			return core.MsgRecentLoaded{Err: err}
+		}
+		// Without sessions, every launch shows as stopped.
+		sessions, _ := m.sessions.ListSessions()
+		return core.MsgRecentLoaded{Launches: launches, Sessions: sessions}
+	}
+}
+
+// cleanupWorktreesCmd deletes the worktrees one by one, like
+// deleteWorktreeCmd, and keeps going past failures so the report covers
+// every worktree.
+func (m Model) cleanupWorktreesCmd(worktrees []core.StaleWorktree, deleteBranches bool) tea.Cmd {
+	deleter, canDeleteBranches := m.fs.(ports.BranchDeleter)
+	return func() tea.Msg {
+		var report core.CleanupReport
+		for _, wt := range worktrees {
+			if err := m.deleteStaleWorktree(wt); err != nil {
+				report.Failures = append(report.Failures, fmt.Sprintf("%s: %v", wt.Worktree.Name, err))
+				continue
+			}
+			report.Removed = append(report.Removed, wt)
+			branch := wt.Worktree.Branch
+			if !deleteBranches || !canDeleteBranches || branch == "" || branch == "(detached)" {
+				continue
+			}
+			if err := deleter.DeleteBranch(wt.ProjectPath, branch, false); err != nil {
+				report.Failures = append(report.Failures, fmt.Sprintf("%s: branch %s kept: %v", wt.Worktree.Name, branch, err))
+			}
+		}
+		return core.MsgWorktreesCleaned{Report: report}
+	}
+}
+
+func (m Model) deleteStaleWorktree(wt core.StaleWorktree) error {
+	if m.sessions != nil {
+		running, err := m.sessions.ListSessions()
+		if err != nil {
+			return err
+		}
+		for _, session := range core.SessionsInDir(running, wt.Worktree.Path) {
+			if err := m.sessions.KillSessionByName(session.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return m.fs.DeleteWorktree(wt.ProjectPath, wt.Worktree.Path)
+}
+
+var errReviewUnsupported = errors.New("reviewing changes is not supported")
+
+func (m Model) loadChangesCmd(worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		reviewer, ok := m.fs.(ports.WorktreeReviewer)
+		if !ok {
+			return core.MsgChangesLoaded{WorktreePath: worktreePath, Err: errReviewUnsupported}
+		}
+		changes, err := reviewer.ListChanges(worktreePath)
+		return core.MsgChangesLoaded{WorktreePath: worktreePath, Changes: changes, Err: err}
+	}
+}
+
+func (m Model) loadFileDiffCmd(worktreePath, mergeBase string, file core.FileChange) tea.Cmd {
+	return func() tea.Msg {
+		reviewer, ok := m.fs.(ports.WorktreeReviewer)
+		if !ok {
+			return core.MsgFileDiffLoaded{WorktreePath: worktreePath, Path: file.Path, Err: errReviewUnsupported}
+		}
+		diff, err := reviewer.FileDiff(worktreePath, mergeBase, file)
+		return core.MsgFileDiffLoaded{WorktreePath: worktreePath, Path: file.Path, Diff: diff, Err: err}
+	}
+}
+
+func (m Model) discardFileCmd(worktreePath, mergeBase string, file core.FileChange) tea.Cmd {
+	return func() tea.Msg {
+		reviewer, ok := m.fs.(ports.WorktreeReviewer)
+		if !ok {
+			return core.MsgFileDiscarded{WorktreePath: worktreePath, Path: file.Path, Err: errReviewUnsupported}
+		}
+		err := reviewer.DiscardFile(worktreePath, mergeBase, file)
+		return core.MsgFileDiscarded{WorktreePath: worktreePath, Path: file.Path, Err: err}
+	}
+}
+
+func (m Model) commitAllCmd(worktreePath, message string) tea.Cmd {
+	return func() tea.Msg {
+		reviewer, ok := m.fs.(ports.WorktreeReviewer)
+		if !ok {
+			return core.MsgChangesCommitted{WorktreePath: worktreePath, Err: errReviewUnsupported}
+		}
+		err := reviewer.CommitAll(worktreePath, message)
+		return core.MsgChangesCommitted{WorktreePath: worktreePath, Err: err}
+	}
+}
+
+// openEditorCmd hands the terminal to $VISUAL or $EDITOR, falling back to vi,
+// and reports back once the editor exits.
+func openEditorCmd(worktreePath, path string) tea.Cmd {
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Dir = worktreePath
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return core.MsgEditorClosed{WorktreePath: worktreePath, Err: err}
+	})
+}
+
+func (m Model) prewarmAllToolsCmd(dirPath string, tools []string) tea.Cmd {
+	if m.sessions == nil || len(tools) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		created, err := m.sessions.PrewarmTools(dirPath, tools)
+		startedAt := time.Now()
+		cmds := make([]tea.Cmd, 0, len(tools))
+		for _, tool := range tools {
+			var msg tea.Msg
+			switch {
+			case err != nil:
+				msg = core.MsgToolPrewarmFailed{Tool: tool, Err: err}
+			case created[tool]:
+				msg = core.MsgToolPrewarmStarted{Tool: tool, StartedAt: startedAt}
+			default:
+				msg = core.MsgToolPrewarmExisting{Tool: tool}
+			}
+			cmds = append(cmds, func() tea.Msg { return msg })
+		}
+		return tea.BatchMsg(cmds)
+	}
 }
 
 func (m Model) listSessionsCmd() tea.Cmd {
@@ -884,6 +2086,166 @@ func (m Model) attachSessionCmd(session core.SessionInfo) tea.Cmd {
 	}
 }
 
+// killSessionsCmd kills the sessions one by one, keeping going past
+// failures so the report covers every session.
+func (m Model) killSessionsCmd(sessions []core.SessionInfo) tea.Cmd {
+	return func() tea.Msg {
+		var report core.BatchReport
+		for _, session := range sessions {
+			if m.sessions == nil {
+				report.Fail(session.Name, errNoSessions)
+				continue
+			}
+			if err := m.sessions.KillSessionByName(session.Name); err != nil {
+				report.Fail(session.Name, err)
+				continue
+			}
+			report.Done = append(report.Done, session.Name)
+		}
+		return core.MsgSessionsKilled{Report: report}
+	}
+}
+
+// startSessionsCmd opens the sessions, which are detached, so none of them
+// is attached to.
+func (m Model) startSessionsCmd(specs []core.SessionSpec) tea.Cmd {
+	return func() tea.Msg {
+		var report core.BatchReport
+		for _, spec := range specs {
+			if m.sessions == nil {
+				report.Fail(filepath.Base(spec.DirPath), errNoSessions)
+				continue
+			}
+			if err := m.sessions.OpenSession(spec); err != nil {
+				report.Fail(filepath.Base(spec.DirPath), err)
+				continue
+			}
+			report.Done = append(report.Done, spec.DirPath)
+		}
+		return core.MsgSessionsStarted{Report: report}
+	}
+}
+
+func (m Model) watchSessionsCmd() tea.Cmd {
+	watcher, ok := m.sessions.(ports.SessionWatcher)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		sub, err := watcher.WatchSessions()
+		if err != nil {
+			// Live updates are optional; the snapshot from EffListSessions stays usable.
+			return nil
+		}
+		return sessionWatchStartedMsg{sub: sub}
+	}
+}
+
+func (m Model) unwatchSessionsCmd() tea.Cmd {
+	sub := m.sessionWatch.take()
+	if sub == nil {
+		return nil
+	}
+	return closeSubscriptionCmd(sub)
+}
+
+func closeSubscriptionCmd(sub ports.SessionSubscription) tea.Cmd {
+	return func() tea.Msg {
+		_ = sub.Close()
+		return nil
+	}
+}
+
+func waitForSessionEventCmd(sub ports.SessionSubscription) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-sub.Events()
+		if !ok {
+			return nil
+		}
+		return sessionEventMsg{sub: sub, event: event}
+	}
+}
+
+func (m Model) watchProjectsCmd(roots []string) tea.Cmd {
+	watcher, ok := m.fs.(ports.ProjectWatcher)
+	if !ok {
+		return nil
+	}
+	maxDepth := m.maxDepth
+	return func() tea.Msg {
+		sub, err := watcher.WatchProjects(roots, maxDepth)
+		if err != nil {
+			// Without a watcher the lists simply stay as scanned.
+			return nil
+		}
+		return projectWatchStartedMsg{sub: sub}
+	}
+}
+
+func waitForProjectEventCmd(sub ports.ProjectSubscription) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-sub.Events()
+		if !ok {
+			return nil
+		}
+		return projectEventMsg{sub: sub, event: event}
+	}
+}
+
+// Close releases background resources: a running project scan, clone or
+// worktree setup and the live session and project subscriptions.
+func (m Model) Close() error {
+	m.projectScan.stop()
+	m.projectClone.stop()
+	m.worktreeSetup.stop()
+	var errs []error
+	if sub := m.projectWatch.take(); sub != nil {
+		errs = append(errs, sub.Close())
+	}
+	if sub := m.sessionWatch.take(); sub != nil {
+		errs = append(errs, sub.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (m Model) findLegacySessionsCmd(projectPaths []string) tea.Cmd {
+	if m.sessions == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		var worktreePaths []string
+		for _, projectPath := range projectPaths {
+			paths, err := m.fs.ListWorktreePaths(projectPath)
+			if err != nil {
+				continue
+			}
+			worktreePaths = append(worktreePaths, paths...)
+		}
+		sessions, err := m.sessions.FindLegacySessions(worktreePaths)
+		return core.MsgLegacySessionsFound{Sessions: sessions, Err: err}
+	}
+}
+
+// migrateSessionsCmd migrates the sessions one by one and keeps going past
+// failures so the report covers every session.
+func (m Model) migrateSessionsCmd(sessions []core.SessionMigration) tea.Cmd {
+	if m.sessions == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		var msg core.MsgSessionsMigrated
+		for _, migration := range sessions {
+			if err := m.sessions.MigrateSession(migration); err != nil {
+				msg.Failed = append(msg.Failed, migration)
+				msg.Failures = append(msg.Failures, fmt.Sprintf("%s: %v", migration.Name, err))
+				continue
+			}
+			msg.Migrated++
+		}
+		return msg
+	}
+}
+
 const toolStartingMinDuration = 200 * time.Millisecond
 
 func (m *Model) beginToolStartingProgress(now time.Time) {
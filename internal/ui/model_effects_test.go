@@ -1,13 +1,17 @@
 package ui
 
 import (
+	"context"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/ariguillegp/rivet/internal/core"
+	"github.com/ariguillegp/rivet/internal/ports"
 )
 
 type fakeFilesystem struct {
@@ -115,6 +119,8 @@ type deleteWorktreeCall struct {
 type fakeSessionManager struct {
 	openErr          error
 	prewarmFn        func(spec core.SessionSpec) (bool, error)
+	prewarmToolsFn   func(dirPath string, tools []string) (map[string]bool, error)
+	prewarmToolCalls [][]string
 	killErr          error
 	listSessionsResp []core.SessionInfo
 	listSessionsErr  error
@@ -122,7 +128,12 @@ type fakeSessionManager struct {
 	openCalls        []core.SessionSpec
 	prewarmCalls     []core.SessionSpec
 	killCalls        []core.SessionSpec
+	killNameCalls    []string
 	attachCalls      []string
+	legacySessions   []core.SessionMigration
+	legacyPaths      []string
+	migrateCalls     []core.SessionMigration
+	migrateErrs      map[string]error
 }
 
 func (f *fakeSessionManager) OpenSession(spec core.SessionSpec) error {
@@ -138,11 +149,24 @@ func (f *fakeSessionManager) PrewarmSession(spec core.SessionSpec) (bool, error)
 	return false, nil
 }
 
+func (f *fakeSessionManager) PrewarmTools(dirPath string, tools []string) (map[string]bool, error) {
+	f.prewarmToolCalls = append(f.prewarmToolCalls, append([]string(nil), tools...))
+	if f.prewarmToolsFn != nil {
+		return f.prewarmToolsFn(dirPath, tools)
+	}
+	return map[string]bool{}, nil
+}
+
 func (f *fakeSessionManager) KillSession(spec core.SessionSpec) error {
 	f.killCalls = append(f.killCalls, spec)
 	return f.killErr
 }
 
+func (f *fakeSessionManager) KillSessionByName(name string) error {
+	f.killNameCalls = append(f.killNameCalls, name)
+	return f.killErr
+}
+
 func (f *fakeSessionManager) ListSessions() ([]core.SessionInfo, error) {
 	if f.listSessionsErr != nil {
 		return nil, f.listSessionsErr
@@ -155,6 +179,16 @@ func (f *fakeSessionManager) AttachSession(name string) error {
 	return f.attachErr
 }
 
+func (f *fakeSessionManager) FindLegacySessions(worktreePaths []string) ([]core.SessionMigration, error) {
+	f.legacyPaths = append([]string(nil), worktreePaths...)
+	return append([]core.SessionMigration(nil), f.legacySessions...), nil
+}
+
+func (f *fakeSessionManager) MigrateSession(migration core.SessionMigration) error {
+	f.migrateCalls = append(f.migrateCalls, migration)
+	return f.migrateErrs[migration.Name]
+}
+
 func TestScanDirsCmdReturnsScanCompletedMsg(t *testing.T) {
 	fs := &fakeFilesystem{
 		scanDirsEntries: []core.DirEntry{{Path: "/projects/demo", Name: "demo"}},
@@ -200,7 +234,7 @@ func TestLoadWorktreesCmdReturnsListing(t *testing.T) {
 	}
 	m := New(nil, fs, nil)
 
-	msg := m.loadWorktreesCmd("/projects/demo")()
+	msg := m.loadWorktreesCmd("/projects/demo", false)()
 	loaded, ok := msg.(worktreesLoadedMsg)
 	if !ok {
 		t.Fatalf("expected worktreesLoadedMsg, got %T", msg)
@@ -226,7 +260,7 @@ func TestCreateAndDeleteWorktreeCmds(t *testing.T) {
 		t.Fatalf("unexpected created path %q", created.path)
 	}
 
-	deletedMsg := m.deleteWorktreeCmd("/projects/demo", "/projects/demo/feature-x")()
+	deletedMsg := m.deleteWorktreeCmd(core.EffDeleteWorktree{ProjectPath: "/projects/demo", WorktreePath: "/projects/demo/feature-x"})()
 	deleted, ok := deletedMsg.(worktreeDeletedMsg)
 	if !ok {
 		t.Fatalf("expected worktreeDeletedMsg, got %T", deletedMsg)
@@ -291,7 +325,7 @@ func TestDeleteWorktreeCmdStopsOnSessionKillError(t *testing.T) {
 	sessions := &fakeSessionManager{killErr: errors.New("kill failed")}
 	m := New(nil, fs, sessions)
 
-	msg := m.deleteWorktreeCmd("/projects/demo", "/projects/demo/main")()
+	msg := m.deleteWorktreeCmd(core.EffDeleteWorktree{ProjectPath: "/projects/demo", WorktreePath: "/projects/demo/main"})()
 	deleted, ok := msg.(worktreeDeletedMsg)
 	if !ok {
 		t.Fatalf("expected worktreeDeletedMsg, got %T", msg)
@@ -304,42 +338,55 @@ func TestDeleteWorktreeCmdStopsOnSessionKillError(t *testing.T) {
 	}
 }
 
-func TestPrewarmAllToolsCmdReturnsMessagesForCreatedExistingAndFailed(t *testing.T) {
+func TestPrewarmAllToolsCmdReturnsMessagesForCreatedAndExisting(t *testing.T) {
 	sessions := &fakeSessionManager{
-		prewarmFn: func(spec core.SessionSpec) (bool, error) {
-			switch spec.Tool {
-			case "amp":
-				return true, nil
-			case "codex":
-				return false, nil
-			default:
-				return false, errors.New("prewarm failed")
-			}
+		prewarmToolsFn: func(_ string, _ []string) (map[string]bool, error) {
+			return map[string]bool{"amp": true}, nil
 		},
 	}
 	m := New(nil, &fakeFilesystem{}, sessions)
 
-	cmd := m.prewarmAllToolsCmd("/projects/demo/main", []string{"amp", "codex", "claude"})
+	cmd := m.prewarmAllToolsCmd("/projects/demo/main", []string{"amp", "codex"})
 	msgs := runCmd(cmd)
-	if len(msgs) != 3 {
-		t.Fatalf("expected three messages, got %d", len(msgs))
+	if len(msgs) != 2 {
+		t.Fatalf("expected two messages, got %d", len(msgs))
+	}
+	if len(sessions.prewarmToolCalls) != 1 {
+		t.Fatalf("expected a single batched prewarm call, got %d", len(sessions.prewarmToolCalls))
 	}
 
 	seenStarted := false
 	seenExisting := false
-	seenFailed := false
 	for _, msg := range msgs {
 		switch typed := msg.(type) {
 		case core.MsgToolPrewarmStarted:
 			seenStarted = typed.Tool == "amp" && !typed.StartedAt.IsZero()
 		case core.MsgToolPrewarmExisting:
 			seenExisting = typed.Tool == "codex"
-		case core.MsgToolPrewarmFailed:
-			seenFailed = typed.Tool == "claude" && typed.Err != nil
 		}
 	}
-	if !seenStarted || !seenExisting || !seenFailed {
-		t.Fatalf("expected started/existing/failed messages, got %#v", msgs)
+	if !seenStarted || !seenExisting {
+		t.Fatalf("expected started/existing messages, got %#v", msgs)
+	}
+}
+
+func TestPrewarmAllToolsCmdFailsEveryToolWhenBatchFails(t *testing.T) {
+	sessions := &fakeSessionManager{
+		prewarmToolsFn: func(_ string, _ []string) (map[string]bool, error) {
+			return nil, errors.New("prewarm failed")
+		},
+	}
+	m := New(nil, &fakeFilesystem{}, sessions)
+
+	msgs := runCmd(m.prewarmAllToolsCmd("/projects/demo/main", []string{"amp", "claude"}))
+	if len(msgs) != 2 {
+		t.Fatalf("expected two messages, got %d", len(msgs))
+	}
+	for _, msg := range msgs {
+		failed, ok := msg.(core.MsgToolPrewarmFailed)
+		if !ok || failed.Err == nil {
+			t.Fatalf("expected MsgToolPrewarmFailed, got %#v", msg)
+		}
 	}
 }
 
@@ -388,6 +435,70 @@ func TestAttachSessionCmdWithoutManagerReturnsError(t *testing.T) {
 	}
 }
 
+func TestFindLegacySessionsCmdCollectsWorktreePaths(t *testing.T) {
+	fs := &fakeFilesystem{
+		listWorktreePathsResult: []string{"/projects/demo", "/worktrees/demo--feature"},
+	}
+	sessions := &fakeSessionManager{
+		legacySessions: []core.SessionMigration{{Name: "demo__amp", DirPath: "/projects/demo"}},
+	}
+	m := New(nil, fs, sessions)
+
+	msg := m.findLegacySessionsCmd([]string{"/projects/demo"})()
+	found, ok := msg.(core.MsgLegacySessionsFound)
+	if !ok {
+		t.Fatalf("expected MsgLegacySessionsFound, got %T", msg)
+	}
+	if len(found.Sessions) != 1 || found.Sessions[0].Name != "demo__amp" {
+		t.Fatalf("unexpected legacy sessions payload: %+v", found.Sessions)
+	}
+	if len(sessions.legacyPaths) != 2 {
+		t.Fatalf("expected worktree paths to be forwarded, got %v", sessions.legacyPaths)
+	}
+}
+
+func TestKillSessionsCmdKillsSessionsByName(t *testing.T) {
+	sessions := &fakeSessionManager{}
+	m := New(nil, &fakeFilesystem{}, sessions)
+	marked := []core.SessionInfo{
+		{Name: "scratch", DirPath: "/projects/demo"},
+		{Name: "-projects-demo", DirPath: "/projects/demo", Project: "demo"},
+	}
+
+	msg := m.killSessionsCmd(marked)()
+	killed, ok := msg.(core.MsgSessionsKilled)
+	if !ok {
+		t.Fatalf("expected MsgSessionsKilled, got %T", msg)
+	}
+	if len(killed.Report.Done) != 2 {
+		t.Fatalf("expected both sessions to be killed, got %+v", killed.Report)
+	}
+	if !slices.Equal(sessions.killNameCalls, []string{"scratch", "-projects-demo"}) || len(sessions.killCalls) != 0 {
+		t.Fatalf("expected sessions to be killed by their own names, got %v and %+v", sessions.killNameCalls, sessions.killCalls)
+	}
+}
+
+func TestMigrateSessionsCmdReportsFailedSessions(t *testing.T) {
+	sessions := &fakeSessionManager{migrateErrs: map[string]error{"api__claude": errors.New("duplicate session")}}
+	m := New(nil, &fakeFilesystem{}, sessions)
+	legacy := []core.SessionMigration{
+		{Name: "demo__amp", DirPath: "/projects/demo", Action: core.SessionMigrationRename},
+		{Name: "api__claude", DirPath: "/projects/api", Action: core.SessionMigrationRename},
+	}
+
+	msg := m.migrateSessionsCmd(legacy)()
+	migrated, ok := msg.(core.MsgSessionsMigrated)
+	if !ok {
+		t.Fatalf("expected MsgSessionsMigrated, got %T", msg)
+	}
+	if len(sessions.migrateCalls) != 2 || migrated.Migrated != 1 {
+		t.Fatalf("expected both sessions to be tried and one migrated, got %+v", migrated)
+	}
+	if len(migrated.Failed) != 1 || migrated.Failed[0].Name != "api__claude" || migrated.Failures[0] != "api__claude: duplicate session" {
+		t.Fatalf("unexpected failures: %+v", migrated)
+	}
+}
+
 func TestCheckToolReadyCmdImmediateWhenWarmStartIsZero(t *testing.T) {
 	m := New(nil, &fakeFilesystem{}, nil)
 	m.core.ToolWarmStart = map[string]time.Time{
@@ -440,3 +551,1146 @@ func runCmd(cmd tea.Cmd) []tea.Msg {
 
 	return []tea.Msg{msg}
 }
+
+type fakeSubscription struct {
+	events chan string
+	closed int
+}
+
+func newFakeSubscription() *fakeSubscription {
+	return &fakeSubscription{events: make(chan string, 1)}
+}
+
+func (f *fakeSubscription) Events() <-chan string { return f.events }
+
+func (f *fakeSubscription) Close() error {
+	f.closed++
+	return nil
+}
+
+type watchingSessionManager struct {
+	fakeSessionManager
+	sub *fakeSubscription
+}
+
+func (w *watchingSessionManager) WatchSessions() (ports.SessionSubscription, error) {
+	return w.sub, nil
+}
+
+func TestWatchSessionsCmdIsOptional(t *testing.T) {
+	m := New(nil, &fakeFilesystem{}, &fakeSessionManager{})
+	if cmd := m.watchSessionsCmd(); cmd != nil {
+		t.Fatalf("expected no watch command when the manager cannot watch")
+	}
+}
+
+func TestSessionWatchEventsRefreshSessionsAndCloseOnLeave(t *testing.T) {
+	sub := newFakeSubscription()
+	sessions := &watchingSessionManager{
+		fakeSessionManager: fakeSessionManager{listSessionsResp: []core.SessionInfo{{Name: "demo"}}},
+		sub:                sub,
+	}
+	m := New(nil, &fakeFilesystem{}, sessions)
+	m.core.Mode = core.ModeSessions
+
+	msg := m.watchSessionsCmd()()
+	started, ok := msg.(sessionWatchStartedMsg)
+	if !ok {
+		t.Fatalf("expected sessionWatchStartedMsg, got %T", msg)
+	}
+	updated, _ := m.Update(started)
+	m = updated.(Model)
+	if !m.sessionWatch.isCurrent(sub) {
+		t.Fatalf("expected subscription to be stored")
+	}
+
+	updated, cmd := m.Update(sessionEventMsg{sub: sub, event: "window-close"})
+	m = updated.(Model)
+	sub.events <- "sessions-changed"
+	msgs := runCmd(cmd)
+	seenLoaded := false
+	seenNext := false
+	for _, msg := range msgs {
+		switch msg.(type) {
+		case sessionsLoadedMsg:
+			seenLoaded = true
+		case sessionEventMsg:
+			seenNext = true
+		}
+	}
+	if !seenLoaded || !seenNext {
+		t.Fatalf("expected refresh and next event wait, got %#v", msgs)
+	}
+
+	runCmd(m.runEffects([]core.Effect{core.EffUnwatchSessions{}}))
+	if sub.closed != 1 {
+		t.Fatalf("expected subscription to be closed once, got %d", sub.closed)
+	}
+	if m.sessionWatch.isCurrent(sub) {
+		t.Fatalf("expected subscription to be released")
+	}
+}
+
+func TestReplacedSessionWatchIsClosedOutsideUpdate(t *testing.T) {
+	first, second := newFakeSubscription(), newFakeSubscription()
+	m := New(nil, &fakeFilesystem{}, &fakeSessionManager{})
+	m.core.Mode = core.ModeSessions
+
+	updated, _ := m.Update(sessionWatchStartedMsg{sub: first})
+	m = updated.(Model)
+	updated, cmd := m.Update(sessionWatchStartedMsg{sub: second})
+	m = updated.(Model)
+	if first.closed != 0 {
+		t.Fatal("expected the replaced subscription not to be closed inside Update")
+	}
+	second.events <- "sessions-changed"
+	runCmd(cmd)
+	if first.closed != 1 || second.closed != 0 || !m.sessionWatch.isCurrent(second) {
+		t.Fatalf("expected only the replaced subscription closed, got %d and %d", first.closed, second.closed)
+	}
+}
+
+func TestSessionWatchStartedOutsideSessionsModeIsClosed(t *testing.T) {
+	sub := newFakeSubscription()
+	m := New(nil, &fakeFilesystem{}, &fakeSessionManager{})
+	m.core.Mode = core.ModeBrowsing
+
+	_, cmd := m.Update(sessionWatchStartedMsg{sub: sub})
+	runCmd(cmd)
+	if sub.closed != 1 {
+		t.Fatalf("expected late subscription to be closed, got %d", sub.closed)
+	}
+}
+
+type streamingFilesystem struct {
+	*fakeFilesystem
+	batches   [][]core.DirEntry
+	release   chan struct{}
+	cancelled chan struct{}
+}
+
+func (s *streamingFilesystem) ScanDirsStream(ctx context.Context, _ []string, _ int, emit func([]core.DirEntry)) ([]core.DirEntry, error) {
+	var all []core.DirEntry
+	for _, batch := range s.batches {
+		emit(batch)
+		all = append(all, batch...)
+	}
+	if s.release != nil {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			close(s.cancelled)
+			return nil, ctx.Err()
+		}
+	}
+	return all, nil
+}
+
+func TestScanDirsCmdStreamsProgressBeforeCompletion(t *testing.T) {
+	fs := &streamingFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		batches: [][]core.DirEntry{
+			{{Path: "/projects/api", Name: "api"}},
+			{{Path: "/projects/web", Name: "web"}},
+		},
+		release: make(chan struct{}),
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core, _ = core.Init(m.core)
+
+	msg := m.scanDirsCmd([]string{"/projects"})()
+	progress, ok := msg.(scanProgressMsg)
+	if !ok {
+		t.Fatalf("expected scanProgressMsg, got %T", msg)
+	}
+
+	updatedModel, cmd := m.Update(progress)
+	updated := updatedModel.(Model)
+	if updated.core.Mode != core.ModeBrowsing {
+		t.Fatalf("expected browsing mode after first batch, got %v", updated.core.Mode)
+	}
+	if cmd == nil {
+		t.Fatalf("expected a command waiting for more scan results")
+	}
+
+	close(fs.release)
+	for {
+		msg = cmd()
+		if _, done := msg.(scanCompletedMsg); done {
+			break
+		}
+		updatedModel, cmd = updated.Update(msg)
+		updated = updatedModel.(Model)
+	}
+	updatedModel, _ = updated.Update(msg)
+	updated = updatedModel.(Model)
+	if updated.core.Scanning {
+		t.Fatalf("expected scan to be finished")
+	}
+	if len(updated.core.Dirs) != 2 {
+		t.Fatalf("expected both projects after completion, got %+v", updated.core.Dirs)
+	}
+	if fs.scanDirsCalls != 0 {
+		t.Fatalf("expected streaming scan to bypass ScanDirs, got %d calls", fs.scanDirsCalls)
+	}
+}
+
+func TestCancelledScanStopsWaitingForUpdates(t *testing.T) {
+	fs := &streamingFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		batches:        [][]core.DirEntry{{{Path: "/projects/api", Name: "api"}}},
+		release:        make(chan struct{}),
+		cancelled:      make(chan struct{}),
+	}
+	m := New([]string{"/projects"}, fs, nil)
+
+	progress, ok := m.scanDirsCmd([]string{"/projects"})().(scanProgressMsg)
+	if !ok {
+		t.Fatal("expected scanProgressMsg")
+	}
+	m.projectScan.stop()
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- waitForScanCmd(progress.gen, progress.updates)() }()
+	select {
+	case msg := <-done:
+		if msg != nil {
+			t.Fatalf("expected no message from a cancelled scan, got %T", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the wait to end once the scan was cancelled")
+	}
+}
+
+func TestCloseCancelsRunningScan(t *testing.T) {
+	fs := &streamingFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		batches:        [][]core.DirEntry{{{Path: "/projects/api", Name: "api"}}},
+		release:        make(chan struct{}),
+		cancelled:      make(chan struct{}),
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core, _ = core.Init(m.core)
+
+	msg := m.scanDirsCmd([]string{"/projects"})()
+	progress, ok := msg.(scanProgressMsg)
+	if !ok {
+		t.Fatalf("expected scanProgressMsg, got %T", msg)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	select {
+	case <-fs.cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("expected scan to be cancelled on close")
+	}
+
+	updatedModel, cmd := m.Update(progress)
+	if cmd != nil {
+		t.Fatalf("expected progress from a cancelled scan to be ignored")
+	}
+	if len(updatedModel.(Model).core.Dirs) != 0 {
+		t.Fatalf("expected no dirs from a cancelled scan")
+	}
+}
+
+type indexedFilesystem struct {
+	*streamingFilesystem
+	cached []core.DirEntry
+	stored []core.DirEntry
+}
+
+func (f *indexedFilesystem) CachedDirs([]string, int) ([]core.DirEntry, bool) {
+	return f.cached, f.cached != nil
+}
+
+func (f *indexedFilesystem) StoreDirs(_ []string, _ int, dirs []core.DirEntry) error {
+	f.stored = dirs
+	return nil
+}
+
+func TestScanDirsCmdServesCachedProjectsFirst(t *testing.T) {
+	fs := &indexedFilesystem{
+		streamingFilesystem: &streamingFilesystem{
+			fakeFilesystem: &fakeFilesystem{},
+			batches:        [][]core.DirEntry{{{Path: "/projects/api", Name: "api", Exists: true}}},
+		},
+		cached: []core.DirEntry{{Path: "/projects/old", Name: "old"}},
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core, _ = core.Init(m.core)
+
+	msg := m.scanDirsCmd([]string{"/projects"})()
+	progress, ok := msg.(scanProgressMsg)
+	if !ok || !progress.cached {
+		t.Fatalf("expected cached scanProgressMsg first, got %#v", msg)
+	}
+	updatedModel, cmd := m.Update(progress)
+	updated := updatedModel.(Model)
+	if len(updated.core.Filtered) != 1 || updated.core.Filtered[0].Name != "old" {
+		t.Fatalf("expected cached project to be listed, got %+v", updated.core.Filtered)
+	}
+
+	for {
+		msg = cmd()
+		if _, done := msg.(scanCompletedMsg); done {
+			break
+		}
+		updatedModel, cmd = updated.Update(msg)
+		updated = updatedModel.(Model)
+	}
+	updatedModel, _ = updated.Update(msg)
+	updated = updatedModel.(Model)
+	if len(updated.core.Dirs) != 1 || updated.core.Dirs[0].Name != "api" {
+		t.Fatalf("expected rescan to replace cached projects, got %+v", updated.core.Dirs)
+	}
+	if len(fs.stored) != 1 || fs.stored[0].Name != "api" {
+		t.Fatalf("expected completed scan to be stored, got %+v", fs.stored)
+	}
+}
+
+type fakeProjectSubscription struct {
+	events chan ports.ProjectEvent
+	closed int
+}
+
+func (s *fakeProjectSubscription) Events() <-chan ports.ProjectEvent { return s.events }
+
+func (s *fakeProjectSubscription) Close() error {
+	s.closed++
+	if s.closed == 1 {
+		close(s.events)
+	}
+	return nil
+}
+
+type watchingFilesystem struct {
+	*fakeFilesystem
+	sub   *fakeProjectSubscription
+	roots []string
+}
+
+func (f *watchingFilesystem) WatchProjects(roots []string, _ int) (ports.ProjectSubscription, error) {
+	f.roots = roots
+	return f.sub, nil
+}
+
+func TestProjectWatchEventsUpdateProjectsAndWorktrees(t *testing.T) {
+	sub := &fakeProjectSubscription{events: make(chan ports.ProjectEvent, 1)}
+	fs := &watchingFilesystem{
+		fakeFilesystem: &fakeFilesystem{
+			listWorktreesListing: core.WorktreeListing{Worktrees: []core.Worktree{{Path: "/wt/feature", Name: "feature"}}},
+		},
+		sub: sub,
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/demo"
+
+	msg := m.watchProjectsCmd([]string{"/projects"})()
+	started, ok := msg.(projectWatchStartedMsg)
+	if !ok {
+		t.Fatalf("expected projectWatchStartedMsg, got %T", msg)
+	}
+	if len(fs.roots) != 1 || fs.roots[0] != "/projects" {
+		t.Fatalf("unexpected watch roots: %v", fs.roots)
+	}
+	updatedModel, cmd := m.Update(started)
+	m = updatedModel.(Model)
+
+	sub.events <- ports.ProjectEvent{
+		Added:     []core.DirEntry{{Path: "/projects/new", Name: "new", Exists: true}},
+		Worktrees: true,
+	}
+	updatedModel, cmd = m.Update(cmd())
+	m = updatedModel.(Model)
+	if len(m.core.Dirs) != 1 || m.core.Dirs[0].Name != "new" {
+		t.Fatalf("expected new project to be listed, got %+v", m.core.Dirs)
+	}
+
+	var refreshed bool
+	for _, msg := range runCmdNonBlocking(cmd) {
+		if refresh, ok := msg.(core.MsgWorktreesRefreshed); ok {
+			refreshed = true
+			updatedModel, _ = m.Update(refresh)
+			m = updatedModel.(Model)
+		}
+	}
+	if !refreshed {
+		t.Fatalf("expected worktree refresh after a worktree change")
+	}
+	if len(m.core.Worktrees) != 1 || m.core.Worktrees[0].Name != "feature" {
+		t.Fatalf("expected refreshed worktrees, got %+v", m.core.Worktrees)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if sub.closed != 1 {
+		t.Fatalf("expected project subscription to be closed once, got %d", sub.closed)
+	}
+}
+
+// runCmdNonBlocking is runCmd for batches that also contain a command
+// waiting on a subscription; such commands are skipped.
+func runCmdNonBlocking(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var out []tea.Msg
+		for _, nested := range batch {
+			out = append(out, runCmdNonBlocking(nested)...)
+		}
+		return out
+	}
+	if msg == nil {
+		return nil
+	}
+	return []tea.Msg{msg}
+}
+
+type cloningFilesystem struct {
+	*fakeFilesystem
+	progress  []core.CloneProgress
+	release   chan struct{}
+	cancelled chan struct{}
+	source    string
+	path      string
+	bare      string
+}
+
+func (c *cloningFilesystem) CloneProject(ctx context.Context, source, path string, progress func(core.CloneProgress)) (string, error) {
+	c.source = source
+	c.path = path
+	for _, update := range c.progress {
+		progress(update)
+	}
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		close(c.cancelled)
+		return "", ctx.Err()
+	}
+	return path, nil
+}
+
+func (c *cloningFilesystem) IsBareRepository(path string) bool {
+	return path == c.bare
+}
+
+func (c *cloningFilesystem) IsCloneOf(string, string) bool {
+	return true
+}
+
+func TestCloneProjectStreamsProgressIntoStepTwo(t *testing.T) {
+	fs := &cloningFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		progress:       []core.CloneProgress{{Phase: "Receiving objects", Percent: 60}},
+		release:        make(chan struct{}),
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core, _ = core.Update(m.core, core.MsgScanCompleted{})
+	m.core, _ = core.Update(m.core, core.MsgQueryChanged{Query: "https://example.com/org/service.git"})
+	m.syncLists()
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	if m.core.Mode != core.ModeProjectCloning {
+		t.Fatalf("expected cloning mode, got %v", m.core.Mode)
+	}
+
+	msg := m.cloneProjectCmd(m.core.CloningSource, m.core.CloningPath)()
+	progress, ok := msg.(cloneProgressMsg)
+	if !ok {
+		t.Fatalf("expected cloneProgressMsg, got %T", msg)
+	}
+	updatedModel, cmd := m.Update(progress)
+	m = updatedModel.(Model)
+	if m.core.CloneProgress.Percent != 60 {
+		t.Fatalf("expected progress to reach the model, got %+v", m.core.CloneProgress)
+	}
+	if !strings.Contains(m.View(), "Receiving objects") {
+		t.Fatalf("expected progress in the view, got %q", m.View())
+	}
+
+	close(fs.release)
+	msg = cmd()
+	if _, ok := msg.(cloneCompletedMsg); !ok {
+		t.Fatalf("expected cloneCompletedMsg, got %T", msg)
+	}
+	updatedModel, _ = m.Update(msg)
+	m = updatedModel.(Model)
+	if fs.source != "https://example.com/org/service.git" || fs.path != "/projects/service" {
+		t.Fatalf("unexpected clone arguments: %q -> %q", fs.source, fs.path)
+	}
+	if m.core.Mode != core.ModeWorktree || m.core.SelectedProject != "/projects/service" {
+		t.Fatalf("expected step 2 for the clone, got mode %v project %q", m.core.Mode, m.core.SelectedProject)
+	}
+}
+
+func TestCancelCloneStopsRunningClone(t *testing.T) {
+	fs := &cloningFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		progress:       []core.CloneProgress{{Phase: "Counting objects", Percent: 10}},
+		release:        make(chan struct{}),
+		cancelled:      make(chan struct{}),
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core, _ = core.Update(m.core, core.MsgScanCompleted{})
+	m.core, _ = core.Update(m.core, core.MsgQueryChanged{Query: "git@example.com:org/service.git"})
+	m.syncLists()
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	progress := m.cloneProjectCmd(m.core.CloningSource, m.core.CloningPath)()
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(Model)
+	if m.core.Mode != core.ModeBrowsing {
+		t.Fatalf("expected browsing mode after cancel, got %v", m.core.Mode)
+	}
+	runCmd(cmd)
+	select {
+	case <-fs.cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("expected clone to be cancelled")
+	}
+
+	if _, cmd := m.Update(progress); cmd != nil {
+		t.Fatalf("expected progress from a cancelled clone to be ignored")
+	}
+}
+
+func TestCloneRowOffersBareRepositoryWithoutSuffix(t *testing.T) {
+	fs := &cloningFilesystem{fakeFilesystem: &fakeFilesystem{}, bare: "/srv/git/mirror"}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core, _ = core.Update(m.core, core.MsgScanCompleted{})
+
+	var effects []core.Effect
+	m.core, effects = core.Update(m.core, core.MsgQueryChanged{Query: "/srv/git/mirror"})
+	for _, msg := range runCmd(m.runEffects(effects)) {
+		updatedModel, _ := m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	if source, ok := m.core.CloneSource(); !ok || source != "/srv/git/mirror" {
+		t.Fatalf("expected the bare repository to be cloned, got %q, %v", source, ok)
+	}
+	if !strings.Contains(m.View(), "clone") {
+		t.Fatalf("expected a clone row in the view, got %q", m.View())
+	}
+}
+
+func TestCloneProjectWithoutClonerReportsError(t *testing.T) {
+	m := New([]string{"/projects"}, &fakeFilesystem{}, nil)
+	m.core, _ = core.Update(m.core, core.MsgScanCompleted{})
+	m.core, _ = core.Update(m.core, core.MsgQueryChanged{Query: "/srv/git/service.git"})
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	for _, msg := range runCmd(cmd) {
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	if m.core.Mode != core.ModeBrowsing || m.core.CloneError == "" {
+		t.Fatalf("expected a clone error in step 1, got mode %v error %q", m.core.Mode, m.core.CloneError)
+	}
+}
+
+type adoptingFilesystem struct {
+	*fakeFilesystem
+	allListing core.WorktreeListing
+	adopted    []string
+}
+
+func (a *adoptingFilesystem) ListAllWorktrees(string) (core.WorktreeListing, error) {
+	return a.allListing, nil
+}
+
+func (a *adoptingFilesystem) AdoptWorktree(_, worktreePath string) (string, error) {
+	a.adopted = append(a.adopted, worktreePath)
+	return "/home/u/.rivet/worktrees/api-abc123--spike", nil
+}
+
+func TestShowAllWorktreesListsAndAdoptsUnmanagedWorktrees(t *testing.T) {
+	fs := &adoptingFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		allListing: core.WorktreeListing{Worktrees: []core.Worktree{
+			{Path: "/projects/api", Name: "api", Branch: "main"},
+			{Path: "/tmp/spike", Name: "spike", Branch: "spike", Unmanaged: true},
+		}},
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = updatedModel.(Model)
+	for _, msg := range runCmd(cmd) {
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	if len(m.core.FilteredWT) != 2 {
+		t.Fatalf("expected unmanaged worktree to be listed, got %+v", m.core.FilteredWT)
+	}
+	if !strings.Contains(m.View(), "(unmanaged)") {
+		t.Fatalf("expected unmanaged marker in the view")
+	}
+
+	m.core.WorktreeIdx = 1
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = updatedModel.(Model)
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(core.MsgWorktreeAdopted); !ok {
+			continue
+		}
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	if len(fs.adopted) != 1 || fs.adopted[0] != "/tmp/spike" {
+		t.Fatalf("unexpected adopt calls: %v", fs.adopted)
+	}
+	selected, ok := m.core.SelectedWorktree()
+	if !ok || selected.Unmanaged {
+		t.Fatalf("expected the adopted worktree to be managed, got %+v", selected)
+	}
+}
+
+type preparingFilesystem struct {
+	*fakeFilesystem
+	output  []string
+	release chan struct{}
+	err     error
+}
+
+func (p *preparingFilesystem) SetupWorktree(ctx context.Context, _, _ string, output func(string)) error {
+	for _, line := range p.output {
+		output(line)
+	}
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.err
+}
+
+func TestWorktreeSetupStreamsOutputBeforeStepThree(t *testing.T) {
+	fs := &preparingFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		output:         []string{"$ npm install", "added 12 packages"},
+		release:        make(chan struct{}),
+		err:            errors.New(`post-create hook "npm install" failed: exit status 1`),
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+
+	updatedModel, cmd := m.Update(worktreeCreatedMsg{path: "/projects/api/.rivet/api--feature"})
+	m = updatedModel.(Model)
+	if m.core.Mode != core.ModeWorktreeSetup {
+		t.Fatalf("expected setup mode, got %v", m.core.Mode)
+	}
+
+	msg := cmd()
+	output, ok := msg.(setupOutputMsg)
+	if !ok {
+		t.Fatalf("expected setupOutputMsg, got %T", msg)
+	}
+	updatedModel, cmd = m.Update(output)
+	m = updatedModel.(Model)
+	if !strings.Contains(m.View(), "added 12 packages") {
+		t.Fatalf("expected setup output in the view, got %q", m.View())
+	}
+
+	close(fs.release)
+	for _, msg := range runCmd(cmd) {
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	if m.core.Mode != core.ModeTool {
+		t.Fatalf("expected step 3 after setup, got %v", m.core.Mode)
+	}
+	if !strings.Contains(m.View(), "npm install") {
+		t.Fatalf("expected the setup warning in step 3, got %q", m.View())
+	}
+}
+
+type recordingHookRunner struct {
+	payloads []core.HookPayload
+	err      error
+}
+
+func (r *recordingHookRunner) RunHook(ctx context.Context, _ string, payload core.HookPayload) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("expected a deadline")
+	}
+	r.payloads = append(r.payloads, payload)
+	return r.err
+}
+
+func TestHookFailureShowsWarning(t *testing.T) {
+	runner := &recordingHookRunner{err: errors.New("exit status 1")}
+	hooks := core.Hooks{Commands: map[core.HookEvent][]string{core.HookPostWorktreeDelete: {"./cleanup.sh"}}}
+	m := New([]string{"/projects"}, &fakeFilesystem{}, nil, WithHooks(hooks, runner))
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+
+	updatedModel, cmd := m.Update(worktreeDeletedMsg{path: "/projects/api/.rivet/api--old"})
+	m = updatedModel.(Model)
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(core.MsgHookFinished); !ok {
+			continue
+		}
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+
+	if len(runner.payloads) != 1 || runner.payloads[0].WorktreePath != "/projects/api/.rivet/api--old" {
+		t.Fatalf("unexpected hook runs: %+v", runner.payloads)
+	}
+	if !strings.Contains(m.View(), `post_worktree_delete hook "./cleanup.sh" failed`) {
+		t.Fatalf("expected the hook warning in the view, got %q", m.View())
+	}
+}
+
+type reviewingFilesystem struct {
+	*fakeFilesystem
+	changes   core.WorktreeChanges
+	diff      string
+	committed []string
+}
+
+func (r *reviewingFilesystem) ListChanges(string) (core.WorktreeChanges, error) {
+	return r.changes, nil
+}
+
+func (r *reviewingFilesystem) FileDiff(string, string, core.FileChange) (string, error) {
+	return r.diff, nil
+}
+
+func (r *reviewingFilesystem) DiscardFile(string, string, core.FileChange) error {
+	return nil
+}
+
+func (r *reviewingFilesystem) CommitAll(_, message string) error {
+	r.committed = append(r.committed, message)
+	r.changes.Files = nil
+	return nil
+}
+
+func TestReviewShowsDiffAndCommitsTypedMessage(t *testing.T) {
+	fs := &reviewingFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		changes: core.WorktreeChanges{Base: "main", MergeBase: "abc123", Files: []core.FileChange{
+			{Path: "main.go", Status: core.FileModified},
+		}},
+		diff: "@@ -1 +1 @@\n-old line\n+new line\n",
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+	m.core, _ = core.Update(m.core, core.MsgWorktreesLoaded{Worktrees: []core.Worktree{
+		{Path: "/projects/api/.rivet/api--feature", Name: "api--feature", Branch: "feature"},
+	}})
+
+	apply := func(cmd tea.Cmd) {
+		t.Helper()
+		for _, msg := range runCmd(cmd) {
+			updatedModel, next := m.Update(msg)
+			m = updatedModel.(Model)
+			for _, nested := range runCmd(next) {
+				updatedModel, _ = m.Update(nested)
+				m = updatedModel.(Model)
+			}
+		}
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updatedModel.(Model)
+	if m.core.Mode != core.ModeReview {
+		t.Fatalf("expected review mode, got %v", m.core.Mode)
+	}
+	apply(cmd)
+
+	view := m.View()
+	for _, want := range []string{"Changes against main", "main.go", "-old line", "+new line"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the review, got %q", want, view)
+		}
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	m = updatedModel.(Model)
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Update main")})
+	m = updatedModel.(Model)
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	apply(cmd)
+
+	if len(fs.committed) != 1 || fs.committed[0] != "Update main" {
+		t.Fatalf("expected one commit with the typed message, got %+v", fs.committed)
+	}
+	if !strings.Contains(m.View(), "No changes to review") {
+		t.Fatalf("expected an empty review after committing, got %q", m.View())
+	}
+}
+
+type collectingFilesystem struct {
+	*fakeFilesystem
+	activity []core.WorktreeActivity
+}
+
+func (c *collectingFilesystem) WorktreeActivity(string) ([]core.WorktreeActivity, error) {
+	var remaining []core.WorktreeActivity
+	for _, wt := range c.activity {
+		deleted := false
+		for _, call := range c.deleteWorktreeCalls {
+			deleted = deleted || call.worktree == wt.Worktree.Path
+		}
+		if !deleted {
+			remaining = append(remaining, wt)
+		}
+	}
+	return remaining, nil
+}
+
+func TestCleanupPreviewsAndDeletesMarkedWorktrees(t *testing.T) {
+	fs := &collectingFilesystem{
+		fakeFilesystem: &fakeFilesystem{},
+		activity: []core.WorktreeActivity{
+			{ProjectPath: "/projects/api", Worktree: core.Worktree{Path: "/wt/api--done", Name: "api--done", Branch: "done"}, Merged: true},
+			{ProjectPath: "/projects/api", Worktree: core.Worktree{Path: "/wt/api--gone", Name: "api--gone", Branch: "gone"}, Missing: true},
+		},
+	}
+	m := New([]string{"/projects"}, fs, nil)
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeBrowsing
+	m.core.Dirs = []core.DirEntry{{Path: "/projects/api", Name: "api"}}
+
+	apply := func(cmd tea.Cmd) {
+		t.Helper()
+		for _, msg := range runCmd(cmd) {
+			updatedModel, next := m.Update(msg)
+			m = updatedModel.(Model)
+			for _, nested := range runCmd(next) {
+				updatedModel, _ = m.Update(nested)
+				m = updatedModel.(Model)
+			}
+		}
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	m = updatedModel.(Model)
+	apply(cmd)
+	view := m.View()
+	for _, want := range []string{"Clean Up Workspaces", "[x]", "done", "merged", "gone", "missing"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the cleanup view, got %q", want, view)
+		}
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updatedModel.(Model)
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	if !strings.Contains(m.View(), "Delete 1 workspace and their sessions?") {
+		t.Fatalf("expected a confirmation, got %q", m.View())
+	}
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	apply(cmd)
+
+	if len(fs.deleteWorktreeCalls) != 1 || fs.deleteWorktreeCalls[0].worktree != "/wt/api--gone" {
+		t.Fatalf("expected only the marked worktree to be deleted, got %+v", fs.deleteWorktreeCalls)
+	}
+	view = m.View()
+	if !strings.Contains(view, "Deleted 1 worktree.") || strings.Contains(view, "gone") {
+		t.Fatalf("expected the report and a refreshed list, got %q", view)
+	}
+}
+
+type branchDeletingFilesystem struct {
+	*fakeFilesystem
+	merged          bool
+	deletedBranches []string
+}
+
+func (b *branchDeletingFilesystem) BranchMerged(string, string) (bool, error) {
+	return b.merged, nil
+}
+
+func (b *branchDeletingFilesystem) DeleteBranch(_, branch string, force bool) error {
+	if force {
+		branch += " (forced)"
+	}
+	b.deletedBranches = append(b.deletedBranches, branch)
+	return nil
+}
+
+func TestDeleteWorktreeOffersToForceDeleteUnmergedBranch(t *testing.T) {
+	fs := &branchDeletingFilesystem{fakeFilesystem: &fakeFilesystem{}}
+	m := New([]string{"/projects"}, fs, nil)
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeWorktreeDeleteConfirm
+	m.core.SelectedProject = "/projects/api"
+	m.core.WorktreeDeletePath = "/wt/api--feature"
+	m.core.Worktrees = []core.Worktree{{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"}}
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updatedModel, cmd := m.Update(msg)
+		m = updatedModel.(Model)
+		for _, next := range runCmd(cmd) {
+			updatedModel, _ = m.Update(next)
+			m = updatedModel.(Model)
+		}
+	}
+
+	if !strings.Contains(m.View(), "[ ] Also delete branch feature") {
+		t.Fatalf("expected the branch option, got %q", m.View())
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlD})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(fs.deleteWorktreeCalls) != 0 || len(fs.deletedBranches) != 0 {
+		t.Fatalf("expected nothing to be deleted, got %+v and %v", fs.deleteWorktreeCalls, fs.deletedBranches)
+	}
+	if !strings.Contains(m.View(), "branch feature is not fully merged") {
+		t.Fatalf("expected an unmerged warning, got %q", m.View())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if !strings.Contains(m.View(), "force delete branch feature") {
+		t.Fatalf("expected the force option, got %q", m.View())
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(fs.deleteWorktreeCalls) != 1 || len(fs.deletedBranches) != 1 || fs.deletedBranches[0] != "feature (forced)" {
+		t.Fatalf("expected the worktree and a forced branch delete, got %+v and %v", fs.deleteWorktreeCalls, fs.deletedBranches)
+	}
+}
+
+func TestBulkDeleteSkipsWorktreesWithUnmergedBranches(t *testing.T) {
+	fs := &branchDeletingFilesystem{fakeFilesystem: &fakeFilesystem{}}
+	m := New([]string{"/projects"}, fs, nil)
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+	m.core.Worktrees = []core.Worktree{
+		{Path: "/wt/api--a", Name: "api--a", Branch: "a"},
+		{Path: "/wt/api--b", Name: "api--b", Branch: "b"},
+	}
+	m.core.FilteredWT = m.core.Worktrees
+	fs.listWorktreesListing = core.WorktreeListing{Worktrees: m.core.Worktrees}
+	m.syncLists()
+
+	// The branch check answers with a message that starts the delete, so
+	// its commands are run as well.
+	var press func(msg tea.Msg)
+	press = func(msg tea.Msg) {
+		t.Helper()
+		updatedModel, cmd := m.Update(msg)
+		m = updatedModel.(Model)
+		for _, next := range runCmd(cmd) {
+			if _, ok := next.(core.MsgBranchesChecked); ok {
+				press(next)
+				continue
+			}
+			updatedModel, _ = m.Update(next)
+			m = updatedModel.(Model)
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	press(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.View(), "2 workspaces marked") {
+		t.Fatalf("expected both workspaces to be marked, got %q", m.View())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if !strings.Contains(m.View(), "[ ] Also delete their branches") {
+		t.Fatalf("expected the bulk confirmation, got %q", m.View())
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlD})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(fs.deleteWorktreeCalls) != 0 || len(fs.deletedBranches) != 0 {
+		t.Fatalf("expected unmerged worktrees to be skipped, got %+v and %v", fs.deleteWorktreeCalls, fs.deletedBranches)
+	}
+	if view := m.View(); !strings.Contains(view, "api--a: branch a is not fully merged") {
+		t.Fatalf("expected the skipped worktrees to be reported, got %q", view)
+	}
+
+	fs.merged = true
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	press(tea.KeyMsg{Type: tea.KeyCtrlK})
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	press(tea.KeyMsg{Type: tea.KeyCtrlD})
+	press(tea.KeyMsg{Type: tea.KeyCtrlD})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(fs.deleteWorktreeCalls) != 2 || len(fs.deletedBranches) != 2 {
+		t.Fatalf("expected both worktrees and branches to be deleted, got %+v and %v", fs.deleteWorktreeCalls, fs.deletedBranches)
+	}
+	if view := m.View(); !strings.Contains(view, "Deleted 2 workspaces.") {
+		t.Fatalf("expected the report, got %q", view)
+	}
+}
+
+type historySessionManager struct {
+	fakeSessionManager
+	launches []core.Launch
+}
+
+func (h *historySessionManager) RecentLaunches() ([]core.Launch, error) {
+	return h.launches, nil
+}
+
+func (h *historySessionManager) RecordLaunch(core.SessionSpec) error { return nil }
+
+func TestRecentViewReopensLaunch(t *testing.T) {
+	sessions := &historySessionManager{
+		fakeSessionManager: fakeSessionManager{listSessionsResp: []core.SessionInfo{{Name: "web", DirPath: "/projects/web"}}},
+		launches: []core.Launch{
+			{ProjectPath: "/projects/api", WorktreePath: "/wt/api--fix", Branch: "fix", Tool: "amp"},
+			{ProjectPath: "/projects/web", WorktreePath: "/projects/web", Branch: "main", Tool: "claude"},
+		},
+	}
+	m := New(nil, &fakeFilesystem{}, sessions)
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeBrowsing
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	m = updatedModel.(Model)
+	for _, msg := range runCmd(cmd) {
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	view := stripANSI(m.View())
+	for _, want := range []string{"Recent Workspaces", "api/fix", "amp", "stopped", "web/main", "running"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the recent view, got %q", want, view)
+		}
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updatedModel.(Model)
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	want := core.SessionSpec{DirPath: "/projects/web", Tool: "claude"}
+	if m.SelectedSpec == nil || *m.SelectedSpec != want {
+		t.Fatalf("expected %+v to be selected, got %+v", want, m.SelectedSpec)
+	}
+}
+
+type pinningFilesystem struct {
+	*fakeFilesystem
+	pins       []string
+	pinCalls   []string
+	unpinCalls []string
+}
+
+func (p *pinningFilesystem) Pins() ([]string, error) { return p.pins, nil }
+
+func (p *pinningFilesystem) SetPinned(path string, pinned bool) error {
+	if pinned {
+		p.pinCalls = append(p.pinCalls, path)
+	} else {
+		p.unpinCalls = append(p.unpinCalls, path)
+	}
+	return nil
+}
+
+func TestPinnedProjectsShowStarAndPersist(t *testing.T) {
+	fs := &pinningFilesystem{fakeFilesystem: &fakeFilesystem{}, pins: []string{"/projects/web"}}
+	m := New([]string{"/projects"}, fs, nil)
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeBrowsing
+	m.core.Dirs = []core.DirEntry{{Path: "/projects/api", Name: "api"}, {Path: "/projects/web", Name: "web"}}
+
+	for _, msg := range runCmd(m.loadPinsCmd()) {
+		updatedModel, _ := m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "> "+pinGlyph+" web") {
+		t.Fatalf("expected web pinned on top, got %q", view)
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updatedModel.(Model)
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updatedModel.(Model)
+	for _, msg := range runCmd(cmd) {
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	if len(fs.pinCalls) != 1 || fs.pinCalls[0] != "/projects/api" {
+		t.Fatalf("expected api to be pinned, got %v", fs.pinCalls)
+	}
+	if !strings.Contains(stripANSI(m.View()), "> "+pinGlyph+" api") {
+		t.Fatalf("expected api pinned and selected, got %q", stripANSI(m.View()))
+	}
+}
+
+type taggingFilesystem struct {
+	*fakeFilesystem
+	tags    map[string][]string
+	setTags map[string][]string
+}
+
+func (f *taggingFilesystem) ProjectTags() (map[string][]string, error) { return f.tags, nil }
+
+func (f *taggingFilesystem) SetProjectTags(projectPath string, tags []string) error {
+	if f.setTags == nil {
+		f.setTags = make(map[string][]string)
+	}
+	f.setTags[projectPath] = tags
+	return nil
+}
+
+func TestTagEditorSavesProjectTags(t *testing.T) {
+	fs := &taggingFilesystem{fakeFilesystem: &fakeFilesystem{}}
+	m := New([]string{"/projects"}, fs, nil, WithTagRules(core.TagRules{"oss": {"api"}}))
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeBrowsing
+	m.core.Dirs = []core.DirEntry{{Path: "/projects/api", Name: "api", Exists: true}, {Path: "/projects/web", Name: "web", Exists: true}}
+	m.core.Filtered = m.core.Dirs
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = updatedModel.(Model)
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "Tags for api:") || !strings.Contains(view, "From config: oss") {
+		t.Fatalf("expected the tag editor for api, got %q", view)
+	}
+	for _, r := range "backend" {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updatedModel.(Model)
+	}
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	for _, msg := range runCmd(cmd) {
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+	if got := fs.setTags["/projects/api"]; len(got) != 1 || got[0] != "backend" {
+		t.Fatalf("expected api to be tagged backend, got %v", fs.setTags)
+	}
+
+	for _, r := range "tag:backend" {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updatedModel.(Model)
+	}
+	view = stripANSI(m.View())
+	if !strings.Contains(view, "api - /projects/api #oss #backend") || strings.Contains(view, "web - ") {
+		t.Fatalf("expected only api with its tags, got %q", view)
+	}
+}
@@ -1,10 +1,12 @@
 package ui
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/ariguillegp/rivet/internal/core"
 )
@@ -162,3 +164,235 @@ func TestUpdatePageDownMovesSelectionInBrowsing(t *testing.T) {
 		t.Fatalf("expected pgdown to move selection forward, got %d", next.core.SelectedIdx)
 	}
 }
+
+func TestUpdateUsesRemappedKeys(t *testing.T) {
+	m := New(nil, nil, nil, WithKeyBindings(core.KeyBindings{core.KeyDelete: {"alt+d"}}))
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+	m.core.Worktrees = []core.Worktree{{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"}}
+	m.core.FilteredWT = m.core.Worktrees
+	m.syncLists()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	next := updated.(Model)
+	if next.core.Mode != core.ModeWorktree {
+		t.Fatalf("expected ctrl+d to do nothing once remapped, got mode %v", next.core.Mode)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}, Alt: true})
+	next = updated.(Model)
+	if next.core.Mode != core.ModeWorktreeDeleteConfirm || next.core.WorktreeDeletePath != "/wt/api--feature" {
+		t.Fatalf("expected alt+d to ask to delete the worktree, got mode %v", next.core.Mode)
+	}
+}
+
+func TestPaletteRunsFilteredCommand(t *testing.T) {
+	m := newTestModel()
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+	m.core.Worktrees = []core.Worktree{{Path: "/wt/api--feature", Name: "api--feature", Branch: "feature"}}
+	m.core.FilteredWT = m.core.Worktrees
+	m.syncLists()
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if !m.showPalette {
+		t.Fatal("expected ctrl+p to open the palette")
+	}
+	view := stripANSI(m.View())
+	for _, part := range []string{"Land workspace", "ctrl+x", "Review changes"} {
+		if !strings.Contains(view, part) {
+			t.Fatalf("expected %q in the palette, got %q", part, view)
+		}
+	}
+	if strings.Contains(view, "Clean up workspaces") {
+		t.Fatalf("expected only the commands of Step 2, got %q", view)
+	}
+
+	for _, r := range "land" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if len(m.paletteCommands) != 1 || m.paletteCommands[0].action != core.KeyLand {
+		t.Fatalf("expected the land command alone, got %+v", m.paletteCommands)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.showPalette || m.core.Mode != core.ModeWorktreeLand || m.core.LandPath != "/wt/api--feature" {
+		t.Fatalf("expected the land screen, got mode %v", m.core.Mode)
+	}
+	if m.worktreeInput.Value() != "" {
+		t.Fatalf("expected the palette query to stay out of the worktree filter, got %q", m.worktreeInput.Value())
+	}
+}
+
+func TestPaletteOpensThemePicker(t *testing.T) {
+	m := newTestModel()
+	m.core.Mode = core.ModeBrowsing
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+	for _, r := range "theme" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.showPalette || !m.showThemePicker {
+		t.Fatalf("expected the theme picker, got palette=%v picker=%v", m.showPalette, m.showThemePicker)
+	}
+}
+
+func TestPaletteRunsCommandReachedByKeys(t *testing.T) {
+	m := newTestModel()
+	m.core.Mode = core.ModeBrowsing
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+
+	target := slices.IndexFunc(m.paletteCommands, func(c command) bool { return c.action == core.KeyTheme })
+	if target < 2 {
+		t.Fatalf("expected the theme command below the first rows, got index %d in %+v", target, m.paletteCommands)
+	}
+	for i := 0; i < target; i++ {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+	}
+	if m.paletteList.Index() != target {
+		t.Fatalf("expected row %d highlighted, got %d", target, m.paletteList.Index())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.showPalette || !m.showThemePicker {
+		t.Fatalf("expected the highlighted theme command to run, got palette=%v picker=%v", m.showPalette, m.showThemePicker)
+	}
+}
+
+// screenPos returns where text appears in the rendered frame.
+func screenPos(t *testing.T, m Model, text string) (x, y int) {
+	t.Helper()
+	for y, line := range strings.Split(stripANSI(m.View()), "\n") {
+		if idx := strings.Index(line, text); idx >= 0 {
+			return ansi.StringWidth(line[:idx]), y
+		}
+	}
+	t.Fatalf("expected %q on screen", text)
+	return 0, 0
+}
+
+func mouseWorktreeModel() Model {
+	m := newTestModel()
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeWorktree
+	m.core.SelectedProject = "/projects/api"
+	m.core.Worktrees = []core.Worktree{
+		{Path: "/wt/api--alpha", Name: "api--alpha", Branch: "alpha"},
+		{Path: "/wt/api--beta", Name: "api--beta", Branch: "beta"},
+		{Path: "/wt/api--gamma", Name: "api--gamma", Branch: "gamma"},
+	}
+	m.core.FilteredWT = m.core.Worktrees
+	m.syncLists()
+	return m
+}
+
+func TestMouseClickSelectsAndDoubleClickActivates(t *testing.T) {
+	m := mouseWorktreeModel()
+	click := func(x, y int) {
+		t.Helper()
+		updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+		m = updated.(Model)
+	}
+
+	x, y := screenPos(t, m, "gamma")
+	click(x, y)
+	if m.core.Mode != core.ModeWorktree || m.core.WorktreeIdx != 2 {
+		t.Fatalf("expected a click to select gamma, got mode %v and index %d", m.core.Mode, m.core.WorktreeIdx)
+	}
+
+	click(x, y)
+	if m.core.Mode != core.ModeTool || m.core.SelectedWorktreePath != "/wt/api--gamma" {
+		t.Fatalf("expected a double click to open gamma, got mode %v and %q", m.core.Mode, m.core.SelectedWorktreePath)
+	}
+}
+
+func TestMouseWheelMovesSelectionAndBreadcrumbGoesBack(t *testing.T) {
+	m := mouseWorktreeModel()
+
+	updated, _ := m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.core.WorktreeIdx != 1 {
+		t.Fatalf("expected the wheel to move down, got index %d", m.core.WorktreeIdx)
+	}
+
+	x, y := screenPos(t, m, "Project: api")
+	updated, _ = m.Update(tea.MouseMsg{X: x + 2, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.core.Mode != core.ModeBrowsing {
+		t.Fatalf("expected the breadcrumb to go back to Step 1, got mode %v", m.core.Mode)
+	}
+}
+
+func TestMouseClickSelectsSessionTableRow(t *testing.T) {
+	m := newTestModel()
+	m.width, m.height = 120, 40
+	sessions := []core.SessionInfo{
+		{Name: "api/alpha", Project: "api", Branch: "alpha", DirPath: "/wt/api--alpha"},
+		{Name: "web/beta", Project: "web", Branch: "beta", DirPath: "/wt/web--beta"},
+	}
+	m.core.Mode = core.ModeSessions
+	m.core.Sessions = sessions
+	m.core.FilteredSessions = sessions
+	m.syncLists()
+
+	x, y := screenPos(t, m, "beta")
+	updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.core.SessionIdx != 1 {
+		t.Fatalf("expected the click to select the second session, got %d", m.core.SessionIdx)
+	}
+}
+
+func TestMouseWheelScrollsHelpViewport(t *testing.T) {
+	m := newTestModel()
+	m.width, m.height = 120, 12
+	m.core.Mode = core.ModeBrowsing
+	m.showHelp = true
+	m.View()
+
+	updated, _ := m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.viewport.YOffset == 0 {
+		t.Fatal("expected the wheel to scroll the help viewport")
+	}
+}
+
+func TestMouseWheelMovesPaletteSelection(t *testing.T) {
+	m := newTestModel()
+	m.core.Mode = core.ModeBrowsing
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.paletteList.Index() != 1 {
+		t.Fatalf("expected the wheel to highlight the second command, got %d", m.paletteList.Index())
+	}
+	updated, _ = m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.paletteList.Index() != 0 {
+		t.Fatalf("expected the wheel to move back up, got %d", m.paletteList.Index())
+	}
+}
+
+func TestWithStartOpensWorktreeStepWithQuery(t *testing.T) {
+	m := New(nil, nil, nil, WithStart(core.StartState{ProjectPath: "/projects/api", WorktreeQuery: "feature"}))
+	if m.core.Mode != core.ModeWorktree || m.core.SelectedProject != "/projects/api" {
+		t.Fatalf("expected Step 2 for api, got mode %v", m.core.Mode)
+	}
+	if m.input.Focused() || !m.worktreeInput.Focused() || m.worktreeInput.Value() != "feature" {
+		t.Fatalf("expected the workspace filter to be focused with the query, got %q", m.worktreeInput.Value())
+	}
+}
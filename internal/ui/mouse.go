@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// doubleClickInterval is how soon a second click on the same row has to
+// follow the first to activate it.
+const doubleClickInterval = 400 * time.Millisecond
+
+const breadcrumbSeparator = "  •  "
+
+// mouseClick remembers the last row clicked to detect double clicks.
+type mouseClick struct {
+	mode core.Mode
+	row  int
+	at   time.Time
+}
+
+func (m *Model) updateMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress {
+		return nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		return m.scrollMouse(msg)
+	case tea.MouseButtonLeft:
+		if m.showHelp || m.showThemePicker || m.showPalette {
+			return nil
+		}
+		if target, ok := m.breadcrumbAt(msg.X, msg.Y); ok {
+			return m.navigateBack(target)
+		}
+		if row, ok := m.rowAt(msg.Y); ok {
+			return m.clickRow(row, time.Now())
+		}
+	}
+	return nil
+}
+
+// scrollMouse moves through the open list, or scrolls the help and
+// confirmation viewport.
+func (m *Model) scrollMouse(msg tea.MouseMsg) tea.Cmd {
+	delta := 1
+	action := core.KeyDown
+	if msg.Button == tea.MouseButtonWheelUp {
+		delta = -1
+		action = core.KeyUp
+	}
+	switch {
+	case m.showThemePicker:
+		m.themeList.Select(m.themeList.Index() + delta)
+		m.previewSelectedTheme()
+		return nil
+	case m.showPalette:
+		m.movePaletteSelection(delta)
+		return nil
+	case m.isViewportActive():
+		m.syncViewportContent()
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return cmd
+	case m.core.Mode == core.ModeLoading:
+		return nil
+	}
+	effects, _ := m.applyKeyAction(action)
+	return m.runEffects(effects)
+}
+
+// clickRow highlights a row and activates it when it was clicked twice in a
+// row.
+func (m *Model) clickRow(row int, now time.Time) tea.Cmd {
+	mode := m.core.Mode
+	coreModel, effects := core.Update(m.core, core.MsgRowSelected{Index: row})
+	m.core = coreModel
+	m.syncLists()
+
+	last := m.lastClick
+	if last.mode == mode && last.row == row && now.Sub(last.at) <= doubleClickInterval {
+		m.lastClick = mouseClick{}
+		enterEffects, _ := m.applyKeyAction(core.KeyEnter)
+		effects = append(effects, enterEffects...)
+	} else {
+		m.lastClick = mouseClick{mode: mode, row: row, at: now}
+	}
+	return m.runEffects(effects)
+}
+
+// navigateBack goes back step by step until the mode of a clicked
+// breadcrumb item is reached.
+func (m *Model) navigateBack(target core.Mode) tea.Cmd {
+	var effects []core.Effect
+	// Back may first clear marks or a commit message, so allow a few steps.
+	for range 4 {
+		if m.core.Mode == target {
+			break
+		}
+		backEffects, handled := m.applyKeyAction(core.KeyBack)
+		effects = append(effects, backEffects...)
+		if !handled {
+			break
+		}
+	}
+	return m.runEffects(effects)
+}
+
+// screenLines returns the lines of the current frame without styling; the
+// frame fills the terminal, so a mouse Y is an index into it.
+func (m Model) screenLines() []string {
+	return strings.Split(ansi.Strip(m.View()), "\n")
+}
+
+// rowAt maps a screen line to the row of the list shown in the current
+// mode.
+func (m Model) rowAt(y int) (int, bool) {
+	m.syncLists()
+	var start int
+	var rows []string
+	switch m.core.Mode {
+	case core.ModeBrowsing:
+		start, rows = m.projectList.VisibleRows()
+	case core.ModeWorktree:
+		start, rows = m.worktreeList.VisibleRows()
+	case core.ModeTool:
+		start, rows = m.toolList.VisibleRows()
+	case core.ModeRecent:
+		start, rows = m.recentList.VisibleRows()
+	case core.ModeSessions:
+		if m.sessionListIsCompact() {
+			start, rows = m.sessionList.VisibleRows()
+		} else {
+			rows = tableRowTexts(m.sessionTable)
+		}
+	default:
+		return 0, false
+	}
+	row, ok := matchRow(m.screenLines(), y, rows)
+	return start + row, ok
+}
+
+// tableRowTexts renders each table row the way the table lays out its cells,
+// which is enough to recognize it on screen.
+func tableRowTexts(t table.Model) []string {
+	columns := t.Columns()
+	texts := make([]string, 0, len(t.Rows()))
+	for _, row := range t.Rows() {
+		cells := make([]string, 0, len(row))
+		for i, cell := range row {
+			if i < len(columns) {
+				cell = ansi.Truncate(cell, columns[i].Width, "…")
+			}
+			cells = append(cells, cell)
+		}
+		texts = append(texts, strings.Join(cells, " "))
+	}
+	return texts
+}
+
+// matchRow finds which of rows the screen line y shows. Rows appear on
+// screen in order and may wrap, so lines are compared without whitespace or
+// box borders: a line starts a row when it is a prefix of it, and continues
+// the row above when both lines together still are.
+func matchRow(lines []string, y int, rows []string) (int, bool) {
+	if y < 0 || y >= len(lines) {
+		return 0, false
+	}
+	compacted := make([]string, len(rows))
+	for i, row := range rows {
+		compacted[i] = compactLine(ansi.Strip(row))
+	}
+
+	next, current := 0, -1
+	var consumed string
+	for i := 0; i <= y; i++ {
+		text := compactLine(lines[i])
+		switch {
+		case text == "":
+			current = -1
+		case current >= 0 && strings.HasPrefix(compacted[current], consumed+text):
+			consumed += text
+		default:
+			current = findRow(compacted, next, text)
+			if current >= 0 {
+				consumed = text
+				next = current + 1
+			}
+		}
+	}
+	return current, current >= 0
+}
+
+// findRow returns the first row from start that text matches exactly, or
+// failing that the first one it is a prefix of.
+func findRow(rows []string, start int, text string) int {
+	for i := start; i < len(rows); i++ {
+		if rows[i] == text {
+			return i
+		}
+	}
+	for i := start; i < len(rows); i++ {
+		if strings.HasPrefix(rows[i], text) {
+			return i
+		}
+	}
+	return -1
+}
+
+func compactLine(line string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '│' {
+			return -1
+		}
+		return r
+	}, line)
+}
+
+// breadcrumbAt returns the step of the breadcrumb item at x, y when clicking
+// it goes back to an earlier step.
+func (m Model) breadcrumbAt(x, y int) (core.Mode, bool) {
+	switch m.core.Mode {
+	case core.ModeWorktree, core.ModeTool, core.ModeReview, core.ModeWorktreeLand:
+	default:
+		return 0, false
+	}
+	crumb := ansi.Strip(m.renderBreadcrumb())
+	lines := m.screenLines()
+	if crumb == "" || y < 0 || y >= len(lines) {
+		return 0, false
+	}
+	col := strings.Index(lines[y], crumb)
+	if col < 0 {
+		return 0, false
+	}
+
+	left := ansi.StringWidth(lines[y][:col])
+	for _, item := range strings.Split(crumb, breadcrumbSeparator) {
+		right := left + ansi.StringWidth(item)
+		if x >= left && x < right {
+			var target core.Mode
+			switch {
+			case strings.HasPrefix(item, "Project:"):
+				target = core.ModeBrowsing
+			case strings.HasPrefix(item, "Workspace:"):
+				target = core.ModeWorktree
+			default:
+				return 0, false
+			}
+			return target, target != m.core.Mode
+		}
+		left = right + ansi.StringWidth(breadcrumbSeparator)
+	}
+	return 0, false
+}
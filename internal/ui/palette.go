@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ariguillegp/rivet/internal/core"
+)
+
+// openPalette lists the commands of the current mode in the command palette.
+func (m *Model) openPalette() {
+	m.showPalette = true
+	m.showHelp = false
+	m.paletteInput.SetValue("")
+	m.refreshPaletteFilter()
+	m.blurInputs()
+	m.paletteInput.Focus()
+}
+
+func (m *Model) closePalette() {
+	m.showPalette = false
+	m.paletteInput.SetValue("")
+	m.paletteCommands = nil
+	m.paletteList.Select(0)
+	m.paletteInput.Blur()
+	m.restoreInputFocus()
+}
+
+func (m *Model) refreshPaletteFilter() {
+	m.paletteCommands = core.FilterByName(m.keymap.paletteCommands(m.core.Mode), m.paletteInput.Value(), func(c command) string {
+		return c.name
+	})
+	m.syncPaletteList()
+	m.paletteList.Select(0)
+}
+
+// movePaletteSelection moves the highlighted command by delta rows.
+func (m *Model) movePaletteSelection(delta int) {
+	m.paletteList.Select(m.paletteList.Index() + delta)
+}
+
+// runPaletteCommand closes the palette and runs the highlighted command as
+// if its key had been pressed.
+func (m *Model) runPaletteCommand() tea.Cmd {
+	idx := m.paletteList.Index()
+	if idx < 0 || idx >= len(m.paletteCommands) {
+		return nil
+	}
+	cmd := m.paletteCommands[idx]
+	m.closePalette()
+
+	switch cmd.action {
+	case core.KeyHelp:
+		m.showHelp = true
+		m.blurInputs()
+		return nil
+	case core.KeyTheme:
+		m.openThemePicker()
+		return nil
+	}
+	effects, _ := m.applyKeyAction(cmd.action)
+	m.syncLists()
+	return m.runEffects(effects)
+}
+
+func (m *Model) syncPaletteList() {
+	rows := make([]suggestionItem, 0, len(m.paletteCommands))
+	for _, cmd := range m.paletteCommands {
+		rows = append(rows, suggestionItem{primary: cmd.name, detail: keyName(cmd.binding)})
+	}
+	m.paletteList.SetItems(toItems(rows))
+	m.paletteList.SetHeight(listHeight(m.listLimit(), len(rows)))
+}
+
+func (m Model) renderPalette() string {
+	header := m.styles.Title.Render("Commands")
+	prompt := m.styles.Prompt.Render("Filter commands:")
+	input := prompt + " " + m.paletteInput.View()
+
+	var content string
+	if len(m.paletteList.Items()) > 0 {
+		content = input + "\n" + m.paletteList.View() + m.renderCount(m.paletteList)
+	} else {
+		content = input + "\n" + m.styles.EmptyState.Render("No matching commands.")
+	}
+
+	help := m.help.ShortHelpView([]key.Binding{m.keymap.binding(m.keymap.Select, "run"), m.keymap.binding(m.keymap.Back, "cancel")})
+	content = header + "\n\n" + content + "\n\n" + help
+
+	box := m.renderModalBox(content, false)
+	if m.height <= 0 || m.width <= 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -20,6 +20,7 @@ type Styles struct {
 	Body               lipgloss.Style
 	Error              lipgloss.Style
 	Warning            lipgloss.Style
+	Success            lipgloss.Style
 	DestructiveTitle   lipgloss.Style
 	DestructiveText    lipgloss.Style
 	DestructiveAction  lipgloss.Style
@@ -31,6 +32,10 @@ type Styles struct {
 	Path               lipgloss.Style
 	SelectedPath       lipgloss.Style
 	EmptyState         lipgloss.Style
+	DiffAdded          lipgloss.Style
+	DiffRemoved        lipgloss.Style
+	DiffHunk           lipgloss.Style
+	DiffHeader         lipgloss.Style
 	BaseBox            lipgloss.Style
 }
 
@@ -64,6 +69,8 @@ func NewStyles(theme Theme) Styles {
 			Foreground(theme.Error),
 		Warning: lipgloss.NewStyle().
 			Foreground(theme.Warning),
+		Success: lipgloss.NewStyle().
+			Foreground(theme.Success),
 		DestructiveTitle: lipgloss.NewStyle().
 			Foreground(theme.Text).
 			Bold(true),
@@ -98,6 +105,15 @@ func NewStyles(theme Theme) Styles {
 		EmptyState: lipgloss.NewStyle().
 			Foreground(theme.Muted).
 			Italic(true),
+		DiffAdded: lipgloss.NewStyle().
+			Foreground(theme.Success),
+		DiffRemoved: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		DiffHunk: lipgloss.NewStyle().
+			Foreground(theme.Accent),
+		DiffHeader: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Bold(true),
 		BaseBox: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(theme.Accent).
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type closer interface {
+	comparable
+	Close() error
+}
+
+// subscriptionSlot holds a live subscription such as the session or project
+// watcher. It is shared by pointer because Bubble Tea copies the model on
+// every update.
+type subscriptionSlot[S closer] struct {
+	mu  sync.Mutex
+	sub S
+}
+
+// replace stores sub and returns a command closing the subscription it
+// replaced, if any, so a slow Close does not hold up the update.
+func (w *subscriptionSlot[S]) replace(sub S) tea.Cmd {
+	var zero S
+	w.mu.Lock()
+	previous := w.sub
+	w.sub = sub
+	w.mu.Unlock()
+	if previous == zero || previous == sub {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = previous.Close()
+		return nil
+	}
+}
+
+func (w *subscriptionSlot[S]) take() S {
+	var zero S
+	if w == nil {
+		return zero
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	sub := w.sub
+	w.sub = zero
+	return sub
+}
+
+func (w *subscriptionSlot[S]) isCurrent(sub S) bool {
+	var zero S
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.sub != zero && w.sub == sub
+}
@@ -11,6 +11,7 @@ type Theme struct {
 	Accent     lipgloss.Color
 	Error      lipgloss.Color
 	Warning    lipgloss.Color
+	Success    lipgloss.Color
 	Muted      lipgloss.Color
 	Text       lipgloss.Color
 	Background lipgloss.Color
@@ -22,6 +23,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#c5a97a"),
 		Error:      lipgloss.Color("#e06c75"),
 		Warning:    lipgloss.Color("#f28b2b"),
+		Success:    lipgloss.Color("#b8bb26"),
 		Muted:      lipgloss.Color("#6a6a6a"),
 		Text:       lipgloss.Color("#ffffff"),
 		Background: lipgloss.Color("#3a3a3a"),
@@ -31,6 +33,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#cba6f7"),
 		Error:      lipgloss.Color("#f38ba8"),
 		Warning:    lipgloss.Color("#f28c2a"),
+		Success:    lipgloss.Color("#a6e3a1"),
 		Muted:      lipgloss.Color("#6c7086"),
 		Text:       lipgloss.Color("#cdd6f4"),
 		Background: lipgloss.Color("#313244"),
@@ -40,6 +43,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#7aa2f7"),
 		Error:      lipgloss.Color("#f7768e"),
 		Warning:    lipgloss.Color("#f28b2b"),
+		Success:    lipgloss.Color("#9ece6a"),
 		Muted:      lipgloss.Color("#565f89"),
 		Text:       lipgloss.Color("#c0caf5"),
 		Background: lipgloss.Color("#24283b"),
@@ -49,6 +53,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#88c0d0"),
 		Error:      lipgloss.Color("#bf616a"),
 		Warning:    lipgloss.Color("#f28b2b"),
+		Success:    lipgloss.Color("#a3be8c"),
 		Muted:      lipgloss.Color("#4c566a"),
 		Text:       lipgloss.Color("#eceff4"),
 		Background: lipgloss.Color("#3b4252"),
@@ -58,6 +63,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#bd93f9"),
 		Error:      lipgloss.Color("#ff5555"),
 		Warning:    lipgloss.Color("#ff8c1a"),
+		Success:    lipgloss.Color("#50fa7b"),
 		Muted:      lipgloss.Color("#6272a4"),
 		Text:       lipgloss.Color("#f8f8f2"),
 		Background: lipgloss.Color("#44475a"),
@@ -67,6 +73,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#268bd2"),
 		Error:      lipgloss.Color("#dc322f"),
 		Warning:    lipgloss.Color("#b58900"),
+		Success:    lipgloss.Color("#859900"),
 		Muted:      lipgloss.Color("#586e75"),
 		Text:       lipgloss.Color("#eee8d5"),
 		Background: lipgloss.Color("#002b36"),
@@ -76,6 +83,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#61afef"),
 		Error:      lipgloss.Color("#e06c75"),
 		Warning:    lipgloss.Color("#e5c07b"),
+		Success:    lipgloss.Color("#98c379"),
 		Muted:      lipgloss.Color("#5c6370"),
 		Text:       lipgloss.Color("#abb2bf"),
 		Background: lipgloss.Color("#282c34"),
@@ -85,6 +93,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#a6e22e"),
 		Error:      lipgloss.Color("#f92672"),
 		Warning:    lipgloss.Color("#fd971f"),
+		Success:    lipgloss.Color("#a6e22e"),
 		Muted:      lipgloss.Color("#75715e"),
 		Text:       lipgloss.Color("#f8f8f2"),
 		Background: lipgloss.Color("#272822"),
@@ -94,6 +103,7 @@ var themes = []Theme{
 		Accent:     lipgloss.Color("#c4a7e7"),
 		Error:      lipgloss.Color("#eb6f92"),
 		Warning:    lipgloss.Color("#f6c177"),
+		Success:    lipgloss.Color("#9ccfd8"),
 		Muted:      lipgloss.Color("#6e6a86"),
 		Text:       lipgloss.Color("#e0def4"),
 		Background: lipgloss.Color("#191724"),
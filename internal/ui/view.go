@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/ariguillegp/rivet/internal/core"
 )
@@ -22,6 +23,9 @@ func (m Model) View() string {
 	if m.showThemePicker {
 		return m.renderThemePicker()
 	}
+	if m.showPalette {
+		return m.renderPalette()
+	}
 
 	var content string
 	var helpLine string
@@ -41,8 +45,47 @@ func (m Model) View() string {
 		if len(m.projectList.Items()) > 0 {
 			content = input + "\n" + m.projectList.View() + m.renderCount(m.projectList)
 		} else {
-			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press esc to quit.")
+			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press "+keyName(m.keymap.Back)+" to quit.")
+		}
+		if m.core.Scanning {
+			content += "\n" + m.spinner.View() + " Scanning..."
+		}
+		if m.core.CloneError != "" {
+			content += "\n" + m.styles.Error.Render(m.core.CloneError)
+		}
+		if marked := len(m.core.MarkedDirs()); marked > 0 {
+			content += "\n" + m.styles.Help.Render(countNoun(marked, "project")+" marked; "+keyName(m.keymap.Delete)+" deletes them, "+keyName(m.keymap.Back)+" clears the marks.")
+		}
+		if warning := core.AgeTermWarning(m.core.Query); warning != "" {
+			content += "\n" + m.styles.Warning.Render("⚠ "+warning)
+		}
+		if m.core.BrowseWarning != "" {
+			content += "\n" + m.styles.Warning.Render("⚠ "+m.core.BrowseWarning)
+		}
+		if m.core.BrowseNotice != "" {
+			content += "\n" + m.styles.Success.Render("✓ "+m.core.BrowseNotice)
+		}
+		if notice := m.legacySessionsNotice(); notice != "" {
+			content += "\n" + m.styles.Warning.Render("⚠ "+notice)
+		}
+		helpLine = m.shortHelpView()
+
+	case core.ModeProjectCloning:
+		header = m.styles.Title.Render("Step 1: Clone Project")
+		phase := m.core.CloneProgress.Phase
+		if phase == "" {
+			phase = "Starting git clone..."
 		}
+		bar := m.progress.ViewAs(float64(m.core.CloneProgress.Percent) / 100)
+		content = fmt.Sprintf("%s\n%s\n\n%s %s\n%s",
+			m.core.CloningSource,
+			m.styles.Path.Render("into "+m.displayPath(m.core.CloningPath)),
+			m.spinner.View(), phase, bar)
+		helpLine = m.shortHelpView()
+
+	case core.ModeProjectTag:
+		header = m.styles.Title.Render("Tag Project")
+		content = m.projectTagContent()
 		helpLine = m.shortHelpView()
 
 	case core.ModeProjectDeleteConfirm:
@@ -52,6 +95,12 @@ func (m Model) View() string {
 			content = m.renderViewportContent(viewportContent)
 		}
 
+	case core.ModeSessionMigrateConfirm:
+		header = m.styles.Title.Render("Legacy Sessions")
+		if viewportContent, ok := m.modalViewportContent(); ok {
+			content = m.renderViewportContent(viewportContent)
+		}
+
 	case core.ModeWorktree:
 		header = m.styles.Title.Render("Step 2: Select Workspace")
 		breadcrumb = m.renderBreadcrumb()
@@ -60,7 +109,16 @@ func (m Model) View() string {
 		if len(m.worktreeList.Items()) > 0 {
 			content = input + "\n" + m.worktreeList.View() + m.renderCount(m.worktreeList)
 		} else {
-			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press esc to go back.")
+			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press "+keyName(m.keymap.Back)+" to go back.")
+		}
+		if m.core.ShowAllWorktrees {
+			content += "\n" + m.styles.Help.Render("Showing all worktrees; press "+keyName(m.keymap.Adopt)+" to adopt an unmanaged one.")
+		}
+		if marked := len(m.core.MarkedWorktreeList()); marked > 0 {
+			content += "\n" + m.styles.Help.Render(countNoun(marked, "workspace")+" marked; "+keyName(m.keymap.Select)+" opens a tool in all of them, "+keyName(m.keymap.Delete)+" deletes them, "+keyName(m.keymap.Back)+" clears the marks.")
+		}
+		if warning := core.AgeTermWarning(m.core.WorktreeQuery); warning != "" {
+			content += "\n" + m.styles.Warning.Render("⚠ "+warning)
 		}
 		if m.core.ProjectWarning != "" {
 			content += "\n" + m.styles.Warning.Render("⚠ "+m.core.ProjectWarning)
@@ -68,6 +126,43 @@ func (m Model) View() string {
 		if m.core.WorktreeWarning != "" {
 			content += "\n" + m.styles.Warning.Render("⚠ "+m.core.WorktreeWarning)
 		}
+		if m.core.WorktreeNotice != "" {
+			content += "\n" + m.styles.Success.Render("✓ "+m.core.WorktreeNotice)
+		}
+		helpLine = m.shortHelpView()
+
+	case core.ModeWorktreeLand:
+		header = m.styles.Title.Render("Land Workspace")
+		breadcrumb = m.renderBreadcrumb()
+		label := filepath.Base(m.core.LandPath)
+		for _, wt := range m.core.Worktrees {
+			if wt.Path == m.core.LandPath {
+				label = m.worktreeDisplayLabel(wt)
+			}
+		}
+		prompt := m.styles.Prompt.Render("Land " + label + " into the branch checked out in the project root:")
+		checkbox := "[ ]"
+		if m.core.LandDelete {
+			checkbox = "[x]"
+		}
+		content = prompt + "\n" + m.landList.View() + "\n\n" +
+			m.styles.Body.Render(checkbox+" Delete the workspace and its sessions afterwards")
+		if m.core.Landing {
+			content += "\n\n" + m.spinner.View() + " Landing..."
+		}
+		helpLine = m.shortHelpView()
+
+	case core.ModeReview:
+		header = m.styles.Title.Render("Review Changes")
+		breadcrumb = m.renderBreadcrumb()
+		top, bottom := m.reviewSections()
+		content = top
+		if _, ok := m.core.SelectedReviewFile(); ok {
+			content += "\n\n" + m.renderDiff()
+		}
+		if bottom != "" {
+			content += "\n" + bottom
+		}
 		helpLine = m.shortHelpView()
 
 	case core.ModeWorktreeDeleteConfirm:
@@ -80,22 +175,44 @@ func (m Model) View() string {
 	case core.ModeTool:
 		header = m.styles.Title.Render("Step 3: Select Tool")
 		breadcrumb = m.renderBreadcrumb()
-		prompt := m.styles.Prompt.Render("Select tool:")
+		promptText := "Select tool:"
+		if targets := len(m.core.ToolTargets); targets > 1 {
+			promptText = "Select tool to open in " + countNoun(targets, "workspace") + ":"
+		}
+		prompt := m.styles.Prompt.Render(promptText)
 		input := prompt + " " + m.toolInput.View()
 		if len(m.toolList.Items()) > 0 {
 			content = input + "\n" + m.toolList.View() + m.renderCount(m.toolList)
 		} else {
-			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press esc to go back.")
+			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press "+keyName(m.keymap.Back)+" to go back.")
+		}
+		if m.core.SetupWarning != "" {
+			content += "\n" + m.styles.Warning.Render("⚠ Workspace setup: "+m.core.SetupWarning)
 		}
 		if m.core.ToolError != "" {
 			content += "\n" + m.styles.Error.Render(m.core.ToolError)
 		}
 		helpLine = m.shortHelpView()
 
+	case core.ModeWorktreeSetup:
+		header = m.styles.Title.Render("Setting Up Workspace")
+		breadcrumb = m.renderBreadcrumb()
+		content = m.spinner.View() + " Running workspace setup..."
+		if lines := m.core.SetupOutput; len(lines) > 0 {
+			lines = lines[max(0, len(lines)-m.listLimit()):]
+			content += "\n\n" + m.styles.Path.Render(strings.Join(lines, "\n"))
+		}
+		helpLine = m.shortHelpView()
+
 	case core.ModeToolStarting:
 		toolName := "tool"
 		if m.core.PendingSpec != nil && m.core.PendingSpec.Tool != "" {
 			toolName = m.core.PendingSpec.Tool
+		} else if tool, ok := m.core.SelectedTool(); ok {
+			toolName = tool
+		}
+		if targets := len(m.core.ToolTargets); targets > 1 {
+			toolName += " in " + countNoun(targets, "workspace")
 		}
 		breadcrumb = m.renderBreadcrumb()
 		progressValue := m.toolStartingProgress()
@@ -107,14 +224,17 @@ func (m Model) View() string {
 		header = m.styles.Title.Render("Active tmux sessions")
 		breadcrumb = m.renderBreadcrumb()
 		if len(m.core.Sessions) == 0 {
-			content = m.styles.EmptyState.Render("No active sessions. Press esc to return.")
+			content = m.styles.EmptyState.Render("No active sessions. Press " + keyName(m.keymap.Back) + " to return.")
+			if m.core.SessionNotice != "" {
+				content = m.styles.Success.Render("✓ "+m.core.SessionNotice) + "\n" + content
+			}
 			helpLine = m.sessionsEmptyShortHelpView()
 			break
 		}
 		prompt := m.styles.Prompt.Render("Filter sessions:")
 		input := prompt + " " + m.sessionInput.View()
 		if len(m.core.FilteredSessions) == 0 {
-			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press esc to return.")
+			content = input + "\n" + m.styles.EmptyState.Render("No matches. Press "+keyName(m.keymap.Back)+" to return.")
 			helpLine = m.shortHelpView()
 			break
 		}
@@ -124,6 +244,17 @@ func (m Model) View() string {
 			tableView := m.styles.Path.Render(m.sessionTable.View())
 			content = input + "\n" + tableView + m.renderTableCount(m.sessionTable)
 		}
+		content += m.sessionsFooter()
+		helpLine = m.shortHelpView()
+
+	case core.ModeRecent:
+		header = m.styles.Title.Render("Recent Workspaces")
+		content = m.recentContent()
+		helpLine = m.shortHelpView()
+
+	case core.ModeCleanup:
+		header = m.styles.Title.Render("Clean Up Workspaces")
+		content = m.cleanupContent()
 		helpLine = m.shortHelpView()
 
 	case core.ModeError:
@@ -133,6 +264,13 @@ func (m Model) View() string {
 		helpLine = m.shortHelpView()
 	}
 
+	switch m.core.Mode {
+	case core.ModeBrowsing, core.ModeWorktree, core.ModeTool, core.ModeCleanup:
+		if m.core.HookWarning != "" {
+			content += "\n" + m.styles.Warning.Render("⚠ "+m.core.HookWarning)
+		}
+	}
+
 	if header != "" {
 		if breadcrumb != "" {
 			content = header + "\n" + breadcrumb + "\n\n" + content
@@ -153,6 +291,179 @@ func (m Model) View() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
 }
 
+func (m Model) cleanupContent() string {
+	if len(m.core.CleanupCandidates) == 0 {
+		var content string
+		switch {
+		case m.core.CleanupLoading:
+			content = m.spinner.View() + " Looking for stale workspaces..."
+		case m.core.CleanupWarning != "":
+			content = m.styles.Warning.Render("⚠ " + m.core.CleanupWarning)
+		default:
+			content = m.styles.EmptyState.Render("No merged, missing or inactive workspaces. Press " + keyName(m.keymap.Back) + " to go back.")
+		}
+		if m.core.CleanupNotice != "" {
+			content = m.styles.Success.Render("✓ "+m.core.CleanupNotice) + "\n" + content
+		}
+		return content
+	}
+
+	days := int(m.core.CleanupInactiveFor.Hours() / 24)
+	prompt := m.styles.Prompt.Render(fmt.Sprintf("Workspaces that are merged, missing, or unused for %d days:", days))
+	checkbox := "[ ]"
+	if m.core.CleanupBranches {
+		checkbox = "[x]"
+	}
+	content := prompt + "\n" + m.styles.Path.Render(m.cleanupTable.View()) + m.renderTableCount(m.cleanupTable) + "\n\n" +
+		m.styles.Body.Render(checkbox+" Also delete their branches when fully merged")
+
+	selected := len(m.core.SelectedCleanupWorktrees())
+	switch {
+	case m.core.CleanupRunning:
+		content += "\n\n" + m.spinner.View() + " Deleting workspaces..."
+	case m.core.CleanupConfirm:
+		content += "\n\n" + m.styles.Warning.Render("Delete "+countNoun(selected, "workspace")+" and their sessions? Press "+keyName(m.keymap.Select)+" to confirm or "+keyName(m.keymap.Back)+" to cancel.")
+	case m.core.CleanupLoading:
+		content += "\n\n" + m.spinner.View() + " Refreshing..."
+	}
+	if m.core.CleanupNotice != "" {
+		content += "\n" + m.styles.Success.Render("✓ "+m.core.CleanupNotice)
+	}
+	if m.core.CleanupWarning != "" {
+		content += "\n" + m.styles.Warning.Render("⚠ "+m.core.CleanupWarning)
+	}
+	return content
+}
+
+func (m Model) projectTagContent() string {
+	name := filepath.Base(m.core.TagProjectPath)
+	prompt := m.styles.Prompt.Render("Tags for " + name + ":")
+	lines := []string{
+		prompt + " " + m.tagInput.View(),
+		m.styles.Help.Render("Separate tags with commas or spaces; filter Step 1 with " + core.TagPrefix + "name."),
+	}
+	if fromConfig := m.core.TagRules.Match(core.DirEntry{Path: m.core.TagProjectPath, Name: name}); len(fromConfig) > 0 {
+		lines = append(lines, m.styles.Path.Render("From config: "+strings.Join(fromConfig, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) recentContent() string {
+	var content string
+	switch {
+	case len(m.core.RecentLaunches) > 0:
+		prompt := m.styles.Prompt.Render("Reopen a recent workspace:")
+		content = prompt + "\n" + m.recentList.View() + m.renderCount(m.recentList)
+	case m.core.RecentLoading:
+		content = m.spinner.View() + " Loading recent workspaces..."
+	case m.core.RecentWarning == "":
+		content = m.styles.EmptyState.Render("No recent workspaces yet. Press " + keyName(m.keymap.Back) + " to go back.")
+	}
+	if m.core.RecentWarning != "" {
+		if content != "" {
+			content += "\n"
+		}
+		content += m.styles.Warning.Render("⚠ " + m.core.RecentWarning)
+	}
+	return content
+}
+
+// sessionsFooter renders the marks, the kill confirmation and the result of
+// the last kill below the session list.
+func (m Model) sessionsFooter() string {
+	var lines []string
+	if kill := len(m.core.SessionKillNames); kill > 0 {
+		prompt := m.styles.Body.Render("Kill " + countNoun(kill, "session") + "?")
+		actions := m.confirmActions("kill")
+		lines = append(lines, prompt+"  "+actions)
+	} else if marked := len(m.core.MarkedSessionList()); marked > 0 {
+		lines = append(lines, m.styles.Help.Render(countNoun(marked, "session")+" marked; "+keyName(m.keymap.Delete)+" kills them, "+keyName(m.keymap.Back)+" clears the marks."))
+	}
+	if m.core.SessionWarning != "" {
+		lines = append(lines, m.styles.Warning.Render("⚠ "+m.core.SessionWarning))
+	}
+	if m.core.SessionNotice != "" {
+		lines = append(lines, m.styles.Success.Render("✓ "+m.core.SessionNotice))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
+
+// countNoun renders a count with its noun, e.g. "1 workspace" or "3
+// workspaces".
+func countNoun(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// deleteBranchLabel returns the branch of the workspace being deleted, or ""
+// when it has none to delete.
+func (m Model) deleteBranchLabel() string {
+	if branches := m.core.DeletableBranches(); len(branches) > 0 {
+		return branches[0]
+	}
+	return ""
+}
+
+// bulkWorktreeDeleteContent is the delete confirmation for marked
+// workspaces.
+func (m Model) bulkWorktreeDeleteContent() string {
+	paths := m.core.WorktreeDeletePaths
+	prompt := m.styles.Body.Render("This will delete the following " + countNoun(len(paths), "workspace") + ":")
+	lines := make([]string, 0, len(paths))
+	for _, path := range paths {
+		label := filepath.Base(path)
+		for _, wt := range m.core.Worktrees {
+			if wt.Path == path {
+				label = m.worktreeDisplayLabel(wt)
+			}
+		}
+		lines = append(lines, m.styles.Body.Render("  "+label)+" "+m.styles.Path.Render(m.displayPath(path)))
+	}
+	warning := m.styles.Body.Render("This action cannot be undone.")
+	actions := m.confirmActions("delete")
+	content := prompt + "\n\n" + strings.Join(lines, "\n") + "\n\n" + warning
+	if len(m.core.DeletableBranches()) > 0 {
+		content += "\n\n" + m.renderBranchDeletion("their branches")
+		actions += "  " + m.styles.Key.Render(keyName(m.keymap.Delete)) + " " + m.styles.Help.Render("branch")
+	}
+	return content + "\n\n" + actions
+}
+
+// renderBranchDeletion renders the branch choice of the delete
+// confirmation; branch is "branch <name>" or "their branches".
+func (m Model) renderBranchDeletion(branch string) string {
+	var line string
+	switch m.core.WorktreeDeleteBranch {
+	case core.BranchDelete:
+		line = m.styles.Body.Render("[x] Also delete " + branch + " if fully merged")
+	case core.BranchForceDelete:
+		line = m.styles.DestructiveAction.Render("[!] Also force delete " + branch + ", losing unmerged commits")
+	default:
+		line = m.styles.Body.Render("[ ] Also delete " + branch)
+	}
+	if m.core.DeleteBranchWarning != "" {
+		line += "\n" + m.styles.Warning.Render("⚠ "+m.core.DeleteBranchWarning+". Press "+keyName(m.keymap.Delete)+" to force delete it or cycle back to keep it.")
+	}
+	return line
+}
+
+func (m Model) legacySessionsNotice() string {
+	count := len(m.core.LegacySessions)
+	switch count {
+	case 0:
+		return ""
+	case 1:
+		return "1 tmux session uses a legacy name. Press " + keyName(m.keymap.Adopt) + " to rename or adopt it."
+	default:
+		return fmt.Sprintf("%d tmux sessions use a legacy name. Press %s to rename or adopt them.", count, keyName(m.keymap.Adopt))
+	}
+}
+
 func (m Model) renderTableCount(t table.Model) string {
 	total := len(t.Rows())
 	if total == 0 {
@@ -201,6 +512,8 @@ func (m Model) renderBreadcrumb() string {
 	}
 	if m.core.SelectedWorktreePath != "" {
 		items = append(items, m.renderBreadcrumbItem("Workspace", m.worktreeBreadcrumbLabel()))
+	} else if m.core.Mode == core.ModeReview && m.core.ReviewPath != "" {
+		items = append(items, m.renderBreadcrumbItem("Workspace", m.reviewBreadcrumbLabel()))
 	}
 	if m.core.Mode == core.ModeTool || m.core.Mode == core.ModeToolStarting {
 		if tool, ok := m.core.SelectedTool(); ok {
@@ -212,7 +525,7 @@ func (m Model) renderBreadcrumb() string {
 	if len(items) == 0 {
 		return ""
 	}
-	return strings.Join(items, m.styles.Help.Render("  •  "))
+	return strings.Join(items, m.styles.Help.Render(breadcrumbSeparator))
 }
 
 func (m Model) worktreeBreadcrumbLabel() string {
@@ -233,6 +546,16 @@ func (m Model) worktreeBreadcrumbLabel() string {
 	return filepath.Base(m.core.SelectedWorktreePath)
 }
 
+func (m Model) reviewBreadcrumbLabel() string {
+	reviewPath := filepath.Clean(m.core.ReviewPath)
+	for _, wt := range m.core.Worktrees {
+		if filepath.Clean(wt.Path) == reviewPath {
+			return m.worktreeDisplayLabel(wt)
+		}
+	}
+	return filepath.Base(m.core.ReviewPath)
+}
+
 func (m Model) worktreeDisplayLabel(wt core.Worktree) string {
 	if wt.Branch != "" {
 		return wt.Branch
@@ -255,8 +578,15 @@ func (m Model) renderHelpModal() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
 }
 
+// confirmActions renders the keys of a confirmation: select runs the
+// destructive action, back cancels it.
+func (m Model) confirmActions(action string) string {
+	return m.styles.Key.Render(keyName(m.keymap.Select)) + " " + m.styles.DestructiveAction.Render(action) + "  " +
+		m.styles.Key.Render(keyName(m.keymap.Back)) + " " + m.styles.Help.Render("cancel")
+}
+
 func (m Model) helpModalContent() string {
-	return m.fullHelpView() + "\n\n" + m.styles.Help.Render("Press ? or esc to close")
+	return m.fullHelpView() + "\n\n" + m.styles.Help.Render("Press "+keyName(m.keymap.Toggle)+" or "+keyName(m.keymap.Back)+" to close")
 }
 
 func (m Model) viewportContentSignature(content string) string {
@@ -287,10 +617,21 @@ func (m Model) modalViewportContent() (string, bool) {
 	case core.ModeProjectDeleteConfirm:
 		prompt := m.styles.Body.Render("This will delete the project and all workspaces:")
 		path := m.styles.Path.Render("  " + m.displayPath(m.core.ProjectDeletePath))
+		if paths := m.core.ProjectDeletePaths; len(paths) > 0 {
+			prompt = m.styles.Body.Render("This will delete " + countNoun(len(paths), "project") + " and all their workspaces:")
+			lines := make([]string, 0, len(paths))
+			for _, projectPath := range paths {
+				lines = append(lines, "  "+m.displayPath(projectPath))
+			}
+			path = m.styles.Path.Render(strings.Join(lines, "\n"))
+		}
 		warning := m.styles.Body.Render("This action cannot be undone.")
-		actions := m.styles.Key.Render("enter") + " " + m.styles.DestructiveAction.Render("delete") + "  " + m.styles.Key.Render("esc") + " " + m.styles.Help.Render("cancel")
+		actions := m.confirmActions("delete")
 		return prompt + "\n\n" + path + "\n\n" + warning + "\n\n" + actions, true
 	case core.ModeWorktreeDeleteConfirm:
+		if len(m.core.WorktreeDeletePaths) > 0 {
+			return m.bulkWorktreeDeleteContent(), true
+		}
 		labelText := m.worktreeBreadcrumbLabel()
 		if labelText == "" {
 			labelText = m.displayPath(m.core.WorktreeDeletePath)
@@ -299,8 +640,25 @@ func (m Model) modalViewportContent() (string, bool) {
 		path := m.styles.Path.Render("  " + m.displayPath(m.core.WorktreeDeletePath))
 		prompt := m.styles.Body.Render("This will delete the following workspace:")
 		warning := m.styles.Body.Render("This action cannot be undone.")
-		actions := m.styles.Key.Render("enter") + " " + m.styles.DestructiveAction.Render("delete") + "  " + m.styles.Key.Render("esc") + " " + m.styles.Help.Render("cancel")
-		return prompt + "\n\n" + label + "\n" + path + "\n\n" + warning + "\n\n" + actions, true
+		actions := m.confirmActions("delete")
+		content := prompt + "\n\n" + label + "\n" + path + "\n\n" + warning
+		if branch := m.deleteBranchLabel(); branch != "" {
+			content += "\n\n" + m.renderBranchDeletion("branch "+branch)
+			actions += "  " + m.styles.Key.Render(keyName(m.keymap.Delete)) + " " + m.styles.Help.Render("branch")
+		}
+		return content + "\n\n" + actions, true
+	case core.ModeSessionMigrateConfirm:
+		prompt := m.styles.Body.Render("These tmux sessions use a name rivet no longer derives for their workspace:")
+		lines := make([]string, 0, len(m.core.LegacySessions))
+		for _, migration := range m.core.LegacySessions {
+			lines = append(lines, "  "+migration.Name+" → "+migration.CurrentName+"  "+m.displayPath(migration.DirPath))
+		}
+		sessions := m.styles.Path.Render(strings.Join(lines, "\n"))
+		explain := m.styles.Body.Render("Rename them to the current names, or adopt the names they have.")
+		actions := m.styles.Key.Render(keyName(m.keymap.Select)) + " " + m.styles.Help.Render("rename") + "  " +
+			m.styles.Key.Render(keyName(m.keymap.Adopt)) + " " + m.styles.Help.Render("adopt") + "  " +
+			m.styles.Key.Render(keyName(m.keymap.Back)) + " " + m.styles.Help.Render("cancel")
+		return prompt + "\n\n" + sessions + "\n\n" + explain + "\n\n" + actions, true
 	case core.ModeError:
 		return m.styles.Error.Render(fmt.Sprintf("Error: %v", m.core.Err)), true
 	default:
@@ -308,6 +666,112 @@ func (m Model) modalViewportContent() (string, bool) {
 	}
 }
 
+// reviewSections renders what the review screen shows above and below the
+// diff: the changed files, then any prompt or warning.
+func (m Model) reviewSections() (top, bottom string) {
+	changes := m.core.ReviewChanges
+	switch {
+	case len(changes.Files) > 0:
+		base := changes.Base
+		if base == "" {
+			base = "HEAD"
+		}
+		top = m.styles.Prompt.Render("Changes against "+base+":") + "\n" + m.reviewList.View() + m.renderCount(m.reviewList)
+	case m.core.ReviewLoading:
+		top = m.spinner.View() + " Loading changes..."
+	default:
+		top = m.styles.EmptyState.Render("No changes to review. Press " + keyName(m.keymap.Back) + " to go back.")
+	}
+
+	var lines []string
+	if m.core.ReviewDiscardPath != "" {
+		prompt := m.styles.Body.Render("Discard changes to " + m.core.ReviewDiscardPath + "?")
+		actions := m.confirmActions("discard")
+		lines = append(lines, prompt+"  "+actions)
+	}
+	if m.core.ReviewCommitting {
+		lines = append(lines, m.styles.Prompt.Render("Commit message:")+" "+m.commitInput.View())
+	}
+	if m.core.ReviewLoading && len(changes.Files) > 0 {
+		lines = append(lines, m.spinner.View()+" Refreshing...")
+	}
+	if m.core.ReviewWarning != "" {
+		lines = append(lines, m.styles.Warning.Render("⚠ "+m.core.ReviewWarning))
+	}
+	return top, strings.Join(lines, "\n")
+}
+
+// diffViewportSize fits the diff into what is left of the box once the
+// header, file list, prompts and help line are drawn.
+func (m Model) diffViewportSize() (width, height int) {
+	boxWidth, boxHeight := m.modalBoxDimensions()
+	boxStyle := m.styles.BoxWithWidth(m.width)
+	width = max(boxWidth-boxStyle.GetHorizontalFrameSize(), 1)
+	if boxHeight <= 0 {
+		return width, defaultDiffHeight
+	}
+	top, bottom := m.reviewSections()
+	// Header, breadcrumb and the blank lines around the list, diff and help.
+	used := 6 + lipgloss.Height(top)
+	if bottom != "" {
+		used += lipgloss.Height(bottom)
+	}
+	height = boxHeight - boxStyle.GetVerticalFrameSize() - used
+	return width, max(height, minDiffHeight)
+}
+
+const (
+	defaultDiffHeight = 12
+	minDiffHeight     = 3
+)
+
+func (m Model) diffSignature() string {
+	file, _ := m.core.SelectedReviewFile()
+	return "review:" + m.core.ReviewPath + ":" + file.Path
+}
+
+func (m Model) diffContent(width int) string {
+	if m.core.ReviewDiff == "" {
+		return m.styles.EmptyState.Render("No diff to show.")
+	}
+	return colorizeDiff(m.styles, m.core.ReviewDiff, width)
+}
+
+func (m Model) renderDiff() string {
+	vp := m.viewport
+	width, height := m.diffViewportSize()
+	vp.Width = width
+	vp.Height = height
+	vp.SetContent(m.diffContent(width))
+	if m.diffSignature() != m.viewportContentSig {
+		vp.GotoTop()
+	}
+	return vp.View()
+}
+
+// colorizeDiff styles a unified diff line by line. Lines are cut to width
+// rather than wrapped so the diff keeps its shape while scrolling.
+func colorizeDiff(styles Styles, diff string, width int) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	for i, line := range lines {
+		line = ansi.Truncate(strings.ReplaceAll(line, "\t", "    "), width, "…")
+		style := styles.Body
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+			strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+			style = styles.DiffHeader
+		case strings.HasPrefix(line, "@@"):
+			style = styles.DiffHunk
+		case strings.HasPrefix(line, "+"):
+			style = styles.DiffAdded
+		case strings.HasPrefix(line, "-"):
+			style = styles.DiffRemoved
+		}
+		lines[i] = style.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderThemePicker() string {
 	header := m.styles.Title.Render("Theme Picker")
 	prompt := m.styles.Prompt.Render("Filter themes:")
@@ -624,3 +624,64 @@ func TestRenderViewportContentUsesContentHeightWhenShort(t *testing.T) {
 		t.Fatalf("expected rendered viewport height 2, got %d", got)
 	}
 }
+
+func TestViewHelpFollowsRemappedKeys(t *testing.T) {
+	m := New(nil, nil, nil, WithKeyBindings(core.KeyBindings{
+		core.KeyDelete:   {"alt+d"},
+		core.KeySessions: {"f2", "alt+s"},
+	}))
+	m.width, m.height = 120, 25
+	m.core.Mode = core.ModeBrowsing
+	m.core.MarkedProjects = map[string]bool{"/projects/api": true}
+	m.core.Dirs = []core.DirEntry{{Path: "/projects/api", Name: "api"}}
+
+	view := stripANSI(m.View())
+	for _, part := range []string{"alt+d delete", "f2/alt+s sessions", "alt+d deletes them"} {
+		if !strings.Contains(view, part) {
+			t.Fatalf("expected %q in view, got %q", part, view)
+		}
+	}
+	if strings.Contains(view, "ctrl+d") || strings.Contains(view, "ctrl+s") {
+		t.Fatalf("expected the default keys to be gone, got %q", view)
+	}
+
+	m.showHelp = true
+	view = stripANSI(m.View())
+	if !strings.Contains(view, "alt+d") || strings.Contains(view, "ctrl+d") {
+		t.Fatalf("expected the help modal to show the remapped key, got %q", view)
+	}
+}
+
+func TestViewHelpModalListsPaletteAndRefresh(t *testing.T) {
+	m := newTestModel()
+	m.width, m.height = 120, 40
+	m.core.Mode = core.ModeBrowsing
+	m.showHelp = true
+
+	view := stripANSI(m.View())
+	for _, part := range []string{"ctrl+p", "commands", "f5", "refresh", "ctrl+y", "cleanup"} {
+		if !strings.Contains(view, part) {
+			t.Fatalf("expected help modal to contain %q, got %q", part, view)
+		}
+	}
+}
+
+func TestViewSessionsTableHeadsGroupsWithTag(t *testing.T) {
+	m := newTestModel()
+	m.height = 25
+	m.width = 140
+	m.core.Mode = core.ModeSessions
+	m.core.TagRules = core.TagRules{"backend": {"api"}}
+	m.core.Dirs = []core.DirEntry{{Path: "/repo/api", Name: "api"}}
+	m.core.Sessions = []core.SessionInfo{
+		{Name: "api-main", DirPath: "/repo/api", Project: "api", Branch: "main"},
+		{Name: "api-fix", DirPath: "/wt/api--fix", Project: "api", Branch: "fix"},
+	}
+	m.core.FilteredSessions = m.core.Sessions
+	m.syncSessionList()
+
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "Tag") || strings.Count(view, "backend") != 1 {
+		t.Fatalf("expected the backend tag to head its group once, got %q", view)
+	}
+}